@@ -0,0 +1,32 @@
+-- Create "review_logs" table
+CREATE TABLE "review_logs" (
+  "id" uuid NOT NULL,
+  "grade" smallint NOT NULL,
+  "ease" double precision NOT NULL,
+  "interval" integer NOT NULL,
+  "previous_interval" integer NOT NULL,
+  "elapsed_days" integer NOT NULL,
+  "reviewed_at" timestamptz NOT NULL,
+  "word_review_logs" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "review_logs_words_reviewLogs" FOREIGN KEY ("word_review_logs") REFERENCES "words" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create "word_reviews" table
+CREATE TABLE "word_reviews" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "ease" double precision NOT NULL DEFAULT 2.5,
+  "interval" integer NOT NULL DEFAULT 0,
+  "repetitions" integer NOT NULL DEFAULT 0,
+  "lapses" integer NOT NULL DEFAULT 0,
+  "due_at" timestamptz NOT NULL,
+  "last_reviewed_at" timestamptz NULL,
+  "word_review" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "word_reviews_words_review" FOREIGN KEY ("word_review") REFERENCES "words" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "word_reviews_word_review_key" to table: "word_reviews"
+CREATE UNIQUE INDEX "word_reviews_word_review_key" ON "word_reviews" ("word_review");
+-- Create index "wordreview_due_at" to table: "word_reviews"
+CREATE INDEX "wordreview_due_at" ON "word_reviews" ("due_at");
@@ -0,0 +1,18 @@
+-- Modify "word_reviews" table
+ALTER TABLE "word_reviews" ADD COLUMN "stability" double precision NULL, ADD COLUMN "difficulty" double precision NULL;
+-- Create "srs_settings" table
+CREATE TABLE "srs_settings" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "scheduler" character varying NOT NULL DEFAULT 'SM2',
+  "desired_retention" double precision NOT NULL DEFAULT 0.9,
+  "fsrs_weights" jsonb NULL,
+  "optimized_at" timestamptz NULL,
+  "optimized_review_count" integer NOT NULL DEFAULT 0,
+  "user_srs_settings" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "srs_settings_users_srsSettings" FOREIGN KEY ("user_srs_settings") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "srs_settings_user_srs_settings_key" to table: "srs_settings"
+CREATE UNIQUE INDEX "srs_settings_user_srs_settings_key" ON "srs_settings" ("user_srs_settings");
@@ -0,0 +1,21 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "is_admin" boolean NOT NULL DEFAULT false;
+-- Create "translation_cache_entries" table
+CREATE TABLE "translation_cache_entries" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "normalized_text" character varying NOT NULL,
+  "language_from" character varying NOT NULL,
+  "language_to" character varying NOT NULL,
+  "provider" character varying NOT NULL,
+  "variants" jsonb NOT NULL,
+  "hit_count" bigint NOT NULL DEFAULT 0,
+  "last_hit_at" timestamptz NULL,
+  "expires_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "translationcacheentry_expires_at" to table: "translation_cache_entries"
+CREATE INDEX "translationcacheentry_expires_at" ON "translation_cache_entries" ("expires_at");
+-- Create index "translationcacheentry_normalized_text_language_from_language_to_provider" to table: "translation_cache_entries"
+CREATE UNIQUE INDEX "translationcacheentry_normalized_text_language_from_language_to_provider" ON "translation_cache_entries" ("normalized_text", "language_from", "language_to", "provider");
@@ -0,0 +1,18 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "autofill_definitions" boolean NOT NULL DEFAULT false;
+-- Create "autofill_jobs" table
+CREATE TABLE "autofill_jobs" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "status" character varying NOT NULL DEFAULT 'PENDING',
+  "total" integer NOT NULL DEFAULT 0,
+  "processed" integer NOT NULL DEFAULT 0,
+  "filled" integer NOT NULL DEFAULT 0,
+  "failed" integer NOT NULL DEFAULT 0,
+  "error" character varying NULL,
+  "finished_at" timestamptz NULL,
+  "folder_autofill_jobs" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "autofill_jobs_folders_autofillJobs" FOREIGN KEY ("folder_autofill_jobs") REFERENCES "folders" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
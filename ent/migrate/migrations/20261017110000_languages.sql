@@ -0,0 +1,32 @@
+-- Create "languages" table
+CREATE TABLE "languages" (
+  "id" character varying NOT NULL,
+  "legacy_code" character varying NULL,
+  "name" character varying NOT NULL,
+  "native_name" character varying NOT NULL,
+  "script" character varying NOT NULL,
+  "direction" character varying NOT NULL DEFAULT 'LTR',
+  "providers" jsonb NOT NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "languages_legacy_code_key" to table: "languages"
+CREATE UNIQUE INDEX "languages_legacy_code_key" ON "languages" ("legacy_code");
+-- Seed "languages" table with the languages the legacy codes refer to, so
+-- existing folders can be converted below. The application adds the rest
+-- of the catalogue on startup and keeps every row up to date with it.
+INSERT INTO "languages" ("id", "legacy_code", "name", "native_name", "script", "direction", "providers") VALUES
+  ('zh', 'CHINESE', 'Chinese (Simplified)', '简体中文', 'Hans', 'LTR', '["google", "deepl", "libretranslate"]'),
+  ('en', 'ENGLISH', 'English', 'English', 'Latn', 'LTR', '["google", "deepl", "libretranslate"]'),
+  ('fr', 'FRENCH', 'French', 'Français', 'Latn', 'LTR', '["google", "deepl", "libretranslate"]'),
+  ('ka', 'GEORGIAN', 'Georgian', 'ქართული', 'Geor', 'LTR', '["google"]'),
+  ('de', 'GERMAN', 'German', 'Deutsch', 'Latn', 'LTR', '["google", "deepl", "libretranslate"]'),
+  ('ja', 'JAPANESE', 'Japanese', '日本語', 'Jpan', 'LTR', '["google", "deepl", "libretranslate"]'),
+  ('ru', 'RUSSIAN', 'Russian', 'Русский', 'Cyrl', 'LTR', '["google", "deepl", "libretranslate"]'),
+  ('es', 'SPANISH', 'Spanish', 'Español', 'Latn', 'LTR', '["google", "deepl", "libretranslate"]');
+-- Move folder and translation cache language codes from the legacy enum to catalogue tags
+UPDATE "folders" SET "language_from" = "languages"."id" FROM "languages" WHERE "folders"."language_from" = "languages"."legacy_code";
+UPDATE "folders" SET "language_to" = "languages"."id" FROM "languages" WHERE "folders"."language_to" = "languages"."legacy_code";
+UPDATE "translation_cache_entries" SET "language_from" = "languages"."id" FROM "languages" WHERE "translation_cache_entries"."language_from" = "languages"."legacy_code";
+UPDATE "translation_cache_entries" SET "language_to" = "languages"."id" FROM "languages" WHERE "translation_cache_entries"."language_to" = "languages"."legacy_code";
+-- Modify "folders" table
+ALTER TABLE "folders" ADD CONSTRAINT "folders_languages_sourceFolders" FOREIGN KEY ("language_from") REFERENCES "languages" ("id") ON UPDATE NO ACTION ON DELETE SET NULL, ADD CONSTRAINT "folders_languages_targetFolders" FOREIGN KEY ("language_to") REFERENCES "languages" ("id") ON UPDATE NO ACTION ON DELETE SET NULL;
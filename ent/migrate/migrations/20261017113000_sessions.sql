@@ -0,0 +1,21 @@
+-- Create "sessions" table
+CREATE TABLE "sessions" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "refresh_token_hash" character varying NOT NULL,
+  "previous_refresh_token_hash" character varying NULL,
+  "device_name" character varying NOT NULL DEFAULT '',
+  "ip" character varying NOT NULL DEFAULT '',
+  "user_agent" character varying NOT NULL DEFAULT '',
+  "last_used_at" timestamptz NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "revoked_at" timestamptz NULL,
+  "user_sessions" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "sessions_users_sessions" FOREIGN KEY ("user_sessions") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "sessions_refresh_token_hash_key" to table: "sessions"
+CREATE UNIQUE INDEX "sessions_refresh_token_hash_key" ON "sessions" ("refresh_token_hash");
+-- Create index "session_previous_refresh_token_hash" to table: "sessions"
+CREATE INDEX "session_previous_refresh_token_hash" ON "sessions" ("previous_refresh_token_hash");
@@ -0,0 +1,17 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "email_verified" boolean NOT NULL DEFAULT false;
+-- Create "one_time_tokens" table
+CREATE TABLE "one_time_tokens" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "purpose" character varying NOT NULL,
+  "token_hash" character varying NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz NULL,
+  "user_one_time_tokens" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "one_time_tokens_users_oneTimeTokens" FOREIGN KEY ("user_one_time_tokens") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "one_time_tokens_token_hash_key" to table: "one_time_tokens"
+CREATE UNIQUE INDEX "one_time_tokens_token_hash_key" ON "one_time_tokens" ("token_hash");
@@ -0,0 +1,16 @@
+-- Modify "users" table
+ALTER TABLE "users" ALTER COLUMN "password" DROP NOT NULL;
+-- Create "identities" table
+CREATE TABLE "identities" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "provider" character varying NOT NULL,
+  "subject" character varying NOT NULL,
+  "email" character varying NOT NULL DEFAULT '',
+  "user_identities" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "identities_users_identities" FOREIGN KEY ("user_identities") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "identity_provider_subject" to table: "identities"
+CREATE UNIQUE INDEX "identity_provider_subject" ON "identities" ("provider", "subject");
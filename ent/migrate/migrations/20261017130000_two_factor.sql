@@ -0,0 +1,15 @@
+-- Modify "users" table
+ALTER TABLE "users" ADD COLUMN "totp_secret" character varying NULL, ADD COLUMN "totp_enabled_at" timestamptz NULL, ADD COLUMN "totp_last_step" bigint NOT NULL DEFAULT 0;
+-- Modify "one_time_tokens" table
+ALTER TABLE "one_time_tokens" ADD COLUMN "attempts" bigint NOT NULL DEFAULT 0;
+-- Create "recovery_codes" table
+CREATE TABLE "recovery_codes" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "code_hash" character varying NOT NULL,
+  "used_at" timestamptz NULL,
+  "user_recovery_codes" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "recovery_codes_users_recoveryCodes" FOREIGN KEY ("user_recovery_codes") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+-- Create "personal_access_tokens" table
+CREATE TABLE "personal_access_tokens" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "name" character varying NOT NULL,
+  "token_hash" character varying NOT NULL,
+  "token_prefix" character varying NOT NULL,
+  "scopes" jsonb NOT NULL,
+  "expires_at" timestamptz NULL,
+  "last_used_at" timestamptz NULL,
+  "user_personal_access_tokens" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "personal_access_tokens_users_personalAccessTokens" FOREIGN KEY ("user_personal_access_tokens") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "personal_access_tokens_token_hash_key" to table: "personal_access_tokens"
+CREATE UNIQUE INDEX "personal_access_tokens_token_hash_key" ON "personal_access_tokens" ("token_hash");
@@ -0,0 +1,27 @@
+-- Create "audit_logs" table
+CREATE TABLE "audit_logs" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "event" character varying NOT NULL,
+  "user_id" uuid NULL,
+  "email" character varying NOT NULL DEFAULT '',
+  "ip" character varying NOT NULL DEFAULT '',
+  "user_agent" character varying NOT NULL DEFAULT '',
+  "details" jsonb NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "auditlog_user_id" to table: "audit_logs"
+CREATE INDEX "auditlog_user_id" ON "audit_logs" ("user_id");
+-- Create index "auditlog_ip" to table: "audit_logs"
+CREATE INDEX "auditlog_ip" ON "audit_logs" ("ip");
+-- Create "throttle_entries" table
+CREATE TABLE "throttle_entries" (
+  "id" character varying NOT NULL,
+  "failures" bigint NOT NULL,
+  "last_failure_at" timestamptz NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "throttleentry_expires_at" to table: "throttle_entries"
+CREATE INDEX "throttleentry_expires_at" ON "throttle_entries" ("expires_at");
@@ -0,0 +1,14 @@
+-- Create "folder_shares" table
+CREATE TABLE "folder_shares" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "role" character varying NOT NULL,
+  "folder_shares" uuid NOT NULL,
+  "user_folder_shares" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "folder_shares_folders_shares" FOREIGN KEY ("folder_shares") REFERENCES "folders" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "folder_shares_users_folderShares" FOREIGN KEY ("user_folder_shares") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "foldershare_folder_shares_user_folder_shares" to table: "folder_shares"
+CREATE UNIQUE INDEX "foldershare_folder_shares_user_folder_shares" ON "folder_shares" ("folder_shares", "user_folder_shares");
@@ -0,0 +1,4 @@
+-- Create index "folder_user_folders" to table: "folders"
+CREATE INDEX "folder_user_folders" ON "folders" ("user_folders");
+-- Create index "word_folder_words" to table: "words"
+CREATE INDEX "word_folder_words" ON "words" ("folder_words");
@@ -0,0 +1,6 @@
+-- Add "pg_trgm" extension for fuzzy word search
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+-- Create index "word_text" to table: "words"
+CREATE INDEX "word_text" ON "words" USING GIN ("text" gin_trgm_ops);
+-- Create index "word_definition" to table: "words"
+CREATE INDEX "word_definition" ON "words" USING GIN ("definition" gin_trgm_ops);
@@ -0,0 +1,40 @@
+-- Modify "words" table
+ALTER TABLE "words" ADD COLUMN "search_config" regconfig NOT NULL DEFAULT 'simple';
+-- Index existing words with the configuration for their folder's language
+UPDATE "words" SET "search_config" = (
+	CASE "folders"."language_from"
+		WHEN 'ar' THEN 'arabic'
+		WHEN 'ca' THEN 'catalan'
+		WHEN 'da' THEN 'danish'
+		WHEN 'de' THEN 'german'
+		WHEN 'el' THEN 'greek'
+		WHEN 'en' THEN 'english'
+		WHEN 'es' THEN 'spanish'
+		WHEN 'eu' THEN 'basque'
+		WHEN 'fi' THEN 'finnish'
+		WHEN 'fr' THEN 'french'
+		WHEN 'ga' THEN 'irish'
+		WHEN 'hi' THEN 'hindi'
+		WHEN 'hu' THEN 'hungarian'
+		WHEN 'hy' THEN 'armenian'
+		WHEN 'id' THEN 'indonesian'
+		WHEN 'it' THEN 'italian'
+		WHEN 'lt' THEN 'lithuanian'
+		WHEN 'nb' THEN 'norwegian'
+		WHEN 'ne' THEN 'nepali'
+		WHEN 'nl' THEN 'dutch'
+		WHEN 'pt' THEN 'portuguese'
+		WHEN 'ro' THEN 'romanian'
+		WHEN 'ru' THEN 'russian'
+		WHEN 'sr' THEN 'serbian'
+		WHEN 'sv' THEN 'swedish'
+		WHEN 'ta' THEN 'tamil'
+		WHEN 'tr' THEN 'turkish'
+		WHEN 'yi' THEN 'yiddish'
+		ELSE 'simple'
+	END
+)::regconfig
+FROM "folders"
+WHERE "folders"."id" = "words"."folder_words";
+-- Create index "word_search_document" to table: "words"
+CREATE INDEX "word_search_document" ON "words" USING GIN ((setweight(to_tsvector("search_config", "text"), 'A') || setweight(to_tsvector("search_config", "definition"), 'B')));
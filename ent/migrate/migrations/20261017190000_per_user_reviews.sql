@@ -0,0 +1,21 @@
+-- Modify "word_reviews" table
+ALTER TABLE "word_reviews" ADD COLUMN "user_word_reviews" uuid NULL;
+-- Existing reviews belong to the owner of the word's folder
+UPDATE "word_reviews" SET "user_word_reviews" = "folders"."user_folders"
+FROM "words" JOIN "folders" ON "folders"."id" = "words"."folder_words"
+WHERE "words"."id" = "word_reviews"."word_review";
+DELETE FROM "word_reviews" WHERE "user_word_reviews" IS NULL;
+ALTER TABLE "word_reviews" ALTER COLUMN "user_word_reviews" SET NOT NULL, ADD CONSTRAINT "word_reviews_users_wordReviews" FOREIGN KEY ("user_word_reviews") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+ALTER TABLE "word_reviews" RENAME CONSTRAINT "word_reviews_words_review" TO "word_reviews_words_reviews";
+-- Drop index "word_reviews_word_review_key" from table: "word_reviews"
+DROP INDEX "word_reviews_word_review_key";
+-- Create index "wordreview_user_word_reviews_word_review" to table: "word_reviews"
+CREATE UNIQUE INDEX "wordreview_user_word_reviews_word_review" ON "word_reviews" ("user_word_reviews", "word_review");
+-- Modify "review_logs" table
+ALTER TABLE "review_logs" ADD COLUMN "user_review_logs" uuid NULL;
+-- Existing logs belong to the owner of the word's folder
+UPDATE "review_logs" SET "user_review_logs" = "folders"."user_folders"
+FROM "words" JOIN "folders" ON "folders"."id" = "words"."folder_words"
+WHERE "words"."id" = "review_logs"."word_review_logs";
+DELETE FROM "review_logs" WHERE "user_review_logs" IS NULL;
+ALTER TABLE "review_logs" ALTER COLUMN "user_review_logs" SET NOT NULL, ADD CONSTRAINT "review_logs_users_reviewLogs" FOREIGN KEY ("user_review_logs") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
@@ -0,0 +1,9 @@
+-- Keep only the oldest pending or running job of each folder
+UPDATE "autofill_jobs" SET "status" = 'FAILED', "error" = 'Another job for the folder was already running', "finished_at" = now()
+WHERE "status" IN ('PENDING', 'RUNNING') AND "id" NOT IN (
+	SELECT DISTINCT ON ("folder_autofill_jobs") "id" FROM "autofill_jobs"
+	WHERE "status" IN ('PENDING', 'RUNNING')
+	ORDER BY "folder_autofill_jobs", "create_time"
+);
+-- Create index "autofilljob_folder_autofill_jobs" to table: "autofill_jobs"
+CREATE UNIQUE INDEX "autofilljob_folder_autofill_jobs" ON "autofill_jobs" ("folder_autofill_jobs") WHERE status IN ('PENDING', 'RUNNING');
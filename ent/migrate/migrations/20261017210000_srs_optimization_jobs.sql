@@ -0,0 +1,20 @@
+-- Create "srs_optimization_jobs" table
+CREATE TABLE "srs_optimization_jobs" (
+  "id" uuid NOT NULL,
+  "create_time" timestamptz NOT NULL,
+  "update_time" timestamptz NOT NULL,
+  "status" character varying NOT NULL DEFAULT 'PENDING',
+  "previous_weights" jsonb NULL,
+  "review_count" integer NOT NULL DEFAULT 0,
+  "log_loss_before" double precision NOT NULL DEFAULT 0,
+  "log_loss_after" double precision NOT NULL DEFAULT 0,
+  "current_daily_reviews" double precision NOT NULL DEFAULT 0,
+  "optimized_daily_reviews" double precision NOT NULL DEFAULT 0,
+  "error" character varying NULL,
+  "finished_at" timestamptz NULL,
+  "user_srs_optimization_jobs" uuid NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "srs_optimization_jobs_users_srsOptimizationJobs" FOREIGN KEY ("user_srs_optimization_jobs") REFERENCES "users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "srsoptimizationjob_user_srs_optimization_jobs" to table: "srs_optimization_jobs"
+CREATE UNIQUE INDEX "srsoptimizationjob_user_srs_optimization_jobs" ON "srs_optimization_jobs" ("user_srs_optimization_jobs") WHERE status IN ('PENDING', 'RUNNING');
@@ -3,11 +3,96 @@
 package migrate
 
 import (
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/dialect/sql/schema"
 	"entgo.io/ent/schema/field"
 )
 
 var (
+	// AuditLogsColumns holds the columns for the "audit_logs" table.
+	AuditLogsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "event", Type: field.TypeString},
+		{Name: "user_id", Type: field.TypeUUID, Nullable: true},
+		{Name: "email", Type: field.TypeString, Default: ""},
+		{Name: "ip", Type: field.TypeString, Default: ""},
+		{Name: "user_agent", Type: field.TypeString, Default: ""},
+		{Name: "details", Type: field.TypeJSON, Nullable: true},
+	}
+	// AuditLogsTable holds the schema information for the "audit_logs" table.
+	AuditLogsTable = &schema.Table{
+		Name:       "audit_logs",
+		Columns:    AuditLogsColumns,
+		PrimaryKey: []*schema.Column{AuditLogsColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "auditlog_user_id",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[4]},
+			},
+			{
+				Name:    "auditlog_ip",
+				Unique:  false,
+				Columns: []*schema.Column{AuditLogsColumns[6]},
+			},
+		},
+	}
+	// AutofillJobsColumns holds the columns for the "autofill_jobs" table.
+	AutofillJobsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "status", Type: field.TypeEnum, Enums: []string{"PENDING", "RUNNING", "COMPLETED", "FAILED"}, Default: "PENDING"},
+		{Name: "total", Type: field.TypeInt32, Default: 0},
+		{Name: "processed", Type: field.TypeInt32, Default: 0},
+		{Name: "filled", Type: field.TypeInt32, Default: 0},
+		{Name: "failed", Type: field.TypeInt32, Default: 0},
+		{Name: "error", Type: field.TypeString, Nullable: true},
+		{Name: "finished_at", Type: field.TypeTime, Nullable: true},
+		{Name: "folder_autofill_jobs", Type: field.TypeUUID},
+	}
+	// AutofillJobsTable holds the schema information for the "autofill_jobs" table.
+	AutofillJobsTable = &schema.Table{
+		Name:       "autofill_jobs",
+		Columns:    AutofillJobsColumns,
+		PrimaryKey: []*schema.Column{AutofillJobsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "autofill_jobs_folders_autofillJobs",
+				Columns:    []*schema.Column{AutofillJobsColumns[10]},
+				RefColumns: []*schema.Column{FoldersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "autofilljob_folder_autofill_jobs",
+				Unique:  true,
+				Columns: []*schema.Column{AutofillJobsColumns[10]},
+				Annotation: &entsql.IndexAnnotation{
+					Where: "status IN ('PENDING', 'RUNNING')",
+				},
+			},
+		},
+	}
+	// LanguagesColumns holds the columns for the "languages" table.
+	LanguagesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "legacy_code", Type: field.TypeString, Unique: true, Nullable: true},
+		{Name: "name", Type: field.TypeString},
+		{Name: "native_name", Type: field.TypeString},
+		{Name: "script", Type: field.TypeString},
+		{Name: "direction", Type: field.TypeEnum, Enums: []string{"LTR", "RTL"}, Default: "LTR"},
+		{Name: "providers", Type: field.TypeJSON},
+	}
+	// LanguagesTable holds the schema information for the "languages" table.
+	LanguagesTable = &schema.Table{
+		Name:       "languages",
+		Columns:    LanguagesColumns,
+		PrimaryKey: []*schema.Column{LanguagesColumns[0]},
+	}
 	// FoldersColumns holds the columns for the "folders" table.
 	FoldersColumns = []*schema.Column{
 		{Name: "id", Type: field.TypeUUID},
@@ -16,8 +101,8 @@ var (
 		{Name: "name", Type: field.TypeString},
 		{Name: "word_count", Type: field.TypeInt32},
 		{Name: "type", Type: field.TypeEnum, Enums: []string{"FOLDER_COLLECTION", "WORD_COLLECTION"}, Default: "WORD_COLLECTION"},
-		{Name: "language_from", Type: field.TypeEnum, Nullable: true, Enums: []string{"ENGLISH", "GEORGIAN", "SPANISH", "FRENCH", "GERMAN", "RUSSIAN", "JAPANESE", "CHINESE"}},
-		{Name: "language_to", Type: field.TypeEnum, Nullable: true, Enums: []string{"ENGLISH", "GEORGIAN", "SPANISH", "FRENCH", "GERMAN", "RUSSIAN", "JAPANESE", "CHINESE"}},
+		{Name: "language_from", Type: field.TypeString, Nullable: true},
+		{Name: "language_to", Type: field.TypeString, Nullable: true},
 		{Name: "user_folders", Type: field.TypeUUID, Nullable: true},
 	}
 	// FoldersTable holds the schema information for the "folders" table.
@@ -26,6 +111,18 @@ var (
 		Columns:    FoldersColumns,
 		PrimaryKey: []*schema.Column{FoldersColumns[0]},
 		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "folders_languages_sourceFolders",
+				Columns:    []*schema.Column{FoldersColumns[6]},
+				RefColumns: []*schema.Column{LanguagesColumns[0]},
+				OnDelete:   schema.SetNull,
+			},
+			{
+				Symbol:     "folders_languages_targetFolders",
+				Columns:    []*schema.Column{FoldersColumns[7]},
+				RefColumns: []*schema.Column{LanguagesColumns[0]},
+				OnDelete:   schema.SetNull,
+			},
 			{
 				Symbol:     "folders_users_folders",
 				Columns:    []*schema.Column{FoldersColumns[8]},
@@ -33,6 +130,342 @@ var (
 				OnDelete:   schema.SetNull,
 			},
 		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "folder_user_folders",
+				Unique:  false,
+				Columns: []*schema.Column{FoldersColumns[8]},
+			},
+		},
+	}
+	// FolderSharesColumns holds the columns for the "folder_shares" table.
+	FolderSharesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "role", Type: field.TypeEnum, Enums: []string{"VIEWER", "EDITOR"}},
+		{Name: "folder_shares", Type: field.TypeUUID},
+		{Name: "user_folder_shares", Type: field.TypeUUID},
+	}
+	// FolderSharesTable holds the schema information for the "folder_shares" table.
+	FolderSharesTable = &schema.Table{
+		Name:       "folder_shares",
+		Columns:    FolderSharesColumns,
+		PrimaryKey: []*schema.Column{FolderSharesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "folder_shares_folders_shares",
+				Columns:    []*schema.Column{FolderSharesColumns[4]},
+				RefColumns: []*schema.Column{FoldersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+			{
+				Symbol:     "folder_shares_users_folderShares",
+				Columns:    []*schema.Column{FolderSharesColumns[5]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "foldershare_folder_shares_user_folder_shares",
+				Unique:  true,
+				Columns: []*schema.Column{FolderSharesColumns[4], FolderSharesColumns[5]},
+			},
+		},
+	}
+	// IdentitiesColumns holds the columns for the "identities" table.
+	IdentitiesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "provider", Type: field.TypeString},
+		{Name: "subject", Type: field.TypeString},
+		{Name: "email", Type: field.TypeString, Default: ""},
+		{Name: "user_identities", Type: field.TypeUUID},
+	}
+	// IdentitiesTable holds the schema information for the "identities" table.
+	IdentitiesTable = &schema.Table{
+		Name:       "identities",
+		Columns:    IdentitiesColumns,
+		PrimaryKey: []*schema.Column{IdentitiesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "identities_users_identities",
+				Columns:    []*schema.Column{IdentitiesColumns[6]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "identity_provider_subject",
+				Unique:  true,
+				Columns: []*schema.Column{IdentitiesColumns[3], IdentitiesColumns[4]},
+			},
+		},
+	}
+	// OneTimeTokensColumns holds the columns for the "one_time_tokens" table.
+	OneTimeTokensColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "purpose", Type: field.TypeEnum, Enums: []string{"PASSWORD_RESET", "EMAIL_VERIFICATION", "MFA_CHALLENGE"}},
+		{Name: "token_hash", Type: field.TypeString, Unique: true},
+		{Name: "expires_at", Type: field.TypeTime},
+		{Name: "used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "attempts", Type: field.TypeInt, Default: 0},
+		{Name: "user_one_time_tokens", Type: field.TypeUUID},
+	}
+	// OneTimeTokensTable holds the schema information for the "one_time_tokens" table.
+	OneTimeTokensTable = &schema.Table{
+		Name:       "one_time_tokens",
+		Columns:    OneTimeTokensColumns,
+		PrimaryKey: []*schema.Column{OneTimeTokensColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "one_time_tokens_users_oneTimeTokens",
+				Columns:    []*schema.Column{OneTimeTokensColumns[8]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
+	// PersonalAccessTokensColumns holds the columns for the "personal_access_tokens" table.
+	PersonalAccessTokensColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "name", Type: field.TypeString},
+		{Name: "token_hash", Type: field.TypeString, Unique: true},
+		{Name: "token_prefix", Type: field.TypeString},
+		{Name: "scopes", Type: field.TypeJSON},
+		{Name: "expires_at", Type: field.TypeTime, Nullable: true},
+		{Name: "last_used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_personal_access_tokens", Type: field.TypeUUID},
+	}
+	// PersonalAccessTokensTable holds the schema information for the "personal_access_tokens" table.
+	PersonalAccessTokensTable = &schema.Table{
+		Name:       "personal_access_tokens",
+		Columns:    PersonalAccessTokensColumns,
+		PrimaryKey: []*schema.Column{PersonalAccessTokensColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "personal_access_tokens_users_personalAccessTokens",
+				Columns:    []*schema.Column{PersonalAccessTokensColumns[9]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
+	// RecoveryCodesColumns holds the columns for the "recovery_codes" table.
+	RecoveryCodesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "code_hash", Type: field.TypeString},
+		{Name: "used_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_recovery_codes", Type: field.TypeUUID},
+	}
+	// RecoveryCodesTable holds the schema information for the "recovery_codes" table.
+	RecoveryCodesTable = &schema.Table{
+		Name:       "recovery_codes",
+		Columns:    RecoveryCodesColumns,
+		PrimaryKey: []*schema.Column{RecoveryCodesColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "recovery_codes_users_recoveryCodes",
+				Columns:    []*schema.Column{RecoveryCodesColumns[5]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
+	// ReviewLogsColumns holds the columns for the "review_logs" table.
+	ReviewLogsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "grade", Type: field.TypeInt8},
+		{Name: "ease", Type: field.TypeFloat64},
+		{Name: "interval", Type: field.TypeInt32},
+		{Name: "previous_interval", Type: field.TypeInt32},
+		{Name: "elapsed_days", Type: field.TypeInt32},
+		{Name: "reviewed_at", Type: field.TypeTime},
+		{Name: "user_review_logs", Type: field.TypeUUID},
+		{Name: "word_review_logs", Type: field.TypeUUID},
+	}
+	// ReviewLogsTable holds the schema information for the "review_logs" table.
+	ReviewLogsTable = &schema.Table{
+		Name:       "review_logs",
+		Columns:    ReviewLogsColumns,
+		PrimaryKey: []*schema.Column{ReviewLogsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "review_logs_users_reviewLogs",
+				Columns:    []*schema.Column{ReviewLogsColumns[7]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+			{
+				Symbol:     "review_logs_words_reviewLogs",
+				Columns:    []*schema.Column{ReviewLogsColumns[8]},
+				RefColumns: []*schema.Column{WordsColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
+	// SessionsColumns holds the columns for the "sessions" table.
+	SessionsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "refresh_token_hash", Type: field.TypeString, Unique: true},
+		{Name: "previous_refresh_token_hash", Type: field.TypeString, Nullable: true},
+		{Name: "device_name", Type: field.TypeString, Default: ""},
+		{Name: "ip", Type: field.TypeString, Default: ""},
+		{Name: "user_agent", Type: field.TypeString, Default: ""},
+		{Name: "last_used_at", Type: field.TypeTime},
+		{Name: "expires_at", Type: field.TypeTime},
+		{Name: "revoked_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_sessions", Type: field.TypeUUID},
+	}
+	// SessionsTable holds the schema information for the "sessions" table.
+	SessionsTable = &schema.Table{
+		Name:       "sessions",
+		Columns:    SessionsColumns,
+		PrimaryKey: []*schema.Column{SessionsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "sessions_users_sessions",
+				Columns:    []*schema.Column{SessionsColumns[11]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "session_previous_refresh_token_hash",
+				Unique:  false,
+				Columns: []*schema.Column{SessionsColumns[4]},
+			},
+		},
+	}
+	// SrsOptimizationJobsColumns holds the columns for the "srs_optimization_jobs" table.
+	SrsOptimizationJobsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "status", Type: field.TypeEnum, Enums: []string{"PENDING", "RUNNING", "COMPLETED", "FAILED"}, Default: "PENDING"},
+		{Name: "previous_weights", Type: field.TypeJSON, Nullable: true},
+		{Name: "review_count", Type: field.TypeInt32, Default: 0},
+		{Name: "log_loss_before", Type: field.TypeFloat64, Default: 0},
+		{Name: "log_loss_after", Type: field.TypeFloat64, Default: 0},
+		{Name: "current_daily_reviews", Type: field.TypeFloat64, Default: 0},
+		{Name: "optimized_daily_reviews", Type: field.TypeFloat64, Default: 0},
+		{Name: "error", Type: field.TypeString, Nullable: true},
+		{Name: "finished_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_srs_optimization_jobs", Type: field.TypeUUID},
+	}
+	// SrsOptimizationJobsTable holds the schema information for the "srs_optimization_jobs" table.
+	SrsOptimizationJobsTable = &schema.Table{
+		Name:       "srs_optimization_jobs",
+		Columns:    SrsOptimizationJobsColumns,
+		PrimaryKey: []*schema.Column{SrsOptimizationJobsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "srs_optimization_jobs_users_srsOptimizationJobs",
+				Columns:    []*schema.Column{SrsOptimizationJobsColumns[12]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "srsoptimizationjob_user_srs_optimization_jobs",
+				Unique:  true,
+				Columns: []*schema.Column{SrsOptimizationJobsColumns[12]},
+				Annotation: &entsql.IndexAnnotation{
+					Where: "status IN ('PENDING', 'RUNNING')",
+				},
+			},
+		},
+	}
+	// SrsSettingsColumns holds the columns for the "srs_settings" table.
+	SrsSettingsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "scheduler", Type: field.TypeEnum, Enums: []string{"SM2", "FSRS"}, Default: "SM2"},
+		{Name: "desired_retention", Type: field.TypeFloat64, Default: 0.9},
+		{Name: "fsrs_weights", Type: field.TypeJSON, Nullable: true},
+		{Name: "optimized_at", Type: field.TypeTime, Nullable: true},
+		{Name: "optimized_review_count", Type: field.TypeInt32, Default: 0},
+		{Name: "user_srs_settings", Type: field.TypeUUID, Unique: true},
+	}
+	// SrsSettingsTable holds the schema information for the "srs_settings" table.
+	SrsSettingsTable = &schema.Table{
+		Name:       "srs_settings",
+		Columns:    SrsSettingsColumns,
+		PrimaryKey: []*schema.Column{SrsSettingsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "srs_settings_users_srsSettings",
+				Columns:    []*schema.Column{SrsSettingsColumns[8]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+	}
+	// ThrottleEntriesColumns holds the columns for the "throttle_entries" table.
+	ThrottleEntriesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeString},
+		{Name: "failures", Type: field.TypeInt},
+		{Name: "last_failure_at", Type: field.TypeTime},
+		{Name: "expires_at", Type: field.TypeTime},
+	}
+	// ThrottleEntriesTable holds the schema information for the "throttle_entries" table.
+	ThrottleEntriesTable = &schema.Table{
+		Name:       "throttle_entries",
+		Columns:    ThrottleEntriesColumns,
+		PrimaryKey: []*schema.Column{ThrottleEntriesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "throttleentry_expires_at",
+				Unique:  false,
+				Columns: []*schema.Column{ThrottleEntriesColumns[3]},
+			},
+		},
+	}
+	// TranslationCacheEntriesColumns holds the columns for the "translation_cache_entries" table.
+	TranslationCacheEntriesColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "normalized_text", Type: field.TypeString},
+		{Name: "language_from", Type: field.TypeString},
+		{Name: "language_to", Type: field.TypeString},
+		{Name: "provider", Type: field.TypeString},
+		{Name: "variants", Type: field.TypeJSON},
+		{Name: "hit_count", Type: field.TypeInt, Default: 0},
+		{Name: "last_hit_at", Type: field.TypeTime, Nullable: true},
+		{Name: "expires_at", Type: field.TypeTime},
+	}
+	// TranslationCacheEntriesTable holds the schema information for the "translation_cache_entries" table.
+	TranslationCacheEntriesTable = &schema.Table{
+		Name:       "translation_cache_entries",
+		Columns:    TranslationCacheEntriesColumns,
+		PrimaryKey: []*schema.Column{TranslationCacheEntriesColumns[0]},
+		Indexes: []*schema.Index{
+			{
+				Name:    "translationcacheentry_normalized_text_language_from_language_to_provider",
+				Unique:  true,
+				Columns: []*schema.Column{TranslationCacheEntriesColumns[3], TranslationCacheEntriesColumns[4], TranslationCacheEntriesColumns[5], TranslationCacheEntriesColumns[6]},
+			},
+			{
+				Name:    "translationcacheentry_expires_at",
+				Unique:  false,
+				Columns: []*schema.Column{TranslationCacheEntriesColumns[10]},
+			},
+		},
 	}
 	// UsersColumns holds the columns for the "users" table.
 	UsersColumns = []*schema.Column{
@@ -41,7 +474,14 @@ var (
 		{Name: "update_time", Type: field.TypeTime},
 		{Name: "username", Type: field.TypeString},
 		{Name: "email", Type: field.TypeString, Unique: true},
-		{Name: "password", Type: field.TypeString},
+		{Name: "password", Type: field.TypeString, Nullable: true},
+		{Name: "is_admin", Type: field.TypeBool, Default: false},
+		{Name: "autofill_definitions", Type: field.TypeBool, Default: false},
+		{Name: "email_verified", Type: field.TypeBool, Default: false},
+		{Name: "totp_secret", Type: field.TypeString, Nullable: true},
+		{Name: "totp_enabled_at", Type: field.TypeTime, Nullable: true},
+		{Name: "totp_last_step", Type: field.TypeInt64, Default: 0},
+		{Name: "deleted_at", Type: field.TypeTime, Nullable: true},
 	}
 	// UsersTable holds the schema information for the "users" table.
 	UsersTable = &schema.Table{
@@ -54,6 +494,11 @@ var (
 				Unique:  true,
 				Columns: []*schema.Column{UsersColumns[4]},
 			},
+			{
+				Name:    "user_deleted_at",
+				Unique:  false,
+				Columns: []*schema.Column{UsersColumns[12]},
+			},
 		},
 	}
 	// WordsColumns holds the columns for the "words" table.
@@ -63,6 +508,7 @@ var (
 		{Name: "update_time", Type: field.TypeTime},
 		{Name: "text", Type: field.TypeString},
 		{Name: "definition", Type: field.TypeString},
+		{Name: "search_config", Type: field.TypeString, Default: "simple", SchemaType: map[string]string{"postgres": "regconfig"}},
 		{Name: "folder_words", Type: field.TypeUUID, Nullable: true},
 	}
 	// WordsTable holds the schema information for the "words" table.
@@ -73,11 +519,84 @@ var (
 		ForeignKeys: []*schema.ForeignKey{
 			{
 				Symbol:     "words_folders_words",
-				Columns:    []*schema.Column{WordsColumns[5]},
+				Columns:    []*schema.Column{WordsColumns[6]},
 				RefColumns: []*schema.Column{FoldersColumns[0]},
 				OnDelete:   schema.SetNull,
 			},
 		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "word_folder_words",
+				Unique:  false,
+				Columns: []*schema.Column{WordsColumns[6]},
+			},
+			{
+				Name:    "word_text",
+				Unique:  false,
+				Columns: []*schema.Column{WordsColumns[3]},
+				Annotation: &entsql.IndexAnnotation{
+					OpClass: "gin_trgm_ops",
+					Type:    "GIN",
+				},
+			},
+			{
+				Name:    "word_definition",
+				Unique:  false,
+				Columns: []*schema.Column{WordsColumns[4]},
+				Annotation: &entsql.IndexAnnotation{
+					OpClass: "gin_trgm_ops",
+					Type:    "GIN",
+				},
+			},
+		},
+	}
+	// WordReviewsColumns holds the columns for the "word_reviews" table.
+	WordReviewsColumns = []*schema.Column{
+		{Name: "id", Type: field.TypeUUID},
+		{Name: "create_time", Type: field.TypeTime},
+		{Name: "update_time", Type: field.TypeTime},
+		{Name: "ease", Type: field.TypeFloat64, Default: 2.5},
+		{Name: "interval", Type: field.TypeInt32, Default: 0},
+		{Name: "repetitions", Type: field.TypeInt32, Default: 0},
+		{Name: "lapses", Type: field.TypeInt32, Default: 0},
+		{Name: "stability", Type: field.TypeFloat64, Nullable: true},
+		{Name: "difficulty", Type: field.TypeFloat64, Nullable: true},
+		{Name: "due_at", Type: field.TypeTime},
+		{Name: "last_reviewed_at", Type: field.TypeTime, Nullable: true},
+		{Name: "user_word_reviews", Type: field.TypeUUID},
+		{Name: "word_review", Type: field.TypeUUID},
+	}
+	// WordReviewsTable holds the schema information for the "word_reviews" table.
+	WordReviewsTable = &schema.Table{
+		Name:       "word_reviews",
+		Columns:    WordReviewsColumns,
+		PrimaryKey: []*schema.Column{WordReviewsColumns[0]},
+		ForeignKeys: []*schema.ForeignKey{
+			{
+				Symbol:     "word_reviews_users_wordReviews",
+				Columns:    []*schema.Column{WordReviewsColumns[11]},
+				RefColumns: []*schema.Column{UsersColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+			{
+				Symbol:     "word_reviews_words_reviews",
+				Columns:    []*schema.Column{WordReviewsColumns[12]},
+				RefColumns: []*schema.Column{WordsColumns[0]},
+				OnDelete:   schema.Cascade,
+			},
+		},
+		Indexes: []*schema.Index{
+			{
+				Name:    "wordreview_due_at",
+				Unique:  false,
+				Columns: []*schema.Column{WordReviewsColumns[9]},
+			},
+			{
+				Name:    "wordreview_user_word_reviews_word_review",
+				Unique:  true,
+				Columns: []*schema.Column{WordReviewsColumns[11], WordReviewsColumns[12]},
+			},
+		},
 	}
 	// FolderSubfoldersColumns holds the columns for the "folder_subfolders" table.
 	FolderSubfoldersColumns = []*schema.Column{
@@ -106,16 +625,50 @@ var (
 	}
 	// Tables holds all the tables in the schema.
 	Tables = []*schema.Table{
+		AuditLogsTable,
+		AutofillJobsTable,
+		LanguagesTable,
 		FoldersTable,
+		FolderSharesTable,
+		IdentitiesTable,
+		OneTimeTokensTable,
+		PersonalAccessTokensTable,
+		RecoveryCodesTable,
+		ReviewLogsTable,
+		SessionsTable,
+		SrsOptimizationJobsTable,
+		SrsSettingsTable,
+		ThrottleEntriesTable,
+		TranslationCacheEntriesTable,
 		UsersTable,
 		WordsTable,
+		WordReviewsTable,
 		FolderSubfoldersTable,
 	}
 )
 
 func init() {
-	FoldersTable.ForeignKeys[0].RefTable = UsersTable
+	AutofillJobsTable.ForeignKeys[0].RefTable = FoldersTable
+	LanguagesTable.Annotation = &entsql.Annotation{
+		Table: "languages",
+	}
+	FoldersTable.ForeignKeys[0].RefTable = LanguagesTable
+	FoldersTable.ForeignKeys[1].RefTable = LanguagesTable
+	FoldersTable.ForeignKeys[2].RefTable = UsersTable
+	FolderSharesTable.ForeignKeys[0].RefTable = FoldersTable
+	FolderSharesTable.ForeignKeys[1].RefTable = UsersTable
+	IdentitiesTable.ForeignKeys[0].RefTable = UsersTable
+	OneTimeTokensTable.ForeignKeys[0].RefTable = UsersTable
+	PersonalAccessTokensTable.ForeignKeys[0].RefTable = UsersTable
+	RecoveryCodesTable.ForeignKeys[0].RefTable = UsersTable
+	ReviewLogsTable.ForeignKeys[0].RefTable = UsersTable
+	ReviewLogsTable.ForeignKeys[1].RefTable = WordsTable
+	SessionsTable.ForeignKeys[0].RefTable = UsersTable
+	SrsOptimizationJobsTable.ForeignKeys[0].RefTable = UsersTable
+	SrsSettingsTable.ForeignKeys[0].RefTable = UsersTable
 	WordsTable.ForeignKeys[0].RefTable = FoldersTable
+	WordReviewsTable.ForeignKeys[0].RefTable = UsersTable
+	WordReviewsTable.ForeignKeys[1].RefTable = WordsTable
 	FolderSubfoldersTable.ForeignKeys[0].RefTable = FoldersTable
 	FolderSubfoldersTable.ForeignKeys[1].RefTable = FoldersTable
 }
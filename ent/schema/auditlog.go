@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+// AuditLog records security-relevant events. It keeps the user ID as a plain
+// column rather than an edge so entries outlive the account they describe.
+type AuditLog struct {
+	ent.Schema
+}
+
+func (AuditLog) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.String("event").
+			GoType(AuditEvent("")),
+		field.UUID("userId", uuid.UUID{}).
+			Optional().
+			Nillable(),
+		field.String("email").
+			Default(""),
+		field.String("ip").
+			Default(""),
+		field.String("userAgent").
+			Default(""),
+		field.JSON("details", map[string]any{}).
+			Optional(),
+	}
+}
+
+func (AuditLog) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("userId"),
+		index.Fields("ip"),
+	}
+}
+
+func (AuditLog) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+type AutofillJob struct {
+	ent.Schema
+}
+
+func (AutofillJob) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.Enum("status").
+			GoType(AutofillJobStatus("")).
+			Default(string(AutofillJobStatusPending)),
+		field.Int32("total").
+			Default(0),
+		field.Int32("processed").
+			Default(0),
+		field.Int32("filled").
+			Default(0),
+		field.Int32("failed").
+			Default(0),
+		field.String("error").
+			Optional().
+			Nillable(),
+		field.Time("finishedAt").
+			Optional().
+			Nillable(),
+	}
+}
+
+func (AutofillJob) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("folder", Folder.Type).
+			Ref("autofillJobs").
+			Unique().
+			Required(),
+	}
+}
+
+func (AutofillJob) Indexes() []ent.Index {
+	return []ent.Index{
+		// A folder has at most one job that is pending or running.
+		index.Edges("folder").
+			Unique().
+			Annotations(entsql.IndexWhere("status IN ('PENDING', 'RUNNING')")),
+	}
+}
+
+func (AutofillJob) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+)
+
+// CatalogLanguage is one entry of the language catalogue. The id is the
+// BCP-47 tag that providers understand; legacyCode keeps the old enum value
+// (e.g. ENGLISH) resolvable for clients that still send it.
+type CatalogLanguage struct {
+	ent.Schema
+}
+
+func (CatalogLanguage) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "languages"},
+	}
+}
+
+func (CatalogLanguage) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			GoType(Language("")).
+			NotEmpty().
+			Immutable(),
+		field.String("legacyCode").
+			GoType(Language("")).
+			Optional().
+			Nillable().
+			Unique(),
+		field.String("name").
+			NotEmpty(),
+		field.String("nativeName").
+			NotEmpty(),
+		field.String("script").
+			NotEmpty(),
+		field.Enum("direction").
+			GoType(TextDirection("")).
+			Default(string(TextDirectionLTR)),
+		field.JSON("providers", []string{}),
+	}
+}
+
+func (CatalogLanguage) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("sourceFolders", Folder.Type),
+		edge.To("targetFolders", Folder.Type),
+	}
+}
@@ -1,5 +1,8 @@
 package schema
 
+// Language is a language code. Canonical values are the BCP-47 tags stored
+// in the languages table; the upper-case constants below are the legacy codes
+// from before the catalogue existed and are still accepted everywhere.
 type Language string
 
 const (
@@ -13,17 +16,15 @@ const (
 	LanguageChinese  Language = "CHINESE"
 )
 
-func (Language) Values() (kinds []string) {
-	for _, s := range []Language{
-		LanguageEnglish,
-		LanguageGeorgian,
-		LanguageSpanish,
-		LanguageFrench,
-		LanguageGerman,
-		LanguageRussian,
-		LanguageJapanese,
-		LanguageChinese,
-	} {
+type TextDirection string
+
+const (
+	TextDirectionLTR TextDirection = "LTR"
+	TextDirectionRTL TextDirection = "RTL"
+)
+
+func (TextDirection) Values() (kinds []string) {
+	for _, s := range []TextDirection{TextDirectionLTR, TextDirectionRTL} {
 		kinds = append(kinds, string(s))
 	}
 	return
@@ -42,3 +43,111 @@ func (FolderType) Values() (kinds []string) {
 	}
 	return
 }
+
+type SrsScheduler string
+
+const (
+	SrsSchedulerSM2  SrsScheduler = "SM2"
+	SrsSchedulerFSRS SrsScheduler = "FSRS"
+)
+
+func (SrsScheduler) Values() (kinds []string) {
+	for _, s := range []SrsScheduler{SrsSchedulerSM2, SrsSchedulerFSRS} {
+		kinds = append(kinds, string(s))
+	}
+	return
+}
+
+type AutofillJobStatus string
+
+const (
+	AutofillJobStatusPending   AutofillJobStatus = "PENDING"
+	AutofillJobStatusRunning   AutofillJobStatus = "RUNNING"
+	AutofillJobStatusCompleted AutofillJobStatus = "COMPLETED"
+	AutofillJobStatusFailed    AutofillJobStatus = "FAILED"
+)
+
+func (AutofillJobStatus) Values() (kinds []string) {
+	for _, s := range []AutofillJobStatus{
+		AutofillJobStatusPending,
+		AutofillJobStatusRunning,
+		AutofillJobStatusCompleted,
+		AutofillJobStatusFailed,
+	} {
+		kinds = append(kinds, string(s))
+	}
+	return
+}
+
+type SrsOptimizationJobStatus string
+
+const (
+	SrsOptimizationJobStatusPending   SrsOptimizationJobStatus = "PENDING"
+	SrsOptimizationJobStatusRunning   SrsOptimizationJobStatus = "RUNNING"
+	SrsOptimizationJobStatusCompleted SrsOptimizationJobStatus = "COMPLETED"
+	SrsOptimizationJobStatusFailed    SrsOptimizationJobStatus = "FAILED"
+)
+
+func (SrsOptimizationJobStatus) Values() (kinds []string) {
+	for _, s := range []SrsOptimizationJobStatus{
+		SrsOptimizationJobStatusPending,
+		SrsOptimizationJobStatusRunning,
+		SrsOptimizationJobStatusCompleted,
+		SrsOptimizationJobStatusFailed,
+	} {
+		kinds = append(kinds, string(s))
+	}
+	return
+}
+
+type OneTimeTokenPurpose string
+
+const (
+	OneTimeTokenPurposePasswordReset     OneTimeTokenPurpose = "PASSWORD_RESET"
+	OneTimeTokenPurposeEmailVerification OneTimeTokenPurpose = "EMAIL_VERIFICATION"
+	OneTimeTokenPurposeMfaChallenge      OneTimeTokenPurpose = "MFA_CHALLENGE"
+)
+
+func (OneTimeTokenPurpose) Values() (kinds []string) {
+	for _, s := range []OneTimeTokenPurpose{
+		OneTimeTokenPurposePasswordReset,
+		OneTimeTokenPurposeEmailVerification,
+		OneTimeTokenPurposeMfaChallenge,
+	} {
+		kinds = append(kinds, string(s))
+	}
+	return
+}
+
+type AuditEvent string
+
+const (
+	AuditEventSignInFailed    AuditEvent = "SIGN_IN_FAILED"
+	AuditEventSignInThrottled AuditEvent = "SIGN_IN_THROTTLED"
+	AuditEventAccountLocked   AuditEvent = "ACCOUNT_LOCKED"
+)
+
+func (AuditEvent) Values() (kinds []string) {
+	for _, s := range []AuditEvent{
+		AuditEventSignInFailed,
+		AuditEventSignInThrottled,
+		AuditEventAccountLocked,
+	} {
+		kinds = append(kinds, string(s))
+	}
+	return
+}
+
+type FolderShareRole string
+
+const (
+	FolderShareRoleViewer FolderShareRole = "VIEWER"
+	FolderShareRoleEditor FolderShareRole = "EDITOR"
+)
+
+func (FolderShareRole) Values() (kinds []string) {
+	for _, s := range []FolderShareRole{FolderShareRoleViewer, FolderShareRoleEditor} {
+		kinds = append(kinds, string(s))
+	}
+	return
+}
@@ -2,8 +2,10 @@ package schema
 
 import (
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 	"entgo.io/ent/schema/mixin"
 	"github.com/google/uuid"
 )
@@ -22,11 +24,11 @@ func (Folder) Fields() []ent.Field {
 		field.Enum("type").
 			GoType(FolderType("")).
 			Default(string(FolderTypeWordCollection)),
-		field.Enum("languageFrom").
+		field.String("languageFrom").
 			GoType(Language("")).
 			Optional().
 			Nillable(),
-		field.Enum("languageTo").
+		field.String("languageTo").
 			GoType(Language("")).
 			Optional().
 			Nillable(),
@@ -41,11 +43,25 @@ func (Folder) Edges() []ent.Edge {
 		edge.To("words", Word.Type),
 		edge.To("subfolders", Folder.Type).
 			From("parent"),
+		edge.To("autofillJobs", AutofillJob.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("shares", FolderShare.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.From("sourceLanguage", CatalogLanguage.Type).
+			Ref("sourceFolders").
+			Field("languageFrom").
+			Unique(),
+		edge.From("targetLanguage", CatalogLanguage.Type).
+			Ref("targetFolders").
+			Field("languageTo").
+			Unique(),
 	}
 }
 
 func (Folder) Indexes() []ent.Index {
-	return nil
+	return []ent.Index{
+		index.Edges("user"),
+	}
 }
 
 func (Folder) Mixin() []ent.Mixin {
@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+// FolderShare gives another user a role on a folder. The role also applies
+// to every subfolder and word below it.
+type FolderShare struct {
+	ent.Schema
+}
+
+func (FolderShare) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.Enum("role").
+			GoType(FolderShareRole("")),
+	}
+}
+
+func (FolderShare) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("folder", Folder.Type).
+			Ref("shares").
+			Unique().
+			Required(),
+		edge.From("user", User.Type).
+			Ref("folderShares").
+			Unique().
+			Required(),
+	}
+}
+
+func (FolderShare) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Edges("folder", "user").
+			Unique(),
+	}
+}
+
+func (FolderShare) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+// Identity links a user to an account at an OpenID Connect provider. The
+// provider's subject is the stable key; the email is only a copy from the
+// last ID token for display.
+type Identity struct {
+	ent.Schema
+}
+
+func (Identity) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.String("provider").
+			NotEmpty(),
+		field.String("subject").
+			NotEmpty(),
+		field.String("email").
+			Default(""),
+	}
+}
+
+func (Identity) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("identities").
+			Unique().
+			Required(),
+	}
+}
+
+func (Identity) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("provider", "subject").
+			Unique(),
+	}
+}
+
+func (Identity) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
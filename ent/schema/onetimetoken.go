@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+// OneTimeToken backs the links sent by email, such as password resets and
+// email verification. Only the token hash is stored and a token can be used
+// once.
+type OneTimeToken struct {
+	ent.Schema
+}
+
+func (OneTimeToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.Enum("purpose").
+			GoType(OneTimeTokenPurpose("")),
+		field.String("tokenHash").
+			NotEmpty().
+			Unique().
+			Sensitive(),
+		field.Time("expiresAt"),
+		field.Time("usedAt").
+			Optional().
+			Nillable(),
+		// attempts counts failed uses for tokens that allow retries, such
+		// as two-factor sign-in challenges.
+		field.Int("attempts").
+			Default(0),
+	}
+}
+
+func (OneTimeToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("oneTimeTokens").
+			Unique().
+			Required(),
+	}
+}
+
+func (OneTimeToken) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
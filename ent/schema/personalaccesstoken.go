@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+// PersonalAccessToken is a long-lived token for scripts and integrations.
+// It only grants the listed scopes. The token itself is shown once on
+// creation; tokenPrefix keeps its first characters so users can tell their
+// tokens apart.
+type PersonalAccessToken struct {
+	ent.Schema
+}
+
+func (PersonalAccessToken) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.String("name").
+			NotEmpty(),
+		field.String("tokenHash").
+			NotEmpty().
+			Unique().
+			Sensitive(),
+		field.String("tokenPrefix"),
+		field.Strings("scopes"),
+		field.Time("expiresAt").
+			Optional().
+			Nillable(),
+		field.Time("lastUsedAt").
+			Optional().
+			Nillable(),
+	}
+}
+
+func (PersonalAccessToken) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("personalAccessTokens").
+			Unique().
+			Required(),
+	}
+}
+
+func (PersonalAccessToken) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
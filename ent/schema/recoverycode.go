@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+// RecoveryCode is a single-use two-factor backup code. Only its hash is
+// stored.
+type RecoveryCode struct {
+	ent.Schema
+}
+
+func (RecoveryCode) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.String("codeHash").
+			NotEmpty().
+			Sensitive(),
+		field.Time("usedAt").
+			Optional().
+			Nillable(),
+	}
+}
+
+func (RecoveryCode) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("recoveryCodes").
+			Unique().
+			Required(),
+	}
+}
+
+func (RecoveryCode) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"time"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"github.com/google/uuid"
+)
+
+type ReviewLog struct {
+	ent.Schema
+}
+
+func (ReviewLog) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.Int8("grade"),
+		field.Float("ease"),
+		field.Int32("interval"),
+		field.Int32("previousInterval"),
+		field.Int32("elapsedDays"),
+		field.Time("reviewedAt").
+			Default(time.Now).
+			Immutable(),
+	}
+}
+
+func (ReviewLog) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("word", Word.Type).
+			Ref("reviewLogs").
+			Unique().
+			Required(),
+		edge.From("user", User.Type).
+			Ref("reviewLogs").
+			Unique().
+			Required(),
+	}
+}
+
+func (ReviewLog) Indexes() []ent.Index {
+	return nil
+}
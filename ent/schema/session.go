@@ -0,0 +1,64 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+// Session is one signed-in device. Only hashes of refresh tokens are stored;
+// the previous hash is kept after rotation so a replayed token can be
+// recognised and the session revoked.
+type Session struct {
+	ent.Schema
+}
+
+func (Session) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.String("refreshTokenHash").
+			NotEmpty().
+			Unique().
+			Sensitive(),
+		field.String("previousRefreshTokenHash").
+			Optional().
+			Nillable().
+			Sensitive(),
+		field.String("deviceName").
+			Default(""),
+		field.String("ip").
+			Default(""),
+		field.String("userAgent").
+			Default(""),
+		field.Time("lastUsedAt"),
+		field.Time("expiresAt"),
+		field.Time("revokedAt").
+			Optional().
+			Nillable(),
+	}
+}
+
+func (Session) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("sessions").
+			Unique().
+			Required(),
+	}
+}
+
+func (Session) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("previousRefreshTokenHash"),
+	}
+}
+
+func (Session) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+type SrsOptimizationJob struct {
+	ent.Schema
+}
+
+func (SrsOptimizationJob) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.Enum("status").
+			GoType(SrsOptimizationJobStatus("")).
+			Default(string(SrsOptimizationJobStatusPending)),
+		field.JSON("previousWeights", []float64{}).
+			Optional(),
+		field.Int32("reviewCount").
+			Default(0),
+		field.Float("logLossBefore").
+			Default(0),
+		field.Float("logLossAfter").
+			Default(0),
+		field.Float("currentDailyReviews").
+			Default(0),
+		field.Float("optimizedDailyReviews").
+			Default(0),
+		field.String("error").
+			Optional().
+			Nillable(),
+		field.Time("finishedAt").
+			Optional().
+			Nillable(),
+	}
+}
+
+func (SrsOptimizationJob) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("srsOptimizationJobs").
+			Unique().
+			Required(),
+	}
+}
+
+func (SrsOptimizationJob) Indexes() []ent.Index {
+	return []ent.Index{
+		// A user has at most one job that is pending or running.
+		index.Edges("user").
+			Unique().
+			Annotations(entsql.IndexWhere("status IN ('PENDING', 'RUNNING')")),
+	}
+}
+
+func (SrsOptimizationJob) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
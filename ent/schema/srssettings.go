@@ -0,0 +1,51 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+type SrsSettings struct {
+	ent.Schema
+}
+
+func (SrsSettings) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.Enum("scheduler").
+			GoType(SrsScheduler("")).
+			Default(string(SrsSchedulerSM2)),
+		field.Float("desiredRetention").
+			Default(0.9),
+		field.JSON("fsrsWeights", []float64{}).
+			Optional(),
+		field.Time("optimizedAt").
+			Optional().
+			Nillable(),
+		field.Int32("optimizedReviewCount").
+			Default(0),
+	}
+}
+
+func (SrsSettings) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("user", User.Type).
+			Ref("srsSettings").
+			Unique().
+			Required(),
+	}
+}
+
+func (SrsSettings) Indexes() []ent.Index {
+	return nil
+}
+
+func (SrsSettings) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
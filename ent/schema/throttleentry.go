@@ -0,0 +1,29 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// ThrottleEntry counts recent failures of one throttle key, such as an
+// account or an IP signing in, for deployments with several replicas.
+type ThrottleEntry struct {
+	ent.Schema
+}
+
+func (ThrottleEntry) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("id").
+			NotEmpty(),
+		field.Int("failures"),
+		field.Time("lastFailureAt"),
+		field.Time("expiresAt"),
+	}
+}
+
+func (ThrottleEntry) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("expiresAt"),
+	}
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+// CachedTranslationVariant mirrors the variants returned by the translate
+// module so cached responses can be served without going upstream.
+type CachedTranslationVariant struct {
+	Text       string  `json:"text"`
+	Confidence float32 `json:"confidence"`
+	Source     string  `json:"source"`
+	Via        string  `json:"via,omitempty"`
+}
+
+type TranslationCacheEntry struct {
+	ent.Schema
+}
+
+func (TranslationCacheEntry) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.String("normalizedText").
+			NotEmpty(),
+		field.String("languageFrom").
+			GoType(Language("")),
+		field.String("languageTo").
+			GoType(Language("")),
+		field.String("provider").
+			NotEmpty(),
+		field.JSON("variants", []CachedTranslationVariant{}),
+		field.Int("hitCount").
+			Default(0),
+		field.Time("lastHitAt").
+			Optional().
+			Nillable(),
+		field.Time("expiresAt"),
+	}
+}
+
+func (TranslationCacheEntry) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("normalizedText", "languageFrom", "languageTo", "provider").
+			Unique(),
+		index.Fields("expiresAt"),
+	}
+}
+
+func (TranslationCacheEntry) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
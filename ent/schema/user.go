@@ -2,6 +2,7 @@ package schema
 
 import (
 	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
 	"entgo.io/ent/schema/index"
@@ -20,13 +21,61 @@ func (User) Fields() []ent.Field {
 		field.String("username").
 			NotEmpty(),
 		field.String("email").Unique(),
-		field.String("password").Sensitive(),
+		// Empty for users who only sign in through an identity provider.
+		field.String("password").
+			Optional().
+			Sensitive(),
+		field.Bool("isAdmin").
+			Default(false),
+		field.Bool("autofillDefinitions").
+			Default(false),
+		field.Bool("emailVerified").
+			Default(false),
+		// totpSecret is set on enrolment and only takes effect once
+		// totpEnabledAt is set by confirming a first code.
+		field.String("totpSecret").
+			Optional().
+			Nillable().
+			Sensitive(),
+		field.Time("totpEnabledAt").
+			Optional().
+			Nillable(),
+		// totpLastStep is the time step of the last accepted code, so a code
+		// cannot be used twice.
+		field.Int64("totpLastStep").
+			Default(0),
+		// deletedAt is set when the user deletes their account. The account
+		// and everything in it is purged once the grace period has passed.
+		field.Time("deletedAt").
+			Optional().
+			Nillable(),
 	}
 }
 
 func (User) Edges() []ent.Edge {
 	return []ent.Edge{
 		edge.To("folders", Folder.Type),
+		edge.To("srsSettings", SrsSettings.Type).
+			Unique().
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("sessions", Session.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("oneTimeTokens", OneTimeToken.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("identities", Identity.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("recoveryCodes", RecoveryCode.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("personalAccessTokens", PersonalAccessToken.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("folderShares", FolderShare.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("wordReviews", WordReview.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("reviewLogs", ReviewLog.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("srsOptimizationJobs", SrsOptimizationJob.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
 	}
 }
 
@@ -34,6 +83,7 @@ func (User) Indexes() []ent.Index {
 	return []ent.Index{
 		index.Fields("email").
 			Unique(),
+		index.Fields("deletedAt"),
 	}
 }
 
@@ -2,8 +2,11 @@ package schema
 
 import (
 	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/schema/edge"
 	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
 	"entgo.io/ent/schema/mixin"
 	"github.com/google/uuid"
 )
@@ -19,6 +22,13 @@ func (Word) Fields() []ent.Field {
 		field.String("text").
 			NotEmpty(),
 		field.String("definition"),
+		// searchConfig is the full-text search configuration for the
+		// language of the word's folder. Storing it lets the search document
+		// be indexed; folders never change language, so it is set once.
+		field.String("searchConfig").
+			SchemaType(map[string]string{dialect.Postgres: "regconfig"}).
+			Default("simple").
+			Immutable(),
 	}
 }
 
@@ -27,11 +37,26 @@ func (Word) Edges() []ent.Edge {
 		edge.From("folder", Folder.Type).
 			Ref("words").
 			Unique(),
+		// Every user who studies the word has a review of their own.
+		edge.To("reviews", WordReview.Type).
+			StorageKey(edge.Column("word_review")).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
+		edge.To("reviewLogs", ReviewLog.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)),
 	}
 }
 
 func (Word) Indexes() []ent.Index {
-	return nil
+	return []ent.Index{
+		index.Edges("folder"),
+		// Trigram indexes for fuzzy search. They need the pg_trgm extension.
+		// Full-text search uses an expression index on the search document,
+		// which only exists in the migrations.
+		index.Fields("text").
+			Annotations(entsql.IndexType("GIN"), entsql.OpClass("gin_trgm_ops")),
+		index.Fields("definition").
+			Annotations(entsql.IndexType("GIN"), entsql.OpClass("gin_trgm_ops")),
+	}
 }
 
 func (Word) Mixin() []ent.Mixin {
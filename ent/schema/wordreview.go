@@ -0,0 +1,66 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"github.com/google/uuid"
+)
+
+type WordReview struct {
+	ent.Schema
+}
+
+func (WordReview) Fields() []ent.Field {
+	return []ent.Field{
+		field.UUID("id", uuid.UUID{}).
+			Default(uuid.New),
+		field.Float("ease").
+			Default(2.5),
+		field.Int32("interval").
+			Default(0),
+		field.Int32("repetitions").
+			Default(0),
+		field.Int32("lapses").
+			Default(0),
+		field.Float("stability").
+			Optional().
+			Nillable(),
+		field.Float("difficulty").
+			Optional().
+			Nillable(),
+		field.Time("dueAt"),
+		field.Time("lastReviewedAt").
+			Optional().
+			Nillable(),
+	}
+}
+
+func (WordReview) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("word", Word.Type).
+			Ref("reviews").
+			Unique().
+			Required(),
+		edge.From("user", User.Type).
+			Ref("wordReviews").
+			Unique().
+			Required(),
+	}
+}
+
+func (WordReview) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("dueAt"),
+		index.Edges("user", "word").
+			Unique(),
+	}
+}
+
+func (WordReview) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixin.Time{},
+	}
+}
@@ -0,0 +1,94 @@
+// Package apperr defines the errors services return to handlers. Every error
+// has a kind, one of the sentinels below, which decides the HTTP status, and
+// a machine-readable code that clients can rely on. Match kinds with
+// errors.Is and specific errors by comparing codes.
+package apperr
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	// ErrInvalidState is for requests that are well-formed but cannot be
+	// carried out on the entity as it is, such as deleting a folder that
+	// still has words.
+	ErrInvalidState = errors.New("invalid state")
+	// ErrTooManyRequests is for requests refused by a rate limit. The error's
+	// RetryAfter says when the client may try again.
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	// Details is sent to clients alongside the code, for example the fields
+	// that failed validation.
+	Details any
+	// RetryAfter is how long a rate-limited client has to wait.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	return e.Kind == target
+}
+
+func (e *Error) ErrorCode() string {
+	return e.Code
+}
+
+func (e *Error) ErrorMessage() string {
+	return e.Message
+}
+
+func (e *Error) ErrorDetails() any {
+	return e.Details
+}
+
+func New(kind error, code string, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+func InvalidInput(code string, message string) *Error {
+	return New(ErrInvalidInput, code, message)
+}
+
+func Unauthorized(code string, message string) *Error {
+	return New(ErrUnauthorized, code, message)
+}
+
+func Forbidden(code string, message string) *Error {
+	return New(ErrForbidden, code, message)
+}
+
+func NotFound(code string, message string) *Error {
+	return New(ErrNotFound, code, message)
+}
+
+func Conflict(code string, message string) *Error {
+	return New(ErrConflict, code, message)
+}
+
+func InvalidState(code string, message string) *Error {
+	return New(ErrInvalidState, code, message)
+}
+
+func TooManyRequests(code string, message string, retryAfter time.Duration) *Error {
+	err := New(ErrTooManyRequests, code, message)
+	err.RetryAfter = retryAfter
+	return err
+}
@@ -0,0 +1,210 @@
+// Package authz decides what a user may do with folders and words. A user's
+// role on a folder comes from owning it, from a share on the folder or one of
+// its ancestors, or from being an admin; words take the role of their
+// folder.
+package authz
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/folder"
+	"lexia/ent/schema"
+	"lexia/ent/user"
+	"lexia/ent/word"
+	"lexia/internal/apperr"
+	"lexia/internal/shared"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFolderNotFound = apperr.NotFound("FOLDER_NOT_FOUND", "Folder not found")
+	ErrWordNotFound   = apperr.NotFound("WORD_NOT_FOUND", "Word not found")
+	ErrAccessDenied   = apperr.Forbidden(shared.ErrForbidden, "You do not have access to this folder")
+)
+
+// Role is what a user may do with a folder. Each role includes the ones
+// below it.
+type Role int
+
+const (
+	RoleNone Role = iota
+	// RoleViewer reads the folder, its subfolders and their words.
+	RoleViewer
+	// RoleEditor also renames the folder and adds, edits and deletes words.
+	RoleEditor
+	// RoleOwner also creates, moves and deletes subfolders and manages
+	// shares. Admins have it on every folder.
+	RoleOwner
+)
+
+// ShareRole maps a stored share to its role.
+func ShareRole(shareRole schema.FolderShareRole) Role {
+	switch shareRole {
+	case schema.FolderShareRoleEditor:
+		return RoleEditor
+	case schema.FolderShareRoleViewer:
+		return RoleViewer
+	default:
+		return RoleNone
+	}
+}
+
+// folderSharesQuery returns the roles shared with a user on a folder and on
+// each of its ancestors. In folder_subfolders folder_id is the parent and
+// parent_id the child.
+const folderSharesQuery = `
+WITH RECURSIVE ancestors AS (
+	SELECT $1::uuid AS id, ARRAY[$1::uuid] AS path
+	UNION ALL
+	SELECT fs.folder_id, a.path || fs.folder_id
+	FROM ancestors a
+	JOIN folder_subfolders fs ON fs.parent_id = a.id
+	WHERE NOT fs.folder_id = ANY(a.path)
+)
+SELECT s.role
+FROM folder_shares s
+WHERE s.folder_shares IN (SELECT id FROM ancestors)
+	AND s.user_folder_shares = $2
+`
+
+// FolderRole returns the user's role on an existing folder.
+func FolderRole(ctx context.Context, db *ent.Client, userID uuid.UUID, folderEntity *ent.Folder) (Role, error) {
+	if folderEntity.Edges.User != nil && folderEntity.Edges.User.ID == userID {
+		return RoleOwner, nil
+	}
+
+	role, err := sharedRole(ctx, db, userID, folderEntity.ID)
+	if err != nil {
+		return RoleNone, err
+	}
+
+	if role < RoleOwner {
+		isAdmin, err := db.User.Query().
+			Where(user.ID(userID), user.IsAdmin(true)).
+			Exist(ctx)
+		if err != nil {
+			log.Println("Error checking admin role: ", err)
+			return RoleNone, err
+		}
+		if isAdmin {
+			return RoleOwner, nil
+		}
+	}
+
+	return role, nil
+}
+
+func sharedRole(ctx context.Context, db *ent.Client, userID uuid.UUID, folderID uuid.UUID) (Role, error) {
+	rows, err := db.QueryContext(ctx, folderSharesQuery, folderID, userID)
+	if err != nil {
+		log.Println("Error finding folder shares: ", err)
+		return RoleNone, err
+	}
+	defer rows.Close()
+
+	role := RoleNone
+	for rows.Next() {
+		var shareRole schema.FolderShareRole
+		if err := rows.Scan(&shareRole); err != nil {
+			return RoleNone, err
+		}
+		role = max(role, ShareRole(shareRole))
+	}
+
+	return role, rows.Err()
+}
+
+// requireFolderRole loads the folder and checks the user has at least role
+// on it. A missing folder is a 404 and too weak a role a 403.
+func requireFolderRole(ctx context.Context, db *ent.Client, userID uuid.UUID, folderID uuid.UUID, role Role) (*ent.Folder, error) {
+	folderEntity, err := db.Folder.Query().
+		Where(folder.ID(folderID)).
+		WithUser().
+		Only(ctx)
+	if err != nil {
+		if shared.IsDatabaseErorNotFound(err) {
+			return nil, ErrFolderNotFound
+		}
+		log.Println("Error finding folder: ", err)
+		return nil, err
+	}
+
+	actual, err := FolderRole(ctx, db, userID, folderEntity)
+	if err != nil {
+		return nil, err
+	}
+
+	if actual < role {
+		return nil, ErrAccessDenied
+	}
+
+	return folderEntity, nil
+}
+
+// CanReadFolder returns the folder, with its owner loaded, if the user may
+// read it.
+func CanReadFolder(ctx context.Context, db *ent.Client, userID uuid.UUID, folderID uuid.UUID) (*ent.Folder, error) {
+	return requireFolderRole(ctx, db, userID, folderID, RoleViewer)
+}
+
+// CanEditFolder returns the folder if the user may rename it and change its
+// words.
+func CanEditFolder(ctx context.Context, db *ent.Client, userID uuid.UUID, folderID uuid.UUID) (*ent.Folder, error) {
+	return requireFolderRole(ctx, db, userID, folderID, RoleEditor)
+}
+
+// CanManageFolder returns the folder if the user may add subfolders to it,
+// move it or delete it.
+func CanManageFolder(ctx context.Context, db *ent.Client, userID uuid.UUID, folderID uuid.UUID) (*ent.Folder, error) {
+	return requireFolderRole(ctx, db, userID, folderID, RoleOwner)
+}
+
+// CanShareFolder returns the folder if the user may share it with others.
+func CanShareFolder(ctx context.Context, db *ent.Client, userID uuid.UUID, folderID uuid.UUID) (*ent.Folder, error) {
+	return requireFolderRole(ctx, db, userID, folderID, RoleOwner)
+}
+
+func requireWordRole(ctx context.Context, db *ent.Client, userID uuid.UUID, wordID uuid.UUID, role Role) (*ent.Word, error) {
+	wordEntity, err := db.Word.Query().
+		Where(word.ID(wordID)).
+		WithFolder(func(q *ent.FolderQuery) {
+			q.WithUser()
+		}).
+		Only(ctx)
+	if err != nil {
+		if shared.IsDatabaseErorNotFound(err) {
+			return nil, ErrWordNotFound
+		}
+		log.Println("Error finding word: ", err)
+		return nil, err
+	}
+
+	if wordEntity.Edges.Folder == nil {
+		return nil, ErrWordNotFound
+	}
+
+	actual, err := FolderRole(ctx, db, userID, wordEntity.Edges.Folder)
+	if err != nil {
+		return nil, err
+	}
+
+	if actual < role {
+		return nil, ErrAccessDenied
+	}
+
+	return wordEntity, nil
+}
+
+// CanReadWord returns the word, with its folder loaded, if the user may read
+// it.
+func CanReadWord(ctx context.Context, db *ent.Client, userID uuid.UUID, wordID uuid.UUID) (*ent.Word, error) {
+	return requireWordRole(ctx, db, userID, wordID, RoleViewer)
+}
+
+// CanEditWord returns the word, with its folder loaded, if the user may
+// change or delete it.
+func CanEditWord(ctx context.Context, db *ent.Client, userID uuid.UUID, wordID uuid.UUID) (*ent.Word, error) {
+	return requireWordRole(ctx, db, userID, wordID, RoleEditor)
+}
@@ -0,0 +1,125 @@
+// Package jobqueue runs jobs queued in a database table one at a time. A job
+// is pending until a runner claims it, running while the runner works on it,
+// and then completed or failed.
+package jobqueue
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StaleJobError is recorded on running jobs that stopped reporting progress.
+const StaleJobError = "Interrupted by server restart"
+
+// Queue is one table of jobs.
+type Queue interface {
+	// NextPending returns the oldest pending job. found is false when there
+	// is none.
+	NextPending(ctx context.Context) (jobID uuid.UUID, found bool, err error)
+	// Claim moves a pending job to running. It returns false when another
+	// runner claimed the job first.
+	Claim(ctx context.Context, jobID uuid.UUID) (bool, error)
+	// Run does the work of a claimed job. It should return soon after ctx is
+	// cancelled.
+	Run(ctx context.Context, jobID uuid.UUID) error
+	// Finish marks a job completed, or failed with jobErr.
+	Finish(ctx context.Context, jobID uuid.UUID, jobErr error) error
+	// FailStale marks running jobs last updated before the given time as
+	// failed with StaleJobError.
+	FailStale(ctx context.Context, before time.Time, now time.Time) error
+}
+
+type Config struct {
+	// Name appears in log lines.
+	Name         string
+	PollInterval time.Duration
+	// StaleAfter is how long a running job may go without an update before
+	// it is considered abandoned by a server that stopped.
+	StaleAfter time.Duration
+}
+
+// Runner runs the jobs of a queue. Notify wakes it up after a job is queued,
+// and it also polls, so any replica picks up jobs queued on another.
+type Runner struct {
+	queue  Queue
+	config Config
+	wake   chan struct{}
+}
+
+func NewRunner(queue Queue, config Config) *Runner {
+	return &Runner{
+		queue:  queue,
+		config: config,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Notify tells the runner a job is waiting. It never blocks.
+func (r *Runner) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run runs jobs until ctx is cancelled. A job that is running then is marked
+// failed before Run returns.
+func (r *Runner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		if err := r.queue.FailStale(ctx, now.Add(-r.config.StaleAfter), now); err != nil {
+			log.Println("Error failing stale "+r.config.Name+" jobs: ", err)
+		}
+
+		for ctx.Err() == nil && r.runNextJob(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNextJob claims the oldest pending job and runs it. It returns false when
+// there was no job to run.
+func (r *Runner) runNextJob(ctx context.Context) bool {
+	jobID, found, err := r.queue.NextPending(ctx)
+	if err != nil {
+		log.Println("Error finding pending "+r.config.Name+" job: ", err)
+		return false
+	}
+	if !found {
+		return false
+	}
+
+	// Another replica may have claimed the job since it was read.
+	claimed, err := r.queue.Claim(ctx, jobID)
+	if err != nil {
+		log.Println("Error starting "+r.config.Name+" job: ", err)
+		return false
+	}
+	if !claimed {
+		return true
+	}
+
+	jobErr := r.queue.Run(ctx, jobID)
+	if jobErr != nil {
+		log.Println("Error running "+r.config.Name+" job: ", jobErr)
+	}
+
+	// The outcome is recorded even when ctx was cancelled by a shutdown.
+	if err := r.queue.Finish(context.WithoutCancel(ctx), jobID, jobErr); err != nil {
+		log.Println("Error finishing "+r.config.Name+" job: ", err)
+	}
+
+	return true
+}
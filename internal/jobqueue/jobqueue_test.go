@@ -0,0 +1,130 @@
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fakeQueue struct {
+	mu       sync.Mutex
+	pending  []uuid.UUID
+	taken    map[uuid.UUID]bool
+	runErr   error
+	finished map[uuid.UUID]error
+	done     chan uuid.UUID
+}
+
+func newFakeQueue(jobs ...uuid.UUID) *fakeQueue {
+	return &fakeQueue{
+		pending:  jobs,
+		taken:    map[uuid.UUID]bool{},
+		finished: map[uuid.UUID]error{},
+		done:     make(chan uuid.UUID, len(jobs)),
+	}
+}
+
+func (q *fakeQueue) NextPending(ctx context.Context) (uuid.UUID, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 {
+		return uuid.Nil, false, nil
+	}
+	return q.pending[0], true, nil
+}
+
+func (q *fakeQueue) Claim(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = q.pending[1:]
+	if q.taken[jobID] {
+		return false, nil
+	}
+	q.taken[jobID] = true
+	return true, nil
+}
+
+func (q *fakeQueue) Run(ctx context.Context, jobID uuid.UUID) error {
+	return q.runErr
+}
+
+func (q *fakeQueue) Finish(ctx context.Context, jobID uuid.UUID, jobErr error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	q.mu.Lock()
+	q.finished[jobID] = jobErr
+	q.mu.Unlock()
+
+	q.done <- jobID
+	return nil
+}
+
+func (q *fakeQueue) FailStale(ctx context.Context, before time.Time, now time.Time) error {
+	return nil
+}
+
+func runRunner(t *testing.T, queue *fakeQueue, jobs int) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go NewRunner(queue, Config{Name: "test", PollInterval: time.Hour, StaleAfter: time.Hour}).Run(ctx)
+
+	for i := 0; i < jobs; i++ {
+		select {
+		case <-queue.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for jobs to finish")
+		}
+	}
+}
+
+func TestRunnerRunsPendingJobs(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	queue := newFakeQueue(first, second)
+
+	runRunner(t, queue, 2)
+
+	for _, jobID := range []uuid.UUID{first, second} {
+		jobErr, ok := queue.finished[jobID]
+		if !ok || jobErr != nil {
+			t.Errorf("expected job %s to complete, got finished=%v err=%v", jobID, ok, jobErr)
+		}
+	}
+}
+
+func TestRunnerSkipsJobsClaimedElsewhere(t *testing.T) {
+	claimedElsewhere, next := uuid.New(), uuid.New()
+	queue := newFakeQueue(claimedElsewhere, next)
+	queue.taken[claimedElsewhere] = true
+
+	runRunner(t, queue, 1)
+
+	if _, ok := queue.finished[claimedElsewhere]; ok {
+		t.Error("expected a job claimed by another runner not to run")
+	}
+	if _, ok := queue.finished[next]; !ok {
+		t.Error("expected the next job to run")
+	}
+}
+
+func TestRunnerRecordsJobErrors(t *testing.T) {
+	jobID := uuid.New()
+	queue := newFakeQueue(jobID)
+	queue.runErr = errors.New("provider unavailable")
+
+	runRunner(t, queue, 1)
+
+	if queue.finished[jobID] != queue.runErr {
+		t.Errorf("expected the job to fail with %v, got %v", queue.runErr, queue.finished[jobID])
+	}
+}
@@ -0,0 +1,19 @@
+package mailer
+
+import (
+	"context"
+	"lexia/internal/logger"
+)
+
+// LogMailer writes messages to the log instead of sending them. It is the
+// default for development, where links can be copied from the output.
+type LogMailer struct{}
+
+func NewLogMailer() *LogMailer {
+	return &LogMailer{}
+}
+
+func (m *LogMailer) Send(_ context.Context, message Message) error {
+	logger.Info("Mail to " + message.To + ": " + message.Subject + "\n" + message.Body)
+	return nil
+}
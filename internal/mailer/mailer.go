@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	BackendLog  = "log"
+	BackendSMTP = "smtp"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers plain-text messages. Implementations must be safe for
+// concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Compose builds a message just before it is sent.
+type Compose func(ctx context.Context) (Message, error)
+
+// SendLater sends the message compose builds. A Queue composes it on its
+// worker, so whatever compose does, such as issuing the token a link
+// carries, does not slow down the request either. Other mailers compose and
+// send at once.
+func SendLater(ctx context.Context, mailer Mailer, compose Compose) error {
+	if queue, ok := mailer.(*Queue); ok {
+		return queue.enqueue(compose)
+	}
+
+	message, err := compose(ctx)
+	if err != nil {
+		return err
+	}
+	return mailer.Send(ctx, message)
+}
+
+type Config struct {
+	Backend string
+	SMTP    SMTPConfig
+}
+
+func New(config Config) (Mailer, error) {
+	switch config.Backend {
+	case BackendLog, "":
+		return NewLogMailer(), nil
+	case BackendSMTP:
+		return NewSMTPMailer(config.SMTP), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer backend %q", config.Backend)
+	}
+}
@@ -0,0 +1,75 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+)
+
+const DefaultQueueSize = 100
+
+var ErrQueueFull = errors.New("mail queue is full")
+
+// Queue hands messages to a background worker, so a request never waits on
+// the mail server. Without it, how long an endpoint takes would tell whether
+// it sent mail, for example whether a password reset email has an account.
+type Queue struct {
+	mailer   Mailer
+	messages chan Compose
+	done     chan struct{}
+	close    sync.Once
+}
+
+func NewQueue(mailer Mailer, size int) *Queue {
+	return &Queue{
+		mailer:   mailer,
+		messages: make(chan Compose, size),
+		done:     make(chan struct{}),
+	}
+}
+
+// Send queues the message and returns at once. It must not be called after
+// Close.
+func (q *Queue) Send(_ context.Context, message Message) error {
+	return q.enqueue(func(context.Context) (Message, error) {
+		return message, nil
+	})
+}
+
+func (q *Queue) enqueue(compose Compose) error {
+	select {
+	case q.messages <- compose:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Run delivers queued messages until Close is called and the queue is empty.
+func (q *Queue) Run() {
+	defer close(q.done)
+
+	for compose := range q.messages {
+		ctx := context.Background()
+
+		message, err := compose(ctx)
+		if err != nil {
+			log.Println("Error composing mail: ", err)
+			continue
+		}
+
+		if err := q.mailer.Send(ctx, message); err != nil {
+			log.Println("Error sending mail: ", err)
+		}
+	}
+}
+
+// Close stops accepting messages and waits for Run to deliver the ones
+// already queued.
+func (q *Queue) Close() {
+	q.close.Do(func() {
+		close(q.messages)
+	})
+	<-q.done
+}
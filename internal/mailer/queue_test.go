@@ -0,0 +1,81 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestQueueDeliversBeforeClose(t *testing.T) {
+	memory := NewMemoryMailer()
+	queue := NewQueue(memory, 10)
+	go queue.Run()
+
+	for _, to := range []string{"a@example.com", "b@example.com"} {
+		if err := queue.Send(context.Background(), Message{To: to}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+	queue.Close()
+
+	messages := memory.Messages()
+	if len(messages) != 2 || messages[0].To != "a@example.com" || messages[1].To != "b@example.com" {
+		t.Errorf("messages = %v", messages)
+	}
+}
+
+func TestSendLaterComposesOnWorker(t *testing.T) {
+	memory := NewMemoryMailer()
+	queue := NewQueue(memory, 10)
+
+	composed := false
+	err := SendLater(context.Background(), queue, func(context.Context) (Message, error) {
+		composed = true
+		return Message{To: "a@example.com"}, nil
+	})
+	if err != nil {
+		t.Fatalf("SendLater: %v", err)
+	}
+	err = SendLater(context.Background(), queue, func(context.Context) (Message, error) {
+		return Message{}, errors.New("no token")
+	})
+	if err != nil {
+		t.Fatalf("SendLater: %v", err)
+	}
+	if composed {
+		t.Fatal("expected the message to be composed by Run")
+	}
+
+	go queue.Run()
+	queue.Close()
+
+	messages := memory.Messages()
+	if len(messages) != 1 || messages[0].To != "a@example.com" {
+		t.Errorf("messages = %v", messages)
+	}
+}
+
+func TestSendLaterWithoutQueue(t *testing.T) {
+	memory := NewMemoryMailer()
+
+	err := SendLater(context.Background(), memory, func(context.Context) (Message, error) {
+		return Message{To: "a@example.com"}, nil
+	})
+	if err != nil {
+		t.Fatalf("SendLater: %v", err)
+	}
+	if len(memory.Messages()) != 1 {
+		t.Errorf("expected the message to be sent at once")
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	queue := NewQueue(NewMemoryMailer(), 1)
+
+	if err := queue.Send(context.Background(), Message{}); err != nil {
+		t.Fatalf("first Send: %v", err)
+	}
+	if err := queue.Send(context.Background(), Message{}); err != ErrQueueFull {
+		t.Errorf("second Send err = %v, want ErrQueueFull", err)
+	}
+}
@@ -0,0 +1,147 @@
+package mailer
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// fakeSMTPServer accepts a single session, MailHog style: no TLS, no auth,
+// every recipient accepted. It records the envelope and data.
+type fakeSMTPServer struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     string
+	done     chan struct{}
+}
+
+func startFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	server := &fakeSMTPServer{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 fake")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.to = append(s.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPMailerSend(t *testing.T) {
+	server := startFakeSMTPServer(t)
+
+	mailer := NewSMTPMailer(SMTPConfig{
+		Host: "127.0.0.1",
+		Port: server.port(),
+		From: "noreply@lexia.test",
+	})
+
+	err := mailer.Send(context.Background(), Message{
+		To:      "user@example.com",
+		Subject: "Reset your password",
+		Body:    "Line one\nLine two",
+	})
+	if err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	<-server.done
+
+	if server.from != "noreply@lexia.test" {
+		t.Errorf("MAIL FROM = %q", server.from)
+	}
+	if len(server.to) != 1 || server.to[0] != "user@example.com" {
+		t.Errorf("RCPT TO = %v", server.to)
+	}
+	if !strings.Contains(server.data, "Subject: Reset your password\r\n") {
+		t.Errorf("data is missing subject header: %q", server.data)
+	}
+	if !strings.Contains(server.data, "Line one\r\nLine two") {
+		t.Errorf("data is missing body: %q", server.data)
+	}
+}
+
+func TestSMTPMailerSendConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mailer := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: port, From: "noreply@lexia.test"})
+
+	if err := mailer.Send(context.Background(), Message{To: "user@example.com"}); err == nil {
+		t.Fatal("expected an error when the server is unreachable")
+	}
+}
+
+func TestNewUnknownBackend(t *testing.T) {
+	if _, err := New(Config{Backend: "carrier-pigeon"}); err == nil {
+		t.Fatal("expected an error for an unknown backend")
+	}
+}
@@ -0,0 +1,30 @@
+package accesstoken
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type createAccessTokenDTO struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays *int     `json:"expiresInDays" binding:"omitempty,min=1,max=365"`
+}
+
+type AccessTokenDTO struct {
+	ID          uuid.UUID  `json:"id"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"tokenPrefix"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expiresAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+}
+
+// CreatedAccessTokenDTO is returned once on creation and is the only time
+// the plaintext token is ever shown.
+type CreatedAccessTokenDTO struct {
+	AccessTokenDTO
+	Token string `json:"token"`
+}
@@ -0,0 +1,9 @@
+package accesstoken
+
+import "lexia/internal/apperr"
+
+var ErrAccessTokenNotFound = apperr.NotFound("ACCESS_TOKEN_NOT_FOUND", "Personal access token not found")
+
+func errUnknownScope(scope string) error {
+	return apperr.InvalidInput("UNKNOWN_SCOPE", "Unknown scope: "+scope)
+}
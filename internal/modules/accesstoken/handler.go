@@ -0,0 +1,90 @@
+package accesstoken
+
+import (
+	"lexia/internal/shared"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func handleGetAccessTokens(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		tokens, err := GetUserAccessTokens(c.Request.Context(), apiCfg.DB, authPayload.UserID)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, AccessTokenEntitiesToDtos(tokens))
+	}
+}
+
+func handleCreateAccessToken(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body createAccessTokenDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		args := CreateAccessTokenArgs{
+			UserID: authPayload.UserID,
+			Name:   body.Name,
+			Scopes: body.Scopes,
+		}
+		if body.ExpiresInDays != nil {
+			args.ExpiresIn = time.Duration(*body.ExpiresInDays) * 24 * time.Hour
+		}
+
+		tokenEntity, token, err := CreateAccessToken(c.Request.Context(), apiCfg.DB, args)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResCreated(c, CreatedAccessTokenDTO{
+			AccessTokenDTO: AccessTokenEntityToDto(tokenEntity),
+			Token:          token,
+		})
+	}
+}
+
+func handleDeleteAccessToken(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		tokenID, err := uuid.Parse(c.Param("tokenId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid token ID")
+			return
+		}
+
+		err = DeleteAccessToken(c.Request.Context(), apiCfg.DB, DeleteAccessTokenArgs{
+			TokenID: tokenID,
+			UserID:  authPayload.UserID,
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResNoContent(c)
+	}
+}
@@ -0,0 +1,18 @@
+package accesstoken
+
+import (
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Router registers token management. The routes declare no scopes, so a
+// personal access token can never be used to mint or revoke tokens.
+func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
+	tokenGroup := rg.Group("/user/tokens")
+	{
+		tokenGroup.GET("", handleGetAccessTokens(apiCfg))
+		tokenGroup.POST("", handleCreateAccessToken(apiCfg))
+		tokenGroup.DELETE("/:tokenId", handleDeleteAccessToken(apiCfg))
+	}
+}
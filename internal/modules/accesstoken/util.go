@@ -0,0 +1,23 @@
+package accesstoken
+
+import "lexia/ent"
+
+func AccessTokenEntityToDto(tokenEntity *ent.PersonalAccessToken) AccessTokenDTO {
+	return AccessTokenDTO{
+		ID:          tokenEntity.ID,
+		CreatedAt:   tokenEntity.CreateTime,
+		Name:        tokenEntity.Name,
+		TokenPrefix: tokenEntity.TokenPrefix,
+		Scopes:      tokenEntity.Scopes,
+		ExpiresAt:   tokenEntity.ExpiresAt,
+		LastUsedAt:  tokenEntity.LastUsedAt,
+	}
+}
+
+func AccessTokenEntitiesToDtos(tokenEntities []*ent.PersonalAccessToken) []AccessTokenDTO {
+	dtos := make([]AccessTokenDTO, len(tokenEntities))
+	for i, tokenEntity := range tokenEntities {
+		dtos[i] = AccessTokenEntityToDto(tokenEntity)
+	}
+	return dtos
+}
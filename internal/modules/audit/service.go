@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+type RecordArgs struct {
+	Event     schema.AuditEvent
+	UserID    *uuid.UUID
+	Email     string
+	IP        string
+	UserAgent string
+	Details   map[string]any
+}
+
+// Record writes an audit log entry. The audit log must never break the
+// action it describes, so failures are only logged.
+func Record(ctx context.Context, db *ent.Client, args RecordArgs) {
+	err := db.AuditLog.Create().
+		SetEvent(args.Event).
+		SetNillableUserId(args.UserID).
+		SetEmail(args.Email).
+		SetIP(args.IP).
+		SetUserAgent(args.UserAgent).
+		SetDetails(args.Details).
+		Exec(ctx)
+	if err != nil {
+		log.Println("Error recording audit log: ", err)
+	}
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/internal/modules/session"
+	"lexia/internal/modules/signinthrottle"
+	"lexia/internal/modules/user"
+	"lexia/internal/shared"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// confirmPassword checks the password of a signed-in user before an account
+// change. Wrong passwords count towards the sign-in throttle, so a stolen
+// access token cannot be used to guess the password. Passwordless accounts
+// have to set a password through the reset flow first.
+func confirmPassword(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	authUser *ent.User,
+	password string,
+	client session.ClientInfo,
+) error {
+	throttleKeys := signinthrottle.GetKeys(authUser.Email, client.IP)
+	if err := signinthrottle.Attempt(apiCfg, ctx, throttleKeys, authUser.Email, client); err != nil {
+		return err
+	}
+
+	match, _, err := apiCfg.PasswordHasher.Verify(password, authUser.Password)
+	if err != nil {
+		log.Println("Error verifying password: ", err)
+		return err
+	}
+
+	if !match {
+		signinthrottle.RecordFailure(apiCfg, ctx, throttleKeys, authUser.Email, &authUser.ID, client)
+		return ErrInvalidPassword
+	}
+
+	passSignInThrottle(apiCfg, ctx, throttleKeys, authUser)
+
+	return nil
+}
+
+func getAuthUser(apiCfg *shared.ApiConfig, ctx context.Context, userID uuid.UUID) (*ent.User, error) {
+	authUser, err := user.GetUserByID(ctx, apiCfg.DB, userID)
+	if err != nil {
+		if shared.IsDatabaseErorNotFound(err) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return authUser, nil
+}
+
+type ChangePasswordArgs struct {
+	UserID          uuid.UUID
+	SessionID       uuid.UUID
+	CurrentPassword string
+	NewPassword     string
+	Client          session.ClientInfo
+}
+
+// ChangePassword replaces the password after checking the current one and
+// signs out every other session.
+func ChangePassword(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args ChangePasswordArgs,
+) error {
+	authUser, err := getAuthUser(apiCfg, ctx, args.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := confirmPassword(apiCfg, ctx, authUser, args.CurrentPassword, args.Client); err != nil {
+		return err
+	}
+
+	passwordHash, err := apiCfg.PasswordHasher.Hash(args.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return shared.WithTx(ctx, apiCfg.DB, func(client *ent.Client) error {
+		if err := user.UpdateUserPassword(ctx, client, authUser.ID, passwordHash); err != nil {
+			return err
+		}
+
+		return session.RevokeUserSessions(ctx, client, session.RevokeUserSessionsArgs{
+			UserID:          authUser.ID,
+			ExceptSessionID: args.SessionID,
+		})
+	})
+}
+
+type ChangeEmailArgs struct {
+	UserID   uuid.UUID
+	Email    string
+	Password string
+	Client   session.ClientInfo
+}
+
+// ChangeEmail moves the account to a new address, which stays unverified
+// until the link mailed to it is followed. The old address is told about the
+// change.
+func ChangeEmail(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args ChangeEmailArgs,
+) (*user.UserDto, error) {
+	authUser, err := getAuthUser(apiCfg, ctx, args.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := confirmPassword(apiCfg, ctx, authUser, args.Password, args.Client); err != nil {
+		return nil, err
+	}
+
+	userExistsByEmail, err := user.UserExistsByEmail(ctx, apiCfg.DB, args.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if userExistsByEmail {
+		return nil, ErrEmailAlreadyExists
+	}
+
+	updatedUser, err := user.UpdateUserEmail(ctx, apiCfg.DB, authUser.ID, args.Email)
+	if err != nil {
+		if ent.IsConstraintError(err) {
+			return nil, ErrEmailAlreadyExists
+		}
+		return nil, err
+	}
+
+	startEmailVerification(apiCfg, ctx, updatedUser)
+	sendEmailChangedMail(ctx, apiCfg, authUser, updatedUser.Email)
+
+	userDto := user.UserEntityToDto(updatedUser)
+	return &userDto, nil
+}
+
+type DeleteAccountArgs struct {
+	UserID   uuid.UUID
+	Password string
+	Client   session.ClientInfo
+}
+
+// DeleteAccount signs the user out everywhere and schedules the account for
+// purging once the grace period has passed. Signing in again before then
+// cancels the deletion.
+func DeleteAccount(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args DeleteAccountArgs,
+) (*accountDeletionDTO, error) {
+	authUser, err := getAuthUser(apiCfg, ctx, args.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := confirmPassword(apiCfg, ctx, authUser, args.Password, args.Client); err != nil {
+		return nil, err
+	}
+
+	deletedAt := time.Now()
+	err = shared.WithTx(ctx, apiCfg.DB, func(client *ent.Client) error {
+		if err := user.MarkUserDeleted(ctx, client, authUser.ID, deletedAt); err != nil {
+			return err
+		}
+
+		return session.RevokeUserSessions(ctx, client, session.RevokeUserSessionsArgs{
+			UserID:          authUser.ID,
+			ExceptSessionID: uuid.Nil,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	purgeAt := deletedAt.Add(user.AccountDeletionGracePeriod(apiCfg.Env))
+	sendAccountDeletionMail(ctx, apiCfg, authUser, purgeAt)
+
+	return &accountDeletionDTO{PurgeAt: purgeAt}, nil
+}
@@ -1,19 +1,78 @@
 package auth
 
-import "lexia/internal/modules/user"
+import (
+	"lexia/internal/modules/user"
+	"time"
+)
 
 type tokenPayloadDTO struct {
-	AccessToken string       `json:"accessToken"`
-	User        user.UserDto `json:"user"`
+	AccessToken  string       `json:"accessToken"`
+	RefreshToken string       `json:"refreshToken"`
+	User         user.UserDto `json:"user"`
+}
+
+type mfaChallengeDTO struct {
+	Status         string `json:"status"`
+	ChallengeToken string `json:"challengeToken"`
+	ExpiresIn      int64  `json:"expiresIn"`
 }
 
 type emailSignInDTO struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=8,max=128"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required,min=8,max=128"`
+	DeviceName string `json:"deviceName" binding:"max=100"`
 }
 
 type EmailSignUpDTO struct {
-	Username string `json:"username" validate:"username" binding:"required,min=2,max=50,alphanum"`
-	Email    string `json:"email" binding:"required,email"`
+	Username   string `json:"username" validate:"username" binding:"required,min=2,max=50,alphanum"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" validate:"strong_password" binding:"required,min=8,max=128"`
+	DeviceName string `json:"deviceName" binding:"max=100"`
+}
+
+type refreshTokenDTO struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type emailDTO struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type resetPasswordDTO struct {
+	Token    string `json:"token" binding:"required"`
 	Password string `json:"password" validate:"strong_password" binding:"required,min=8,max=128"`
 }
+
+type verifyEmailDTO struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type oidcSignInDTO struct {
+	IDToken    string `json:"idToken" binding:"required"`
+	Nonce      string `json:"nonce" binding:"max=256"`
+	DeviceName string `json:"deviceName" binding:"max=100"`
+}
+
+type mfaSignInDTO struct {
+	ChallengeToken string `json:"challengeToken" binding:"required"`
+	Code           string `json:"code" binding:"required,max=32"`
+	DeviceName     string `json:"deviceName" binding:"max=100"`
+}
+
+type changePasswordDTO struct {
+	CurrentPassword string `json:"currentPassword" binding:"required,max=128"`
+	NewPassword     string `json:"newPassword" validate:"strong_password" binding:"required,min=8,max=128"`
+}
+
+type changeEmailDTO struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,max=128"`
+}
+
+type deleteAccountDTO struct {
+	Password string `json:"password" binding:"required,max=128"`
+}
+
+type accountDeletionDTO struct {
+	PurgeAt time.Time `json:"purgeAt"`
+}
@@ -2,65 +2,106 @@ package auth
 
 import (
 	"context"
+	"lexia/ent"
+	"lexia/internal/modules/session"
+	"lexia/internal/modules/signinthrottle"
 	"lexia/internal/modules/user"
 	"lexia/internal/shared"
+	"log"
 )
 
 type SignInWithEmailArgs struct {
 	Email    string
 	Password string
+	Client   session.ClientInfo
 }
 
+// SignInWithEmail checks the password and signs the user in, or returns a
+// two-factor challenge if the account has it enabled. Repeated failures for
+// the same account or from the same IP are throttled.
 func SignInWithEmail(
 	apiCfg *shared.ApiConfig,
 	ctx context.Context,
 	args SignInWithEmailArgs,
-) (*tokenPayloadDTO, *shared.HttpError) {
+) (*signInResult, error) {
+	throttleKeys := signinthrottle.GetKeys(args.Email, args.Client.IP)
+	if err := signinthrottle.Attempt(apiCfg, ctx, throttleKeys, args.Email, args.Client); err != nil {
+		return nil, err
+	}
+
 	authUser, err := user.GetUserByEmail(ctx, apiCfg.DB, args.Email)
 
 	if err != nil {
 		if shared.IsDatabaseErorNotFound(err) {
-			return nil, shared.Unauthorized(shared.ErrInvalidEmailOrPassword)
+			apiCfg.PasswordHasher.VerifyNothing(args.Password)
+			signinthrottle.RecordFailure(apiCfg, ctx, throttleKeys, args.Email, nil, args.Client)
+			return nil, ErrInvalidEmailOrPassword
 		}
 
-		return nil, shared.InternalServerErrorDef()
+		return nil, err
+	}
+
+	match, needsRehash, err := apiCfg.PasswordHasher.Verify(args.Password, authUser.Password)
+	if err != nil {
+		log.Println("Error verifying password: ", err)
+		return nil, err
+	}
+
+	if !match {
+		signinthrottle.RecordFailure(apiCfg, ctx, throttleKeys, args.Email, &authUser.ID, args.Client)
+		return nil, ErrInvalidEmailOrPassword
 	}
 
-	if !ComparePasswordHash(args.Password, authUser.Password) {
-		return nil, shared.Unauthorized(shared.ErrInvalidEmailOrPassword)
+	passSignInThrottle(apiCfg, ctx, throttleKeys, authUser)
+
+	if needsRehash {
+		rehashPassword(apiCfg, ctx, authUser, args.Password)
 	}
 
-	tokenPayload, err := getTokenPayloadDtoFromUserEntity(authUser)
+	return completeSignIn(apiCfg, ctx, authUser, args.Client)
+}
+
+// rehashPassword upgrades a hash made with an older algorithm or weaker
+// parameters while the plaintext is at hand. Failing only means trying again
+// on the next sign-in.
+func rehashPassword(apiCfg *shared.ApiConfig, ctx context.Context, authUser *ent.User, password string) {
+	passwordHash, err := apiCfg.PasswordHasher.Hash(password)
 	if err != nil {
-		return nil, shared.InternalServerErrorDef()
+		log.Println("Error rehashing password: ", err)
+		return
 	}
 
-	return tokenPayload, nil
+	if err := user.UpdateUserPassword(ctx, apiCfg.DB, authUser.ID, passwordHash); err != nil {
+		return
+	}
+
+	authUser.Password = passwordHash
 }
 
 type SignUpWithEmailArgs struct {
 	Username string
 	Email    string
 	Password string
+	Client   session.ClientInfo
 }
 
 func SignUpWithEmail(
 	apiCfg *shared.ApiConfig,
 	ctx context.Context,
 	args SignUpWithEmailArgs,
-) (*tokenPayloadDTO, *shared.HttpError) {
+) (*tokenPayloadDTO, error) {
 	userExistsByEmail, err := user.UserExistsByEmail(ctx, apiCfg.DB, args.Email)
 	if err != nil {
-		return nil, shared.InternalServerErrorDef()
+		return nil, err
 	}
 
 	if userExistsByEmail {
-		return nil, shared.BadRequest(shared.ErrEmailAlreadyExists)
+		return nil, ErrEmailAlreadyExists
 	}
 
-	passwordHash, err := HashPassword(args.Password)
+	passwordHash, err := apiCfg.PasswordHasher.Hash(args.Password)
 	if err != nil {
-		return nil, shared.InternalServerErrorDef()
+		return nil, err
 	}
 
 	newUser, err := user.CreateUser(ctx, apiCfg.DB, user.CreateUserArgs{
@@ -69,13 +110,31 @@ func SignUpWithEmail(
 		Password: passwordHash,
 	})
 	if err != nil {
-		return nil, shared.InternalServerErrorDef()
+		return nil, err
 	}
 
-	tokenPayload, err := getTokenPayloadDtoFromUserEntity(newUser)
+	startEmailVerification(apiCfg, ctx, newUser)
+
+	return startSession(apiCfg, ctx, newUser, args.Client)
+}
+
+type RefreshSessionArgs struct {
+	RefreshToken string
+	Client       session.ClientInfo
+}
+
+func RefreshSession(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args RefreshSessionArgs,
+) (*tokenPayloadDTO, error) {
+	sessionEntity, refreshToken, err := session.RotateSession(ctx, apiCfg.DB, session.RotateSessionArgs{
+		RefreshToken: args.RefreshToken,
+		Client:       args.Client,
+	})
 	if err != nil {
-		return nil, shared.InternalServerErrorDef()
+		return nil, err
 	}
 
-	return tokenPayload, nil
+	return getTokenPayloadDto(apiCfg.Tokens, sessionEntity.Edges.User, sessionEntity, refreshToken)
 }
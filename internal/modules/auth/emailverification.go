@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/internal/modules/user"
+	"lexia/internal/shared"
+)
+
+// startEmailVerification issues a verification token and mails it. Failures
+// are logged by the helpers and never block the caller.
+func startEmailVerification(apiCfg *shared.ApiConfig, ctx context.Context, userEntity *ent.User) {
+	token, err := issueOneTimeToken(ctx, apiCfg.DB, userEntity.ID, schema.OneTimeTokenPurposeEmailVerification, emailVerificationTokenTTL)
+	if err != nil {
+		return
+	}
+
+	sendVerificationMail(ctx, apiCfg, userEntity, token)
+}
+
+type ResendEmailVerificationArgs struct {
+	Email string
+}
+
+// ResendEmailVerification sends a new verification link to an unverified
+// account. Like password reset it succeeds for unknown emails.
+func ResendEmailVerification(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args ResendEmailVerificationArgs,
+) error {
+	authUser, err := user.GetUserByEmail(ctx, apiCfg.DB, args.Email)
+	if err != nil {
+		if shared.IsDatabaseErorNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !authUser.EmailVerified {
+		startEmailVerification(apiCfg, ctx, authUser)
+	}
+
+	return nil
+}
+
+type VerifyEmailArgs struct {
+	Token string
+}
+
+func VerifyEmail(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args VerifyEmailArgs,
+) error {
+	return shared.WithTx(ctx, apiCfg.DB, func(client *ent.Client) error {
+		authUser, err := consumeOneTimeToken(ctx, client, args.Token, schema.OneTimeTokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		return user.MarkUserEmailVerified(ctx, client, authUser.ID)
+	})
+}
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"lexia/internal/apperr"
+	"lexia/internal/shared"
+)
+
+var (
+	ErrInvalidEmailOrPassword = apperr.Unauthorized(shared.ErrInvalidEmailOrPassword, "Invalid email or password")
+	ErrInvalidPassword        = apperr.Forbidden(shared.ErrInvalidPassword, "Password is incorrect")
+	ErrUserNotFound           = apperr.NotFound(shared.ErrUserNotFound, "User not found")
+	ErrEmailAlreadyExists     = apperr.InvalidInput(shared.ErrEmailAlreadyExists, "Email is already in use")
+	ErrInvalidOrExpiredToken  = apperr.InvalidInput(shared.ErrInvalidOrExpiredToken, "Link is invalid or has expired")
+	ErrInvalidMfaChallenge    = apperr.Unauthorized(shared.ErrInvalidOrExpiredToken, "Two-factor challenge is invalid or has expired")
+	ErrIDTokenWithoutEmail    = apperr.InvalidInput(shared.ErrInvalidIDToken, "ID token has no email")
+	// Signing in through a provider with the email of an existing account
+	// conflicts with that account unless both sides have verified it.
+	ErrEmailBelongsToAccount   = apperr.Conflict(shared.ErrEmailAlreadyExists, "An account with this email already exists")
+	ErrAccountEmailNotVerified = apperr.Conflict(shared.ErrEmailNotVerified, "Verify the email of the existing account first")
+)
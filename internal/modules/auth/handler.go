@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"lexia/internal/modules/session"
 	"lexia/internal/shared"
 
 	"github.com/gin-gonic/gin"
@@ -20,16 +21,17 @@ func handleEmailSignIn(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		tokenPayload, httpErr := SignInWithEmail(apiCfg, c.Request.Context(), SignInWithEmailArgs{
+		result, err := SignInWithEmail(apiCfg, c.Request.Context(), SignInWithEmailArgs{
 			Email:    body.Email,
 			Password: body.Password,
+			Client:   getClientInfo(c, body.DeviceName),
 		})
-		if httpErr != nil {
-			shared.ResHttpError(c, httpErr)
+		if err != nil {
+			shared.ResError(c, err)
 			return
 		}
 
-		shared.ResOK(c, tokenPayload)
+		resSignIn(c, result)
 	}
 }
 
@@ -41,16 +43,277 @@ func handleEmailSignUp(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		tokenPayload, httpErr := SignUpWithEmail(apiCfg, c.Request.Context(), SignUpWithEmailArgs{
+		tokenPayload, err := SignUpWithEmail(apiCfg, c.Request.Context(), SignUpWithEmailArgs{
 			Username: body.Username,
 			Email:    body.Email,
 			Password: body.Password,
+			Client:   getClientInfo(c, body.DeviceName),
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, tokenPayload)
+	}
+}
+
+func handleRefresh(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body refreshTokenDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		tokenPayload, err := RefreshSession(apiCfg, c.Request.Context(), RefreshSessionArgs{
+			RefreshToken: body.RefreshToken,
+			Client:       getClientInfo(c, ""),
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, tokenPayload)
+	}
+}
+
+func handleLogout(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body refreshTokenDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		if err := session.RevokeSessionByRefreshToken(c.Request.Context(), apiCfg.DB, body.RefreshToken); err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResNoContent(c)
+	}
+}
+
+func handleForgotPassword(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body emailDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		err := RequestPasswordReset(apiCfg, c.Request.Context(), RequestPasswordResetArgs{
+			Email: body.Email,
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResAccepted(c, shared.OkDTO{Ok: true})
+	}
+}
+
+func handleResetPassword(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body resetPasswordDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		err := ResetPassword(apiCfg, c.Request.Context(), ResetPasswordArgs{
+			Token:    body.Token,
+			Password: body.Password,
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResNoContent(c)
+	}
+}
+
+func handleVerifyEmail(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body verifyEmailDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		err := VerifyEmail(apiCfg, c.Request.Context(), VerifyEmailArgs{
+			Token: body.Token,
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResNoContent(c)
+	}
+}
+
+func handleResendEmailVerification(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body emailDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		err := ResendEmailVerification(apiCfg, c.Request.Context(), ResendEmailVerificationArgs{
+			Email: body.Email,
 		})
-		if httpErr != nil {
-			shared.ResHttpError(c, httpErr)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResAccepted(c, shared.OkDTO{Ok: true})
+	}
+}
+
+func handleOIDCSignIn(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body oidcSignInDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		result, err := SignInWithOIDC(apiCfg, c.Request.Context(), SignInWithOIDCArgs{
+			Provider: c.Param("provider"),
+			IDToken:  body.IDToken,
+			Nonce:    body.Nonce,
+			Client:   getClientInfo(c, body.DeviceName),
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		resSignIn(c, result)
+	}
+}
+
+func handleMfaSignIn(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body mfaSignInDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		tokenPayload, err := CompleteMfaSignIn(apiCfg, c.Request.Context(), CompleteMfaSignInArgs{
+			ChallengeToken: body.ChallengeToken,
+			Code:           body.Code,
+			Client:         getClientInfo(c, body.DeviceName),
+		})
+		if err != nil {
+			shared.ResError(c, err)
 			return
 		}
 
 		shared.ResOK(c, tokenPayload)
 	}
 }
+
+func handleChangePassword(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body changePasswordDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		err = ChangePassword(apiCfg, c.Request.Context(), ChangePasswordArgs{
+			UserID:          authPayload.UserID,
+			SessionID:       authPayload.SessionID,
+			CurrentPassword: body.CurrentPassword,
+			NewPassword:     body.NewPassword,
+			Client:          getClientInfo(c, ""),
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResNoContent(c)
+	}
+}
+
+func handleChangeEmail(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body changeEmailDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		userDto, err := ChangeEmail(apiCfg, c.Request.Context(), ChangeEmailArgs{
+			UserID:   authPayload.UserID,
+			Email:    body.Email,
+			Password: body.Password,
+			Client:   getClientInfo(c, ""),
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, userDto)
+	}
+}
+
+func handleDeleteAccount(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body deleteAccountDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		deletion, err := DeleteAccount(apiCfg, c.Request.Context(), DeleteAccountArgs{
+			UserID:   authPayload.UserID,
+			Password: body.Password,
+			Client:   getClientInfo(c, ""),
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResAccepted(c, deletion)
+	}
+}
+
+func resSignIn(c *gin.Context, result *signInResult) {
+	if result.challenge != nil {
+		shared.ResOK(c, result.challenge)
+		return
+	}
+
+	shared.ResOK(c, result.tokens)
+}
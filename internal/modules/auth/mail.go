@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/internal/mailer"
+	"lexia/internal/shared"
+	"log"
+	"net/url"
+	"time"
+)
+
+// sendPasswordResetMail issues the reset token when the mail is composed,
+// which the mail queue does in the background. Only known emails get a
+// token, so issuing it in the request would make them answer slower.
+func sendPasswordResetMail(ctx context.Context, apiCfg *shared.ApiConfig, userEntity *ent.User) {
+	err := mailer.SendLater(ctx, apiCfg.Mailer, func(ctx context.Context) (mailer.Message, error) {
+		token, err := issueOneTimeToken(ctx, apiCfg.DB, userEntity.ID, schema.OneTimeTokenPurposePasswordReset, passwordResetTokenTTL)
+		if err != nil {
+			return mailer.Message{}, err
+		}
+
+		return mailer.Message{
+			To:      userEntity.Email,
+			Subject: "Reset your Lexia password",
+			Body: fmt.Sprintf(
+				"Hi %s,\n\nOpen the link below to choose a new password. It expires in one hour.\n\n%s\n\nIf you did not ask for a password reset, you can ignore this email.\n",
+				userEntity.Username,
+				appLink(apiCfg, "/reset-password", token),
+			),
+		}, nil
+	})
+	if err != nil {
+		log.Println("Error sending mail: ", err)
+	}
+}
+
+func sendVerificationMail(ctx context.Context, apiCfg *shared.ApiConfig, userEntity *ent.User, token string) {
+	link := appLink(apiCfg, "/verify-email", token)
+
+	sendMail(ctx, apiCfg, mailer.Message{
+		To:      userEntity.Email,
+		Subject: "Verify your Lexia email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nOpen the link below to verify your email address. It expires in 24 hours.\n\n%s\n",
+			userEntity.Username,
+			link,
+		),
+	})
+}
+
+func sendEmailChangedMail(ctx context.Context, apiCfg *shared.ApiConfig, userEntity *ent.User, newEmail string) {
+	sendMail(ctx, apiCfg, mailer.Message{
+		To:      userEntity.Email,
+		Subject: "Your Lexia email address was changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe email address of your Lexia account was changed to %s.\n\nIf you did not make this change, reset your password and contact support.\n",
+			userEntity.Username,
+			newEmail,
+		),
+	})
+}
+
+func sendAccountDeletionMail(ctx context.Context, apiCfg *shared.ApiConfig, userEntity *ent.User, purgeAt time.Time) {
+	sendMail(ctx, apiCfg, mailer.Message{
+		To:      userEntity.Email,
+		Subject: "Your Lexia account will be deleted",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nYour Lexia account and all of its folders and words will be deleted on %s.\n\nTo keep your account, sign in again before then.\n",
+			userEntity.Username,
+			purgeAt.UTC().Format("2 January 2006"),
+		),
+	})
+}
+
+// sendMail logs delivery failures instead of returning them: a mail server
+// outage should not fail sign-up or reveal anything to the caller.
+func sendMail(ctx context.Context, apiCfg *shared.ApiConfig, message mailer.Message) {
+	if err := apiCfg.Mailer.Send(ctx, message); err != nil {
+		log.Println("Error sending mail: ", err)
+	}
+}
+
+func appLink(apiCfg *shared.ApiConfig, path string, token string) string {
+	return apiCfg.Env.AppUrl + path + "?token=" + url.QueryEscape(token)
+}
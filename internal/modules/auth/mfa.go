@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"lexia/ent"
+	"lexia/ent/onetimetoken"
+	"lexia/ent/schema"
+	"lexia/internal/modules/session"
+	"lexia/internal/modules/signinthrottle"
+	"lexia/internal/modules/twofactor"
+	"lexia/internal/shared"
+	"log"
+	"time"
+)
+
+const (
+	mfaChallengeTTL         = 5 * time.Minute
+	mfaChallengeMaxAttempts = 5
+	mfaRequiredStatus       = "mfa_required"
+)
+
+// signInResult holds either the session tokens or, for users with
+// two-factor enabled, the challenge to complete with a second factor.
+type signInResult struct {
+	tokens    *tokenPayloadDTO
+	challenge *mfaChallengeDTO
+}
+
+// completeSignIn finishes a sign-in once the first factor has been checked.
+func completeSignIn(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	userEntity *ent.User,
+	client session.ClientInfo,
+) (*signInResult, error) {
+	if !twofactor.IsEnabled(userEntity) {
+		tokenPayload, err := startSession(apiCfg, ctx, userEntity, client)
+		if err != nil {
+			return nil, err
+		}
+		return &signInResult{tokens: tokenPayload}, nil
+	}
+
+	challengeToken, err := issueOneTimeToken(ctx, apiCfg.DB, userEntity.ID, schema.OneTimeTokenPurposeMfaChallenge, mfaChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &signInResult{
+		challenge: &mfaChallengeDTO{
+			Status:         mfaRequiredStatus,
+			ChallengeToken: challengeToken,
+			ExpiresIn:      int64(mfaChallengeTTL.Seconds()),
+		},
+	}, nil
+}
+
+type CompleteMfaSignInArgs struct {
+	ChallengeToken string
+	Code           string
+	Client         session.ClientInfo
+}
+
+// passSignInThrottle clears the sign-in throttle after a correct password.
+// With two-factor enabled the password alone proves nothing, so only this
+// attempt is taken back and the account's failures stay until a second
+// factor succeeds.
+func passSignInThrottle(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	keys signinthrottle.Keys,
+	userEntity *ent.User,
+) {
+	if twofactor.IsEnabled(userEntity) {
+		signinthrottle.Forgive(apiCfg, ctx, keys)
+		return
+	}
+	signinthrottle.Reset(apiCfg, ctx, keys)
+}
+
+// CompleteMfaSignIn exchanges a challenge token and a TOTP or recovery code
+// for a session. A challenge allows a few wrong codes before it is spent and
+// the user has to sign in with their password again. Wrong codes also count
+// towards the sign-in throttle, so signing in again does not give fresh
+// attempts.
+func CompleteMfaSignIn(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args CompleteMfaSignInArgs,
+) (*tokenPayloadDTO, error) {
+	tokenHash := shared.HashOpaqueToken(args.ChallengeToken)
+	challenge, err := apiCfg.DB.OneTimeToken.Query().
+		Where(
+			onetimetoken.TokenHash(tokenHash),
+			onetimetoken.PurposeEQ(schema.OneTimeTokenPurposeMfaChallenge),
+			onetimetoken.UsedAtIsNil(),
+			onetimetoken.ExpiresAtGT(time.Now()),
+		).
+		WithUser().
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrInvalidMfaChallenge
+		}
+		log.Println("Error finding MFA challenge: ", err)
+		return nil, err
+	}
+
+	userEntity := challenge.Edges.User
+	throttleKeys := signinthrottle.GetKeys(userEntity.Email, args.Client.IP)
+	if err := signinthrottle.Attempt(apiCfg, ctx, throttleKeys, userEntity.Email, args.Client); err != nil {
+		return nil, err
+	}
+
+	err = twofactor.VerifySecondFactor(ctx, apiCfg.DB, userEntity, args.Code)
+	if err != nil {
+		// Failures of ours should not spend the user's attempts.
+		if errors.Is(err, twofactor.ErrInvalidCode) {
+			recordFailedMfaAttempt(ctx, apiCfg.DB, challenge)
+			signinthrottle.RecordFailure(apiCfg, ctx, throttleKeys, userEntity.Email, &userEntity.ID, args.Client)
+		}
+		return nil, err
+	}
+
+	consumed, err := apiCfg.DB.OneTimeToken.Update().
+		Where(
+			onetimetoken.ID(challenge.ID),
+			onetimetoken.UsedAtIsNil(),
+		).
+		SetUsedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error consuming MFA challenge: ", err)
+		return nil, err
+	}
+
+	if consumed == 0 {
+		return nil, ErrInvalidMfaChallenge
+	}
+
+	signinthrottle.Reset(apiCfg, ctx, throttleKeys)
+
+	return startSession(apiCfg, ctx, userEntity, args.Client)
+}
+
+func recordFailedMfaAttempt(ctx context.Context, db *ent.Client, challenge *ent.OneTimeToken) {
+	updated, err := db.OneTimeToken.UpdateOneID(challenge.ID).
+		AddAttempts(1).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error recording failed MFA attempt: ", err)
+		return
+	}
+
+	if updated.Attempts < mfaChallengeMaxAttempts {
+		return
+	}
+
+	err = db.OneTimeToken.UpdateOneID(challenge.ID).
+		SetUsedAt(time.Now()).
+		Exec(ctx)
+	if err != nil {
+		log.Println("Error spending MFA challenge: ", err)
+	}
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/internal/modules/identity"
+	"lexia/internal/modules/session"
+	"lexia/internal/modules/user"
+	"lexia/internal/shared"
+	"strings"
+	"unicode"
+)
+
+type SignInWithOIDCArgs struct {
+	Provider string
+	IDToken  string
+	Nonce    string
+	Client   session.ClientInfo
+}
+
+// SignInWithOIDC signs in with an ID token from an identity provider. A
+// known identity signs its user in. Otherwise an account with the same email
+// is linked when both the provider and the account have verified the email,
+// and a new account is created when there is none. Linking is refused when
+// either side is unverified: an unverified provider email would let anyone
+// who registers the address there take the account over, and an unverified
+// account may have been signed up by someone else with a password they still
+// know. Accounts with two-factor enabled get a challenge, as with email
+// sign-in.
+func SignInWithOIDC(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args SignInWithOIDCArgs,
+) (*signInResult, error) {
+	claims, err := identity.VerifyIDToken(ctx, apiCfg.Env.OidcProviders, args.Provider, args.IDToken, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	var authUser *ent.User
+	err = shared.WithTx(ctx, apiCfg.DB, func(client *ent.Client) error {
+		var err error
+		authUser, err = findOrCreateOIDCUser(ctx, client, args.Provider, claims)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return completeSignIn(apiCfg, ctx, authUser, args.Client)
+}
+
+func findOrCreateOIDCUser(
+	ctx context.Context,
+	db *ent.Client,
+	provider string,
+	claims *identity.IDTokenClaims,
+) (*ent.User, error) {
+	authUser, err := identity.FindIdentityUser(ctx, db, provider, claims.Subject)
+	if err != nil || authUser != nil {
+		return authUser, err
+	}
+
+	if claims.Email == "" {
+		return nil, ErrIDTokenWithoutEmail
+	}
+
+	authUser, err = user.GetUserByEmail(ctx, db, claims.Email)
+	if err != nil && !shared.IsDatabaseErorNotFound(err) {
+		return nil, err
+	}
+
+	if authUser != nil {
+		if !claims.EmailVerified {
+			return nil, ErrEmailBelongsToAccount
+		}
+
+		// The owner of the address verifies it, or resets the password,
+		// before the account can be signed into through a provider.
+		if !authUser.EmailVerified {
+			return nil, ErrAccountEmailNotVerified
+		}
+	} else {
+		authUser, err = user.CreateUser(ctx, db, user.CreateUserArgs{
+			Username:      usernameFromClaims(claims),
+			Email:         claims.Email,
+			EmailVerified: claims.EmailVerified,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = identity.CreateIdentity(ctx, db, identity.CreateIdentityArgs{
+		UserID:   authUser.ID,
+		Provider: provider,
+		Claims:   claims,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return authUser, nil
+}
+
+// usernameFromClaims derives a username that passes the sign-up rules
+// (alphanumeric, 2-50 characters) from the name or email in the token.
+func usernameFromClaims(claims *identity.IDTokenClaims) string {
+	candidates := []string{claims.Name, strings.Split(claims.Email, "@")[0]}
+
+	for _, candidate := range candidates {
+		username := strings.Map(func(r rune) rune {
+			if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				return r
+			}
+			return -1
+		}, candidate)
+
+		if len(username) > 50 {
+			username = username[:50]
+		}
+
+		if len(username) >= 2 {
+			return username
+		}
+	}
+
+	return "user"
+}
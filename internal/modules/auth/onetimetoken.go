@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/onetimetoken"
+	"lexia/ent/schema"
+	"lexia/ent/user"
+	"lexia/internal/shared"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	passwordResetTokenTTL     = time.Hour
+	emailVerificationTokenTTL = 24 * time.Hour
+)
+
+// issueOneTimeToken creates a token for the user and returns its plaintext
+// value. Earlier unused tokens with the same purpose are spent, so only the
+// most recent link in the user's inbox works.
+func issueOneTimeToken(
+	ctx context.Context,
+	db *ent.Client,
+	userID uuid.UUID,
+	purpose schema.OneTimeTokenPurpose,
+	ttl time.Duration,
+) (string, error) {
+	token, err := shared.GenerateOpaqueToken()
+	if err != nil {
+		log.Println("Error generating one-time token: ", err)
+		return "", err
+	}
+
+	now := time.Now()
+	err = shared.WithTx(ctx, db, func(client *ent.Client) error {
+		_, err := client.OneTimeToken.Update().
+			Where(
+				onetimetoken.HasUserWith(user.ID(userID)),
+				onetimetoken.PurposeEQ(purpose),
+				onetimetoken.UsedAtIsNil(),
+			).
+			SetUsedAt(now).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+
+		return client.OneTimeToken.Create().
+			SetUserID(userID).
+			SetPurpose(purpose).
+			SetTokenHash(shared.HashOpaqueToken(token)).
+			SetExpiresAt(now.Add(ttl)).
+			Exec(ctx)
+	})
+	if err != nil {
+		log.Println("Error issuing one-time token: ", err)
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeOneTimeToken marks a token as used and returns its owner. The
+// update is conditional on the token still being unused, so two concurrent
+// requests with the same token cannot both succeed.
+func consumeOneTimeToken(
+	ctx context.Context,
+	db *ent.Client,
+	token string,
+	purpose schema.OneTimeTokenPurpose,
+) (*ent.User, error) {
+	tokenHash := shared.HashOpaqueToken(token)
+	now := time.Now()
+
+	consumed, err := db.OneTimeToken.Update().
+		Where(
+			onetimetoken.TokenHash(tokenHash),
+			onetimetoken.PurposeEQ(purpose),
+			onetimetoken.UsedAtIsNil(),
+			onetimetoken.ExpiresAtGT(now),
+		).
+		SetUsedAt(now).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error consuming one-time token: ", err)
+		return nil, err
+	}
+
+	if consumed == 0 {
+		return nil, ErrInvalidOrExpiredToken
+	}
+
+	userEntity, err := db.OneTimeToken.Query().
+		Where(onetimetoken.TokenHash(tokenHash)).
+		QueryUser().
+		Only(ctx)
+	if err != nil {
+		log.Println("Error finding one-time token user: ", err)
+		return nil, err
+	}
+
+	return userEntity, nil
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/internal/modules/session"
+	"lexia/internal/modules/user"
+	"lexia/internal/shared"
+
+	"github.com/google/uuid"
+)
+
+type RequestPasswordResetArgs struct {
+	Email string
+}
+
+// RequestPasswordReset mails a reset link if the email belongs to a user.
+// Unknown emails succeed silently so the endpoint cannot be used to find out
+// who has an account. The server mails through a queue, and the reset token
+// is issued when the queue composes the mail, so known emails do not answer
+// slower either.
+func RequestPasswordReset(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args RequestPasswordResetArgs,
+) error {
+	authUser, err := user.GetUserByEmail(ctx, apiCfg.DB, args.Email)
+	if err != nil {
+		if shared.IsDatabaseErorNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	sendPasswordResetMail(ctx, apiCfg, authUser)
+
+	return nil
+}
+
+type ResetPasswordArgs struct {
+	Token    string
+	Password string
+}
+
+// ResetPassword sets a new password using a reset token and signs the user
+// out everywhere. Following the link also proves the user owns the email
+// address, so it is marked verified.
+func ResetPassword(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	args ResetPasswordArgs,
+) error {
+	passwordHash, err := apiCfg.PasswordHasher.Hash(args.Password)
+	if err != nil {
+		return err
+	}
+
+	return shared.WithTx(ctx, apiCfg.DB, func(client *ent.Client) error {
+		authUser, err := consumeOneTimeToken(ctx, client, args.Token, schema.OneTimeTokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		if err := user.UpdateUserPassword(ctx, client, authUser.ID, passwordHash); err != nil {
+			return err
+		}
+
+		if err := user.MarkUserEmailVerified(ctx, client, authUser.ID); err != nil {
+			return err
+		}
+
+		return session.RevokeUserSessions(ctx, client, session.RevokeUserSessionsArgs{
+			UserID:          authUser.ID,
+			ExceptSessionID: uuid.Nil,
+		})
+	})
+}
@@ -11,6 +11,25 @@ func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
 	{
 		authGroup.GET("/status", handleGetAuthStatus())
 		authGroup.POST("/signin", handleEmailSignIn(apiCfg))
+		authGroup.POST("/signin/mfa", handleMfaSignIn(apiCfg))
 		authGroup.POST("/signup", handleEmailSignUp(apiCfg))
+		authGroup.POST("/refresh", handleRefresh(apiCfg))
+		authGroup.POST("/logout", handleLogout(apiCfg))
+		authGroup.POST("/oidc/:provider", handleOIDCSignIn(apiCfg))
+		authGroup.POST("/password/forgot", handleForgotPassword(apiCfg))
+		authGroup.POST("/password/reset", handleResetPassword(apiCfg))
+		authGroup.POST("/email/verify", handleVerifyEmail(apiCfg))
+		authGroup.POST("/email/verify/resend", handleResendEmailVerification(apiCfg))
+	}
+}
+
+// AccountRouter serves the signed-in user's credential and account changes.
+// It is mounted behind AuthMW.
+func AccountRouter(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
+	accountGroup := rg.Group("/user/auth")
+	{
+		accountGroup.PUT("/password", handleChangePassword(apiCfg))
+		accountGroup.PUT("/email", handleChangeEmail(apiCfg))
+		accountGroup.DELETE("", handleDeleteAccount(apiCfg))
 	}
 }
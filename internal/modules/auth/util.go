@@ -1,28 +1,55 @@
 package auth
 
 import (
+	"context"
 	"lexia/ent"
+	"lexia/internal/modules/session"
 	"lexia/internal/modules/user"
 	"lexia/internal/shared"
+	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/gin-gonic/gin"
 )
 
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	return string(bytes), err
-}
+// startSession opens a new session for the user and returns its tokens.
+// Signing in to an account that is scheduled for deletion cancels the
+// deletion.
+func startSession(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	userEntity *ent.User,
+	client session.ClientInfo,
+) (*tokenPayloadDTO, error) {
+	if userEntity.DeletedAt != nil {
+		if err := user.RestoreUser(ctx, apiCfg.DB, userEntity.ID); err != nil {
+			return nil, err
+		}
+		userEntity.DeletedAt = nil
+	}
 
-func ComparePasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	sessionEntity, refreshToken, err := session.CreateSession(ctx, apiCfg.DB, session.CreateSessionArgs{
+		UserID: userEntity.ID,
+		Client: client,
+		TTL:    time.Duration(apiCfg.Env.RefreshTokenExpSeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return getTokenPayloadDto(apiCfg.Tokens, userEntity, sessionEntity, refreshToken)
 }
 
-func getTokenPayloadDtoFromUserEntity(userEntity *ent.User) (*tokenPayloadDTO, error) {
-	accessToken, err := shared.GenerateAccessToken(
+func getTokenPayloadDto(
+	tokens shared.TokenService,
+	userEntity *ent.User,
+	sessionEntity *ent.Session,
+	refreshToken string,
+) (*tokenPayloadDTO, error) {
+	accessToken, err := tokens.GenerateAccessToken(
 		&shared.TokenClaims{
-			UserID: userEntity.ID,
-			Email:  userEntity.Email,
+			UserID:    userEntity.ID,
+			Email:     userEntity.Email,
+			SessionID: sessionEntity.ID,
 		},
 	)
 
@@ -31,7 +58,16 @@ func getTokenPayloadDtoFromUserEntity(userEntity *ent.User) (*tokenPayloadDTO, e
 	}
 
 	return &tokenPayloadDTO{
-		AccessToken: accessToken,
-		User:        user.UserEntityToDto(userEntity),
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		User:         user.UserEntityToDto(userEntity),
 	}, nil
 }
+
+func getClientInfo(c *gin.Context, deviceName string) session.ClientInfo {
+	return session.ClientInfo{
+		DeviceName: deviceName,
+		IP:         c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
+	}
+}
@@ -1,11 +1,17 @@
 package modules
 
 import (
-	"lexia/internal/logger"
+	"fmt"
+	"lexia/internal/modules/accesstoken"
 	"lexia/internal/modules/auth"
 	"lexia/internal/modules/folder"
+	"lexia/internal/modules/identity"
+	"lexia/internal/modules/review"
+	"lexia/internal/modules/session"
 	"lexia/internal/modules/translate"
+	"lexia/internal/modules/twofactor"
 	"lexia/internal/modules/user"
+	"lexia/internal/modules/wellknown"
 	"lexia/internal/modules/word"
 	"lexia/internal/shared"
 
@@ -23,7 +29,8 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID, Retry-After")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -35,37 +42,60 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
-func CreateWebserver(apiCfg *shared.ApiConfig) (*gin.Engine, error) {
-	envVars, err := shared.ParseEnv()
-	if err != nil {
-		logger.Fatal("Failed to parse environment variables: ", err)
-		return nil, err
-	}
-
+func CreateWebserver(apiCfg *shared.ApiConfig, envVars *shared.EnvVariables) (*gin.Engine, error) {
 	if envVars.IsProduction {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	r := gin.Default()
 
+	// Client IPs drive sign-in throttling, so X-Forwarded-For is only
+	// believed when it comes from a configured proxy.
+	if err := r.SetTrustedProxies(envVars.TrustedProxies); err != nil {
+		return nil, err
+	}
+
 	r.Use(CORSMiddleware())
+	r.Use(shared.RequestIDMW())
 	r.Use(gin.Logger())
-	r.Use(gin.Recovery())
+	// ErrorMW wraps recovery so a panic still gets the error envelope.
+	r.Use(shared.ErrorMW())
+	r.Use(gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		shared.ResError(c, fmt.Errorf("panic: %v", recovered))
+	}))
+
+	r.NoRoute(func(c *gin.Context) {
+		shared.ResNotFound(c, shared.ErrNotFound)
+	})
 
 	r.GET("/", HealthcheckHandler)
 	r.GET("/health", HealthcheckHandler)
 
+	wellknown.Router(apiCfg, &r.RouterGroup)
+
 	v1 := r.Group("/api/v1")
 	{
 		auth.Router(apiCfg, v1)
 
 		protected := v1.Group("/")
-		protected.Use(shared.AuthMW())
+		protected.Use(shared.AuthMW(apiCfg.DB, apiCfg.Tokens))
 		{
 			user.Router(apiCfg, protected)
+			auth.AccountRouter(apiCfg, protected)
+			session.Router(apiCfg, protected)
+			identity.Router(apiCfg, protected)
+			twofactor.Router(apiCfg, protected)
+			accesstoken.Router(apiCfg, protected)
 			folder.Router(apiCfg, protected)
 			word.Router(apiCfg, protected)
 			translate.Router(apiCfg, protected)
+			review.Router(apiCfg, protected)
+
+			admin := protected.Group("/admin")
+			admin.Use(shared.AdminMW(apiCfg.DB))
+			{
+				translate.AdminRouter(apiCfg, admin)
+			}
 		}
 	}
 
@@ -3,6 +3,10 @@ package folder
 import (
 	"lexia/ent"
 	"lexia/ent/schema"
+	"lexia/internal/modules/languages"
+	"lexia/internal/pagination"
+	"lexia/internal/service"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -20,6 +24,17 @@ type UpdateFolderDTO struct {
 	ParentID *uuid.UUID `json:"parentId,omitempty"`
 }
 
+type ShareFolderDTO struct {
+	Email string                 `json:"email" binding:"required,email"`
+	Role  schema.FolderShareRole `json:"role" binding:"required,oneof=VIEWER EDITOR"`
+}
+
+type ListFoldersQueryDTO struct {
+	pagination.QueryDTO
+	CreatedAfter  *time.Time `form:"createdAfter"`
+	CreatedBefore *time.Time `form:"createdBefore"`
+}
+
 type FolderDTO struct {
 	ID           uuid.UUID         `json:"id"`
 	Name         string            `json:"name"`
@@ -34,6 +49,31 @@ type FolderDTO struct {
 	HasWords     bool              `json:"hasWords"`
 }
 
+type FolderTreeNodeDTO struct {
+	ID             uuid.UUID           `json:"id"`
+	Name           string              `json:"name"`
+	Type           schema.FolderType   `json:"type"`
+	LanguageFrom   *schema.Language    `json:"languageFrom,omitempty"`
+	LanguageTo     *schema.Language    `json:"languageTo,omitempty"`
+	ParentID       *uuid.UUID          `json:"parentId,omitempty"`
+	Depth          int                 `json:"depth"`
+	WordCount      int                 `json:"wordCount"`
+	TotalWordCount int                 `json:"totalWordCount"`
+	CreatedAt      string              `json:"createdAt"`
+	UpdatedAt      string              `json:"updatedAt"`
+	Children       []FolderTreeNodeDTO `json:"children"`
+}
+
+type FolderShareDTO struct {
+	UserID    uuid.UUID              `json:"userId"`
+	Username  string                 `json:"username"`
+	Email     string                 `json:"email"`
+	Role      schema.FolderShareRole `json:"role"`
+	CreatedAt string                 `json:"createdAt"`
+}
+
+type FolderPathItemDTO = service.FolderPathItem
+
 func FolderEntityToDto(folder *ent.Folder) FolderDTO {
 	dto := FolderDTO{
 		ID:        folder.ID,
@@ -42,16 +82,11 @@ func FolderEntityToDto(folder *ent.Folder) FolderDTO {
 		WordCount: folder.WordCount,
 		CreatedAt: folder.CreateTime.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt: folder.UpdateTime.Format("2006-01-02T15:04:05Z"),
-		HasWords:  len(folder.Edges.Words) > 0,
-	}
-
-	if folder.LanguageFrom != nil {
-		dto.LanguageFrom = folder.LanguageFrom
+		HasWords:  folder.WordCount > 0,
 	}
 
-	if folder.LanguageTo != nil {
-		dto.LanguageTo = folder.LanguageTo
-	}
+	dto.LanguageFrom = publicLanguageCode(folder.LanguageFrom)
+	dto.LanguageTo = publicLanguageCode(folder.LanguageTo)
 
 	if len(folder.Edges.Parent) > 0 {
 		dto.ParentID = &folder.Edges.Parent[0].ID
@@ -66,3 +101,52 @@ func FolderEntityToDto(folder *ent.Folder) FolderDTO {
 
 	return dto
 }
+
+func FolderTreeNodeToDto(node *FolderTreeNode) FolderTreeNodeDTO {
+	dto := FolderTreeNodeDTO{
+		ID:             node.ID,
+		Name:           node.Name,
+		Type:           node.Type,
+		LanguageFrom:   publicLanguageCode(node.LanguageFrom),
+		LanguageTo:     publicLanguageCode(node.LanguageTo),
+		ParentID:       node.ParentID,
+		Depth:          node.Depth,
+		WordCount:      node.WordCount,
+		TotalWordCount: node.TotalWordCount,
+		CreatedAt:      node.CreateTime.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      node.UpdateTime.Format("2006-01-02T15:04:05Z"),
+		Children:       make([]FolderTreeNodeDTO, len(node.Children)),
+	}
+
+	for i, child := range node.Children {
+		dto.Children[i] = FolderTreeNodeToDto(child)
+	}
+
+	return dto
+}
+
+func FolderShareEntityToDto(share *ent.FolderShare) FolderShareDTO {
+	dto := FolderShareDTO{
+		Role:      share.Role,
+		CreatedAt: share.CreateTime.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if share.Edges.User != nil {
+		dto.UserID = share.Edges.User.ID
+		dto.Username = share.Edges.User.Username
+		dto.Email = share.Edges.User.Email
+	}
+
+	return dto
+}
+
+// publicLanguageCode reports stored catalogue tags the way clients sent them
+// before the catalogue existed, so legacy enum values round-trip unchanged.
+func publicLanguageCode(code *schema.Language) *schema.Language {
+	if code == nil {
+		return nil
+	}
+
+	publicCode := languages.PublicCode(*code)
+	return &publicCode
+}
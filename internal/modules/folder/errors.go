@@ -0,0 +1,20 @@
+package folder
+
+import (
+	"lexia/internal/apperr"
+	"lexia/internal/shared"
+)
+
+var (
+	ErrLanguageFromRequired = apperr.InvalidInput("LANGUAGE_FROM_REQUIRED", "languageFrom is required for word_collection folders")
+	ErrLanguagesNotAllowed  = apperr.InvalidInput("LANGUAGES_NOT_ALLOWED", "languageFrom and languageTo should not be provided for folder collection folders")
+	ErrNotFolderCollection  = apperr.InvalidState("NOT_FOLDER_COLLECTION", "Subfolders can only be added to folder collection folders")
+	ErrNotWordCollection    = apperr.InvalidState("NOT_WORD_COLLECTION", "Words can only be added to word collection folders")
+	ErrFolderHasSubfolders  = apperr.InvalidState("FOLDER_HAS_SUBFOLDERS", "Cannot delete folder that contains subfolders")
+	ErrFolderHasWords       = apperr.InvalidState("FOLDER_HAS_WORDS", "Cannot delete folder that contains words")
+	ErrCircularReference    = apperr.InvalidState("CIRCULAR_REFERENCE", "Cannot move a folder into itself or one of its subfolders")
+	ErrParentOwnedByOther   = apperr.InvalidState("PARENT_OWNED_BY_OTHER_USER", "Parent folder belongs to another user")
+	ErrShareWithOwner       = apperr.InvalidInput("SHARE_WITH_OWNER", "Folder cannot be shared with its owner")
+	ErrShareUserNotFound    = apperr.NotFound(shared.ErrUserNotFound, "No user with this email")
+	ErrShareNotFound        = apperr.NotFound("SHARE_NOT_FOUND", "Share not found")
+)
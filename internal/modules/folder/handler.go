@@ -1,7 +1,7 @@
 package folder
 
 import (
-	"lexia/ent/schema"
+	"lexia/internal/pagination"
 	"lexia/internal/shared"
 
 	"github.com/gin-gonic/gin"
@@ -22,17 +22,8 @@ func handleCreateFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		if body.Type == schema.FolderTypeWordCollection && body.LanguageFrom == nil {
-			shared.ResBadRequest(c, "languageFrom is required for word_collection folders")
-			return
-		}
-		if body.Type == schema.FolderTypeFolderCollection && (body.LanguageFrom != nil || body.LanguageTo != nil) {
-			shared.ResBadRequest(c, "languageFrom and languageTo should not be provided for folder collection folders")
-			return
-		}
-
-		folder, err := CreateFolder(
-			c.Request.Context(), apiCfg.DB,
+		folder, err := apiCfg.Folders.CreateFolder(
+			c.Request.Context(),
 			CreateFolderArgs{
 				UserID:       authPayload.UserID,
 				Name:         body.Name,
@@ -42,9 +33,8 @@ func handleCreateFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 				ParentID:     body.ParentID,
 			},
 		)
-
 		if err != nil {
-			shared.ResBadRequest(c, err.Error())
+			shared.ResError(c, err)
 			return
 		}
 
@@ -67,9 +57,9 @@ func handleGetFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		folder, err := GetFolderByID(c.Request.Context(), apiCfg.DB, folderID, authPayload.UserID)
+		folder, err := apiCfg.Folders.GetFolderByID(c.Request.Context(), folderID, authPayload.UserID)
 		if err != nil {
-			shared.ResNotFound(c, "Folder not found")
+			shared.ResError(c, err)
 			return
 		}
 
@@ -85,18 +75,24 @@ func handleGetUserFolders(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		folders, err := GetUserFolders(c.Request.Context(), apiCfg.DB, authPayload.UserID)
-		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+		var query ListFoldersQueryDTO
+		if validationErr := shared.BindQueryAndValidate(c, &query); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
 			return
 		}
 
-		folderDTOs := make([]FolderDTO, len(folders))
-		for i, folder := range folders {
-			folderDTOs[i] = FolderEntityToDto(folder)
+		folders, err := apiCfg.Folders.GetUserFolders(c.Request.Context(), ListFoldersArgs{
+			UserID:        authPayload.UserID,
+			Page:          query.Params(),
+			CreatedAfter:  query.CreatedAfter,
+			CreatedBefore: query.CreatedBefore,
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
 		}
 
-		shared.ResOK(c, folderDTOs)
+		shared.ResOK(c, pagination.PageToDTO(folders, FolderEntityToDto))
 	}
 }
 
@@ -108,18 +104,47 @@ func handleGetRootFolders(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		folders, err := GetRootFolders(c.Request.Context(), apiCfg.DB, authPayload.UserID)
+		var query ListFoldersQueryDTO
+		if validationErr := shared.BindQueryAndValidate(c, &query); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		folders, err := apiCfg.Folders.GetRootFolders(c.Request.Context(), ListFoldersArgs{
+			UserID:        authPayload.UserID,
+			Page:          query.Params(),
+			CreatedAfter:  query.CreatedAfter,
+			CreatedBefore: query.CreatedBefore,
+		})
 		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
-		folderDTOs := make([]FolderDTO, len(folders))
-		for i, folder := range folders {
-			folderDTOs[i] = FolderEntityToDto(folder)
+		shared.ResOK(c, pagination.PageToDTO(folders, FolderEntityToDto))
+	}
+}
+
+func handleGetFolderTree(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
 		}
 
-		shared.ResOK(c, folderDTOs)
+		roots, err := apiCfg.Folders.GetFolderTree(c.Request.Context(), authPayload.UserID)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		treeDTOs := make([]FolderTreeNodeDTO, len(roots))
+		for i, root := range roots {
+			treeDTOs[i] = FolderTreeNodeToDto(root)
+		}
+
+		shared.ResOK(c, treeDTOs)
 	}
 }
 
@@ -138,9 +163,9 @@ func handleGetSubfoldersByFolderID(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		folders, err := GetFoldersByParentID(c.Request.Context(), apiCfg.DB, parentFolderID, authPayload.UserID)
+		folders, err := apiCfg.Folders.GetFoldersByParentID(c.Request.Context(), parentFolderID, authPayload.UserID)
 		if err != nil {
-			shared.ResNotFound(c, "Parent folder not found or access denied")
+			shared.ResError(c, err)
 			return
 		}
 
@@ -174,8 +199,8 @@ func handleUpdateFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		folder, err := UpdateFolder(
-			c.Request.Context(), apiCfg.DB,
+		folder, err := apiCfg.Folders.UpdateFolder(
+			c.Request.Context(),
 			UpdateFolderArgs{
 				FolderID: folderID,
 				UserID:   authPayload.UserID,
@@ -185,7 +210,7 @@ func handleUpdateFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 		)
 
 		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
@@ -208,13 +233,9 @@ func handleDeleteFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		err = DeleteFolder(c.Request.Context(), apiCfg.DB, folderID, authPayload.UserID)
+		err = apiCfg.Folders.DeleteFolder(c.Request.Context(), folderID, authPayload.UserID)
 		if err != nil {
-			if httpErr, ok := err.(*shared.HttpError); ok {
-				shared.ResHttpError(c, httpErr)
-				return
-			}
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
@@ -247,12 +268,139 @@ func handleMoveFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		folder, err := MoveFolder(c.Request.Context(), apiCfg.DB, folderID, body.ParentID, authPayload.UserID)
+		folder, err := apiCfg.Folders.MoveFolder(c.Request.Context(), folderID, body.ParentID, authPayload.UserID)
 		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
 		shared.ResOK(c, FolderEntityToDto(folder))
 	}
 }
+
+func handleGetSharedFolders(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		folders, err := apiCfg.Folders.GetSharedFolders(c.Request.Context(), authPayload.UserID)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		folderDTOs := make([]FolderDTO, len(folders))
+		for i, folder := range folders {
+			folderDTOs[i] = FolderEntityToDto(folder)
+		}
+
+		shared.ResOK(c, folderDTOs)
+	}
+}
+
+func handleGetFolderShares(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		folderID, err := uuid.Parse(c.Param("folderId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid folder ID")
+			return
+		}
+
+		shares, err := apiCfg.Folders.GetFolderShares(c.Request.Context(), folderID, authPayload.UserID)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shareDTOs := make([]FolderShareDTO, len(shares))
+		for i, share := range shares {
+			shareDTOs[i] = FolderShareEntityToDto(share)
+		}
+
+		shared.ResOK(c, shareDTOs)
+	}
+}
+
+func handleShareFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		folderID, err := uuid.Parse(c.Param("folderId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid folder ID")
+			return
+		}
+
+		var body ShareFolderDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		share, err := apiCfg.Folders.ShareFolder(
+			c.Request.Context(),
+			ShareFolderArgs{
+				FolderID: folderID,
+				UserID:   authPayload.UserID,
+				Email:    body.Email,
+				Role:     body.Role,
+			},
+		)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, FolderShareEntityToDto(share))
+	}
+}
+
+func handleUnshareFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		folderID, err := uuid.Parse(c.Param("folderId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid folder ID")
+			return
+		}
+
+		sharedUserID, err := uuid.Parse(c.Param("userId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid user ID")
+			return
+		}
+
+		err = apiCfg.Folders.UnshareFolder(
+			c.Request.Context(),
+			UnshareFolderArgs{
+				FolderID:     folderID,
+				UserID:       authPayload.UserID,
+				SharedUserID: sharedUserID,
+			},
+		)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResNoContent(c)
+	}
+}
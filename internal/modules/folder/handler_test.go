@@ -0,0 +1,205 @@
+package folder
+
+import (
+	"context"
+	"encoding/json"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/internal/authz"
+	"lexia/internal/pagination"
+	"lexia/internal/service"
+	"lexia/internal/shared"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fakeFolderService keeps folders in memory. Methods the tests do not use
+// are left to the embedded nil interface and panic if called.
+type fakeFolderService struct {
+	service.FolderService
+	folders  map[uuid.UUID]*ent.Folder
+	owners   map[uuid.UUID]uuid.UUID
+	listArgs ListFoldersArgs
+}
+
+func newFakeFolderService() *fakeFolderService {
+	return &fakeFolderService{
+		folders: map[uuid.UUID]*ent.Folder{},
+		owners:  map[uuid.UUID]uuid.UUID{},
+	}
+}
+
+func (s *fakeFolderService) CreateFolder(_ context.Context, args CreateFolderArgs) (*ent.Folder, error) {
+	if args.Type == schema.FolderTypeWordCollection && args.LanguageFrom == nil {
+		return nil, ErrLanguageFromRequired
+	}
+
+	folderEntity := &ent.Folder{
+		ID:           uuid.New(),
+		Name:         args.Name,
+		Type:         args.Type,
+		LanguageFrom: args.LanguageFrom,
+		LanguageTo:   args.LanguageTo,
+	}
+	s.folders[folderEntity.ID] = folderEntity
+	s.owners[folderEntity.ID] = args.UserID
+
+	return folderEntity, nil
+}
+
+func (s *fakeFolderService) GetFolderByID(_ context.Context, folderID uuid.UUID, userID uuid.UUID) (*ent.Folder, error) {
+	folderEntity, ok := s.folders[folderID]
+	if !ok {
+		return nil, authz.ErrFolderNotFound
+	}
+	if s.owners[folderID] != userID {
+		return nil, authz.ErrAccessDenied
+	}
+
+	return folderEntity, nil
+}
+
+func (s *fakeFolderService) GetUserFolders(_ context.Context, args ListFoldersArgs) (*pagination.Page[*ent.Folder], error) {
+	s.listArgs = args
+
+	page := &pagination.Page[*ent.Folder]{NextCursor: "next"}
+	for folderID, folderEntity := range s.folders {
+		if s.owners[folderID] == args.UserID {
+			page.Items = append(page.Items, folderEntity)
+		}
+	}
+	return page, nil
+}
+
+func newTestRouter(folders service.FolderService, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(shared.ErrorMW())
+	r.Use(func(c *gin.Context) {
+		shared.SetAuthPayload(c, &shared.TokenClaims{UserID: userID, SessionID: uuid.New()})
+	})
+
+	Router(&shared.ApiConfig{Services: &shared.Services{Folders: folders}}, &r.RouterGroup)
+
+	return r
+}
+
+func serve(r *gin.Engine, method string, path string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandleCreateAndGetFolder(t *testing.T) {
+	userID := uuid.New()
+	r := newTestRouter(newFakeFolderService(), userID)
+
+	rec := serve(r, http.MethodPost, "/folders", `{"name": "Collection", "type": "FOLDER_COLLECTION"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create status = %d, body %s", rec.Code, rec.Body)
+	}
+
+	var created FolderDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "Collection" || created.Type != schema.FolderTypeFolderCollection {
+		t.Errorf("created = %+v", created)
+	}
+
+	rec = serve(r, http.MethodGet, "/folders/"+created.ID.String(), "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get status = %d, body %s", rec.Code, rec.Body)
+	}
+}
+
+func TestHandleFolderErrors(t *testing.T) {
+	folders := newFakeFolderService()
+	otherFolder, _ := folders.CreateFolder(context.Background(), CreateFolderArgs{
+		UserID: uuid.New(),
+		Name:   "Other",
+		Type:   schema.FolderTypeFolderCollection,
+	})
+
+	r := newTestRouter(folders, uuid.New())
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"missing folder", http.MethodGet, "/folders/" + uuid.NewString(), "", http.StatusNotFound, "FOLDER_NOT_FOUND"},
+		{"other user's folder", http.MethodGet, "/folders/" + otherFolder.ID.String(), "", http.StatusForbidden, shared.ErrForbidden},
+		{"invalid ID", http.MethodGet, "/folders/not-a-uuid", "", http.StatusBadRequest, shared.ErrInvalidRequest},
+		{"missing language", http.MethodPost, "/folders", `{"name": "Words", "type": "WORD_COLLECTION"}`, http.StatusBadRequest, "LANGUAGE_FROM_REQUIRED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(r, tt.method, tt.path, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d, body %s", rec.Code, tt.status, rec.Body)
+			}
+
+			var response shared.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Code != tt.code {
+				t.Errorf("code = %q, want %q", response.Code, tt.code)
+			}
+		})
+	}
+}
+
+func TestHandleListFolders(t *testing.T) {
+	userID := uuid.New()
+	folders := newFakeFolderService()
+	folders.CreateFolder(context.Background(), CreateFolderArgs{
+		UserID: userID,
+		Name:   "Collection",
+		Type:   schema.FolderTypeFolderCollection,
+	})
+
+	r := newTestRouter(folders, userID)
+
+	rec := serve(r, http.MethodGet, "/folders?limit=10&cursor=abc&sort=name&order=desc&createdAfter=2026-10-17T12:00:00Z", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body)
+	}
+
+	var page pagination.PageDTO[FolderDTO]
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.NextCursor == nil || *page.NextCursor != "next" {
+		t.Errorf("page = %+v", page)
+	}
+
+	want := pagination.Params{Limit: 10, Cursor: "abc", Sort: "name", Order: pagination.Desc}
+	if folders.listArgs.UserID != userID || folders.listArgs.Page != want {
+		t.Errorf("args = %+v, want page %+v", folders.listArgs, want)
+	}
+	if folders.listArgs.CreatedAfter == nil || !folders.listArgs.CreatedAfter.Equal(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("createdAfter = %v", folders.listArgs.CreatedAfter)
+	}
+
+	rec = serve(r, http.MethodGet, "/folders?limit=many", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid limit status = %d, want 400", rec.Code)
+	}
+}
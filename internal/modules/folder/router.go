@@ -8,17 +8,24 @@ import (
 
 func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
 	folderGroup := rg.Group("/folders")
+	read := shared.RequireScope(shared.ScopeFoldersRead)
+	write := shared.RequireScope(shared.ScopeFoldersWrite)
 	{
-		folderGroup.GET("", handleGetUserFolders(apiCfg))
-		folderGroup.GET("/root", handleGetRootFolders(apiCfg))
-		folderGroup.GET("/:folderId", handleGetFolder(apiCfg))
-		folderGroup.GET("/:folderId/subfolders", handleGetSubfoldersByFolderID(apiCfg))
+		folderGroup.GET("", read, handleGetUserFolders(apiCfg))
+		folderGroup.GET("/root", read, handleGetRootFolders(apiCfg))
+		folderGroup.GET("/tree", read, handleGetFolderTree(apiCfg))
+		folderGroup.GET("/shared", read, handleGetSharedFolders(apiCfg))
+		folderGroup.GET("/:folderId", read, handleGetFolder(apiCfg))
+		folderGroup.GET("/:folderId/subfolders", read, handleGetSubfoldersByFolderID(apiCfg))
+		folderGroup.GET("/:folderId/shares", read, handleGetFolderShares(apiCfg))
 
-		folderGroup.POST("", handleCreateFolder(apiCfg))
+		folderGroup.POST("", write, handleCreateFolder(apiCfg))
 
-		folderGroup.PUT("/:folderId", handleUpdateFolder(apiCfg))
-		folderGroup.PUT("/:folderId/move", handleMoveFolder(apiCfg))
+		folderGroup.PUT("/:folderId", write, handleUpdateFolder(apiCfg))
+		folderGroup.PUT("/:folderId/move", write, handleMoveFolder(apiCfg))
+		folderGroup.PUT("/:folderId/shares", write, handleShareFolder(apiCfg))
 
-		folderGroup.DELETE("/:folderId", handleDeleteFolder(apiCfg))
+		folderGroup.DELETE("/:folderId", write, handleDeleteFolder(apiCfg))
+		folderGroup.DELETE("/:folderId/shares/:userId", write, handleUnshareFolder(apiCfg))
 	}
 }
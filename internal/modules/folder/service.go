@@ -2,70 +2,82 @@ package folder
 
 import (
 	"context"
-	"fmt"
 	"lexia/ent"
 	"lexia/ent/folder"
+	"lexia/ent/predicate"
 	"lexia/ent/schema"
 	"lexia/ent/user"
+	"lexia/internal/authz"
+	"lexia/internal/modules/languages"
+	"lexia/internal/pagination"
+	"lexia/internal/service"
 	"lexia/internal/shared"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-type CreateFolderArgs struct {
-	UserID       uuid.UUID
-	Name         string
-	Type         schema.FolderType
-	LanguageFrom *schema.Language
-	LanguageTo   *schema.Language
-	ParentID     *uuid.UUID
+type (
+	CreateFolderArgs = service.CreateFolderArgs
+	UpdateFolderArgs = service.UpdateFolderArgs
+	ListFoldersArgs  = service.ListFoldersArgs
+)
+
+type entService struct {
+	db *ent.Client
 }
 
-type UpdateFolderArgs struct {
-	FolderID uuid.UUID
-	UserID   uuid.UUID
-	Name     *string
-	ParentID *uuid.UUID
+// NewService returns the folder service backed by db.
+func NewService(db *ent.Client) service.FolderService {
+	return &entService{db: db}
 }
 
-func CreateFolder(ctx context.Context, db *ent.Client, args CreateFolderArgs) (*ent.Folder, error) {
+func (s *entService) CreateFolder(ctx context.Context, args CreateFolderArgs) (*ent.Folder, error) {
 	if args.Type == schema.FolderTypeWordCollection && args.LanguageFrom == nil {
-		return nil, fmt.Errorf("languageFrom is required for word_collection folders")
+		return nil, ErrLanguageFromRequired
 	}
 	if args.Type == schema.FolderTypeFolderCollection && (args.LanguageFrom != nil || args.LanguageTo != nil) {
-		return nil, fmt.Errorf("languageFrom and languageTo should not be provided for folder collection folders")
+		return nil, ErrLanguagesNotAllowed
 	}
 
+	// Subfolders belong to the owner of the tree they are created in, so an
+	// admin adding one to another user's folder does not end up owning it.
+	ownerID := args.UserID
 	if args.ParentID != nil {
-		parentFolder, err := db.Folder.Query().
-			Where(folder.ID(*args.ParentID)).
-			WithUser().
-			Only(ctx)
+		parentFolder, err := authz.CanManageFolder(ctx, s.db, args.UserID, *args.ParentID)
 		if err != nil {
-			return nil, fmt.Errorf("parent folder not found: %w", err)
+			return nil, err
 		}
 
-		if parentFolder.Edges.User.ID != args.UserID {
-			return nil, fmt.Errorf("parent folder does not belong to user")
+		if err := ValidateCanAddSubfolder(ctx, s.db, *args.ParentID); err != nil {
+			return nil, err
 		}
 
-		if err := ValidateCanAddSubfolder(ctx, db, *args.ParentID); err != nil {
-			return nil, err
+		if parentFolder.Edges.User != nil {
+			ownerID = parentFolder.Edges.User.ID
 		}
 	}
 
-	mutation := db.Folder.Create().
+	mutation := s.db.Folder.Create().
 		SetName(args.Name).
 		SetWordCount(0).
 		SetType(args.Type).
-		SetUserID(args.UserID)
+		SetUserID(ownerID)
 
 	if args.Type == schema.FolderTypeWordCollection {
 		if args.LanguageFrom != nil {
-			mutation = mutation.SetLanguageFrom(*args.LanguageFrom)
+			languageFrom, err := languages.ResolveLanguage(ctx, s.db, *args.LanguageFrom)
+			if err != nil {
+				return nil, err
+			}
+			mutation = mutation.SetLanguageFrom(languageFrom.ID)
 		}
 		if args.LanguageTo != nil {
-			mutation = mutation.SetLanguageTo(*args.LanguageTo)
+			languageTo, err := languages.ResolveLanguage(ctx, s.db, *args.LanguageTo)
+			if err != nil {
+				return nil, err
+			}
+			mutation = mutation.SetLanguageTo(languageTo.ID)
 		}
 	}
 
@@ -78,200 +90,215 @@ func CreateFolder(ctx context.Context, db *ent.Client, args CreateFolderArgs) (*
 		return nil, err
 	}
 
-	return db.Folder.Query().
+	return s.db.Folder.Query().
 		Where(folder.ID(createdFolder.ID)).
 		WithParent().
 		WithSubfolders().
-		WithWords().
 		Only(ctx)
 }
 
-func GetFolderByID(ctx context.Context, db *ent.Client, folderID uuid.UUID, userID uuid.UUID) (*ent.Folder, error) {
-	return db.Folder.Query().
+func (s *entService) GetFolderByID(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) (*ent.Folder, error) {
+	if _, err := authz.CanReadFolder(ctx, s.db, userID, folderID); err != nil {
+		return nil, err
+	}
+
+	return s.db.Folder.Query().
 		Where(folder.ID(folderID)).
 		WithUser().
-		WithWords().
 		WithParent().
-		WithSubfolders(func(q *ent.FolderQuery) {
-			q.WithWords()
-		}).
+		WithSubfolders().
 		Only(ctx)
 }
 
-func GetUserFolders(ctx context.Context, db *ent.Client, userID uuid.UUID) ([]*ent.Folder, error) {
-	return db.Folder.Query().
-		Where(folder.HasUserWith(user.ID(userID))).
-		WithWords().
+// folderSorter lists what folder listings can be sorted by.
+var folderSorter = pagination.Sorter[*ent.Folder]{
+	Fields: []pagination.Field[*ent.Folder]{
+		pagination.TimeField("createdAt", pagination.Column(folder.FieldCreateTime), func(f *ent.Folder) time.Time {
+			return f.CreateTime
+		}),
+		pagination.TimeField("updatedAt", pagination.Column(folder.FieldUpdateTime), func(f *ent.Folder) time.Time {
+			return f.UpdateTime
+		}),
+		pagination.StringField("name", pagination.Column(folder.FieldName), func(f *ent.Folder) string {
+			return f.Name
+		}),
+	},
+	ID: func(f *ent.Folder) uuid.UUID {
+		return f.ID
+	},
+}
+
+func listFoldersPredicates(args ListFoldersArgs) []predicate.Folder {
+	predicates := []predicate.Folder{folder.HasUserWith(user.ID(args.UserID))}
+	if args.CreatedAfter != nil {
+		predicates = append(predicates, folder.CreateTimeGT(*args.CreatedAfter))
+	}
+	if args.CreatedBefore != nil {
+		predicates = append(predicates, folder.CreateTimeLT(*args.CreatedBefore))
+	}
+	return predicates
+}
+
+func (s *entService) GetUserFolders(ctx context.Context, args ListFoldersArgs) (*pagination.Page[*ent.Folder], error) {
+	query := s.db.Folder.Query().
+		Where(listFoldersPredicates(args)...).
 		WithParent().
-		WithSubfolders(func(q *ent.FolderQuery) {
-			q.WithWords()
-		}).
-		All(ctx)
+		WithSubfolders()
+
+	return pagination.Paginate(ctx, query, folderSorter, args.Page)
 }
 
-func GetRootFolders(ctx context.Context, db *ent.Client, userID uuid.UUID) ([]*ent.Folder, error) {
-	return db.Folder.Query().
-		Where(
-			folder.HasUserWith(user.ID(userID)),
-			folder.Not(folder.HasParent()),
-		).
-		WithWords().
+func (s *entService) GetRootFolders(ctx context.Context, args ListFoldersArgs) (*pagination.Page[*ent.Folder], error) {
+	query := s.db.Folder.Query().
+		Where(listFoldersPredicates(args)...).
+		Where(folder.Not(folder.HasParent())).
 		WithSubfolders(func(q *ent.FolderQuery) {
-			q.WithWords().
-				WithSubfolders(func(q2 *ent.FolderQuery) {
-					q2.WithWords()
-				})
-		}).
-		All(ctx)
+			q.WithSubfolders()
+		})
+
+	return pagination.Paginate(ctx, query, folderSorter, args.Page)
 }
 
-func GetFoldersByParentID(
+func (s *entService) GetFoldersByParentID(
 	ctx context.Context,
-	db *ent.Client,
 	parentFolderID uuid.UUID,
 	userID uuid.UUID,
 ) ([]*ent.Folder, error) {
-	parentFolder, err := db.Folder.Query().
-		Where(folder.ID(parentFolderID)).
-		WithUser().
-		Only(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("parent folder not found: %w", err)
-	}
-
-	if parentFolder.Edges.User.ID != userID {
-		return nil, fmt.Errorf("parent folder does not belong to user")
+	if _, err := authz.CanReadFolder(ctx, s.db, userID, parentFolderID); err != nil {
+		return nil, err
 	}
 
-	return db.Folder.Query().
+	return s.db.Folder.Query().
 		Where(folder.HasParentWith(folder.ID(parentFolderID))).
-		WithWords().
-		WithSubfolders(func(q *ent.FolderQuery) {
-			q.WithWords()
-		}).
+		WithSubfolders().
 		All(ctx)
 }
 
-func UpdateFolder(ctx context.Context, db *ent.Client, args UpdateFolderArgs) (*ent.Folder, error) {
-	existingFolder, err := db.Folder.Query().
-		Where(folder.ID(args.FolderID)).
-		WithUser().
-		Only(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("folder not found: %w", err)
-	}
-
-	if existingFolder.Edges.User.ID != args.UserID {
-		return nil, fmt.Errorf("folder does not belong to user")
+// UpdateFolder renames the folder, which editors may do, and moves it when
+// ParentID is set, which needs the owner role.
+func (s *entService) UpdateFolder(ctx context.Context, args UpdateFolderArgs) (*ent.Folder, error) {
+	canChange := authz.CanEditFolder
+	if args.ParentID != nil {
+		canChange = authz.CanManageFolder
 	}
 
-	mutation := db.Folder.UpdateOneID(args.FolderID)
-
-	if args.Name != nil {
-		mutation = mutation.SetName(*args.Name)
+	existingFolder, err := canChange(ctx, s.db, args.UserID, args.FolderID)
+	if err != nil {
+		return nil, err
 	}
 
 	if args.ParentID != nil {
-		if err := validateParentChange(ctx, db, args.FolderID, *args.ParentID, args.UserID); err != nil {
+		if err := validateParentChange(ctx, s.db, existingFolder, *args.ParentID, args.UserID); err != nil {
 			return nil, err
 		}
-
-		mutation = mutation.ClearParent().AddParentIDs(*args.ParentID)
 	}
 
-	updatedFolder, err := mutation.Save(ctx)
+	var updatedFolder *ent.Folder
+	err = shared.WithTx(ctx, s.db, func(client *ent.Client) error {
+		mutation := client.Folder.UpdateOneID(args.FolderID)
+
+		if args.Name != nil {
+			mutation = mutation.SetName(*args.Name)
+		}
+
+		if args.ParentID != nil {
+			mutation = mutation.ClearParent().AddParentIDs(*args.ParentID)
+		}
+
+		var err error
+		updatedFolder, err = mutation.Save(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return db.Folder.Query().
+	return s.db.Folder.Query().
 		Where(folder.ID(updatedFolder.ID)).
 		WithParent().
 		WithSubfolders().
-		WithWords().
 		Only(ctx)
 }
 
-func DeleteFolder(ctx context.Context, db *ent.Client, folderID uuid.UUID, userID uuid.UUID) error {
-	existingFolder, err := db.Folder.Query().
+func (s *entService) DeleteFolder(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) error {
+	if _, err := authz.CanManageFolder(ctx, s.db, userID, folderID); err != nil {
+		return err
+	}
+
+	existingFolder, err := s.db.Folder.Query().
 		Where(folder.ID(folderID)).
-		WithUser().
 		WithSubfolders().
 		WithWords().
 		Only(ctx)
 	if err != nil {
-		return fmt.Errorf("folder not found: %w", err)
-	}
-
-	if existingFolder.Edges.User.ID != userID {
-		return fmt.Errorf("folder does not belong to user")
+		return err
 	}
 
 	if len(existingFolder.Edges.Subfolders) > 0 {
-		return shared.BadRequest("Cannot delete folder that contains subfolders")
+		return ErrFolderHasSubfolders
 	}
 
 	if len(existingFolder.Edges.Words) > 0 {
-		return shared.BadRequest("Cannot delete folder that contains words")
+		return ErrFolderHasWords
 	}
 
-	return db.Folder.DeleteOneID(folderID).Exec(ctx)
+	return s.db.Folder.DeleteOneID(folderID).Exec(ctx)
 }
 
-func MoveFolder(ctx context.Context, db *ent.Client, folderID uuid.UUID, newParentID *uuid.UUID, userID uuid.UUID) (*ent.Folder, error) {
-	existingFolder, err := db.Folder.Query().
-		Where(folder.ID(folderID)).
-		WithUser().
-		Only(ctx)
+func (s *entService) MoveFolder(ctx context.Context, folderID uuid.UUID, newParentID *uuid.UUID, userID uuid.UUID) (*ent.Folder, error) {
+	existingFolder, err := authz.CanManageFolder(ctx, s.db, userID, folderID)
 	if err != nil {
-		return nil, fmt.Errorf("folder not found: %w", err)
-	}
-
-	if existingFolder.Edges.User.ID != userID {
-		return nil, fmt.Errorf("folder does not belong to user")
+		return nil, err
 	}
 
-	mutation := db.Folder.UpdateOneID(folderID).ClearParent()
-
 	if newParentID != nil {
-		if err := validateParentChange(ctx, db, folderID, *newParentID, userID); err != nil {
+		if err := validateParentChange(ctx, s.db, existingFolder, *newParentID, userID); err != nil {
 			return nil, err
 		}
-		mutation = mutation.AddParentIDs(*newParentID)
 	}
 
-	movedFolder, err := mutation.Save(ctx)
+	var movedFolder *ent.Folder
+	err = shared.WithTx(ctx, s.db, func(client *ent.Client) error {
+		mutation := client.Folder.UpdateOneID(folderID).ClearParent()
+
+		if newParentID != nil {
+			mutation = mutation.AddParentIDs(*newParentID)
+		}
+
+		var err error
+		movedFolder, err = mutation.Save(ctx)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return db.Folder.Query().
+	return s.db.Folder.Query().
 		Where(folder.ID(movedFolder.ID)).
 		WithParent().
 		WithSubfolders().
-		WithWords().
 		Only(ctx)
 }
 
-func validateParentChange(ctx context.Context, db *ent.Client, folderID uuid.UUID, newParentID uuid.UUID, userID uuid.UUID) error {
-	parentFolder, err := db.Folder.Query().
-		Where(folder.ID(newParentID)).
-		WithUser().
-		Only(ctx)
+// validateParentChange keeps every tree under a single owner: the new parent
+// has to belong to the folder's owner, even when an admin does the move.
+func validateParentChange(ctx context.Context, db *ent.Client, existingFolder *ent.Folder, newParentID uuid.UUID, userID uuid.UUID) error {
+	parentFolder, err := authz.CanManageFolder(ctx, db, userID, newParentID)
 	if err != nil {
-		return fmt.Errorf("parent folder not found: %w", err)
+		return err
 	}
 
-	if parentFolder.Edges.User.ID != userID {
-		return fmt.Errorf("parent folder does not belong to user")
+	if parentFolder.Edges.User == nil || existingFolder.Edges.User == nil ||
+		parentFolder.Edges.User.ID != existingFolder.Edges.User.ID {
+		return ErrParentOwnedByOther
 	}
 
-	return checkCircularReference(ctx, db, folderID, newParentID)
+	return checkCircularReference(ctx, db, existingFolder.ID, newParentID)
 }
 
 func checkCircularReference(ctx context.Context, db *ent.Client, folderID uuid.UUID, potentialChildID uuid.UUID) error {
 	if folderID == potentialChildID {
-		return fmt.Errorf("cannot set folder as its own parent")
+		return ErrCircularReference
 	}
 
 	descendants, err := getDescendants(ctx, db, folderID)
@@ -281,33 +308,26 @@ func checkCircularReference(ctx context.Context, db *ent.Client, folderID uuid.U
 
 	for _, descendant := range descendants {
 		if descendant == potentialChildID {
-			return fmt.Errorf("circular reference detected: cannot move folder to its own descendant")
+			return ErrCircularReference
 		}
 	}
 
 	return nil
 }
 
-func getDescendants(ctx context.Context, db *ent.Client, folderID uuid.UUID) ([]uuid.UUID, error) {
-	var descendants []uuid.UUID
-
-	subfolders, err := db.Folder.Query().
-		Where(folder.HasParentWith(folder.ID(folderID))).
-		All(ctx)
-	if err != nil {
+// GetFolderSubtreeIDs returns the folder and all of its descendants if the
+// user may read the folder.
+func GetFolderSubtreeIDs(ctx context.Context, db *ent.Client, folderID uuid.UUID, userID uuid.UUID) ([]uuid.UUID, error) {
+	if _, err := authz.CanReadFolder(ctx, db, userID, folderID); err != nil {
 		return nil, err
 	}
 
-	for _, subfolder := range subfolders {
-		descendants = append(descendants, subfolder.ID)
-		subDescendants, err := getDescendants(ctx, db, subfolder.ID)
-		if err != nil {
-			return nil, err
-		}
-		descendants = append(descendants, subDescendants...)
+	descendants, err := getDescendants(ctx, db, folderID)
+	if err != nil {
+		return nil, err
 	}
 
-	return descendants, nil
+	return append([]uuid.UUID{folderID}, descendants...), nil
 }
 
 func ValidateCanAddWords(ctx context.Context, db *ent.Client, folderID uuid.UUID) error {
@@ -315,11 +335,14 @@ func ValidateCanAddWords(ctx context.Context, db *ent.Client, folderID uuid.UUID
 		Where(folder.ID(folderID)).
 		Only(ctx)
 	if err != nil {
-		return fmt.Errorf("folder not found: %w", err)
+		if shared.IsDatabaseErorNotFound(err) {
+			return authz.ErrFolderNotFound
+		}
+		return err
 	}
 
 	if folder.Type != schema.FolderTypeWordCollection {
-		return fmt.Errorf("words can only be added to word_collection folders")
+		return ErrNotWordCollection
 	}
 
 	return nil
@@ -330,11 +353,14 @@ func ValidateCanAddSubfolder(ctx context.Context, db *ent.Client, parentFolderID
 		Where(folder.ID(parentFolderID)).
 		Only(ctx)
 	if err != nil {
-		return fmt.Errorf("parent folder not found: %w", err)
+		if shared.IsDatabaseErorNotFound(err) {
+			return authz.ErrFolderNotFound
+		}
+		return err
 	}
 
 	if folder.Type != schema.FolderTypeFolderCollection {
-		return fmt.Errorf("subfolders can only be added to folder collection folders")
+		return ErrNotFolderCollection
 	}
 
 	return nil
@@ -345,7 +371,10 @@ func GetFolderType(ctx context.Context, db *ent.Client, folderID uuid.UUID) (str
 		Where(folder.ID(folderID)).
 		Only(ctx)
 	if err != nil {
-		return "", fmt.Errorf("folder not found: %w", err)
+		if shared.IsDatabaseErorNotFound(err) {
+			return "", authz.ErrFolderNotFound
+		}
+		return "", err
 	}
 
 	return string(folder.Type), nil
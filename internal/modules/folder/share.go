@@ -0,0 +1,131 @@
+package folder
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/folder"
+	"lexia/ent/foldershare"
+	"lexia/ent/user"
+	"lexia/internal/authz"
+	"lexia/internal/service"
+	"lexia/internal/shared"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+type (
+	ShareFolderArgs   = service.ShareFolderArgs
+	UnshareFolderArgs = service.UnshareFolderArgs
+)
+
+// GetSharedFolders returns the folders other users have shared directly with
+// the user. Their subfolders are reachable through them.
+func (s *entService) GetSharedFolders(ctx context.Context, userID uuid.UUID) ([]*ent.Folder, error) {
+	return s.db.Folder.Query().
+		Where(folder.HasSharesWith(foldershare.HasUserWith(user.ID(userID)))).
+		WithSubfolders().
+		All(ctx)
+}
+
+func (s *entService) GetFolderShares(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) ([]*ent.FolderShare, error) {
+	if _, err := authz.CanShareFolder(ctx, s.db, userID, folderID); err != nil {
+		return nil, err
+	}
+
+	return s.db.FolderShare.Query().
+		Where(foldershare.HasFolderWith(folder.ID(folderID))).
+		WithUser().
+		Order(ent.Asc(foldershare.FieldCreateTime)).
+		All(ctx)
+}
+
+// ShareFolder gives the user with the given email a role on the folder, or
+// changes the role if the folder is already shared with them.
+func (s *entService) ShareFolder(ctx context.Context, args ShareFolderArgs) (*ent.FolderShare, error) {
+	folderEntity, err := authz.CanShareFolder(ctx, s.db, args.UserID, args.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedUser, err := s.db.User.Query().
+		Where(user.EmailEQ(args.Email), user.DeletedAtIsNil()).
+		Only(ctx)
+	if err != nil {
+		if shared.IsDatabaseErorNotFound(err) {
+			return nil, ErrShareUserNotFound
+		}
+		log.Println("Error finding user to share with: ", err)
+		return nil, err
+	}
+
+	if folderEntity.Edges.User != nil && folderEntity.Edges.User.ID == sharedUser.ID {
+		return nil, ErrShareWithOwner
+	}
+
+	var shareID uuid.UUID
+	err = shared.WithTx(ctx, s.db, func(client *ent.Client) error {
+		existing, err := client.FolderShare.Query().
+			Where(
+				foldershare.HasFolderWith(folder.ID(args.FolderID)),
+				foldershare.HasUserWith(user.ID(sharedUser.ID)),
+			).
+			Only(ctx)
+		if err == nil {
+			shareID = existing.ID
+			return client.FolderShare.UpdateOneID(existing.ID).
+				SetRole(args.Role).
+				Exec(ctx)
+		}
+		if !ent.IsNotFound(err) {
+			return err
+		}
+
+		created, err := client.FolderShare.Create().
+			SetFolderID(args.FolderID).
+			SetUserID(sharedUser.ID).
+			SetRole(args.Role).
+			Save(ctx)
+		if err != nil {
+			return err
+		}
+		shareID = created.ID
+		return nil
+	})
+	if err != nil {
+		log.Println("Error sharing folder: ", err)
+		return nil, err
+	}
+
+	return s.db.FolderShare.Query().
+		Where(foldershare.ID(shareID)).
+		WithUser().
+		Only(ctx)
+}
+
+// UnshareFolder removes a user's share. Users may always remove their own
+// share; removing anyone else's needs the right to share the folder.
+func (s *entService) UnshareFolder(ctx context.Context, args UnshareFolderArgs) error {
+	if args.SharedUserID != args.UserID {
+		if _, err := authz.CanShareFolder(ctx, s.db, args.UserID, args.FolderID); err != nil {
+			return err
+		}
+	}
+
+	deleted, err := s.db.FolderShare.Delete().
+		Where(
+			foldershare.HasFolderWith(folder.ID(args.FolderID)),
+			foldershare.HasUserWith(user.ID(args.SharedUserID)),
+		).
+		Exec(ctx)
+	if err != nil {
+		log.Println("Error removing folder share: ", err)
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrShareNotFound
+	}
+
+	return nil
+}
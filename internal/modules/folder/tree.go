@@ -0,0 +1,182 @@
+package folder
+
+import (
+	"context"
+	"database/sql"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// The folder hierarchy is stored in the folder_subfolders join table, where
+// folder_id is the parent and parent_id is the child (ent names the columns
+// after the edge owner and the inverse edge). Every query below walks it with
+// a recursive CTE and carries the visited path to stop on accidental cycles.
+
+const folderTreeQuery = `
+WITH RECURSIVE tree AS (
+	SELECT f.id, NULL::uuid AS parent_id, 0 AS depth, ARRAY[f.id] AS path
+	FROM folders f
+	WHERE f.user_folders = $1
+		AND NOT EXISTS (SELECT 1 FROM folder_subfolders fs WHERE fs.parent_id = f.id)
+	UNION ALL
+	SELECT fs.parent_id, tree.id, tree.depth + 1, tree.path || fs.parent_id
+	FROM tree
+	JOIN folder_subfolders fs ON fs.folder_id = tree.id
+	WHERE NOT fs.parent_id = ANY(tree.path)
+)
+SELECT
+	t.id,
+	t.parent_id,
+	t.depth,
+	f.name,
+	f.type,
+	f.language_from,
+	f.language_to,
+	f.create_time,
+	f.update_time,
+	f.word_count
+FROM tree t
+JOIN folders f ON f.id = t.id
+ORDER BY t.depth, f.create_time
+`
+
+const folderDescendantsQuery = `
+WITH RECURSIVE descendants AS (
+	SELECT fs.parent_id AS id, ARRAY[fs.folder_id, fs.parent_id] AS path
+	FROM folder_subfolders fs
+	WHERE fs.folder_id = $1
+	UNION ALL
+	SELECT fs.parent_id, d.path || fs.parent_id
+	FROM descendants d
+	JOIN folder_subfolders fs ON fs.folder_id = d.id
+	WHERE NOT fs.parent_id = ANY(d.path)
+)
+SELECT DISTINCT id FROM descendants
+`
+
+const folderAncestorsQuery = `
+WITH RECURSIVE ancestors AS (
+	SELECT f.id, f.name, 0 AS depth, ARRAY[f.id] AS path
+	FROM folders f
+	WHERE f.id = $1
+	UNION ALL
+	SELECT p.id, p.name, a.depth + 1, a.path || p.id
+	FROM ancestors a
+	JOIN folder_subfolders fs ON fs.parent_id = a.id
+	JOIN folders p ON p.id = fs.folder_id
+	WHERE NOT p.id = ANY(a.path)
+)
+SELECT id, name FROM ancestors ORDER BY depth DESC
+`
+
+type FolderTreeNode = service.FolderTreeNode
+
+// GetFolderTree returns the user's root folders with their complete subtrees,
+// loaded with a single recursive query regardless of depth. The stored word
+// count already covers a folder's whole subtree, so it is reported as both
+// counts.
+func (s *entService) GetFolderTree(ctx context.Context, userID uuid.UUID) ([]*FolderTreeNode, error) {
+	rows, err := s.db.QueryContext(ctx, folderTreeQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roots []*FolderTreeNode
+	nodes := map[uuid.UUID]*FolderTreeNode{}
+
+	for rows.Next() {
+		var (
+			node         FolderTreeNode
+			parentID     uuid.NullUUID
+			languageFrom sql.NullString
+			languageTo   sql.NullString
+		)
+
+		if err := rows.Scan(
+			&node.ID,
+			&parentID,
+			&node.Depth,
+			&node.Name,
+			&node.Type,
+			&languageFrom,
+			&languageTo,
+			&node.CreateTime,
+			&node.UpdateTime,
+			&node.WordCount,
+		); err != nil {
+			return nil, err
+		}
+		node.TotalWordCount = node.WordCount
+
+		if languageFrom.Valid {
+			lang := schema.Language(languageFrom.String)
+			node.LanguageFrom = &lang
+		}
+		if languageTo.Valid {
+			lang := schema.Language(languageTo.String)
+			node.LanguageTo = &lang
+		}
+
+		current := &node
+		nodes[node.ID] = current
+
+		if !parentID.Valid {
+			roots = append(roots, current)
+			continue
+		}
+
+		current.ParentID = &parentID.UUID
+		if parent, ok := nodes[parentID.UUID]; ok {
+			parent.Children = append(parent.Children, current)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return roots, nil
+}
+
+// GetFolderPath returns the folder and all of its ancestors, root first.
+func GetFolderPath(ctx context.Context, db *ent.Client, folderID uuid.UUID) ([]FolderPathItemDTO, error) {
+	rows, err := db.QueryContext(ctx, folderAncestorsQuery, folderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var path []FolderPathItemDTO
+	for rows.Next() {
+		var item FolderPathItemDTO
+		if err := rows.Scan(&item.ID, &item.Name); err != nil {
+			return nil, err
+		}
+		path = append(path, item)
+	}
+
+	return path, rows.Err()
+}
+
+func getDescendants(ctx context.Context, db *ent.Client, folderID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := db.QueryContext(ctx, folderDescendantsQuery, folderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var descendants []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		descendants = append(descendants, id)
+	}
+
+	return descendants, rows.Err()
+}
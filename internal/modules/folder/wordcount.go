@@ -0,0 +1,309 @@
+package folder
+
+import (
+	"context"
+	"fmt"
+
+	"lexia/ent"
+	"lexia/ent/folder"
+	"lexia/ent/hook"
+	"lexia/ent/word"
+	"lexia/internal/shared"
+
+	entgo "entgo.io/ent"
+	"github.com/google/uuid"
+)
+
+// Folder.wordCount holds the number of words in a folder's subtree. For
+// WORD_COLLECTION folders that is the number of words stored directly in the
+// folder; for FOLDER_COLLECTION folders it is the sum over all descendants.
+// The hooks below keep the column in sync for every ancestor whenever a word
+// is added, removed or moved, or a folder is moved to another parent.
+//
+// They are registered at runtime rather than in ent/schema because the
+// generated packages import the schema enums, which would make schema hooks
+// an import cycle.
+func RegisterWordCountHooks(db *ent.Client) {
+	db.Word.Use(wordCountHook())
+	db.Folder.Use(folderMoveWordCountHook())
+}
+
+func wordCountHook() ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return hook.WordFunc(func(ctx context.Context, m *ent.WordMutation) (ent.Value, error) {
+			switch {
+			case m.Op().Is(entgo.OpCreate):
+				value, err := next.Mutate(ctx, m)
+				if err != nil {
+					return nil, err
+				}
+
+				if folderID, ok := m.FolderID(); ok {
+					if err := adjustFolderWordCount(ctx, m.Client(), folderID, 1); err != nil {
+						return nil, err
+					}
+				}
+
+				return value, nil
+
+			case m.Op().Is(entgo.OpDelete | entgo.OpDeleteOne):
+				previousCounts, err := wordCountsByFolder(ctx, m)
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := next.Mutate(ctx, m)
+				if err != nil {
+					return nil, err
+				}
+
+				for folderID, count := range previousCounts {
+					if err := adjustFolderWordCount(ctx, m.Client(), folderID, -count); err != nil {
+						return nil, err
+					}
+				}
+
+				return value, nil
+
+			case m.Op().Is(entgo.OpUpdate | entgo.OpUpdateOne):
+				_, folderChanged := m.FolderID()
+				if !folderChanged && !m.FolderCleared() {
+					return next.Mutate(ctx, m)
+				}
+
+				previousCounts, err := wordCountsByFolder(ctx, m)
+				if err != nil {
+					return nil, err
+				}
+
+				value, err := next.Mutate(ctx, m)
+				if err != nil {
+					return nil, err
+				}
+
+				for folderID, count := range previousCounts {
+					if err := adjustFolderWordCount(ctx, m.Client(), folderID, -count); err != nil {
+						return nil, err
+					}
+				}
+
+				if folderID, ok := m.FolderID(); ok {
+					moved := int32(0)
+					for _, count := range previousCounts {
+						moved += count
+					}
+					if err := adjustFolderWordCount(ctx, m.Client(), folderID, moved); err != nil {
+						return nil, err
+					}
+				}
+
+				return value, nil
+			}
+
+			return next.Mutate(ctx, m)
+		})
+	}
+}
+
+func folderMoveWordCountHook() ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return hook.FolderFunc(func(ctx context.Context, m *ent.FolderMutation) (ent.Value, error) {
+			if !m.Op().Is(entgo.OpUpdateOne) || (!m.ParentCleared() && len(m.ParentIDs()) == 0 && len(m.RemovedParentIDs()) == 0) {
+				return next.Mutate(ctx, m)
+			}
+
+			folderID, ok := m.ID()
+			if !ok {
+				return next.Mutate(ctx, m)
+			}
+
+			existing, err := m.Client().Folder.Query().
+				Where(folder.ID(folderID)).
+				WithParent(func(q *ent.FolderQuery) {
+					q.Select(folder.FieldID)
+				}).
+				Only(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := next.Mutate(ctx, m)
+			if err != nil {
+				return nil, err
+			}
+
+			if existing.WordCount == 0 {
+				return value, nil
+			}
+
+			for _, parent := range existing.Edges.Parent {
+				if err := adjustFolderWordCount(ctx, m.Client(), parent.ID, -existing.WordCount); err != nil {
+					return nil, err
+				}
+			}
+
+			newParentIDs, err := m.Client().Folder.Query().
+				Where(folder.HasSubfoldersWith(folder.ID(folderID))).
+				IDs(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, parentID := range newParentIDs {
+				if err := adjustFolderWordCount(ctx, m.Client(), parentID, existing.WordCount); err != nil {
+					return nil, err
+				}
+			}
+
+			return value, nil
+		})
+	}
+}
+
+func wordCountsByFolder(ctx context.Context, m *ent.WordMutation) (map[uuid.UUID]int32, error) {
+	ids, err := m.IDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	words, err := m.Client().Word.Query().
+		Where(word.IDIn(ids...)).
+		WithFolder(func(q *ent.FolderQuery) {
+			q.Select(folder.FieldID)
+		}).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[uuid.UUID]int32{}
+	for _, w := range words {
+		if w.Edges.Folder != nil {
+			counts[w.Edges.Folder.ID]++
+		}
+	}
+
+	return counts, nil
+}
+
+// adjustFolderWordCount adds delta to the folder and every one of its
+// ancestors. The update is applied with an atomic increment so concurrent
+// mutations do not lose counts.
+func adjustFolderWordCount(ctx context.Context, client *ent.Client, folderID uuid.UUID, delta int32) error {
+	if delta == 0 {
+		return nil
+	}
+
+	visited := map[uuid.UUID]bool{}
+	current := []uuid.UUID{folderID}
+
+	for len(current) > 0 {
+		var parents []uuid.UUID
+
+		for _, id := range current {
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+
+			if err := client.Folder.UpdateOneID(id).AddWordCount(delta).Exec(ctx); err != nil {
+				return fmt.Errorf("failed to update word count of folder %s: %w", id, err)
+			}
+
+			parentIDs, err := client.Folder.Query().
+				Where(folder.HasSubfoldersWith(folder.ID(id))).
+				IDs(ctx)
+			if err != nil {
+				return err
+			}
+			parents = append(parents, parentIDs...)
+		}
+
+		current = parents
+	}
+
+	return nil
+}
+
+// RecountWordCounts recomputes wordCount for every folder from the words table
+// and returns the number of folders whose stored count was wrong.
+func RecountWordCounts(ctx context.Context, db *ent.Client) (int, error) {
+	folders, err := db.Folder.Query().
+		WithParent(func(q *ent.FolderQuery) {
+			q.Select(folder.FieldID)
+		}).
+		All(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var directCounts []struct {
+		FolderID uuid.UUID `json:"folder_words"`
+		Count    int32     `json:"count"`
+	}
+	err = db.Word.Query().
+		Where(word.HasFolder()).
+		GroupBy(word.FolderColumn).
+		Aggregate(ent.As(ent.Count(), "count")).
+		Scan(ctx, &directCounts)
+	if err != nil {
+		return 0, err
+	}
+
+	totals := map[uuid.UUID]int32{}
+	for _, directCount := range directCounts {
+		totals[directCount.FolderID] = directCount.Count
+	}
+
+	children := map[uuid.UUID][]uuid.UUID{}
+	for _, f := range folders {
+		for _, parent := range f.Edges.Parent {
+			children[parent.ID] = append(children[parent.ID], f.ID)
+		}
+	}
+
+	computed := map[uuid.UUID]int32{}
+	var subtreeTotal func(id uuid.UUID, visiting map[uuid.UUID]bool) int32
+	subtreeTotal = func(id uuid.UUID, visiting map[uuid.UUID]bool) int32 {
+		if total, ok := computed[id]; ok {
+			return total
+		}
+		if visiting[id] {
+			return 0
+		}
+		visiting[id] = true
+
+		total := totals[id]
+		for _, childID := range children[id] {
+			total += subtreeTotal(childID, visiting)
+		}
+
+		computed[id] = total
+		return total
+	}
+
+	updated := 0
+	err = shared.WithTx(ctx, db, func(client *ent.Client) error {
+		for _, f := range folders {
+			total := subtreeTotal(f.ID, map[uuid.UUID]bool{})
+			if total == f.WordCount {
+				continue
+			}
+
+			if err := client.Folder.UpdateOneID(f.ID).SetWordCount(total).Exec(ctx); err != nil {
+				return fmt.Errorf("failed to update word count of folder %s: %w", f.ID, err)
+			}
+			updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,19 @@
+package identity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type linkIdentityDTO struct {
+	IDToken string `json:"idToken" binding:"required"`
+	Nonce   string `json:"nonce" binding:"max=256"`
+}
+
+type IdentityDTO struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+}
@@ -0,0 +1,18 @@
+package identity
+
+import (
+	"fmt"
+	"lexia/internal/apperr"
+	"lexia/internal/shared"
+)
+
+var (
+	ErrInvalidIDToken        = apperr.Unauthorized(shared.ErrInvalidIDToken, "ID token is invalid")
+	ErrIdentityAlreadyLinked = apperr.Conflict(shared.ErrIdentityAlreadyLinked, "This provider account is linked to another user")
+	ErrIdentityNotFound      = apperr.NotFound("IDENTITY_NOT_FOUND", "Identity not found")
+	ErrLastSignInMethod      = apperr.InvalidState(shared.ErrLastSignInMethod, "Cannot remove the last way to sign in")
+)
+
+func errProviderNotConfigured(provider string) error {
+	return apperr.NotFound("IDENTITY_PROVIDER_NOT_FOUND", fmt.Sprintf("Identity provider '%s' is not configured", provider))
+}
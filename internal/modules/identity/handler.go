@@ -0,0 +1,82 @@
+package identity
+
+import (
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func handleGetIdentities(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		identities, err := GetUserIdentities(c.Request.Context(), apiCfg.DB, authPayload.UserID)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, IdentityEntitiesToDtos(identities))
+	}
+}
+
+func handleLinkIdentity(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body linkIdentityDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		identityEntity, err := LinkIdentity(c.Request.Context(), apiCfg.DB, apiCfg.Env.OidcProviders, LinkIdentityArgs{
+			UserID:   authPayload.UserID,
+			Provider: c.Param("provider"),
+			IDToken:  body.IDToken,
+			Nonce:    body.Nonce,
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResCreated(c, IdentityEntityToDto(identityEntity))
+	}
+}
+
+func handleUnlinkIdentity(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		identityID, err := uuid.Parse(c.Param("identityId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid identity ID")
+			return
+		}
+
+		err = UnlinkIdentity(c.Request.Context(), apiCfg.DB, UnlinkIdentityArgs{
+			IdentityID: identityID,
+			UserID:     authPayload.UserID,
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResNoContent(c)
+	}
+}
@@ -0,0 +1,237 @@
+package identity
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	jwksMaxAge = time.Hour
+	// jwksMinRefreshInterval limits refetches triggered by unknown key IDs, so
+	// tokens with made-up kids cannot make us hammer the provider.
+	jwksMinRefreshInterval = time.Minute
+)
+
+var errUnknownKey = errors.New("no key with the given kid")
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type keySet struct {
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// jwksCache keeps the signing keys of each provider in memory. Keys are
+// refetched after jwksMaxAge or when a token names a key we have not seen,
+// which is how providers roll their keys. mu only guards the maps; fetches
+// run without it, and concurrent requests for the same URL share one fetch,
+// so a slow provider holds up only its own sign-ins.
+type jwksCache struct {
+	mu         sync.Mutex
+	sets       map[string]*keySet
+	fetches    map[string]*jwksFetch
+	httpClient *http.Client
+}
+
+// jwksFetch is a fetch in progress. keys and err are set before done is
+// closed.
+type jwksFetch struct {
+	done chan struct{}
+	keys map[string]crypto.PublicKey
+	err  error
+}
+
+var defaultJwksCache = newJwksCache(&http.Client{Timeout: 10 * time.Second})
+
+func newJwksCache(httpClient *http.Client) *jwksCache {
+	return &jwksCache{
+		sets:       map[string]*keySet{},
+		fetches:    map[string]*jwksFetch{},
+		httpClient: httpClient,
+	}
+}
+
+func (c *jwksCache) getKey(ctx context.Context, jwksURL string, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	set := c.sets[jwksURL]
+	if set != nil && time.Since(set.fetchedAt) < jwksMaxAge {
+		if key, ok := set.keys[kid]; ok {
+			c.mu.Unlock()
+			return key, nil
+		}
+
+		if time.Since(set.fetchedAt) < jwksMinRefreshInterval {
+			c.mu.Unlock()
+			return nil, errUnknownKey
+		}
+	}
+
+	inFlight, ok := c.fetches[jwksURL]
+	if !ok {
+		inFlight = &jwksFetch{done: make(chan struct{})}
+		c.fetches[jwksURL] = inFlight
+		// The fetch outlives this request if it is cancelled, since others
+		// may be waiting on it; the client timeout still bounds it.
+		go c.refresh(context.WithoutCancel(ctx), jwksURL, inFlight)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-inFlight.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	if inFlight.err != nil {
+		return nil, inFlight.err
+	}
+
+	key, ok := inFlight.keys[kid]
+	if !ok {
+		return nil, errUnknownKey
+	}
+
+	return key, nil
+}
+
+func (c *jwksCache) refresh(ctx context.Context, jwksURL string, inFlight *jwksFetch) {
+	keys, err := c.fetch(ctx, jwksURL)
+
+	c.mu.Lock()
+	if err == nil {
+		c.sets[jwksURL] = &keySet{keys: keys, fetchedAt: time.Now()}
+	}
+	delete(c.fetches, jwksURL)
+	c.mu.Unlock()
+
+	inFlight.keys = keys
+	inFlight.err = err
+	close(inFlight.done)
+}
+
+func (c *jwksCache) fetch(ctx context.Context, jwksURL string) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var document jsonWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		return nil, fmt.Errorf("decoding JWKS: %w", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		// Keys of unsupported types are skipped rather than failing the
+		// whole set.
+		key, err := parseJSONWebKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func parseJSONWebKey(jwk jsonWebKey) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent too large")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+
+	return new(big.Int).SetBytes(bytes), nil
+}
@@ -0,0 +1,136 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lexia/internal/shared"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type IDTokenClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// VerifyIDToken checks an ID token from a configured provider: signature
+// against the provider's JWKS, issuer, audience, expiry and, when given, the
+// nonce the client sent to the provider.
+func VerifyIDToken(
+	ctx context.Context,
+	providers map[string]shared.OidcProviderConfig,
+	provider string,
+	rawIDToken string,
+	nonce string,
+) (*IDTokenClaims, error) {
+	config, ok := providers[provider]
+	if !ok {
+		return nil, errProviderNotConfigured(provider)
+	}
+
+	claims, err := verifyIDToken(ctx, defaultJwksCache, config, rawIDToken, nonce)
+	if err != nil {
+		log.Println("Error verifying ID token: ", err)
+		return nil, ErrInvalidIDToken
+	}
+
+	return claims, nil
+}
+
+// idTokenClaims are the ID token claims Lexia reads on top of the
+// registered ones.
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Email         string `json:"email"`
+	EmailVerified any    `json:"email_verified"`
+	Name          string `json:"name"`
+	Nonce         string `json:"nonce"`
+}
+
+var idTokenSigningMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+func verifyIDToken(
+	ctx context.Context,
+	cache *jwksCache,
+	config shared.OidcProviderConfig,
+	rawIDToken string,
+	nonce string,
+) (*IDTokenClaims, error) {
+	keyFunc := func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return cache.getKey(ctx, config.JwksUrl, kid)
+	}
+
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(
+		rawIDToken,
+		&claims,
+		keyFunc,
+		jwt.WithValidMethods(idTokenSigningMethods),
+		// ID tokens must always carry exp.
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sameIssuer(claims.Issuer, config.Issuer) {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+
+	if !audienceMatches(claims.Audience, config.ClientIDs) {
+		return nil, errors.New("audience does not match any client ID")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("missing sub")
+	}
+
+	if nonce != "" && claims.Nonce != nonce {
+		return nil, errors.New("nonce mismatch")
+	}
+
+	return &IDTokenClaims{
+		Subject:       claims.Subject,
+		Email:         strings.ToLower(claims.Email),
+		EmailVerified: claimIsTrue(claims.EmailVerified),
+		Name:          claims.Name,
+	}, nil
+}
+
+// sameIssuer compares issuers ignoring the scheme, since Google signs some
+// tokens with "accounts.google.com" and others with the https:// form.
+func sameIssuer(issuer string, expected string) bool {
+	return issuer != "" && strings.TrimPrefix(issuer, "https://") == strings.TrimPrefix(expected, "https://")
+}
+
+// audienceMatches reports whether any audience of the token is one of our
+// client IDs.
+func audienceMatches(audience jwt.ClaimStrings, clientIDs []string) bool {
+	for _, aud := range audience {
+		if slices.Contains(clientIDs, aud) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// claimIsTrue reads a boolean claim. Apple sends email_verified as the
+// string "true" rather than a JSON boolean.
+func claimIsTrue(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
@@ -0,0 +1,214 @@
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"lexia/internal/shared"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type testIssuer struct {
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	fetches atomic.Int32
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	issuer := &testIssuer{key: key}
+	issuer.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer.fetches.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{
+				{"kty": "oct", "kid": "symmetric", "k": "c2VjcmV0"},
+				{
+					"kty": "RSA",
+					"kid": "key-1",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		})
+	}))
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *testIssuer) config() shared.OidcProviderConfig {
+	return shared.OidcProviderConfig{
+		Issuer:    "https://issuer.example.com",
+		JwksUrl:   i.server.URL,
+		ClientIDs: []string{"web-client", "ios-client"},
+	}
+}
+
+func (i *testIssuer) sign(t *testing.T, kid string, overrides jwt.MapClaims) string {
+	t.Helper()
+
+	claims := jwt.MapClaims{
+		"iss": "https://issuer.example.com",
+		"aud": "web-client",
+		"sub": "subject-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+
+	return signed
+}
+
+func TestVerifyIDTokenAcceptsValidTokens(t *testing.T) {
+	issuer := newTestIssuer(t)
+	cache := newJwksCache(http.DefaultClient)
+
+	testCases := []struct {
+		name          string
+		claims        jwt.MapClaims
+		emailVerified bool
+	}{
+		{"string audience", jwt.MapClaims{}, false},
+		{"audience array", jwt.MapClaims{"aud": []string{"other", "ios-client"}}, false},
+		{"issuer without scheme", jwt.MapClaims{"iss": "issuer.example.com"}, false},
+		{"boolean email_verified", jwt.MapClaims{"email_verified": true}, true},
+		{"string email_verified", jwt.MapClaims{"email_verified": "true"}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.claims["email"] = "Jane@Example.com"
+
+			claims, err := verifyIDToken(context.Background(), cache, issuer.config(), issuer.sign(t, "key-1", tc.claims), "")
+			if err != nil {
+				t.Fatalf("verifyIDToken returned error: %v", err)
+			}
+
+			if claims.Subject != "subject-1" || claims.Email != "jane@example.com" || claims.EmailVerified != tc.emailVerified {
+				t.Errorf("unexpected claims %+v", claims)
+			}
+		})
+	}
+
+	if fetches := issuer.fetches.Load(); fetches != 1 {
+		t.Errorf("expected the JWKS to be fetched once, got %d", fetches)
+	}
+}
+
+func TestVerifyIDTokenRejectsInvalidTokens(t *testing.T) {
+	issuer := newTestIssuer(t)
+	cache := newJwksCache(http.DefaultClient)
+
+	testCases := []struct {
+		name   string
+		kid    string
+		claims jwt.MapClaims
+		nonce  string
+	}{
+		{"wrong audience", "key-1", jwt.MapClaims{"aud": "someone-else"}, ""},
+		{"wrong issuer", "key-1", jwt.MapClaims{"iss": "https://evil.example.com"}, ""},
+		{"expired", "key-1", jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}, ""},
+		{"missing exp", "key-1", jwt.MapClaims{"exp": nil}, ""},
+		{"missing sub", "key-1", jwt.MapClaims{"sub": nil}, ""},
+		{"nonce mismatch", "key-1", jwt.MapClaims{"nonce": "other"}, "expected"},
+		{"unknown kid", "key-2", jwt.MapClaims{}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := verifyIDToken(context.Background(), cache, issuer.config(), issuer.sign(t, tc.kid, tc.claims), tc.nonce)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+
+	// Unknown kids within a minute of the last fetch do not refetch
+	if fetches := issuer.fetches.Load(); fetches != 1 {
+		t.Errorf("expected the JWKS to be fetched once, got %d", fetches)
+	}
+}
+
+func TestVerifyIDTokenRejectsSymmetricAlgorithms(t *testing.T) {
+	issuer := newTestIssuer(t)
+	cache := newJwksCache(http.DefaultClient)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": "https://issuer.example.com",
+		"aud": "web-client",
+		"sub": "subject-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "symmetric"
+
+	signed, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("signing token: %v", err)
+	}
+
+	if _, err := verifyIDToken(context.Background(), cache, issuer.config(), signed, ""); err == nil {
+		t.Fatal("expected HS256 tokens to be rejected")
+	}
+}
+
+func TestJwksCacheDoesNotBlockOtherProvidersDuringFetch(t *testing.T) {
+	issuer := newTestIssuer(t)
+	cache := newJwksCache(http.DefaultClient)
+
+	release := make(chan struct{})
+	var slowFetches atomic.Int32
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slowFetches.Add(1)
+		<-release
+		json.NewEncoder(w).Encode(map[string]any{"keys": []any{}})
+	}))
+	t.Cleanup(slow.Close)
+	t.Cleanup(func() { close(release) })
+
+	// Requests for the slow provider wait on one shared fetch
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		if _, err := cache.getKey(ctx, slow.URL, "key-1"); err == nil {
+			t.Fatal("expected the slow fetch to time out")
+		}
+		cancel()
+	}
+	if fetches := slowFetches.Load(); fetches != 1 {
+		t.Errorf("expected one fetch of the slow JWKS, got %d", fetches)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := verifyIDToken(ctx, cache, issuer.config(), issuer.sign(t, "key-1", jwt.MapClaims{}), ""); err != nil {
+		t.Fatalf("expected another provider to verify while a fetch is slow, got %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package identity
+
+import (
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
+	identityGroup := rg.Group("/user/identities")
+	{
+		identityGroup.GET("", handleGetIdentities(apiCfg))
+		identityGroup.POST("/:provider", handleLinkIdentity(apiCfg))
+		identityGroup.DELETE("/:identityId", handleUnlinkIdentity(apiCfg))
+	}
+}
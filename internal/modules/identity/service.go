@@ -0,0 +1,183 @@
+package identity
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/identity"
+	"lexia/ent/user"
+	"lexia/internal/shared"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// FindIdentityUser returns the user linked to a provider subject, or nil if
+// the subject is not linked to anyone.
+func FindIdentityUser(
+	ctx context.Context,
+	db *ent.Client,
+	provider string,
+	subject string,
+) (*ent.User, error) {
+	userEntity, err := db.Identity.Query().
+		Where(
+			identity.Provider(provider),
+			identity.Subject(subject),
+		).
+		QueryUser().
+		Only(ctx)
+
+	if ent.IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		log.Println("Error finding identity user: ", err)
+		return nil, err
+	}
+
+	return userEntity, nil
+}
+
+type CreateIdentityArgs struct {
+	UserID   uuid.UUID
+	Provider string
+	Claims   *IDTokenClaims
+}
+
+func CreateIdentity(
+	ctx context.Context,
+	db *ent.Client,
+	args CreateIdentityArgs,
+) (*ent.Identity, error) {
+	identityEntity, err := db.Identity.Create().
+		SetUserID(args.UserID).
+		SetProvider(args.Provider).
+		SetSubject(args.Claims.Subject).
+		SetEmail(args.Claims.Email).
+		Save(ctx)
+
+	if err != nil {
+		log.Println("Error creating identity: ", err)
+		return nil, err
+	}
+
+	return identityEntity, nil
+}
+
+type LinkIdentityArgs struct {
+	UserID   uuid.UUID
+	Provider string
+	IDToken  string
+	Nonce    string
+}
+
+// LinkIdentity attaches a provider account to an existing user. Linking the
+// same account twice is a no-op; an account linked to someone else is a
+// conflict.
+func LinkIdentity(
+	ctx context.Context,
+	db *ent.Client,
+	providers map[string]shared.OidcProviderConfig,
+	args LinkIdentityArgs,
+) (*ent.Identity, error) {
+	claims, err := VerifyIDToken(ctx, providers, args.Provider, args.IDToken, args.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := db.Identity.Query().
+		Where(
+			identity.Provider(args.Provider),
+			identity.Subject(claims.Subject),
+		).
+		WithUser().
+		Only(ctx)
+	if err == nil {
+		if existing.Edges.User.ID != args.UserID {
+			return nil, ErrIdentityAlreadyLinked
+		}
+		return existing, nil
+	}
+	if !ent.IsNotFound(err) {
+		log.Println("Error finding identity: ", err)
+		return nil, err
+	}
+
+	identityEntity, err := CreateIdentity(ctx, db, CreateIdentityArgs{
+		UserID:   args.UserID,
+		Provider: args.Provider,
+		Claims:   claims,
+	})
+	if ent.IsConstraintError(err) {
+		return nil, ErrIdentityAlreadyLinked
+	}
+
+	return identityEntity, err
+}
+
+func GetUserIdentities(ctx context.Context, db *ent.Client, userID uuid.UUID) ([]*ent.Identity, error) {
+	identities, err := db.Identity.Query().
+		Where(identity.HasUserWith(user.ID(userID))).
+		Order(ent.Asc(identity.FieldCreateTime)).
+		All(ctx)
+	if err != nil {
+		log.Println("Error getting user identities: ", err)
+		return nil, err
+	}
+
+	return identities, nil
+}
+
+type UnlinkIdentityArgs struct {
+	IdentityID uuid.UUID
+	UserID     uuid.UUID
+}
+
+// UnlinkIdentity removes a linked provider account. The last identity of a
+// user without a password cannot be removed, since they would be locked out.
+// The user row is locked before identities are counted, so two unlinks at
+// once cannot each leave the other identity as the last one.
+func UnlinkIdentity(ctx context.Context, db *ent.Client, args UnlinkIdentityArgs) error {
+	return shared.WithTx(ctx, db, func(client *ent.Client) error {
+		userEntity, err := client.User.Query().
+			Where(user.ID(args.UserID)).
+			ForUpdate().
+			Only(ctx)
+		if err != nil {
+			log.Println("Error locking user: ", err)
+			return err
+		}
+
+		identities, err := client.Identity.Query().
+			Where(identity.HasUserWith(user.ID(args.UserID))).
+			All(ctx)
+		if err != nil {
+			log.Println("Error getting user identities: ", err)
+			return err
+		}
+
+		found := false
+		for _, identityEntity := range identities {
+			if identityEntity.ID == args.IdentityID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return ErrIdentityNotFound
+		}
+
+		if userEntity.Password == "" && len(identities) == 1 {
+			return ErrLastSignInMethod
+		}
+
+		err = client.Identity.DeleteOneID(args.IdentityID).Exec(ctx)
+		if err != nil {
+			log.Println("Error deleting identity: ", err)
+		}
+
+		return err
+	})
+}
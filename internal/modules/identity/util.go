@@ -0,0 +1,20 @@
+package identity
+
+import "lexia/ent"
+
+func IdentityEntityToDto(identityEntity *ent.Identity) IdentityDTO {
+	return IdentityDTO{
+		ID:        identityEntity.ID,
+		CreatedAt: identityEntity.CreateTime,
+		Provider:  identityEntity.Provider,
+		Email:     identityEntity.Email,
+	}
+}
+
+func IdentityEntitiesToDtos(identityEntities []*ent.Identity) []IdentityDTO {
+	dtos := make([]IdentityDTO, len(identityEntities))
+	for i, identityEntity := range identityEntities {
+		dtos[i] = IdentityEntityToDto(identityEntity)
+	}
+	return dtos
+}
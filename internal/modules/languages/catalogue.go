@@ -0,0 +1,187 @@
+package languages
+
+import (
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/internal/shared"
+	"slices"
+)
+
+type catalogueEntry struct {
+	Tag        schema.Language
+	Name       string
+	NativeName string
+	Script     string
+	Direction  schema.TextDirection
+	LegacyCode schema.Language
+	Providers  []string
+}
+
+func (e catalogueEntry) direction() schema.TextDirection {
+	if e.Direction == "" {
+		return schema.TextDirectionLTR
+	}
+
+	return e.Direction
+}
+
+// matches reports whether the languages table row already holds the entry.
+func (e catalogueEntry) matches(languageEntity *ent.CatalogLanguage) bool {
+	var legacyCode schema.Language
+	if languageEntity.LegacyCode != nil {
+		legacyCode = *languageEntity.LegacyCode
+	}
+
+	return languageEntity.Name == e.Name &&
+		languageEntity.NativeName == e.NativeName &&
+		languageEntity.Script == e.Script &&
+		languageEntity.Direction == e.direction() &&
+		legacyCode == e.LegacyCode &&
+		slices.Equal(languageEntity.Providers, e.Providers)
+}
+
+var (
+	googleOnly     = []string{shared.TranslationProviderGoogle}
+	googleAndDeepl = []string{shared.TranslationProviderGoogle, shared.TranslationProviderDeepl}
+	googleAndLibre = []string{shared.TranslationProviderGoogle, shared.TranslationProviderLibreTranslate}
+	allProviders   = []string{shared.TranslationProviderGoogle, shared.TranslationProviderDeepl, shared.TranslationProviderLibreTranslate}
+)
+
+// catalogue is the source of the languages table, which SyncLanguages
+// brings up to date on startup. Languages are added or changed here rather
+// than in the database.
+var catalogue = []catalogueEntry{
+	{Tag: "af", Name: "Afrikaans", NativeName: "Afrikaans", Script: "Latn", Providers: googleOnly},
+	{Tag: "sq", Name: "Albanian", NativeName: "Shqip", Script: "Latn", Providers: googleAndLibre},
+	{Tag: "am", Name: "Amharic", NativeName: "አማርኛ", Script: "Ethi", Providers: googleOnly},
+	{Tag: "ar", Name: "Arabic", NativeName: "العربية", Script: "Arab", Direction: schema.TextDirectionRTL, Providers: allProviders},
+	{Tag: "hy", Name: "Armenian", NativeName: "Հայերեն", Script: "Armn", Providers: googleOnly},
+	{Tag: "as", Name: "Assamese", NativeName: "অসমীয়া", Script: "Beng", Providers: googleOnly},
+	{Tag: "ay", Name: "Aymara", NativeName: "Aymar aru", Script: "Latn", Providers: googleOnly},
+	{Tag: "az", Name: "Azerbaijani", NativeName: "Azərbaycan dili", Script: "Latn", Providers: googleAndLibre},
+	{Tag: "bm", Name: "Bambara", NativeName: "Bamanankan", Script: "Latn", Providers: googleOnly},
+	{Tag: "eu", Name: "Basque", NativeName: "Euskara", Script: "Latn", Providers: googleAndLibre},
+	{Tag: "be", Name: "Belarusian", NativeName: "Беларуская", Script: "Cyrl", Providers: googleOnly},
+	{Tag: "bn", Name: "Bengali", NativeName: "বাংলা", Script: "Beng", Providers: googleAndLibre},
+	{Tag: "bho", Name: "Bhojpuri", NativeName: "भोजपुरी", Script: "Deva", Providers: googleOnly},
+	{Tag: "bs", Name: "Bosnian", NativeName: "Bosanski", Script: "Latn", Providers: googleOnly},
+	{Tag: "bg", Name: "Bulgarian", NativeName: "Български", Script: "Cyrl", Providers: allProviders},
+	{Tag: "ca", Name: "Catalan", NativeName: "Català", Script: "Latn", Providers: googleAndLibre},
+	{Tag: "ceb", Name: "Cebuano", NativeName: "Sinugbuanong Binisayâ", Script: "Latn", Providers: googleOnly},
+	{Tag: "ny", Name: "Chichewa", NativeName: "Chichewa", Script: "Latn", Providers: googleOnly},
+	{Tag: "zh", Name: "Chinese (Simplified)", NativeName: "简体中文", Script: "Hans", LegacyCode: schema.LanguageChinese, Providers: allProviders},
+	{Tag: "zh-Hant", Name: "Chinese (Traditional)", NativeName: "繁體中文", Script: "Hant", Providers: allProviders},
+	{Tag: "co", Name: "Corsican", NativeName: "Corsu", Script: "Latn", Providers: googleOnly},
+	{Tag: "hr", Name: "Croatian", NativeName: "Hrvatski", Script: "Latn", Providers: googleOnly},
+	{Tag: "cs", Name: "Czech", NativeName: "Čeština", Script: "Latn", Providers: allProviders},
+	{Tag: "da", Name: "Danish", NativeName: "Dansk", Script: "Latn", Providers: allProviders},
+	{Tag: "dv", Name: "Dhivehi", NativeName: "ދިވެހި", Script: "Thaa", Direction: schema.TextDirectionRTL, Providers: googleOnly},
+	{Tag: "doi", Name: "Dogri", NativeName: "डोगरी", Script: "Deva", Providers: googleOnly},
+	{Tag: "nl", Name: "Dutch", NativeName: "Nederlands", Script: "Latn", Providers: allProviders},
+	{Tag: "en", Name: "English", NativeName: "English", Script: "Latn", LegacyCode: schema.LanguageEnglish, Providers: allProviders},
+	{Tag: "eo", Name: "Esperanto", NativeName: "Esperanto", Script: "Latn", Providers: googleAndLibre},
+	{Tag: "et", Name: "Estonian", NativeName: "Eesti", Script: "Latn", Providers: allProviders},
+	{Tag: "ee", Name: "Ewe", NativeName: "Eʋegbe", Script: "Latn", Providers: googleOnly},
+	{Tag: "fil", Name: "Filipino", NativeName: "Filipino", Script: "Latn", Providers: googleAndLibre},
+	{Tag: "fi", Name: "Finnish", NativeName: "Suomi", Script: "Latn", Providers: allProviders},
+	{Tag: "fr", Name: "French", NativeName: "Français", Script: "Latn", LegacyCode: schema.LanguageFrench, Providers: allProviders},
+	{Tag: "fy", Name: "Frisian", NativeName: "Frysk", Script: "Latn", Providers: googleOnly},
+	{Tag: "gl", Name: "Galician", NativeName: "Galego", Script: "Latn", Providers: googleAndLibre},
+	{Tag: "ka", Name: "Georgian", NativeName: "ქართული", Script: "Geor", LegacyCode: schema.LanguageGeorgian, Providers: googleOnly},
+	{Tag: "de", Name: "German", NativeName: "Deutsch", Script: "Latn", LegacyCode: schema.LanguageGerman, Providers: allProviders},
+	{Tag: "el", Name: "Greek", NativeName: "Ελληνικά", Script: "Grek", Providers: allProviders},
+	{Tag: "gn", Name: "Guarani", NativeName: "Avañe'ẽ", Script: "Latn", Providers: googleOnly},
+	{Tag: "gu", Name: "Gujarati", NativeName: "ગુજરાતી", Script: "Gujr", Providers: googleOnly},
+	{Tag: "ht", Name: "Haitian Creole", NativeName: "Kreyòl ayisyen", Script: "Latn", Providers: googleOnly},
+	{Tag: "ha", Name: "Hausa", NativeName: "Hausa", Script: "Latn", Providers: googleOnly},
+	{Tag: "haw", Name: "Hawaiian", NativeName: "ʻŌlelo Hawaiʻi", Script: "Latn", Providers: googleOnly},
+	{Tag: "he", Name: "Hebrew", NativeName: "עברית", Script: "Hebr", Direction: schema.TextDirectionRTL, Providers: allProviders},
+	{Tag: "hi", Name: "Hindi", NativeName: "हिन्दी", Script: "Deva", Providers: googleAndLibre},
+	{Tag: "hmn", Name: "Hmong", NativeName: "Hmoob", Script: "Latn", Providers: googleOnly},
+	{Tag: "hu", Name: "Hungarian", NativeName: "Magyar", Script: "Latn", Providers: allProviders},
+	{Tag: "is", Name: "Icelandic", NativeName: "Íslenska", Script: "Latn", Providers: googleOnly},
+	{Tag: "ig", Name: "Igbo", NativeName: "Igbo", Script: "Latn", Providers: googleOnly},
+	{Tag: "ilo", Name: "Ilocano", NativeName: "Ilokano", Script: "Latn", Providers: googleOnly},
+	{Tag: "id", Name: "Indonesian", NativeName: "Bahasa Indonesia", Script: "Latn", Providers: allProviders},
+	{Tag: "ga", Name: "Irish", NativeName: "Gaeilge", Script: "Latn", Providers: googleAndLibre},
+	{Tag: "it", Name: "Italian", NativeName: "Italiano", Script: "Latn", Providers: allProviders},
+	{Tag: "ja", Name: "Japanese", NativeName: "日本語", Script: "Jpan", LegacyCode: schema.LanguageJapanese, Providers: allProviders},
+	{Tag: "jv", Name: "Javanese", NativeName: "Basa Jawa", Script: "Latn", Providers: googleOnly},
+	{Tag: "kn", Name: "Kannada", NativeName: "ಕನ್ನಡ", Script: "Knda", Providers: googleOnly},
+	{Tag: "kk", Name: "Kazakh", NativeName: "Қазақ тілі", Script: "Cyrl", Providers: googleOnly},
+	{Tag: "km", Name: "Khmer", NativeName: "ខ្មែរ", Script: "Khmr", Providers: googleOnly},
+	{Tag: "rw", Name: "Kinyarwanda", NativeName: "Ikinyarwanda", Script: "Latn", Providers: googleOnly},
+	{Tag: "gom", Name: "Konkani", NativeName: "कोंकणी", Script: "Deva", Providers: googleOnly},
+	{Tag: "ko", Name: "Korean", NativeName: "한국어", Script: "Kore", Providers: allProviders},
+	{Tag: "kri", Name: "Krio", NativeName: "Krio", Script: "Latn", Providers: googleOnly},
+	{Tag: "ku", Name: "Kurdish (Kurmanji)", NativeName: "Kurdî", Script: "Latn", Providers: googleOnly},
+	{Tag: "ckb", Name: "Kurdish (Sorani)", NativeName: "کوردی", Script: "Arab", Direction: schema.TextDirectionRTL, Providers: googleOnly},
+	{Tag: "ky", Name: "Kyrgyz", NativeName: "Кыргызча", Script: "Cyrl", Providers: googleOnly},
+	{Tag: "lo", Name: "Lao", NativeName: "ລາວ", Script: "Laoo", Providers: googleOnly},
+	{Tag: "la", Name: "Latin", NativeName: "Latina", Script: "Latn", Providers: googleOnly},
+	{Tag: "lv", Name: "Latvian", NativeName: "Latviešu", Script: "Latn", Providers: allProviders},
+	{Tag: "ln", Name: "Lingala", NativeName: "Lingála", Script: "Latn", Providers: googleOnly},
+	{Tag: "lt", Name: "Lithuanian", NativeName: "Lietuvių", Script: "Latn", Providers: allProviders},
+	{Tag: "lg", Name: "Luganda", NativeName: "Luganda", Script: "Latn", Providers: googleOnly},
+	{Tag: "lb", Name: "Luxembourgish", NativeName: "Lëtzebuergesch", Script: "Latn", Providers: googleOnly},
+	{Tag: "mk", Name: "Macedonian", NativeName: "Македонски", Script: "Cyrl", Providers: googleOnly},
+	{Tag: "mai", Name: "Maithili", NativeName: "मैथिली", Script: "Deva", Providers: googleOnly},
+	{Tag: "mg", Name: "Malagasy", NativeName: "Malagasy", Script: "Latn", Providers: googleOnly},
+	{Tag: "ms", Name: "Malay", NativeName: "Bahasa Melayu", Script: "Latn", Providers: googleAndLibre},
+	{Tag: "ml", Name: "Malayalam", NativeName: "മലയാളം", Script: "Mlym", Providers: googleOnly},
+	{Tag: "mt", Name: "Maltese", NativeName: "Malti", Script: "Latn", Providers: googleOnly},
+	{Tag: "mi", Name: "Maori", NativeName: "Te Reo Māori", Script: "Latn", Providers: googleOnly},
+	{Tag: "mr", Name: "Marathi", NativeName: "मराठी", Script: "Deva", Providers: googleOnly},
+	{Tag: "mni-Mtei", Name: "Meiteilon (Manipuri)", NativeName: "ꯃꯤꯇꯩꯂꯣꯟ", Script: "Mtei", Providers: googleOnly},
+	{Tag: "lus", Name: "Mizo", NativeName: "Mizo ṭawng", Script: "Latn", Providers: googleOnly},
+	{Tag: "mn", Name: "Mongolian", NativeName: "Монгол", Script: "Cyrl", Providers: googleOnly},
+	{Tag: "my", Name: "Myanmar (Burmese)", NativeName: "မြန်မာ", Script: "Mymr", Providers: googleOnly},
+	{Tag: "ne", Name: "Nepali", NativeName: "नेपाली", Script: "Deva", Providers: googleOnly},
+	{Tag: "nb", Name: "Norwegian", NativeName: "Norsk bokmål", Script: "Latn", Providers: allProviders},
+	{Tag: "or", Name: "Odia", NativeName: "ଓଡ଼ିଆ", Script: "Orya", Providers: googleOnly},
+	{Tag: "om", Name: "Oromo", NativeName: "Afaan Oromoo", Script: "Latn", Providers: googleOnly},
+	{Tag: "ps", Name: "Pashto", NativeName: "پښتو", Script: "Arab", Direction: schema.TextDirectionRTL, Providers: googleOnly},
+	{Tag: "fa", Name: "Persian", NativeName: "فارسی", Script: "Arab", Direction: schema.TextDirectionRTL, Providers: googleAndLibre},
+	{Tag: "pl", Name: "Polish", NativeName: "Polski", Script: "Latn", Providers: allProviders},
+	{Tag: "pt", Name: "Portuguese", NativeName: "Português", Script: "Latn", Providers: allProviders},
+	{Tag: "pa", Name: "Punjabi", NativeName: "ਪੰਜਾਬੀ", Script: "Guru", Providers: googleOnly},
+	{Tag: "qu", Name: "Quechua", NativeName: "Runasimi", Script: "Latn", Providers: googleOnly},
+	{Tag: "ro", Name: "Romanian", NativeName: "Română", Script: "Latn", Providers: allProviders},
+	{Tag: "ru", Name: "Russian", NativeName: "Русский", Script: "Cyrl", LegacyCode: schema.LanguageRussian, Providers: allProviders},
+	{Tag: "sm", Name: "Samoan", NativeName: "Gagana Samoa", Script: "Latn", Providers: googleOnly},
+	{Tag: "sa", Name: "Sanskrit", NativeName: "संस्कृतम्", Script: "Deva", Providers: googleOnly},
+	{Tag: "gd", Name: "Scottish Gaelic", NativeName: "Gàidhlig", Script: "Latn", Providers: googleOnly},
+	{Tag: "nso", Name: "Sepedi", NativeName: "Sesotho sa Leboa", Script: "Latn", Providers: googleOnly},
+	{Tag: "sr", Name: "Serbian", NativeName: "Српски", Script: "Cyrl", Providers: googleOnly},
+	{Tag: "st", Name: "Sesotho", NativeName: "Sesotho", Script: "Latn", Providers: googleOnly},
+	{Tag: "sn", Name: "Shona", NativeName: "chiShona", Script: "Latn", Providers: googleOnly},
+	{Tag: "sd", Name: "Sindhi", NativeName: "سنڌي", Script: "Arab", Direction: schema.TextDirectionRTL, Providers: googleOnly},
+	{Tag: "si", Name: "Sinhala", NativeName: "සිංහල", Script: "Sinh", Providers: googleOnly},
+	{Tag: "sk", Name: "Slovak", NativeName: "Slovenčina", Script: "Latn", Providers: allProviders},
+	{Tag: "sl", Name: "Slovenian", NativeName: "Slovenščina", Script: "Latn", Providers: allProviders},
+	{Tag: "so", Name: "Somali", NativeName: "Soomaali", Script: "Latn", Providers: googleOnly},
+	{Tag: "es", Name: "Spanish", NativeName: "Español", Script: "Latn", LegacyCode: schema.LanguageSpanish, Providers: allProviders},
+	{Tag: "su", Name: "Sundanese", NativeName: "Basa Sunda", Script: "Latn", Providers: googleOnly},
+	{Tag: "sw", Name: "Swahili", NativeName: "Kiswahili", Script: "Latn", Providers: googleOnly},
+	{Tag: "sv", Name: "Swedish", NativeName: "Svenska", Script: "Latn", Providers: allProviders},
+	{Tag: "tg", Name: "Tajik", NativeName: "Тоҷикӣ", Script: "Cyrl", Providers: googleOnly},
+	{Tag: "ta", Name: "Tamil", NativeName: "தமிழ்", Script: "Taml", Providers: googleOnly},
+	{Tag: "tt", Name: "Tatar", NativeName: "Татарча", Script: "Cyrl", Providers: googleOnly},
+	{Tag: "te", Name: "Telugu", NativeName: "తెలుగు", Script: "Telu", Providers: googleOnly},
+	{Tag: "th", Name: "Thai", NativeName: "ไทย", Script: "Thai", Providers: allProviders},
+	{Tag: "ti", Name: "Tigrinya", NativeName: "ትግርኛ", Script: "Ethi", Providers: googleOnly},
+	{Tag: "ts", Name: "Tsonga", NativeName: "Xitsonga", Script: "Latn", Providers: googleOnly},
+	{Tag: "tr", Name: "Turkish", NativeName: "Türkçe", Script: "Latn", Providers: allProviders},
+	{Tag: "tk", Name: "Turkmen", NativeName: "Türkmençe", Script: "Latn", Providers: googleOnly},
+	{Tag: "ak", Name: "Twi", NativeName: "Twi", Script: "Latn", Providers: googleOnly},
+	{Tag: "uk", Name: "Ukrainian", NativeName: "Українська", Script: "Cyrl", Providers: allProviders},
+	{Tag: "ur", Name: "Urdu", NativeName: "اردو", Script: "Arab", Direction: schema.TextDirectionRTL, Providers: googleAndLibre},
+	{Tag: "ug", Name: "Uyghur", NativeName: "ئۇيغۇرچە", Script: "Arab", Direction: schema.TextDirectionRTL, Providers: googleOnly},
+	{Tag: "uz", Name: "Uzbek", NativeName: "Oʻzbekcha", Script: "Latn", Providers: googleOnly},
+	{Tag: "vi", Name: "Vietnamese", NativeName: "Tiếng Việt", Script: "Latn", Providers: googleAndDeepl},
+	{Tag: "cy", Name: "Welsh", NativeName: "Cymraeg", Script: "Latn", Providers: googleOnly},
+	{Tag: "xh", Name: "Xhosa", NativeName: "isiXhosa", Script: "Latn", Providers: googleOnly},
+	{Tag: "yi", Name: "Yiddish", NativeName: "ייִדיש", Script: "Hebr", Direction: schema.TextDirectionRTL, Providers: googleOnly},
+	{Tag: "yo", Name: "Yoruba", NativeName: "Yorùbá", Script: "Latn", Providers: googleOnly},
+	{Tag: "zu", Name: "Zulu", NativeName: "isiZulu", Script: "Latn", Providers: googleOnly},
+}
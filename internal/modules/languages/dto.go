@@ -0,0 +1,14 @@
+package languages
+
+import "lexia/ent/schema"
+
+type LanguageDTO struct {
+	// Code is what clients send and receive in languageFrom/languageTo.
+	Code       schema.Language      `json:"code"`
+	Tag        schema.Language      `json:"tag"`
+	Name       string               `json:"name"`
+	NativeName string               `json:"nativeName"`
+	Script     string               `json:"script"`
+	Direction  schema.TextDirection `json:"direction"`
+	Providers  []string             `json:"providers"`
+}
@@ -0,0 +1,11 @@
+package languages
+
+import (
+	"fmt"
+	"lexia/ent/schema"
+	"lexia/internal/apperr"
+)
+
+func errUnsupportedLanguage(code schema.Language) error {
+	return apperr.InvalidInput("UNSUPPORTED_LANGUAGE", fmt.Sprintf("Language '%s' is not supported", code))
+}
@@ -0,0 +1,105 @@
+package languages
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/cataloglanguage"
+	"lexia/ent/schema"
+	"log"
+
+	"entgo.io/ent/dialect/sql"
+	"entgo.io/ent/dialect/sql/sqljson"
+)
+
+// SyncLanguages brings the languages table up to date with the catalogue,
+// adding missing languages and updating changed ones. Rows that already
+// match are not written, so on most starts it only reads the table.
+// Languages no longer in the catalogue are kept, since folders may still
+// use them.
+func SyncLanguages(ctx context.Context, db *ent.Client) error {
+	existingLanguages, err := db.CatalogLanguage.Query().All(ctx)
+	if err != nil {
+		log.Println("Error listing languages: ", err)
+		return err
+	}
+
+	existing := make(map[schema.Language]*ent.CatalogLanguage, len(existingLanguages))
+	for _, languageEntity := range existingLanguages {
+		existing[languageEntity.ID] = languageEntity
+	}
+
+	var builders []*ent.CatalogLanguageCreate
+	for _, entry := range catalogue {
+		if languageEntity, ok := existing[entry.Tag]; ok && entry.matches(languageEntity) {
+			continue
+		}
+
+		builder := db.CatalogLanguage.Create().
+			SetID(entry.Tag).
+			SetName(entry.Name).
+			SetNativeName(entry.NativeName).
+			SetScript(entry.Script).
+			SetDirection(entry.direction()).
+			SetProviders(entry.Providers)
+		if entry.LegacyCode != "" {
+			builder.SetLegacyCode(entry.LegacyCode)
+		}
+		builders = append(builders, builder)
+	}
+
+	if len(builders) == 0 {
+		return nil
+	}
+
+	err = db.CatalogLanguage.CreateBulk(builders...).
+		OnConflictColumns(cataloglanguage.FieldID).
+		UpdateNewValues().
+		Exec(ctx)
+	if err != nil {
+		log.Println("Error syncing languages: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// ListLanguages returns the catalogue ordered by name, optionally limited to
+// languages the given provider supports.
+func ListLanguages(ctx context.Context, db *ent.Client, provider string) ([]*ent.CatalogLanguage, error) {
+	query := db.CatalogLanguage.Query()
+	if provider != "" {
+		query = query.Where(func(s *sql.Selector) {
+			s.Where(sqljson.ValueContains(cataloglanguage.FieldProviders, provider))
+		})
+	}
+
+	languageEntities, err := query.
+		Order(ent.Asc(cataloglanguage.FieldName)).
+		All(ctx)
+	if err != nil {
+		log.Println("Error listing languages: ", err)
+		return nil, err
+	}
+
+	return languageEntities, nil
+}
+
+// ResolveLanguage looks up a language by tag or legacy code. Unknown codes
+// are reported as a bad request naming the code the client sent.
+func ResolveLanguage(ctx context.Context, db *ent.Client, code schema.Language) (*ent.CatalogLanguage, error) {
+	languageEntity, err := db.CatalogLanguage.Query().
+		Where(cataloglanguage.Or(
+			cataloglanguage.ID(Normalize(code)),
+			cataloglanguage.LegacyCode(code),
+		)).
+		First(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, errUnsupportedLanguage(code)
+		}
+		log.Println("Error resolving language: ", err)
+		return nil, err
+	}
+
+	return languageEntity, nil
+}
@@ -0,0 +1,72 @@
+package languages
+
+import (
+	"lexia/ent/schema"
+	"slices"
+	"strings"
+)
+
+// textSearchConfigs maps catalogue tags to the Postgres full-text search
+// configuration that stems them. Other languages use "simple", which only
+// lowercases words. Words store the configuration they were created with,
+// so changing an entry needs a migration updating words.search_config.
+var textSearchConfigs = map[schema.Language]string{
+	"ar": "arabic",
+	"ca": "catalan",
+	"da": "danish",
+	"de": "german",
+	"el": "greek",
+	"en": "english",
+	"es": "spanish",
+	"eu": "basque",
+	"fi": "finnish",
+	"fr": "french",
+	"ga": "irish",
+	"hi": "hindi",
+	"hu": "hungarian",
+	"hy": "armenian",
+	"id": "indonesian",
+	"it": "italian",
+	"lt": "lithuanian",
+	"nb": "norwegian",
+	"ne": "nepali",
+	"nl": "dutch",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sr": "serbian",
+	"sv": "swedish",
+	"ta": "tamil",
+	"tr": "turkish",
+	"yi": "yiddish",
+}
+
+const defaultTextSearchConfig = "simple"
+
+// TextSearchConfig returns the full-text search configuration for a language.
+func TextSearchConfig(code schema.Language) string {
+	if config, ok := textSearchConfigs[Normalize(code)]; ok {
+		return config
+	}
+
+	return defaultTextSearchConfig
+}
+
+// TextSearchConfigSQL returns an SQL expression picking the full-text search
+// configuration for the language tag in column.
+func TextSearchConfigSQL(column string) string {
+	tags := make([]string, 0, len(textSearchConfigs))
+	for tag := range textSearchConfigs {
+		tags = append(tags, string(tag))
+	}
+	slices.Sort(tags)
+
+	var b strings.Builder
+	b.WriteString("CASE " + column)
+	for _, tag := range tags {
+		b.WriteString(" WHEN '" + tag + "' THEN '" + textSearchConfigs[schema.Language(tag)] + "'")
+	}
+	b.WriteString(" ELSE '" + defaultTextSearchConfig + "' END::regconfig")
+
+	return b.String()
+}
@@ -0,0 +1,127 @@
+package languages
+
+import (
+	"fmt"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	// tagsByLegacyCode resolves the old enum values to catalogue tags.
+	tagsByLegacyCode = map[schema.Language]schema.Language{}
+	// legacyCodesByTag lets responses keep reporting the old enum value for
+	// the languages that had one.
+	legacyCodesByTag = map[schema.Language]schema.Language{}
+	catalogueTags    = map[schema.Language]bool{}
+)
+
+func init() {
+	for _, entry := range catalogue {
+		catalogueTags[entry.Tag] = true
+		if entry.LegacyCode != "" {
+			tagsByLegacyCode[entry.LegacyCode] = entry.Tag
+			legacyCodesByTag[entry.Tag] = entry.LegacyCode
+		}
+	}
+}
+
+// Normalize turns a legacy code or any spelling of a BCP-47 tag into the
+// canonical tag used as the languages table key. Codes that cannot be parsed
+// are returned unchanged so lookups fail with the caller's original input.
+func Normalize(code schema.Language) schema.Language {
+	trimmed := strings.TrimSpace(string(code))
+	if tag, ok := tagsByLegacyCode[schema.Language(strings.ToUpper(trimmed))]; ok {
+		return tag
+	}
+
+	tag, err := language.Parse(trimmed)
+	if err != nil {
+		return code
+	}
+
+	return schema.Language(tag.String())
+}
+
+// PublicCode is the code reported back to clients: the legacy enum value
+// when the language had one, the tag otherwise.
+func PublicCode(code schema.Language) schema.Language {
+	tag := Normalize(code)
+	if legacyCode, ok := legacyCodesByTag[tag]; ok {
+		return legacyCode
+	}
+
+	return tag
+}
+
+// Tag parses a language code into the tag sent to translation providers.
+func Tag(code schema.Language) (language.Tag, error) {
+	tag, err := language.Parse(string(Normalize(code)))
+	if err != nil || tag == language.Und {
+		return language.Und, fmt.Errorf("unsupported language: %s", code)
+	}
+
+	return tag, nil
+}
+
+// FromTag maps a tag reported by a provider (e.g. zh-CN from detection) to
+// the closest catalogue entry, falling back to its base language.
+func FromTag(tag language.Tag) (schema.Language, error) {
+	if tag == language.Und {
+		return "", fmt.Errorf("unsupported language code: %s", tag.String())
+	}
+
+	base, _ := tag.Base()
+	candidates := []string{tag.String(), base.String()}
+	if script, confidence := tag.Script(); confidence == language.Exact {
+		candidates = []string{tag.String(), base.String() + "-" + script.String(), base.String()}
+	}
+
+	for _, candidate := range candidates {
+		if code := Normalize(schema.Language(candidate)); catalogueTags[code] {
+			return PublicCode(code), nil
+		}
+	}
+
+	return PublicCode(schema.Language(base.String())), nil
+}
+
+// LegacyCodes returns the original enum values of the languages that had
+// one, leaving out the rest.
+func LegacyCodes(languageEntities []*ent.CatalogLanguage) []schema.Language {
+	codes := []schema.Language{}
+	for _, languageEntity := range languageEntities {
+		if languageEntity.LegacyCode != nil {
+			codes = append(codes, *languageEntity.LegacyCode)
+		}
+	}
+
+	return codes
+}
+
+func LanguageEntityToDto(languageEntity *ent.CatalogLanguage) LanguageDTO {
+	providers := languageEntity.Providers
+	if providers == nil {
+		providers = []string{}
+	}
+
+	return LanguageDTO{
+		Code:       PublicCode(languageEntity.ID),
+		Tag:        languageEntity.ID,
+		Name:       languageEntity.Name,
+		NativeName: languageEntity.NativeName,
+		Script:     languageEntity.Script,
+		Direction:  languageEntity.Direction,
+		Providers:  providers,
+	}
+}
+
+func LanguageEntitiesToDtos(languageEntities []*ent.CatalogLanguage) []LanguageDTO {
+	dtos := make([]LanguageDTO, len(languageEntities))
+	for i, languageEntity := range languageEntities {
+		dtos[i] = LanguageEntityToDto(languageEntity)
+	}
+	return dtos
+}
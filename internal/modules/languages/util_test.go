@@ -0,0 +1,139 @@
+package languages
+
+import (
+	"lexia/ent"
+	"lexia/ent/schema"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestCatalogue(t *testing.T) {
+	assert.GreaterOrEqual(t, len(catalogue), 100)
+
+	seen := map[schema.Language]bool{}
+	for _, entry := range catalogue {
+		assert.False(t, seen[entry.Tag], "duplicate tag %s", entry.Tag)
+		seen[entry.Tag] = true
+
+		assert.Equal(t, entry.Tag, Normalize(entry.Tag), "tag %s is not canonical", entry.Tag)
+		assert.NotEmpty(t, entry.Name)
+		assert.NotEmpty(t, entry.NativeName)
+		assert.Len(t, entry.Script, 4)
+		assert.NotEmpty(t, entry.Providers)
+	}
+
+	assert.Len(t, tagsByLegacyCode, 8)
+}
+
+func TestTagLegacyCodes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		language schema.Language
+		expected string
+	}{
+		{"English", schema.LanguageEnglish, "en"},
+		{"Georgian", schema.LanguageGeorgian, "ka"},
+		{"Spanish", schema.LanguageSpanish, "es"},
+		{"French", schema.LanguageFrench, "fr"},
+		{"German", schema.LanguageGerman, "de"},
+		{"Russian", schema.LanguageRussian, "ru"},
+		{"Japanese", schema.LanguageJapanese, "ja"},
+		{"Chinese", schema.LanguageChinese, "zh"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			langTag, err := Tag(tc.language)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, langTag.String())
+		})
+	}
+}
+
+func TestTagUnsupportedLanguage(t *testing.T) {
+	_, err := Tag("UNSUPPORTED")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported language")
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, schema.Language("en"), Normalize("ENGLISH"))
+	assert.Equal(t, schema.Language("en"), Normalize("english"))
+	assert.Equal(t, schema.Language("it"), Normalize("IT"))
+	assert.Equal(t, schema.Language("zh-Hant"), Normalize("zh-hant"))
+	assert.Equal(t, schema.Language("not a code"), Normalize("not a code"))
+}
+
+func TestPublicCode(t *testing.T) {
+	assert.Equal(t, schema.LanguageGeorgian, PublicCode("ka"))
+	assert.Equal(t, schema.LanguageGeorgian, PublicCode(schema.LanguageGeorgian))
+	assert.Equal(t, schema.Language("it"), PublicCode("it"))
+}
+
+func TestFromTag(t *testing.T) {
+	testCases := []struct {
+		tag      string
+		expected schema.Language
+	}{
+		{"fr", schema.LanguageFrench},
+		{"zh-CN", schema.LanguageChinese},
+		{"zh-Hant", "zh-Hant"},
+		{"pt-BR", "pt"},
+		{"it", "it"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.tag, func(t *testing.T) {
+			code, err := FromTag(language.MustParse(tc.tag))
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, code)
+		})
+	}
+
+	_, err := FromTag(language.Und)
+	assert.Error(t, err)
+}
+
+func TestTextSearchConfig(t *testing.T) {
+	for tag := range textSearchConfigs {
+		assert.True(t, catalogueTags[tag], "tag %s is not in the catalogue", tag)
+	}
+
+	assert.Equal(t, "english", TextSearchConfig(schema.LanguageEnglish))
+	assert.Equal(t, "german", TextSearchConfig("de"))
+	assert.Equal(t, "simple", TextSearchConfig(schema.LanguageGeorgian))
+}
+
+func TestTextSearchConfigSQL(t *testing.T) {
+	expr := TextSearchConfigSQL("f.language_from")
+
+	assert.True(t, strings.HasPrefix(expr, "CASE f.language_from WHEN 'ar' THEN 'arabic'"), expr)
+	assert.Contains(t, expr, " WHEN 'en' THEN 'english'")
+	assert.True(t, strings.HasSuffix(expr, " ELSE 'simple' END::regconfig"), expr)
+}
+
+func TestCatalogueEntryMatches(t *testing.T) {
+	entry := catalogueEntry{Tag: "ka", Name: "Georgian", NativeName: "ქართული", Script: "Geor", LegacyCode: schema.LanguageGeorgian, Providers: googleOnly}
+	legacyCode := schema.LanguageGeorgian
+	row := &ent.CatalogLanguage{
+		ID:         "ka",
+		Name:       "Georgian",
+		NativeName: "ქართული",
+		Script:     "Geor",
+		Direction:  schema.TextDirectionLTR,
+		LegacyCode: &legacyCode,
+		Providers:  []string{"google"},
+	}
+
+	assert.True(t, entry.matches(row))
+
+	row.Providers = []string{"google", "deepl"}
+	assert.False(t, entry.matches(row), "a provider change must be synced")
+
+	row.Providers = []string{"google"}
+	row.LegacyCode = nil
+	assert.False(t, entry.matches(row), "a missing legacy code must be synced")
+}
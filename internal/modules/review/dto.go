@@ -0,0 +1,51 @@
+package review
+
+import (
+	"lexia/ent/schema"
+	"lexia/internal/modules/word"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SubmitReviewDTO struct {
+	Grade *int8 `json:"grade" binding:"required,min=0,max=5"`
+}
+
+type ReviewStateDTO struct {
+	WordID         uuid.UUID  `json:"wordId"`
+	Ease           float64    `json:"ease"`
+	Interval       int32      `json:"interval"`
+	Repetitions    int32      `json:"repetitions"`
+	Lapses         int32      `json:"lapses"`
+	DueAt          time.Time  `json:"dueAt"`
+	LastReviewedAt *time.Time `json:"lastReviewedAt,omitempty"`
+}
+
+type DueWordDTO struct {
+	Word   word.WordDTO    `json:"word"`
+	Review *ReviewStateDTO `json:"review"`
+}
+
+type SrsSettingsDTO struct {
+	Scheduler        schema.SrsScheduler `json:"scheduler"`
+	DesiredRetention float64             `json:"desiredRetention"`
+	FsrsWeights      []float64           `json:"fsrsWeights"`
+	OptimizedAt      *time.Time          `json:"optimizedAt,omitempty"`
+}
+
+type UpdateSrsSettingsDTO struct {
+	Scheduler        *schema.SrsScheduler `json:"scheduler,omitempty" binding:"omitempty,oneof=SM2 FSRS"`
+	DesiredRetention *float64             `json:"desiredRetention,omitempty" binding:"omitempty,gte=0.7,lte=0.99"`
+}
+
+type OptimizeSrsSettingsResponseDTO struct {
+	Settings              SrsSettingsDTO `json:"settings"`
+	PreviousWeights       []float64      `json:"previousWeights"`
+	ReviewCount           int            `json:"reviewCount"`
+	LogLossBefore         float64        `json:"logLossBefore"`
+	LogLossAfter          float64        `json:"logLossAfter"`
+	CurrentDailyReviews   float64        `json:"currentDailyReviews"`
+	OptimizedDailyReviews float64        `json:"optimizedDailyReviews"`
+	WorkloadChangePercent float64        `json:"workloadChangePercent"`
+}
@@ -0,0 +1,8 @@
+package review
+
+import "lexia/internal/apperr"
+
+var (
+	ErrInvalidGrade           = apperr.InvalidInput("INVALID_GRADE", "Grade must be between 0 and 5")
+	ErrNotEnoughReviewHistory = apperr.InvalidState("NOT_ENOUGH_REVIEW_HISTORY", "Not enough review history to optimize scheduler parameters")
+)
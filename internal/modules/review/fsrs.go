@@ -0,0 +1,136 @@
+package review
+
+import (
+	"math"
+)
+
+const (
+	RatingAgain = 1
+	RatingHard  = 2
+	RatingGood  = 3
+	RatingEasy  = 4
+)
+
+const (
+	fsrsDecay        = -0.5
+	fsrsFactor       = 19.0 / 81.0
+	fsrsMinStability = 0.01
+	fsrsMaxInterval  = 36500
+)
+
+// DefaultFsrsWeights are the published FSRS-4.5 defaults, used until a user
+// has enough review history to fit their own.
+var DefaultFsrsWeights = []float64{
+	0.4872, 1.4003, 3.7145, 13.8206,
+	5.1618, 1.2298, 0.8975, 0.031,
+	1.6474, 0.1367, 1.0461, 2.1072,
+	0.0793, 0.3246, 1.587, 0.2272,
+	2.8755,
+}
+
+var fsrsWeightBounds = [][2]float64{
+	{0.1, 100}, {0.1, 100}, {0.1, 100}, {0.1, 100},
+	{1, 10}, {0.1, 5}, {0.1, 5}, {0, 0.75},
+	{0, 4}, {0, 0.8}, {0.01, 3}, {0.5, 5},
+	{0.01, 0.2}, {0.01, 0.9}, {0.01, 2}, {0, 1},
+	{1, 6},
+}
+
+type FsrsState struct {
+	Stability  float64
+	Difficulty float64
+}
+
+type Fsrs struct {
+	Weights          []float64
+	DesiredRetention float64
+}
+
+func NewFsrs(weights []float64, desiredRetention float64) Fsrs {
+	if len(weights) != len(DefaultFsrsWeights) {
+		weights = DefaultFsrsWeights
+	}
+
+	return Fsrs{
+		Weights:          weights,
+		DesiredRetention: desiredRetention,
+	}
+}
+
+// GradeToRating maps the 0-5 SM-2 grade accepted by the API onto the four
+// FSRS ratings.
+func GradeToRating(grade int8) int {
+	switch {
+	case grade < GradeCorrectDifficult:
+		return RatingAgain
+	case grade == GradeCorrectDifficult:
+		return RatingHard
+	case grade == GradeCorrectHesitant:
+		return RatingGood
+	default:
+		return RatingEasy
+	}
+}
+
+func (f Fsrs) Retrievability(elapsedDays float64, stability float64) float64 {
+	return math.Pow(1+fsrsFactor*elapsedDays/stability, fsrsDecay)
+}
+
+func (f Fsrs) Interval(stability float64) int32 {
+	interval := stability / fsrsFactor * (math.Pow(f.DesiredRetention, 1/fsrsDecay) - 1)
+	return int32(clamp(math.Round(interval), 1, fsrsMaxInterval))
+}
+
+// Next returns the memory state after a review with the given rating. A nil
+// state means the word is being reviewed for the first time.
+func (f Fsrs) Next(state *FsrsState, elapsedDays float64, rating int) FsrsState {
+	w := f.Weights
+
+	if state == nil {
+		return FsrsState{
+			Stability:  math.Max(w[rating-1], fsrsMinStability),
+			Difficulty: f.initialDifficulty(rating),
+		}
+	}
+
+	r := f.Retrievability(elapsedDays, state.Stability)
+
+	var stability float64
+	if rating == RatingAgain {
+		stability = w[11] *
+			math.Pow(state.Difficulty, -w[12]) *
+			(math.Pow(state.Stability+1, w[13]) - 1) *
+			math.Exp(w[14]*(1-r))
+	} else {
+		hardPenalty, easyBonus := 1.0, 1.0
+		if rating == RatingHard {
+			hardPenalty = w[15]
+		}
+		if rating == RatingEasy {
+			easyBonus = w[16]
+		}
+
+		stability = state.Stability * (1 + math.Exp(w[8])*
+			(11-state.Difficulty)*
+			math.Pow(state.Stability, -w[9])*
+			(math.Exp(w[10]*(1-r))-1)*
+			hardPenalty*
+			easyBonus)
+	}
+
+	difficulty := state.Difficulty - w[6]*float64(rating-RatingGood)
+	difficulty = w[7]*f.initialDifficulty(RatingGood) + (1-w[7])*difficulty
+
+	return FsrsState{
+		Stability:  math.Max(stability, fsrsMinStability),
+		Difficulty: clamp(difficulty, 1, 10),
+	}
+}
+
+func (f Fsrs) initialDifficulty(rating int) float64 {
+	return clamp(f.Weights[4]-float64(rating-RatingGood)*f.Weights[5], 1, 10)
+}
+
+func clamp(value, lower, upper float64) float64 {
+	return math.Min(math.Max(value, lower), upper)
+}
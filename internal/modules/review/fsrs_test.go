@@ -0,0 +1,112 @@
+package review
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGradeToRating(t *testing.T) {
+	testCases := []struct {
+		grade    int8
+		expected int
+	}{
+		{GradeBlackout, RatingAgain},
+		{GradeIncorrect, RatingAgain},
+		{GradeIncorrectEasyRecall, RatingAgain},
+		{GradeCorrectDifficult, RatingHard},
+		{GradeCorrectHesitant, RatingGood},
+		{GradePerfect, RatingEasy},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, GradeToRating(tc.grade))
+	}
+}
+
+func TestFsrsRetrievabilityAtStability(t *testing.T) {
+	fsrs := NewFsrs(nil, 0.9)
+
+	assert.InDelta(t, 1.0, fsrs.Retrievability(0, 10), 0.0001)
+	assert.InDelta(t, 0.9, fsrs.Retrievability(10, 10), 0.0001)
+	assert.Equal(t, int32(10), fsrs.Interval(10))
+}
+
+func TestFsrsIntervalGrowsWithLowerRetention(t *testing.T) {
+	strict := NewFsrs(nil, 0.95)
+	relaxed := NewFsrs(nil, 0.8)
+
+	assert.Less(t, strict.Interval(20), relaxed.Interval(20))
+	assert.Equal(t, int32(1), strict.Interval(0.01))
+}
+
+func TestFsrsNext(t *testing.T) {
+	fsrs := NewFsrs(nil, 0.9)
+
+	initial := fsrs.Next(nil, 0, RatingGood)
+	assert.InDelta(t, DefaultFsrsWeights[2], initial.Stability, 0.0001)
+	assert.InDelta(t, DefaultFsrsWeights[4], initial.Difficulty, 0.0001)
+
+	recalled := fsrs.Next(&initial, initial.Stability, RatingGood)
+	assert.Greater(t, recalled.Stability, initial.Stability)
+
+	forgotten := fsrs.Next(&initial, initial.Stability, RatingAgain)
+	assert.Less(t, forgotten.Stability, initial.Stability)
+	assert.Greater(t, forgotten.Difficulty, initial.Difficulty)
+
+	easy := fsrs.Next(&initial, initial.Stability, RatingEasy)
+	assert.Greater(t, easy.Stability, recalled.Stability)
+	assert.Less(t, easy.Difficulty, initial.Difficulty)
+}
+
+func TestNewFsrsFallsBackToDefaultWeights(t *testing.T) {
+	fsrs := NewFsrs([]float64{1, 2, 3}, 0.9)
+	assert.Equal(t, DefaultFsrsWeights, fsrs.Weights)
+}
+
+func TestOptimizeFsrsWeightsReducesLoss(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+
+	// Simulate a learner who forgets noticeably faster than the defaults assume.
+	var histories [][]ReviewHistoryEntry
+	for range 60 {
+		history := []ReviewHistoryEntry{{Rating: RatingGood}}
+		stability := 1.0
+		for _, elapsed := range []float64{1, 3, 7, 14} {
+			recall := NewFsrs(nil, 0.9).Retrievability(elapsed, stability)
+			rating := RatingGood
+			if random.Float64() > recall {
+				rating = RatingAgain
+				stability = 1
+			} else {
+				stability *= 2
+			}
+			history = append(history, ReviewHistoryEntry{ElapsedDays: elapsed, Rating: rating})
+		}
+		histories = append(histories, history)
+	}
+
+	result := OptimizeFsrsWeights(histories, nil, 0.9)
+
+	assert.Equal(t, 240, result.ReviewCount)
+	assert.Equal(t, DefaultFsrsWeights, result.InitialWeights)
+	assert.Len(t, result.Weights, len(DefaultFsrsWeights))
+	assert.Less(t, result.LogLossAfter, result.LogLossBefore)
+	assert.Greater(t, result.CurrentDailyReviews, 0.0)
+	assert.Greater(t, result.OptimizedDailyReviews, 0.0)
+
+	for i, weight := range result.Weights {
+		assert.GreaterOrEqual(t, weight, fsrsWeightBounds[i][0])
+		assert.LessOrEqual(t, weight, fsrsWeightBounds[i][1])
+	}
+}
+
+func TestCountPredictableReviews(t *testing.T) {
+	histories := [][]ReviewHistoryEntry{
+		{{Rating: RatingGood}},
+		{{Rating: RatingGood}, {ElapsedDays: 1, Rating: RatingGood}, {ElapsedDays: 3, Rating: RatingAgain}},
+	}
+
+	assert.Equal(t, 2, CountPredictableReviews(histories))
+}
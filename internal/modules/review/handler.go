@@ -0,0 +1,168 @@
+package review
+
+import (
+	"lexia/internal/shared"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func handleGetDueWords(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var folderID *uuid.UUID
+		if folderIDStr := c.Query("folderId"); folderIDStr != "" {
+			parsed, err := uuid.Parse(folderIDStr)
+			if err != nil {
+				shared.ResBadRequest(c, "Invalid folder ID")
+				return
+			}
+			folderID = &parsed
+		}
+
+		limit := DefaultDueLimit
+		if limitStr := c.Query("limit"); limitStr != "" {
+			parsed, err := strconv.Atoi(limitStr)
+			if err != nil || parsed < 1 || parsed > MaxDueLimit {
+				shared.ResBadRequest(c, "Limit must be a number between 1 and "+strconv.Itoa(MaxDueLimit))
+				return
+			}
+			limit = parsed
+		}
+
+		words, err := GetDueWords(
+			c.Request.Context(),
+			apiCfg.DB,
+			GetDueWordsArgs{
+				UserID:   authPayload.UserID,
+				FolderID: folderID,
+				Limit:    limit,
+				Now:      time.Now(),
+			},
+		)
+
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, DueWordEntitiesToDTOs(words))
+	}
+}
+
+func handleSubmitReview(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		wordIDStr := c.Param("wordId")
+		wordID, err := uuid.Parse(wordIDStr)
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid word ID")
+			return
+		}
+
+		var body SubmitReviewDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		review, err := SubmitReview(
+			c.Request.Context(),
+			apiCfg.DB,
+			SubmitReviewArgs{
+				UserID: authPayload.UserID,
+				WordID: wordID,
+				Grade:  *body.Grade,
+				Now:    time.Now(),
+			},
+		)
+
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, ReviewEntityToDTO(wordID, review))
+	}
+}
+
+func handleGetSrsSettings(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		settings, err := GetSrsSettings(c.Request.Context(), apiCfg.DB, authPayload.UserID)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, SrsSettingsEntityToDTO(settings))
+	}
+}
+
+func handleUpdateSrsSettings(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body UpdateSrsSettingsDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		settings, err := UpdateSrsSettings(
+			c.Request.Context(),
+			apiCfg.DB,
+			UpdateSrsSettingsArgs{
+				UserID:           authPayload.UserID,
+				Scheduler:        body.Scheduler,
+				DesiredRetention: body.DesiredRetention,
+			},
+		)
+
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, SrsSettingsEntityToDTO(settings))
+	}
+}
+
+func handleOptimizeSrsSettings(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		result, settings, err := OptimizeSrsSettings(c.Request.Context(), apiCfg.DB, authPayload.UserID, time.Now())
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, OptimizationResultToDTO(result, settings))
+	}
+}
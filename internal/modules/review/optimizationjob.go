@@ -0,0 +1,122 @@
+package review
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/ent/srsoptimizationjob"
+	"lexia/internal/jobqueue"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	optimizationPollInterval = 5 * time.Second
+	// optimizationJobStaleAfter is how long a job may run before it is
+	// considered abandoned. Fitting the weights takes seconds, even for years
+	// of reviews.
+	optimizationJobStaleAfter = 30 * time.Minute
+)
+
+func createSrsOptimizationJob(ctx context.Context, db *ent.Client, userID uuid.UUID) (*ent.SrsOptimizationJob, error) {
+	return db.SrsOptimizationJob.Create().
+		SetUserID(userID).
+		Save(ctx)
+}
+
+// NewOptimizationWorker returns the runner for the optimization jobs
+// StartFsrsOptimizer queues.
+func NewOptimizationWorker(db *ent.Client) *jobqueue.Runner {
+	return jobqueue.NewRunner(
+		optimizationQueue{db: db},
+		jobqueue.Config{
+			Name:         "optimization",
+			PollInterval: optimizationPollInterval,
+			StaleAfter:   optimizationJobStaleAfter,
+		},
+	)
+}
+
+// optimizationQueue is the jobqueue.Queue of FSRS optimization jobs.
+type optimizationQueue struct {
+	db *ent.Client
+}
+
+func (q optimizationQueue) NextPending(ctx context.Context) (uuid.UUID, bool, error) {
+	jobID, err := q.db.SrsOptimizationJob.Query().
+		Where(srsoptimizationjob.StatusEQ(schema.SrsOptimizationJobStatusPending)).
+		Order(ent.Asc(srsoptimizationjob.FieldCreateTime)).
+		FirstID(ctx)
+	if ent.IsNotFound(err) {
+		return uuid.Nil, false, nil
+	}
+
+	return jobID, err == nil, err
+}
+
+func (q optimizationQueue) Claim(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	claimed, err := q.db.SrsOptimizationJob.Update().
+		Where(
+			srsoptimizationjob.ID(jobID),
+			srsoptimizationjob.StatusEQ(schema.SrsOptimizationJobStatusPending),
+		).
+		SetStatus(schema.SrsOptimizationJobStatusRunning).
+		Save(ctx)
+
+	return claimed > 0, err
+}
+
+// Run fits the weights and records the fit on the job.
+func (q optimizationQueue) Run(ctx context.Context, jobID uuid.UUID) error {
+	userEntity, err := q.db.SrsOptimizationJob.Query().
+		Where(srsoptimizationjob.ID(jobID)).
+		QueryUser().
+		Only(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, _, err := OptimizeSrsSettings(ctx, q.db, userEntity.ID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return q.db.SrsOptimizationJob.UpdateOneID(jobID).
+		SetPreviousWeights(result.InitialWeights).
+		SetReviewCount(int32(result.ReviewCount)).
+		SetLogLossBefore(result.LogLossBefore).
+		SetLogLossAfter(result.LogLossAfter).
+		SetCurrentDailyReviews(result.CurrentDailyReviews).
+		SetOptimizedDailyReviews(result.OptimizedDailyReviews).
+		Exec(ctx)
+}
+
+func (q optimizationQueue) Finish(ctx context.Context, jobID uuid.UUID, jobErr error) error {
+	update := q.db.SrsOptimizationJob.UpdateOneID(jobID).
+		SetFinishedAt(time.Now())
+
+	if jobErr != nil {
+		update = update.
+			SetStatus(schema.SrsOptimizationJobStatusFailed).
+			SetError(jobErr.Error())
+	} else {
+		update = update.SetStatus(schema.SrsOptimizationJobStatusCompleted)
+	}
+
+	return update.Exec(ctx)
+}
+
+func (q optimizationQueue) FailStale(ctx context.Context, before time.Time, now time.Time) error {
+	_, err := q.db.SrsOptimizationJob.Update().
+		Where(
+			srsoptimizationjob.StatusEQ(schema.SrsOptimizationJobStatusRunning),
+			srsoptimizationjob.UpdateTimeLT(before),
+		).
+		SetStatus(schema.SrsOptimizationJobStatusFailed).
+		SetError(jobqueue.StaleJobError).
+		SetFinishedAt(now).
+		Save(ctx)
+
+	return err
+}
@@ -0,0 +1,169 @@
+package review
+
+import (
+	"math"
+)
+
+const (
+	MinOptimizationReviews = 50
+
+	optimizerIterations   = 200
+	optimizerLearningRate = 0.02
+	optimizerBeta1        = 0.9
+	optimizerBeta2        = 0.999
+	optimizerEpsilon      = 1e-8
+)
+
+type ReviewHistoryEntry struct {
+	ElapsedDays float64
+	Rating      int
+}
+
+type OptimizationResult struct {
+	InitialWeights        []float64
+	Weights               []float64
+	ReviewCount           int
+	LogLossBefore         float64
+	LogLossAfter          float64
+	CurrentDailyReviews   float64
+	OptimizedDailyReviews float64
+}
+
+// CountPredictableReviews returns the number of reviews the optimiser can
+// learn from: every review of a word except its first one.
+func CountPredictableReviews(histories [][]ReviewHistoryEntry) int {
+	count := 0
+	for _, history := range histories {
+		if len(history) > 1 {
+			count += len(history) - 1
+		}
+	}
+	return count
+}
+
+// OptimizeFsrsWeights fits FSRS weights to the given per-word review histories
+// by minimising the log loss of predicted recall with Adam over numerical
+// gradients, starting from initialWeights.
+func OptimizeFsrsWeights(
+	histories [][]ReviewHistoryEntry,
+	initialWeights []float64,
+	desiredRetention float64,
+) OptimizationResult {
+	initial := NewFsrs(initialWeights, desiredRetention).Weights
+
+	weights := append([]float64(nil), initial...)
+	best := append([]float64(nil), initial...)
+	bestLoss := fsrsLogLoss(histories, best)
+	initialLoss := bestLoss
+
+	m := make([]float64, len(weights))
+	v := make([]float64, len(weights))
+
+	for iteration := 1; iteration <= optimizerIterations; iteration++ {
+		gradient := fsrsLossGradient(histories, weights)
+
+		for i := range weights {
+			m[i] = optimizerBeta1*m[i] + (1-optimizerBeta1)*gradient[i]
+			v[i] = optimizerBeta2*v[i] + (1-optimizerBeta2)*gradient[i]*gradient[i]
+
+			mHat := m[i] / (1 - math.Pow(optimizerBeta1, float64(iteration)))
+			vHat := v[i] / (1 - math.Pow(optimizerBeta2, float64(iteration)))
+
+			step := optimizerLearningRate * math.Max(math.Abs(initial[i]), 0.1)
+			weights[i] -= step * mHat / (math.Sqrt(vHat) + optimizerEpsilon)
+			weights[i] = clamp(weights[i], fsrsWeightBounds[i][0], fsrsWeightBounds[i][1])
+		}
+
+		if loss := fsrsLogLoss(histories, weights); loss < bestLoss {
+			bestLoss = loss
+			copy(best, weights)
+		}
+	}
+
+	return OptimizationResult{
+		InitialWeights:        initial,
+		Weights:               best,
+		ReviewCount:           CountPredictableReviews(histories),
+		LogLossBefore:         initialLoss,
+		LogLossAfter:          bestLoss,
+		CurrentDailyReviews:   EstimateDailyReviews(histories, initial, desiredRetention),
+		OptimizedDailyReviews: EstimateDailyReviews(histories, best, desiredRetention),
+	}
+}
+
+// EstimateDailyReviews approximates the steady-state number of reviews per day
+// for the given words, assuming each is reviewed once per scheduled interval.
+func EstimateDailyReviews(histories [][]ReviewHistoryEntry, weights []float64, desiredRetention float64) float64 {
+	fsrs := NewFsrs(weights, desiredRetention)
+
+	total := 0.0
+	for _, history := range histories {
+		state := replayHistory(fsrs, history)
+		if state == nil {
+			continue
+		}
+		total += 1 / float64(fsrs.Interval(state.Stability))
+	}
+
+	return total
+}
+
+func replayHistory(fsrs Fsrs, history []ReviewHistoryEntry) *FsrsState {
+	var state *FsrsState
+	for _, entry := range history {
+		next := fsrs.Next(state, entry.ElapsedDays, entry.Rating)
+		state = &next
+	}
+	return state
+}
+
+func fsrsLogLoss(histories [][]ReviewHistoryEntry, weights []float64) float64 {
+	fsrs := Fsrs{Weights: weights}
+
+	total := 0.0
+	count := 0
+
+	for _, history := range histories {
+		var state *FsrsState
+		for _, entry := range history {
+			if state != nil {
+				r := clamp(fsrs.Retrievability(entry.ElapsedDays, state.Stability), 1e-6, 1-1e-6)
+				if entry.Rating > RatingAgain {
+					total -= math.Log(r)
+				} else {
+					total -= math.Log(1 - r)
+				}
+				count++
+			}
+
+			next := fsrs.Next(state, entry.ElapsedDays, entry.Rating)
+			state = &next
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return total / float64(count)
+}
+
+func fsrsLossGradient(histories [][]ReviewHistoryEntry, weights []float64) []float64 {
+	gradient := make([]float64, len(weights))
+	probe := append([]float64(nil), weights...)
+
+	for i := range weights {
+		h := 1e-4 * math.Max(math.Abs(weights[i]), 1)
+
+		probe[i] = weights[i] + h
+		lossUp := fsrsLogLoss(histories, probe)
+
+		probe[i] = weights[i] - h
+		lossDown := fsrsLogLoss(histories, probe)
+
+		probe[i] = weights[i]
+		gradient[i] = (lossUp - lossDown) / (2 * h)
+	}
+
+	return gradient
+}
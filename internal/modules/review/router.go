@@ -0,0 +1,25 @@
+package review
+
+import (
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
+	read := shared.RequireScope(shared.ScopeReviewsRead)
+	write := shared.RequireScope(shared.ScopeReviewsWrite)
+
+	reviewGroup := rg.Group("/reviews")
+	{
+		reviewGroup.GET("/due", read, handleGetDueWords(apiCfg))
+		reviewGroup.POST("/:wordId", write, handleSubmitReview(apiCfg))
+	}
+
+	srsSettingsGroup := rg.Group("/user/srs-settings")
+	{
+		srsSettingsGroup.GET("", read, handleGetSrsSettings(apiCfg))
+		srsSettingsGroup.PUT("", write, handleUpdateSrsSettings(apiCfg))
+		srsSettingsGroup.POST("/optimize", write, handleOptimizeSrsSettings(apiCfg))
+	}
+}
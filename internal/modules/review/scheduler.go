@@ -0,0 +1,84 @@
+package review
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	GradeBlackout int8 = iota
+	GradeIncorrect
+	GradeIncorrectEasyRecall
+	GradeCorrectDifficult
+	GradeCorrectHesitant
+	GradePerfect
+)
+
+const (
+	defaultEase = 2.5
+	minimumEase = 1.3
+)
+
+type ReviewState struct {
+	Ease        float64
+	Interval    int32
+	Repetitions int32
+	Lapses      int32
+}
+
+func NewReviewState() ReviewState {
+	return ReviewState{
+		Ease: defaultEase,
+	}
+}
+
+func IsValidGrade(grade int8) bool {
+	return grade >= GradeBlackout && grade <= GradePerfect
+}
+
+// ScheduleSM2 applies the SuperMemo-2 algorithm to the given state. Grades
+// below GradeCorrectDifficult reset the repetition streak and count as a lapse.
+func ScheduleSM2(state ReviewState, grade int8) ReviewState {
+	next := state
+
+	if grade >= GradeCorrectDifficult {
+		switch state.Repetitions {
+		case 0:
+			next.Interval = 1
+		case 1:
+			next.Interval = 6
+		default:
+			next.Interval = int32(math.Round(float64(state.Interval) * state.Ease))
+		}
+		next.Repetitions = state.Repetitions + 1
+	} else {
+		next.Interval = 1
+		next.Repetitions = 0
+		next.Lapses = state.Lapses + 1
+	}
+
+	q := float64(GradePerfect - grade)
+	next.Ease = state.Ease + (0.1 - q*(0.08+q*0.02))
+	if next.Ease < minimumEase {
+		next.Ease = minimumEase
+	}
+
+	return next
+}
+
+func DueDate(reviewedAt time.Time, interval int32) time.Time {
+	return reviewedAt.AddDate(0, 0, int(interval))
+}
+
+func elapsedDays(lastReviewedAt *time.Time, now time.Time) int32 {
+	if lastReviewedAt == nil {
+		return 0
+	}
+
+	days := int32(now.Sub(*lastReviewedAt).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+
+	return days
+}
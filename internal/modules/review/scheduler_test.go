@@ -0,0 +1,82 @@
+package review
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleSM2_SuccessfulReviews(t *testing.T) {
+	state := NewReviewState()
+
+	state = ScheduleSM2(state, GradeCorrectHesitant)
+	assert.Equal(t, int32(1), state.Interval)
+	assert.Equal(t, int32(1), state.Repetitions)
+	assert.InDelta(t, 2.5, state.Ease, 0.0001)
+
+	state = ScheduleSM2(state, GradeCorrectHesitant)
+	assert.Equal(t, int32(6), state.Interval)
+	assert.Equal(t, int32(2), state.Repetitions)
+
+	state = ScheduleSM2(state, GradeCorrectHesitant)
+	assert.Equal(t, int32(15), state.Interval)
+	assert.Equal(t, int32(3), state.Repetitions)
+	assert.Equal(t, int32(0), state.Lapses)
+}
+
+func TestScheduleSM2_EaseAdjustment(t *testing.T) {
+	testCases := []struct {
+		name         string
+		grade        int8
+		expectedEase float64
+	}{
+		{"Perfect", GradePerfect, 2.6},
+		{"Hesitant", GradeCorrectHesitant, 2.5},
+		{"Difficult", GradeCorrectDifficult, 2.36},
+		{"Incorrect", GradeIncorrect, 1.96},
+		{"Blackout", GradeBlackout, 1.7},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := ScheduleSM2(NewReviewState(), tc.grade)
+			assert.InDelta(t, tc.expectedEase, state.Ease, 0.0001)
+		})
+	}
+}
+
+func TestScheduleSM2_LapseResetsStreak(t *testing.T) {
+	state := ReviewState{
+		Ease:        2.5,
+		Interval:    15,
+		Repetitions: 3,
+	}
+
+	state = ScheduleSM2(state, GradeIncorrect)
+	assert.Equal(t, int32(1), state.Interval)
+	assert.Equal(t, int32(0), state.Repetitions)
+	assert.Equal(t, int32(1), state.Lapses)
+}
+
+func TestScheduleSM2_MinimumEase(t *testing.T) {
+	state := ReviewState{Ease: minimumEase}
+
+	state = ScheduleSM2(state, GradeBlackout)
+	assert.Equal(t, minimumEase, state.Ease)
+}
+
+func TestIsValidGrade(t *testing.T) {
+	assert.True(t, IsValidGrade(GradeBlackout))
+	assert.True(t, IsValidGrade(GradePerfect))
+	assert.False(t, IsValidGrade(-1))
+	assert.False(t, IsValidGrade(6))
+}
+
+func TestElapsedDays(t *testing.T) {
+	now := time.Date(2025, 7, 10, 12, 0, 0, 0, time.UTC)
+	lastReviewedAt := now.AddDate(0, 0, -3)
+
+	assert.Equal(t, int32(0), elapsedDays(nil, now))
+	assert.Equal(t, int32(3), elapsedDays(&lastReviewedAt, now))
+}
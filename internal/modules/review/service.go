@@ -0,0 +1,380 @@
+package review
+
+import (
+	"context"
+	stdsql "database/sql"
+	"errors"
+	"lexia/ent"
+	"lexia/ent/folder"
+	"lexia/ent/predicate"
+	"lexia/ent/reviewlog"
+	"lexia/ent/schema"
+	"lexia/ent/srssettings"
+	"lexia/ent/user"
+	"lexia/ent/word"
+	"lexia/ent/wordreview"
+	"lexia/internal/authz"
+	foldermodule "lexia/internal/modules/folder"
+	wordmodule "lexia/internal/modules/word"
+	"lexia/internal/shared"
+	"log"
+	"math"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultDueLimit = 50
+	MaxDueLimit     = 500
+)
+
+type GetDueWordsArgs struct {
+	UserID   uuid.UUID
+	FolderID *uuid.UUID
+	Limit    int
+	Now      time.Time
+}
+
+type UpdateSrsSettingsArgs struct {
+	UserID           uuid.UUID
+	Scheduler        *schema.SrsScheduler
+	DesiredRetention *float64
+}
+
+type SubmitReviewArgs struct {
+	UserID uuid.UUID
+	WordID uuid.UUID
+	Grade  int8
+	Now    time.Time
+}
+
+func GetDueWords(
+	ctx context.Context,
+	db *ent.Client,
+	args GetDueWordsArgs,
+) ([]*ent.Word, error) {
+	// Reviews are the user's own, so a shared word is due for each user on
+	// their own schedule.
+	ownReview := wordreview.HasUserWith(user.ID(args.UserID))
+	predicates := []predicate.Word{
+		word.Or(
+			word.Not(word.HasReviewsWith(ownReview)),
+			word.HasReviewsWith(ownReview, wordreview.DueAtLTE(args.Now)),
+		),
+	}
+
+	// Without a folder only the user's own words are due; a folder shared
+	// with the user can be reviewed by asking for it.
+	if args.FolderID != nil {
+		folderIDs, err := foldermodule.GetFolderSubtreeIDs(ctx, db, *args.FolderID, args.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		predicates = append(predicates, word.HasFolderWith(folder.IDIn(folderIDs...)))
+	} else {
+		predicates = append(predicates, word.HasFolderWith(folder.HasUserWith(user.ID(args.UserID))))
+	}
+
+	query := db.Word.Query().
+		Where(predicates...).
+		Order(dueWordsOrder(args.UserID)).
+		WithFolder().
+		WithReviews(wordmodule.WithUserReview(args.UserID))
+	if args.Limit > 0 {
+		query = query.Limit(args.Limit)
+	}
+
+	words, err := query.All(ctx)
+	if err != nil {
+		log.Println("Error getting due words: ", err)
+		return nil, err
+	}
+
+	return words, nil
+}
+
+// dueWordsOrder puts the words that have been due longest first. Words that
+// were never reviewed are due from when they were added, as in the word
+// listing sorted by dueAt.
+func dueWordsOrder(userID uuid.UUID) func(s *sql.Selector) {
+	dueAt := wordmodule.DueAtExpr(userID)
+	return func(s *sql.Selector) {
+		s.OrderExpr(sql.Expr(dueAt(s)))
+		s.OrderExpr(sql.Expr(s.C(word.FieldID)))
+	}
+}
+
+// SubmitReview reschedules the user's own review of a word. Anyone who may
+// read the word can study it; the review does not change the word, so
+// studying a shared word leaves the owner's schedule alone.
+func SubmitReview(
+	ctx context.Context,
+	db *ent.Client,
+	args SubmitReviewArgs,
+) (*ent.WordReview, error) {
+	if !IsValidGrade(args.Grade) {
+		return nil, ErrInvalidGrade
+	}
+
+	if _, err := authz.CanReadWord(ctx, db, args.UserID, args.WordID); err != nil {
+		return nil, err
+	}
+
+	settings, err := GetSrsSettings(ctx, db, args.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviewEntity *ent.WordReview
+	err = shared.WithTx(ctx, db, func(client *ent.Client) error {
+		existing, err := lockWordReview(ctx, client, args.UserID, args.WordID, args.Now)
+		if err != nil {
+			log.Println("Error locking word review: ", err)
+			return err
+		}
+
+		state := ReviewState{
+			Ease:        existing.Ease,
+			Interval:    existing.Interval,
+			Repetitions: existing.Repetitions,
+			Lapses:      existing.Lapses,
+		}
+		var fsrsState *FsrsState
+		if existing.Stability != nil && existing.Difficulty != nil {
+			fsrsState = &FsrsState{
+				Stability:  *existing.Stability,
+				Difficulty: *existing.Difficulty,
+			}
+		}
+
+		var elapsed float64
+		if existing.LastReviewedAt != nil {
+			elapsed = math.Max(args.Now.Sub(*existing.LastReviewedAt).Hours()/24, 0)
+		}
+
+		fsrs := NewFsrs(settings.FsrsWeights, settings.DesiredRetention)
+		nextFsrs := fsrs.Next(fsrsState, elapsed, GradeToRating(args.Grade))
+
+		next := ScheduleSM2(state, args.Grade)
+		if settings.Scheduler == schema.SrsSchedulerFSRS {
+			next.Interval = fsrs.Interval(nextFsrs.Stability)
+		}
+		dueAt := DueDate(args.Now, next.Interval)
+
+		reviewEntity, err = client.WordReview.UpdateOneID(existing.ID).
+			SetEase(next.Ease).
+			SetInterval(next.Interval).
+			SetRepetitions(next.Repetitions).
+			SetLapses(next.Lapses).
+			SetStability(nextFsrs.Stability).
+			SetDifficulty(nextFsrs.Difficulty).
+			SetDueAt(dueAt).
+			SetLastReviewedAt(args.Now).
+			Save(ctx)
+		if err != nil {
+			log.Println("Error saving word review: ", err)
+			return err
+		}
+
+		_, err = client.ReviewLog.Create().
+			SetWordID(args.WordID).
+			SetUserID(args.UserID).
+			SetGrade(args.Grade).
+			SetEase(next.Ease).
+			SetInterval(next.Interval).
+			SetPreviousInterval(state.Interval).
+			SetElapsedDays(elapsedDays(existing.LastReviewedAt, args.Now)).
+			SetReviewedAt(args.Now).
+			Save(ctx)
+		if err != nil {
+			log.Println("Error creating review log: ", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewEntity, nil
+}
+
+// lockWordReview returns the user's review of the word locked until the
+// transaction ends, so concurrent reviews of the word are applied one after
+// the other. A word that was never reviewed gets a fresh review first, which
+// of two first reviews at once only one of them inserts.
+func lockWordReview(
+	ctx context.Context,
+	client *ent.Client,
+	userID uuid.UUID,
+	wordID uuid.UUID,
+	now time.Time,
+) (*ent.WordReview, error) {
+	err := client.WordReview.Create().
+		SetWordID(wordID).
+		SetUserID(userID).
+		SetDueAt(now).
+		OnConflictColumns(wordreview.UserColumn, wordreview.WordColumn).
+		DoNothing().
+		Exec(ctx)
+	// With nothing inserted there is no ID to return, which ent reports as no
+	// rows.
+	if err != nil && !errors.Is(err, stdsql.ErrNoRows) {
+		return nil, err
+	}
+
+	return client.WordReview.Query().
+		Where(
+			wordreview.HasWordWith(word.ID(wordID)),
+			wordreview.HasUserWith(user.ID(userID)),
+		).
+		ForUpdate().
+		Only(ctx)
+}
+
+func GetSrsSettings(
+	ctx context.Context,
+	db *ent.Client,
+	userID uuid.UUID,
+) (*ent.SrsSettings, error) {
+	settings, err := db.SrsSettings.Query().
+		Where(srssettings.HasUserWith(user.ID(userID))).
+		Only(ctx)
+	if err == nil {
+		return settings, nil
+	}
+
+	if !ent.IsNotFound(err) {
+		log.Println("Error getting srs settings: ", err)
+		return nil, err
+	}
+
+	settings, err = db.SrsSettings.Create().
+		SetUserID(userID).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		// Another request created the settings concurrently.
+		return db.SrsSettings.Query().
+			Where(srssettings.HasUserWith(user.ID(userID))).
+			Only(ctx)
+	}
+
+	if err != nil {
+		log.Println("Error creating srs settings: ", err)
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func UpdateSrsSettings(
+	ctx context.Context,
+	db *ent.Client,
+	args UpdateSrsSettingsArgs,
+) (*ent.SrsSettings, error) {
+	settings, err := GetSrsSettings(ctx, db, args.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	mutation := db.SrsSettings.UpdateOneID(settings.ID)
+
+	if args.Scheduler != nil {
+		mutation = mutation.SetScheduler(*args.Scheduler)
+	}
+
+	if args.DesiredRetention != nil {
+		mutation = mutation.SetDesiredRetention(*args.DesiredRetention)
+	}
+
+	updatedSettings, err := mutation.Save(ctx)
+	if err != nil {
+		log.Println("Error updating srs settings: ", err)
+		return nil, err
+	}
+
+	return updatedSettings, nil
+}
+
+func OptimizeSrsSettings(
+	ctx context.Context,
+	db *ent.Client,
+	userID uuid.UUID,
+	now time.Time,
+) (*OptimizationResult, *ent.SrsSettings, error) {
+	settings, err := GetSrsSettings(ctx, db, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	histories, err := getUserReviewHistories(ctx, db, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if CountPredictableReviews(histories) < MinOptimizationReviews {
+		return nil, nil, ErrNotEnoughReviewHistory
+	}
+
+	result := OptimizeFsrsWeights(histories, settings.FsrsWeights, settings.DesiredRetention)
+
+	updatedSettings, err := db.SrsSettings.UpdateOneID(settings.ID).
+		SetFsrsWeights(result.Weights).
+		SetOptimizedAt(now).
+		SetOptimizedReviewCount(int32(result.ReviewCount)).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error saving optimized srs settings: ", err)
+		return nil, nil, err
+	}
+
+	return &result, updatedSettings, nil
+}
+
+func getUserReviewHistories(
+	ctx context.Context,
+	db *ent.Client,
+	userID uuid.UUID,
+) ([][]ReviewHistoryEntry, error) {
+	logs, err := db.ReviewLog.Query().
+		Where(reviewlog.HasUserWith(user.ID(userID))).
+		WithWord(func(q *ent.WordQuery) {
+			q.Select(word.FieldID)
+		}).
+		Order(ent.Asc(reviewlog.FieldReviewedAt)).
+		All(ctx)
+	if err != nil {
+		log.Println("Error getting review logs: ", err)
+		return nil, err
+	}
+
+	var histories [][]ReviewHistoryEntry
+	historyIndex := map[uuid.UUID]int{}
+	lastReviewedAt := map[uuid.UUID]time.Time{}
+
+	for _, reviewLog := range logs {
+		wordID := reviewLog.Edges.Word.ID
+
+		entry := ReviewHistoryEntry{
+			Rating: GradeToRating(reviewLog.Grade),
+		}
+		if previous, ok := lastReviewedAt[wordID]; ok {
+			entry.ElapsedDays = math.Max(reviewLog.ReviewedAt.Sub(previous).Hours()/24, 0)
+		}
+		lastReviewedAt[wordID] = reviewLog.ReviewedAt
+
+		index, ok := historyIndex[wordID]
+		if !ok {
+			index = len(histories)
+			historyIndex[wordID] = index
+			histories = append(histories, nil)
+		}
+		histories[index] = append(histories[index], entry)
+	}
+
+	return histories, nil
+}
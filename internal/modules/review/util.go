@@ -0,0 +1,73 @@
+package review
+
+import (
+	"lexia/ent"
+	"lexia/internal/modules/word"
+
+	"github.com/google/uuid"
+)
+
+func ReviewEntityToDTO(wordID uuid.UUID, reviewEntity *ent.WordReview) ReviewStateDTO {
+	return ReviewStateDTO{
+		WordID:         wordID,
+		Ease:           reviewEntity.Ease,
+		Interval:       reviewEntity.Interval,
+		Repetitions:    reviewEntity.Repetitions,
+		Lapses:         reviewEntity.Lapses,
+		DueAt:          reviewEntity.DueAt,
+		LastReviewedAt: reviewEntity.LastReviewedAt,
+	}
+}
+
+func DueWordEntityToDTO(wordEntity *ent.Word) DueWordDTO {
+	dto := DueWordDTO{
+		Word: word.WordEntityToDTO(wordEntity),
+	}
+
+	if reviewEntity := word.UserReview(wordEntity); reviewEntity != nil {
+		reviewDTO := ReviewEntityToDTO(wordEntity.ID, reviewEntity)
+		dto.Review = &reviewDTO
+	}
+
+	return dto
+}
+
+func DueWordEntitiesToDTOs(wordEntities []*ent.Word) []DueWordDTO {
+	dtos := make([]DueWordDTO, len(wordEntities))
+	for i, wordEntity := range wordEntities {
+		dtos[i] = DueWordEntityToDTO(wordEntity)
+	}
+	return dtos
+}
+
+func SrsSettingsEntityToDTO(settings *ent.SrsSettings) SrsSettingsDTO {
+	weights := settings.FsrsWeights
+	if len(weights) == 0 {
+		weights = DefaultFsrsWeights
+	}
+
+	return SrsSettingsDTO{
+		Scheduler:        settings.Scheduler,
+		DesiredRetention: settings.DesiredRetention,
+		FsrsWeights:      weights,
+		OptimizedAt:      settings.OptimizedAt,
+	}
+}
+
+func OptimizationResultToDTO(result *OptimizationResult, settings *ent.SrsSettings) OptimizeSrsSettingsResponseDTO {
+	var workloadChange float64
+	if result.CurrentDailyReviews > 0 {
+		workloadChange = (result.OptimizedDailyReviews - result.CurrentDailyReviews) / result.CurrentDailyReviews * 100
+	}
+
+	return OptimizeSrsSettingsResponseDTO{
+		Settings:              SrsSettingsEntityToDTO(settings),
+		PreviousWeights:       result.InitialWeights,
+		ReviewCount:           result.ReviewCount,
+		LogLossBefore:         result.LogLossBefore,
+		LogLossAfter:          result.LogLossAfter,
+		CurrentDailyReviews:   result.CurrentDailyReviews,
+		OptimizedDailyReviews: result.OptimizedDailyReviews,
+		WorkloadChangePercent: workloadChange,
+	}
+}
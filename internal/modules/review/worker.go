@@ -0,0 +1,114 @@
+package review
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/ent/srssettings"
+	"lexia/internal/logger"
+	"lexia/internal/service"
+	"lexia/internal/shared"
+	"time"
+)
+
+const DefaultOptimizerInterval = 24 * time.Hour
+
+// fsrsOptimizerLockKey names the advisory lock held while users are queued
+// for optimization, so replicas do not scan at the same time.
+const fsrsOptimizerLockKey int64 = 0x667372736f707431
+
+const tryAdvisoryLockQuery = `SELECT pg_try_advisory_xact_lock($1)`
+
+// StartFsrsOptimizer queues an optimization job for every user on the FSRS
+// scheduler who has accumulated enough new reviews since their last fit,
+// once at start and then every interval, and wakes optimizer to run them.
+// Every replica runs it on its own schedule. The advisory lock only keeps
+// their scans from overlapping; a user is never queued twice because the
+// database allows one pending or running job per user. It returns when ctx
+// is cancelled.
+func StartFsrsOptimizer(ctx context.Context, db *ent.Client, optimizer service.QueueWorker, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		queuePendingOptimizations(ctx, db)
+		optimizer.Notify()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// queuePendingOptimizations holds the lock in a transaction for as long as it
+// scans. Jobs are queued outside that transaction, so a user who already has
+// one does not abort it.
+func queuePendingOptimizations(ctx context.Context, db *ent.Client) {
+	err := shared.WithTx(ctx, db, func(client *ent.Client) error {
+		locked, err := tryAdvisoryLock(ctx, client, fsrsOptimizerLockKey)
+		if err != nil || !locked {
+			return err
+		}
+
+		queueOptimizations(ctx, db)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to take the optimizer lock: ", err)
+	}
+}
+
+func queueOptimizations(ctx context.Context, db *ent.Client) {
+	settingsList, err := db.SrsSettings.Query().
+		Where(srssettings.SchedulerEQ(schema.SrsSchedulerFSRS)).
+		WithUser().
+		All(ctx)
+	if err != nil {
+		logger.Error("Failed to load srs settings for optimization: ", err)
+		return
+	}
+
+	for _, settings := range settingsList {
+		if ctx.Err() != nil {
+			return
+		}
+
+		userID := settings.Edges.User.ID
+
+		histories, err := getUserReviewHistories(ctx, db, userID)
+		if err != nil {
+			logger.Error("Failed to load review history for user ", userID, ": ", err)
+			continue
+		}
+
+		newReviews := CountPredictableReviews(histories) - int(settings.OptimizedReviewCount)
+		if newReviews < MinOptimizationReviews {
+			continue
+		}
+
+		// A constraint error means the user already has a job waiting.
+		_, err = createSrsOptimizationJob(ctx, db, userID)
+		if err != nil && !ent.IsConstraintError(err) {
+			logger.Error("Failed to queue optimization for user ", userID, ": ", err)
+		}
+	}
+}
+
+func tryAdvisoryLock(ctx context.Context, client *ent.Client, key int64) (bool, error) {
+	rows, err := client.QueryContext(ctx, tryAdvisoryLockQuery, key)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var locked bool
+	if rows.Next() {
+		if err := rows.Scan(&locked); err != nil {
+			return false, err
+		}
+	}
+
+	return locked, rows.Err()
+}
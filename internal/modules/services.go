@@ -0,0 +1,31 @@
+package modules
+
+import (
+	"lexia/ent"
+	"lexia/internal/modules/folder"
+	"lexia/internal/modules/review"
+	"lexia/internal/modules/translate"
+	"lexia/internal/modules/word"
+	"lexia/internal/shared"
+)
+
+// NewServices builds the services handlers use. It runs once at startup, so
+// keys are loaded and configuration is read before the first request.
+func NewServices(db *ent.Client, envVars *shared.EnvVariables) (*shared.Services, error) {
+	tokens, err := shared.NewTokenService(shared.TokenConfigFromEnv(envVars))
+	if err != nil {
+		return nil, err
+	}
+
+	translations := translate.NewService(db, translate.ConfigFromEnv(envVars))
+	autofill := word.NewAutofillWorker(db, translations)
+
+	return &shared.Services{
+		Tokens:         tokens,
+		Folders:        folder.NewService(db),
+		Words:          word.NewService(db, translations, autofill),
+		Translations:   translations,
+		AutofillWorker: autofill,
+		SrsOptimizer:   review.NewOptimizationWorker(db),
+	}, nil
+}
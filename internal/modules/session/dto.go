@@ -0,0 +1,18 @@
+package session
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionDTO struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	DeviceName string    `json:"deviceName"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"userAgent"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
@@ -0,0 +1,11 @@
+package session
+
+import (
+	"lexia/internal/apperr"
+	"lexia/internal/shared"
+)
+
+var (
+	ErrInvalidRefreshToken = apperr.Unauthorized(shared.ErrInvalidRefreshToken, "Refresh token is invalid or has expired")
+	ErrSessionNotFound     = apperr.NotFound("SESSION_NOT_FOUND", "Session not found")
+)
@@ -0,0 +1,53 @@
+package session
+
+import (
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func handleGetSessions(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		sessions, err := GetUserSessions(c.Request.Context(), apiCfg.DB, authPayload.UserID)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, SessionEntitiesToDtos(sessions, authPayload.SessionID))
+	}
+}
+
+func handleRevokeSession(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		sessionID, err := uuid.Parse(c.Param("sessionId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid session ID")
+			return
+		}
+
+		err = RevokeUserSession(c.Request.Context(), apiCfg.DB, RevokeUserSessionArgs{
+			SessionID: sessionID,
+			UserID:    authPayload.UserID,
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResNoContent(c)
+	}
+}
@@ -0,0 +1,15 @@
+package session
+
+import (
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
+	sessionGroup := rg.Group("/user/sessions")
+	{
+		sessionGroup.GET("", handleGetSessions(apiCfg))
+		sessionGroup.DELETE("/:sessionId", handleRevokeSession(apiCfg))
+	}
+}
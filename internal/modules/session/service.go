@@ -0,0 +1,226 @@
+package session
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/session"
+	"lexia/ent/user"
+	"lexia/internal/shared"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ClientInfo struct {
+	DeviceName string
+	IP         string
+	UserAgent  string
+}
+
+type CreateSessionArgs struct {
+	UserID uuid.UUID
+	Client ClientInfo
+	// TTL is how long the refresh token stays valid.
+	TTL time.Duration
+}
+
+// CreateSession starts a session for a freshly authenticated user and
+// returns it together with its plaintext refresh token, which is never
+// stored.
+func CreateSession(
+	ctx context.Context,
+	db *ent.Client,
+	args CreateSessionArgs,
+) (*ent.Session, string, error) {
+	refreshToken, err := shared.GenerateOpaqueToken()
+	if err != nil {
+		log.Println("Error generating refresh token: ", err)
+		return nil, "", err
+	}
+
+	now := time.Now()
+	sessionEntity, err := db.Session.Create().
+		SetUserID(args.UserID).
+		SetRefreshTokenHash(shared.HashOpaqueToken(refreshToken)).
+		SetDeviceName(args.Client.DeviceName).
+		SetIP(args.Client.IP).
+		SetUserAgent(args.Client.UserAgent).
+		SetLastUsedAt(now).
+		SetExpiresAt(now.Add(args.TTL)).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error creating session: ", err)
+		return nil, "", err
+	}
+
+	return sessionEntity, refreshToken, nil
+}
+
+type RotateSessionArgs struct {
+	RefreshToken string
+	Client       ClientInfo
+}
+
+// RotateSession exchanges a refresh token for a new one on the same session.
+// Presenting a token that was already rotated means it leaked, so the whole
+// session is revoked.
+func RotateSession(
+	ctx context.Context,
+	db *ent.Client,
+	args RotateSessionArgs,
+) (*ent.Session, string, error) {
+	tokenHash := shared.HashOpaqueToken(args.RefreshToken)
+	now := time.Now()
+
+	sessionEntity, err := db.Session.Query().
+		Where(session.RefreshTokenHash(tokenHash)).
+		WithUser().
+		Only(ctx)
+	if err != nil {
+		if !ent.IsNotFound(err) {
+			log.Println("Error finding session: ", err)
+			return nil, "", err
+		}
+
+		revokeReplayedSession(ctx, db, tokenHash, now)
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	if sessionEntity.RevokedAt != nil || !sessionEntity.ExpiresAt.After(now) {
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	refreshToken, err := shared.GenerateOpaqueToken()
+	if err != nil {
+		log.Println("Error generating refresh token: ", err)
+		return nil, "", err
+	}
+
+	update := db.Session.UpdateOneID(sessionEntity.ID).
+		Where(session.RefreshTokenHash(tokenHash)).
+		SetRefreshTokenHash(shared.HashOpaqueToken(refreshToken)).
+		SetPreviousRefreshTokenHash(tokenHash).
+		SetLastUsedAt(now)
+	if args.Client.IP != "" {
+		update = update.SetIP(args.Client.IP)
+	}
+	if args.Client.UserAgent != "" {
+		update = update.SetUserAgent(args.Client.UserAgent)
+	}
+
+	updatedSession, err := update.Save(ctx)
+	if err != nil {
+		// Another request rotated the same token first.
+		if ent.IsNotFound(err) {
+			return nil, "", ErrInvalidRefreshToken
+		}
+		log.Println("Error rotating session: ", err)
+		return nil, "", err
+	}
+	updatedSession.Edges.User = sessionEntity.Edges.User
+
+	return updatedSession, refreshToken, nil
+}
+
+func revokeReplayedSession(ctx context.Context, db *ent.Client, tokenHash string, now time.Time) {
+	revoked, err := db.Session.Update().
+		Where(
+			session.PreviousRefreshTokenHash(tokenHash),
+			session.RevokedAtIsNil(),
+		).
+		SetRevokedAt(now).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error revoking replayed session: ", err)
+		return
+	}
+
+	if revoked > 0 {
+		log.Println("Revoked session after refresh token reuse")
+	}
+}
+
+// RevokeSessionByRefreshToken ends the session a refresh token belongs to.
+// Unknown or already revoked tokens are ignored so logout is idempotent.
+func RevokeSessionByRefreshToken(ctx context.Context, db *ent.Client, refreshToken string) error {
+	_, err := db.Session.Update().
+		Where(
+			session.RefreshTokenHash(shared.HashOpaqueToken(refreshToken)),
+			session.RevokedAtIsNil(),
+		).
+		SetRevokedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error revoking session: ", err)
+	}
+
+	return err
+}
+
+func GetUserSessions(ctx context.Context, db *ent.Client, userID uuid.UUID) ([]*ent.Session, error) {
+	sessions, err := db.Session.Query().
+		Where(
+			session.HasUserWith(user.ID(userID)),
+			session.RevokedAtIsNil(),
+			session.ExpiresAtGT(time.Now()),
+		).
+		Order(ent.Desc(session.FieldLastUsedAt)).
+		All(ctx)
+	if err != nil {
+		log.Println("Error getting user sessions: ", err)
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+type RevokeUserSessionArgs struct {
+	SessionID uuid.UUID
+	UserID    uuid.UUID
+}
+
+func RevokeUserSession(ctx context.Context, db *ent.Client, args RevokeUserSessionArgs) error {
+	revoked, err := db.Session.Update().
+		Where(
+			session.ID(args.SessionID),
+			session.HasUserWith(user.ID(args.UserID)),
+			session.RevokedAtIsNil(),
+		).
+		SetRevokedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error revoking session: ", err)
+		return err
+	}
+
+	if revoked == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+type RevokeUserSessionsArgs struct {
+	UserID uuid.UUID
+	// ExceptSessionID keeps one session alive, usually the caller's own.
+	ExceptSessionID uuid.UUID
+}
+
+// RevokeUserSessions ends every active session of a user, for example after
+// the password has changed.
+func RevokeUserSessions(ctx context.Context, db *ent.Client, args RevokeUserSessionsArgs) error {
+	_, err := db.Session.Update().
+		Where(
+			session.HasUserWith(user.ID(args.UserID)),
+			session.IDNEQ(args.ExceptSessionID),
+			session.RevokedAtIsNil(),
+		).
+		SetRevokedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error revoking user sessions: ", err)
+	}
+
+	return err
+}
@@ -0,0 +1,28 @@
+package session
+
+import (
+	"lexia/ent"
+
+	"github.com/google/uuid"
+)
+
+func SessionEntityToDto(sessionEntity *ent.Session, currentSessionID uuid.UUID) SessionDTO {
+	return SessionDTO{
+		ID:         sessionEntity.ID,
+		CreatedAt:  sessionEntity.CreateTime,
+		DeviceName: sessionEntity.DeviceName,
+		IP:         sessionEntity.IP,
+		UserAgent:  sessionEntity.UserAgent,
+		LastUsedAt: sessionEntity.LastUsedAt,
+		ExpiresAt:  sessionEntity.ExpiresAt,
+		Current:    sessionEntity.ID == currentSessionID,
+	}
+}
+
+func SessionEntitiesToDtos(sessionEntities []*ent.Session, currentSessionID uuid.UUID) []SessionDTO {
+	dtos := make([]SessionDTO, len(sessionEntities))
+	for i, sessionEntity := range sessionEntities {
+		dtos[i] = SessionEntityToDto(sessionEntity, currentSessionID)
+	}
+	return dtos
+}
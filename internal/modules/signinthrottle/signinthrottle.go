@@ -0,0 +1,150 @@
+// Package signinthrottle throttles guessing of an account's credentials.
+// Passwords and second-factor codes count against the same keys, so knowing
+// the password does not buy an attacker fresh attempts at the second factor.
+package signinthrottle
+
+import (
+	"context"
+	"lexia/ent/schema"
+	"lexia/internal/apperr"
+	"lexia/internal/modules/audit"
+	"lexia/internal/modules/session"
+	"lexia/internal/shared"
+	"lexia/internal/throttle"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Accounts get a few free tries, then the wait doubles with every failure
+// until the account is locked for accountPolicy.MaxDelay. IPs are allowed
+// more failures because many users can share one address.
+var (
+	accountPolicy = throttle.Policy{
+		FreeAttempts: 5,
+		BaseDelay:    time.Second,
+		MaxDelay:     15 * time.Minute,
+		ResetAfter:   time.Hour,
+	}
+	ipPolicy = throttle.Policy{
+		FreeAttempts: 20,
+		BaseDelay:    time.Second,
+		MaxDelay:     time.Hour,
+		ResetAfter:   time.Hour,
+	}
+)
+
+type Keys struct {
+	account throttle.Key
+	ip      throttle.Key
+}
+
+func GetKeys(email string, ip string) Keys {
+	return Keys{
+		account: throttle.Key{
+			ID:     "signin:account:" + strings.ToLower(strings.TrimSpace(email)),
+			Policy: accountPolicy,
+		},
+		ip: throttle.Key{
+			ID:     "signin:ip:" + ip,
+			Policy: ipPolicy,
+		},
+	}
+}
+
+func errTooManyAttempts(wait time.Duration) error {
+	return apperr.TooManyRequests(shared.ErrTooManyAttempts, "Too many failed attempts, try again later", wait)
+}
+
+// Attempt counts the attempt against the IP and the account before the
+// credentials are checked, and refuses it while either still has to wait.
+// The IP goes first so a throttled IP does not add to the account's count.
+func Attempt(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	keys Keys,
+	email string,
+	client session.ClientInfo,
+) error {
+	wait, err := apiCfg.Throttler.Attempt(ctx, keys.ip, keys.account)
+	if err != nil {
+		log.Println("Error recording sign-in attempt: ", err)
+		return err
+	}
+
+	if wait == 0 {
+		return nil
+	}
+
+	audit.Record(ctx, apiCfg.DB, audit.RecordArgs{
+		Event:     schema.AuditEventSignInThrottled,
+		Email:     email,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		Details:   map[string]any{"retryAfterSeconds": int64(wait.Seconds())},
+	})
+
+	return errTooManyAttempts(wait)
+}
+
+// RecordFailure audits wrong credentials, which Attempt already counted.
+// Unknown emails are counted too, so throttling does not reveal which
+// accounts exist.
+func RecordFailure(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	keys Keys,
+	email string,
+	userID *uuid.UUID,
+	client session.ClientInfo,
+) {
+	wait, err := apiCfg.Throttler.Wait(ctx, keys.account)
+	if err != nil {
+		log.Println("Error checking sign-in throttle: ", err)
+	}
+
+	audit.Record(ctx, apiCfg.DB, audit.RecordArgs{
+		Event:     schema.AuditEventSignInFailed,
+		UserID:    userID,
+		Email:     email,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	})
+
+	if wait >= accountPolicy.MaxDelay {
+		audit.Record(ctx, apiCfg.DB, audit.RecordArgs{
+			Event:     schema.AuditEventAccountLocked,
+			UserID:    userID,
+			Email:     email,
+			IP:        client.IP,
+			UserAgent: client.UserAgent,
+			Details:   map[string]any{"lockedForSeconds": int64(wait.Seconds())},
+		})
+	}
+}
+
+// Forgive takes back the attempt Attempt counted, and leaves earlier
+// failures alone. It is for a correct password that still has to be
+// followed by a second factor.
+func Forgive(apiCfg *shared.ApiConfig, ctx context.Context, keys Keys) {
+	for _, key := range []throttle.Key{keys.account, keys.ip} {
+		if err := apiCfg.Throttler.Forgive(ctx, key); err != nil {
+			log.Println("Error forgiving sign-in attempt: ", err)
+		}
+	}
+}
+
+// Reset clears the account's failures once the user has fully proven who
+// they are, and takes back the attempt counted against the IP. The IP keeps
+// its earlier failures so one valid account cannot be used to unlock
+// guessing at others.
+func Reset(apiCfg *shared.ApiConfig, ctx context.Context, keys Keys) {
+	if err := apiCfg.Throttler.Reset(ctx, keys.account); err != nil {
+		log.Println("Error resetting sign-in throttle: ", err)
+	}
+	if err := apiCfg.Throttler.Forgive(ctx, keys.ip); err != nil {
+		log.Println("Error resetting sign-in throttle: ", err)
+	}
+}
@@ -0,0 +1,185 @@
+package translate
+
+import (
+	"context"
+	"lexia/ent/schema"
+	"lexia/internal/modules/languages"
+	"lexia/internal/service"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	MaxBatchTranslateItems = 500
+
+	// Chunks stay under the strictest provider limits (DeepL accepts 50
+	// texts per request, Google recommends at most 30K characters).
+	maxBatchChunkItems = 50
+	maxBatchChunkChars = 20000
+)
+
+type BatchTranslationResult = service.BatchTranslationResult
+
+// TranslateBatch translates many texts for one language pair. Invalid items
+// and upstream failures are reported on the item they belong to, so one bad
+// text does not fail the whole batch. Only the primary translation is
+// returned; cached entries are reused but not written, since the cache holds
+// full variant lists.
+func (s *entService) TranslateBatch(
+	ctx context.Context,
+	texts []string,
+	from schema.Language,
+	to schema.Language,
+) ([]BatchTranslationResult, error) {
+	if len(texts) > MaxBatchTranslateItems {
+		return nil, NewTranslationError(
+			"TOO_MANY_ITEMS",
+			"Too many texts in one batch",
+			"",
+		)
+	}
+
+	if languages.Normalize(from) == languages.Normalize(to) {
+		return nil, NewTranslationError(
+			"SAME_LANGUAGE",
+			"Source and target languages cannot be the same",
+			"",
+		)
+	}
+
+	if err := s.resolveLanguagePair(ctx, from, to); err != nil {
+		return nil, err
+	}
+
+	fromLang, err := languageTag(from)
+	if err != nil {
+		return nil, NewUnsupportedLanguageError(string(from))
+	}
+
+	toLang, err := languageTag(to)
+	if err != nil {
+		return nil, NewUnsupportedLanguageError(string(to))
+	}
+
+	results := make([]BatchTranslationResult, len(texts))
+	pending := map[string][]int{}
+	var pendingTexts []string
+
+	for i, text := range texts {
+		results[i].Text = text
+
+		if err := validateTranslationInput(text, from, to); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		text = strings.TrimSpace(text)
+		if _, ok := pending[text]; !ok {
+			pendingTexts = append(pendingTexts, text)
+		}
+		pending[text] = append(pending[text], i)
+	}
+
+	if len(pendingTexts) == 0 {
+		return results, nil
+	}
+
+	cached := getCachedTranslations(ctx, s.db, pendingTexts, from, to, s.config.Provider, time.Now())
+
+	uncachedTexts := pendingTexts[:0]
+	for _, text := range pendingTexts {
+		variants := cached[normalizeCacheText(text)]
+		if len(variants) == 0 {
+			uncachedTexts = append(uncachedTexts, text)
+			continue
+		}
+
+		for _, index := range pending[text] {
+			results[index].Translation = variants[0].Text
+		}
+	}
+	pendingTexts = uncachedTexts
+
+	if len(pendingTexts) == 0 {
+		return results, nil
+	}
+
+	if s.providerErr != nil {
+		return nil, s.providerErr
+	}
+	provider := s.provider
+
+	for _, chunk := range chunkBatchTexts(pendingTexts) {
+		translations, err := translateBatchChunk(ctx, provider, chunk, fromLang, toLang)
+
+		for i, text := range chunk {
+			for _, index := range pending[text] {
+				switch {
+				case err != nil:
+					results[index].Err = err
+				case i < len(translations):
+					results[index].Translation = translations[i].Text
+				default:
+					results[index].Err = NewTranslationError(
+						"NO_RESULTS",
+						"No translation results returned from the translation provider",
+						provider.Name(),
+					)
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func translateBatchChunk(
+	ctx context.Context,
+	provider Provider,
+	texts []string,
+	fromLang, toLang language.Tag,
+) ([]ProviderTranslation, *TranslationError) {
+	translations, err := performTranslationWithRetry(PerformTranslationWithRetryArgs{
+		ctx:        ctx,
+		provider:   provider,
+		texts:      texts,
+		fromLang:   fromLang,
+		toLang:     toLang,
+		maxRetries: 3,
+	})
+	if err != nil {
+		if translationErr, ok := err.(*TranslationError); ok {
+			return nil, translationErr
+		}
+		return nil, NewTranslationFailedError(err.Error())
+	}
+
+	return translations, nil
+}
+
+// chunkBatchTexts splits texts into provider requests bounded by both item
+// count and total length.
+func chunkBatchTexts(texts []string) [][]string {
+	var chunks [][]string
+	var current []string
+	currentChars := 0
+
+	for _, text := range texts {
+		if len(current) > 0 && (len(current) >= maxBatchChunkItems || currentChars+len(text) > maxBatchChunkChars) {
+			chunks = append(chunks, current)
+			current = nil
+			currentChars = 0
+		}
+
+		current = append(current, text)
+		currentChars += len(text)
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
@@ -0,0 +1,82 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestChunkBatchTexts_ItemLimit(t *testing.T) {
+	texts := make([]string, maxBatchChunkItems*2+1)
+	for i := range texts {
+		texts[i] = "word"
+	}
+
+	chunks := chunkBatchTexts(texts)
+
+	assert.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], maxBatchChunkItems)
+	assert.Len(t, chunks[1], maxBatchChunkItems)
+	assert.Len(t, chunks[2], 1)
+}
+
+func TestChunkBatchTexts_CharacterLimit(t *testing.T) {
+	long := strings.Repeat("a", 5000)
+	texts := []string{long, long, long, long, long}
+
+	chunks := chunkBatchTexts(texts)
+
+	assert.Len(t, chunks, 2)
+	assert.Len(t, chunks[0], 4)
+	assert.Len(t, chunks[1], 1)
+}
+
+func TestChunkBatchTexts_PreservesOrder(t *testing.T) {
+	texts := []string{"one", "two", "three"}
+
+	assert.Equal(t, [][]string{{"one", "two", "three"}}, chunkBatchTexts(texts))
+	assert.Empty(t, chunkBatchTexts(nil))
+}
+
+type countingProvider struct {
+	fakeProvider
+	calls [][]string
+	err   error
+}
+
+func (p *countingProvider) Translate(_ context.Context, texts []string, _, _ language.Tag) ([]ProviderTranslation, error) {
+	p.calls = append(p.calls, texts)
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	results := make([]ProviderTranslation, len(texts))
+	for i, text := range texts {
+		results[i] = ProviderTranslation{Text: strings.ToUpper(text)}
+	}
+	return results, nil
+}
+
+func TestTranslateBatchChunk_SendsWholeChunk(t *testing.T) {
+	provider := &countingProvider{}
+
+	translations, err := translateBatchChunk(context.Background(), provider, []string{"a", "b"}, language.English, language.Spanish)
+
+	assert.Nil(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}}, provider.calls)
+	assert.Equal(t, []ProviderTranslation{{Text: "A"}, {Text: "B"}}, translations)
+}
+
+func TestTranslateBatchChunk_WrapsProviderErrors(t *testing.T) {
+	provider := &countingProvider{err: errors.New("permission denied")}
+
+	_, err := translateBatchChunk(context.Background(), provider, []string{"a"}, language.English, language.Spanish)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "TRANSLATION_FAILED", err.Code)
+	assert.Len(t, provider.calls, 1)
+}
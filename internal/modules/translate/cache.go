@@ -0,0 +1,261 @@
+package translate
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/predicate"
+	"lexia/ent/schema"
+	"lexia/ent/translationcacheentry"
+	"lexia/internal/modules/languages"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Hits and misses since the process started. Per-entry hit counts are kept
+// in the database; these show how well the cache works for the current
+// deployment.
+var (
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
+)
+
+type translationCacheKey struct {
+	text     string
+	from     schema.Language
+	to       schema.Language
+	provider string
+}
+
+type TranslationCacheStats struct {
+	Entries        int
+	ExpiredEntries int
+	TotalHits      int
+	SessionHits    int64
+	SessionMisses  int64
+}
+
+type PurgeTranslationCacheArgs struct {
+	ExpiredOnly bool
+	Now         time.Time
+}
+
+const translationCacheStatsQuery = `
+SELECT
+	COUNT(*),
+	COUNT(*) FILTER (WHERE expires_at <= $1),
+	COALESCE(SUM(hit_count), 0)
+FROM translation_cache_entries
+`
+
+// normalizeCacheText folds the differences learners make when typing the same
+// word: surrounding and repeated whitespace and Unicode composition. Case is
+// kept, since it can change the translation, as with "Paris" and "paris".
+func normalizeCacheText(text string) string {
+	text = norm.NFC.String(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func newTranslationCacheKey(text string, from, to schema.Language, provider string) translationCacheKey {
+	return translationCacheKey{
+		text:     normalizeCacheText(text),
+		from:     languages.Normalize(from),
+		to:       languages.Normalize(to),
+		provider: provider,
+	}
+}
+
+func (k translationCacheKey) predicates() []predicate.TranslationCacheEntry {
+	return []predicate.TranslationCacheEntry{
+		translationcacheentry.NormalizedText(k.text),
+		translationcacheentry.LanguageFromEQ(k.from),
+		translationcacheentry.LanguageToEQ(k.to),
+		translationcacheentry.Provider(k.provider),
+	}
+}
+
+func getCachedTranslation(
+	ctx context.Context,
+	db *ent.Client,
+	key translationCacheKey,
+	now time.Time,
+) ([]TranslationVariant, bool) {
+	entry, err := db.TranslationCacheEntry.Query().
+		Where(key.predicates()...).
+		Where(translationcacheentry.ExpiresAtGT(now)).
+		Only(ctx)
+	if err != nil {
+		if !ent.IsNotFound(err) {
+			log.Println("Error reading translation cache: ", err)
+		}
+		cacheMisses.Add(1)
+		return nil, false
+	}
+
+	cacheHits.Add(1)
+
+	err = db.TranslationCacheEntry.UpdateOneID(entry.ID).
+		AddHitCount(1).
+		SetLastHitAt(now).
+		Exec(ctx)
+	if err != nil {
+		log.Println("Error updating translation cache hit count: ", err)
+	}
+
+	return cachedVariantsToVariants(entry.Variants), true
+}
+
+// getCachedTranslations looks up many texts for one language pair with a
+// single query and counts the hits with a single update. The result is keyed
+// by normalised text and only holds the texts that were cached.
+func getCachedTranslations(
+	ctx context.Context,
+	db *ent.Client,
+	texts []string,
+	from, to schema.Language,
+	provider string,
+	now time.Time,
+) map[string][]TranslationVariant {
+	normalizedTexts := make([]string, len(texts))
+	for i, text := range texts {
+		normalizedTexts[i] = normalizeCacheText(text)
+	}
+
+	entries, err := db.TranslationCacheEntry.Query().
+		Where(
+			translationcacheentry.NormalizedTextIn(normalizedTexts...),
+			translationcacheentry.LanguageFromEQ(languages.Normalize(from)),
+			translationcacheentry.LanguageToEQ(languages.Normalize(to)),
+			translationcacheentry.Provider(provider),
+			translationcacheentry.ExpiresAtGT(now),
+		).
+		All(ctx)
+	if err != nil {
+		log.Println("Error reading translation cache: ", err)
+		cacheMisses.Add(int64(len(texts)))
+		return nil
+	}
+
+	cached := make(map[string][]TranslationVariant, len(entries))
+	ids := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		cached[entry.NormalizedText] = cachedVariantsToVariants(entry.Variants)
+		ids[i] = entry.ID
+	}
+
+	cacheHits.Add(int64(len(entries)))
+	cacheMisses.Add(int64(len(texts) - len(entries)))
+
+	if len(ids) > 0 {
+		err = db.TranslationCacheEntry.Update().
+			Where(translationcacheentry.IDIn(ids...)).
+			AddHitCount(1).
+			SetLastHitAt(now).
+			Exec(ctx)
+		if err != nil {
+			log.Println("Error updating translation cache hit counts: ", err)
+		}
+	}
+
+	return cached
+}
+
+func cachedVariantsToVariants(cachedVariants []schema.CachedTranslationVariant) []TranslationVariant {
+	variants := make([]TranslationVariant, len(cachedVariants))
+	for i, variant := range cachedVariants {
+		variants[i] = TranslationVariant{
+			Text:       variant.Text,
+			Confidence: variant.Confidence,
+			Source:     variant.Source,
+			Via:        variant.Via,
+		}
+	}
+
+	return variants
+}
+
+func storeCachedTranslation(
+	ctx context.Context,
+	db *ent.Client,
+	key translationCacheKey,
+	variants []TranslationVariant,
+	ttl time.Duration,
+	now time.Time,
+) {
+	cachedVariants := make([]schema.CachedTranslationVariant, len(variants))
+	for i, variant := range variants {
+		cachedVariants[i] = schema.CachedTranslationVariant{
+			Text:       variant.Text,
+			Confidence: variant.Confidence,
+			Source:     variant.Source,
+			Via:        variant.Via,
+		}
+	}
+
+	expiresAt := now.Add(ttl)
+
+	err := db.TranslationCacheEntry.Create().
+		SetNormalizedText(key.text).
+		SetLanguageFrom(key.from).
+		SetLanguageTo(key.to).
+		SetProvider(key.provider).
+		SetVariants(cachedVariants).
+		SetExpiresAt(expiresAt).
+		Exec(ctx)
+	if ent.IsConstraintError(err) {
+		// An expired entry, or one written by a concurrent request, already
+		// holds the key; refresh it in place.
+		err = db.TranslationCacheEntry.Update().
+			Where(key.predicates()...).
+			SetVariants(cachedVariants).
+			SetExpiresAt(expiresAt).
+			Exec(ctx)
+	}
+
+	if err != nil {
+		log.Println("Error writing translation cache: ", err)
+	}
+}
+
+func GetTranslationCacheStats(ctx context.Context, db *ent.Client, now time.Time) (*TranslationCacheStats, error) {
+	rows, err := db.QueryContext(ctx, translationCacheStatsQuery, now)
+	if err != nil {
+		log.Println("Error getting translation cache stats: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &TranslationCacheStats{
+		SessionHits:   cacheHits.Load(),
+		SessionMisses: cacheMisses.Load(),
+	}
+
+	if rows.Next() {
+		if err := rows.Scan(&stats.Entries, &stats.ExpiredEntries, &stats.TotalHits); err != nil {
+			log.Println("Error scanning translation cache stats: ", err)
+			return nil, err
+		}
+	}
+
+	return stats, rows.Err()
+}
+
+func PurgeTranslationCache(ctx context.Context, db *ent.Client, args PurgeTranslationCacheArgs) (int, error) {
+	deletion := db.TranslationCacheEntry.Delete()
+
+	if args.ExpiredOnly {
+		deletion = deletion.Where(translationcacheentry.ExpiresAtLTE(args.Now))
+	}
+
+	deleted, err := deletion.Exec(ctx)
+	if err != nil {
+		log.Println("Error purging translation cache: ", err)
+		return 0, err
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,168 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// DeeplProvider talks to the DeepL v2 REST API. Free-tier keys use
+// https://api-free.deepl.com, paid keys https://api.deepl.com.
+type DeeplProvider struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type deeplTranslateRequest struct {
+	Text       []string `json:"text"`
+	SourceLang string   `json:"source_lang,omitempty"`
+	TargetLang string   `json:"target_lang"`
+}
+
+type deeplTranslateResponse struct {
+	Translations []struct {
+		DetectedSourceLanguage string `json:"detected_source_language"`
+		Text                   string `json:"text"`
+	} `json:"translations"`
+}
+
+type deeplLanguage struct {
+	Language string `json:"language"`
+	Name     string `json:"name"`
+}
+
+func NewDeeplProvider(baseURL string, apiKey string, httpClient *http.Client) *DeeplProvider {
+	return &DeeplProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: defaultHttpClient(httpClient),
+	}
+}
+
+func (p *DeeplProvider) Name() string {
+	return "deepl"
+}
+
+func (p *DeeplProvider) Translate(
+	ctx context.Context,
+	texts []string,
+	from, to language.Tag,
+) ([]ProviderTranslation, error) {
+	response, err := p.translate(ctx, deeplTranslateRequest{
+		Text:       texts,
+		SourceLang: deeplSourceCode(from),
+		TargetLang: deeplTargetCode(to),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make([]ProviderTranslation, len(response.Translations))
+	for i, translation := range response.Translations {
+		translations[i] = ProviderTranslation{Text: translation.Text}
+	}
+
+	return translations, nil
+}
+
+// Detect has no dedicated DeepL endpoint, so it translates the text without a
+// source language and reads back what DeepL detected. DeepL does not report
+// a confidence for that guess.
+func (p *DeeplProvider) Detect(ctx context.Context, text string) (ProviderDetection, error) {
+	response, err := p.translate(ctx, deeplTranslateRequest{
+		Text:       []string{text},
+		TargetLang: deeplTargetCode(language.English),
+	})
+	if err != nil {
+		return ProviderDetection{}, err
+	}
+
+	if len(response.Translations) == 0 || response.Translations[0].DetectedSourceLanguage == "" {
+		return ProviderDetection{}, NewNoDetectionError()
+	}
+
+	tag, err := language.Parse(response.Translations[0].DetectedSourceLanguage)
+	if err != nil {
+		return ProviderDetection{}, err
+	}
+
+	return ProviderDetection{Language: tag}, nil
+}
+
+func (p *DeeplProvider) SupportedLanguages(ctx context.Context) ([]language.Tag, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/v2/languages?type=target", nil)
+	if err != nil {
+		return nil, err
+	}
+	p.authorize(req)
+
+	var languages []deeplLanguage
+	if err := doJSONRequest(p.httpClient, req, p.Name(), &languages); err != nil {
+		return nil, err
+	}
+
+	tags := make([]language.Tag, 0, len(languages))
+	for _, lang := range languages {
+		tag, err := language.Parse(lang.Language)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (p *DeeplProvider) Close() error {
+	return nil
+}
+
+func (p *DeeplProvider) translate(ctx context.Context, body deeplTranslateRequest) (*deeplTranslateResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v2/translate", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	p.authorize(req)
+
+	var response deeplTranslateResponse
+	if err := doJSONRequest(p.httpClient, req, p.Name(), &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+func (p *DeeplProvider) authorize(req *http.Request) {
+	req.Header.Set("Authorization", "DeepL-Auth-Key "+p.apiKey)
+}
+
+// DeepL source languages are bare language codes, while a few target
+// languages must name a regional variant.
+func deeplSourceCode(tag language.Tag) string {
+	base, _ := tag.Base()
+	return strings.ToUpper(base.String())
+}
+
+func deeplTargetCode(tag language.Tag) string {
+	switch tag {
+	case language.English:
+		return "EN-US"
+	case language.Portuguese:
+		return "PT-BR"
+	case language.TraditionalChinese:
+		return "ZH-HANT"
+	default:
+		return deeplSourceCode(tag)
+	}
+}
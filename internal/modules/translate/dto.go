@@ -2,6 +2,8 @@ package translate
 
 import (
 	"lexia/ent/schema"
+	"lexia/internal/modules/languages"
+	"lexia/internal/service"
 )
 
 type TranslateRequestDTO struct {
@@ -10,10 +12,7 @@ type TranslateRequestDTO struct {
 	LanguageTo   schema.Language `json:"languageTo" validate:"required"`
 }
 
-type TranslationVariant struct {
-	Text       string  `json:"text"`
-	Confidence float32 `json:"confidence"`
-}
+type TranslationVariant = service.TranslationVariant
 
 type TranslateResponseDTO struct {
 	OriginalText string               `json:"originalText"`
@@ -22,6 +21,30 @@ type TranslateResponseDTO struct {
 	Translations []TranslationVariant `json:"translations"`
 }
 
+type BatchTranslateRequestDTO struct {
+	Texts        []string        `json:"texts" binding:"required,min=1,max=500"`
+	LanguageFrom schema.Language `json:"languageFrom" binding:"required"`
+	LanguageTo   schema.Language `json:"languageTo" binding:"required"`
+}
+
+type BatchTranslationErrorDTO struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type BatchTranslationItemDTO struct {
+	Index       int                       `json:"index"`
+	Text        string                    `json:"text"`
+	Translation *string                   `json:"translation,omitempty"`
+	Error       *BatchTranslationErrorDTO `json:"error,omitempty"`
+}
+
+type BatchTranslateResponseDTO struct {
+	LanguageFrom schema.Language           `json:"languageFrom"`
+	LanguageTo   schema.Language           `json:"languageTo"`
+	Results      []BatchTranslationItemDTO `json:"results"`
+}
+
 type DetectLanguageRequestDTO struct {
 	Text string `json:"text" validate:"required,min=1,max=5000"`
 }
@@ -32,6 +55,69 @@ type DetectLanguageResponseDTO struct {
 	Text             string          `json:"text"`
 }
 
+type SupportedLanguagesQueryDTO struct {
+	Provider string `form:"provider" binding:"omitempty,oneof=google deepl libretranslate"`
+}
+
 type SupportedLanguagesResponseDTO struct {
-	Languages []schema.Language `json:"languages"`
+	// Languages lists the original enum values, for clients written before
+	// the catalogue.
+	Languages []schema.Language       `json:"languages"`
+	Catalogue []languages.LanguageDTO `json:"catalogue"`
+}
+
+type TranslationCacheStatsDTO struct {
+	Entries        int     `json:"entries"`
+	ExpiredEntries int     `json:"expiredEntries"`
+	TotalHits      int     `json:"totalHits"`
+	SessionHits    int64   `json:"sessionHits"`
+	SessionMisses  int64   `json:"sessionMisses"`
+	SessionHitRate float64 `json:"sessionHitRate"`
+}
+
+type PurgeTranslationCacheQueryDTO struct {
+	ExpiredOnly bool `form:"expiredOnly"`
+}
+
+type PurgeTranslationCacheResponseDTO struct {
+	Deleted int `json:"deleted"`
+}
+
+func TranslationCacheStatsToDTO(stats *TranslationCacheStats) TranslationCacheStatsDTO {
+	dto := TranslationCacheStatsDTO{
+		Entries:        stats.Entries,
+		ExpiredEntries: stats.ExpiredEntries,
+		TotalHits:      stats.TotalHits,
+		SessionHits:    stats.SessionHits,
+		SessionMisses:  stats.SessionMisses,
+	}
+
+	if lookups := stats.SessionHits + stats.SessionMisses; lookups > 0 {
+		dto.SessionHitRate = float64(stats.SessionHits) / float64(lookups)
+	}
+
+	return dto
+}
+
+func BatchTranslationResultsToDTO(results []BatchTranslationResult) []BatchTranslationItemDTO {
+	items := make([]BatchTranslationItemDTO, len(results))
+	for i, result := range results {
+		items[i] = BatchTranslationItemDTO{
+			Index: i,
+			Text:  result.Text,
+		}
+
+		if result.Err != nil {
+			items[i].Error = &BatchTranslationErrorDTO{
+				Code:    result.Err.Code,
+				Message: result.Err.Message,
+			}
+			continue
+		}
+
+		translation := result.Translation
+		items[i].Translation = &translation
+	}
+
+	return items
 }
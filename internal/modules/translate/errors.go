@@ -3,6 +3,7 @@ package translate
 import (
 	"errors"
 	"fmt"
+	"lexia/internal/service"
 )
 
 var (
@@ -15,18 +16,7 @@ var (
 	ErrGoogleCredentials       = errors.New("Google Cloud credentials not configured")
 )
 
-type TranslationError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
-}
-
-func (e *TranslationError) Error() string {
-	if e.Details != "" {
-		return fmt.Sprintf("%s: %s (%s)", e.Code, e.Message, e.Details)
-	}
-	return fmt.Sprintf("%s: %s", e.Code, e.Message)
-}
+type TranslationError = service.TranslationError
 
 func NewTranslationError(code, message, details string) *TranslationError {
 	return &TranslationError{
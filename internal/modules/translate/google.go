@@ -0,0 +1,89 @@
+package translate
+
+import (
+	"context"
+
+	"cloud.google.com/go/translate"
+	"golang.org/x/text/language"
+	"google.golang.org/api/option"
+)
+
+type GoogleProvider struct {
+	client *translate.Client
+}
+
+func NewGoogleProvider(ctx context.Context, projectID string, serviceAccountKeyPath string) (*GoogleProvider, error) {
+	var clientOptions []option.ClientOption
+
+	clientOptions = append(clientOptions, option.WithQuotaProject(projectID))
+
+	if serviceAccountKeyPath != "" {
+		clientOptions = append(clientOptions, option.WithCredentialsFile(serviceAccountKeyPath))
+	}
+
+	client, err := translate.NewClient(ctx, clientOptions...)
+	if err != nil {
+		return nil, NewCredentialsError()
+	}
+
+	return &GoogleProvider{client: client}, nil
+}
+
+func (p *GoogleProvider) Name() string {
+	return "google"
+}
+
+func (p *GoogleProvider) Translate(
+	ctx context.Context,
+	texts []string,
+	from, to language.Tag,
+) ([]ProviderTranslation, error) {
+	results, err := p.client.Translate(ctx, texts, to, &translate.Options{
+		Source: from,
+		Format: translate.Text,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make([]ProviderTranslation, len(results))
+	for i, result := range results {
+		translations[i] = ProviderTranslation{Text: result.Text}
+	}
+
+	return translations, nil
+}
+
+func (p *GoogleProvider) Detect(ctx context.Context, text string) (ProviderDetection, error) {
+	detections, err := p.client.DetectLanguage(ctx, []string{text})
+	if err != nil {
+		return ProviderDetection{}, err
+	}
+
+	if len(detections) == 0 || len(detections[0]) == 0 {
+		return ProviderDetection{}, NewNoDetectionError()
+	}
+
+	return ProviderDetection{
+		Language:   detections[0][0].Language,
+		Confidence: float32(detections[0][0].Confidence),
+	}, nil
+}
+
+func (p *GoogleProvider) SupportedLanguages(ctx context.Context) ([]language.Tag, error) {
+	languages, err := p.client.SupportedLanguages(ctx, language.English)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]language.Tag, len(languages))
+	for i, lang := range languages {
+		tags[i] = lang.Tag
+	}
+
+	return tags, nil
+}
+
+func (p *GoogleProvider) Close() error {
+	return p.client.Close()
+}
@@ -1,13 +1,14 @@
 package translate
 
 import (
-	"fmt"
+	"lexia/internal/modules/languages"
 	"lexia/internal/shared"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func handleTranslate(_ *shared.ApiConfig) gin.HandlerFunc {
+func handleTranslate(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, err := shared.GetAuthPayload(c)
 		if err != nil {
@@ -21,68 +22,54 @@ func handleTranslate(_ *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		if body.LanguageFrom == body.LanguageTo {
-			shared.ResBadRequest(c, "Source and target languages must be different")
+		translations, err := apiCfg.Translations.TranslateText(c.Request.Context(), body.Text, body.LanguageFrom, body.LanguageTo)
+		if err != nil {
+			shared.ResError(c, err)
 			return
 		}
 
-		supportedLanguages := GetSupportedLanguages()
-		isFromSupported := false
-		isToSupported := false
-
-		for _, lang := range supportedLanguages {
-			if lang == body.LanguageFrom {
-				isFromSupported = true
-			}
-			if lang == body.LanguageTo {
-				isToSupported = true
-			}
+		response := TranslateResponseDTO{
+			OriginalText: body.Text,
+			LanguageFrom: body.LanguageFrom,
+			LanguageTo:   body.LanguageTo,
+			Translations: translations,
 		}
 
-		if !isFromSupported {
-			shared.ResBadRequest(c, fmt.Sprintf("Source language '%s' is not supported", body.LanguageFrom))
+		shared.ResOK(c, response)
+	}
+}
+
+func handleTranslateBatch(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
 			return
 		}
 
-		if !isToSupported {
-			shared.ResBadRequest(c, fmt.Sprintf("Target language '%s' is not supported", body.LanguageTo))
+		var body BatchTranslateRequestDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
 			return
 		}
 
-		translations, err := TranslateText(c.Request.Context(), body.Text, body.LanguageFrom, body.LanguageTo)
+		results, err := apiCfg.Translations.TranslateBatch(c.Request.Context(), body.Texts, body.LanguageFrom, body.LanguageTo)
 		if err != nil {
-			if translationErr, ok := err.(*TranslationError); ok {
-				switch translationErr.Code {
-				case "EMPTY_TEXT", "SAME_LANGUAGE", "TEXT_TOO_LONG":
-					shared.ResBadRequest(c, translationErr.Message)
-					return
-				case "UNSUPPORTED_LANGUAGE":
-					shared.ResBadRequest(c, translationErr.Message)
-					return
-				case "CREDENTIALS_ERROR":
-					shared.ResInternalServerError(c, "Translation service configuration error")
-					return
-				default:
-					shared.ResInternalServerError(c, "Translation failed")
-					return
-				}
-			}
-			shared.ResInternalServerError(c, "Translation service unavailable")
+			shared.ResError(c, err)
 			return
 		}
 
-		response := TranslateResponseDTO{
-			OriginalText: body.Text,
+		response := BatchTranslateResponseDTO{
 			LanguageFrom: body.LanguageFrom,
 			LanguageTo:   body.LanguageTo,
-			Translations: translations,
+			Results:      BatchTranslationResultsToDTO(results),
 		}
 
 		shared.ResOK(c, response)
 	}
 }
 
-func handleDetectLanguage(_ *shared.ApiConfig) gin.HandlerFunc {
+func handleDetectLanguage(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, err := shared.GetAuthPayload(c)
 		if err != nil {
@@ -96,25 +83,9 @@ func handleDetectLanguage(_ *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		detectedLang, confidence, err := DetectLanguage(c.Request.Context(), body.Text)
+		detectedLang, confidence, err := apiCfg.Translations.DetectLanguage(c.Request.Context(), body.Text)
 		if err != nil {
-			if translationErr, ok := err.(*TranslationError); ok {
-				switch translationErr.Code {
-				case "EMPTY_TEXT", "TEXT_TOO_LONG":
-					shared.ResBadRequest(c, translationErr.Message)
-					return
-				case "UNSUPPORTED_DETECTED_LANGUAGE":
-					shared.ResBadRequest(c, translationErr.Message)
-					return
-				case "CREDENTIALS_ERROR":
-					shared.ResInternalServerError(c, "Language detection service configuration error")
-					return
-				default:
-					shared.ResInternalServerError(c, "Language detection failed")
-					return
-				}
-			}
-			shared.ResInternalServerError(c, "Language detection service unavailable")
+			shared.ResError(c, err)
 			return
 		}
 
@@ -128,7 +99,7 @@ func handleDetectLanguage(_ *shared.ApiConfig) gin.HandlerFunc {
 	}
 }
 
-func handleGetSupportedLanguages(_ *shared.ApiConfig) gin.HandlerFunc {
+func handleGetSupportedLanguages(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		_, err := shared.GetAuthPayload(c)
 		if err != nil {
@@ -136,11 +107,56 @@ func handleGetSupportedLanguages(_ *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		languages := GetSupportedLanguages()
+		var query SupportedLanguagesQueryDTO
+		if err := c.ShouldBindQuery(&query); err != nil {
+			shared.ResBadRequest(c, "Invalid query parameters")
+			return
+		}
+
+		languageEntities, err := apiCfg.Translations.ListLanguages(c.Request.Context(), query.Provider)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
 		response := SupportedLanguagesResponseDTO{
-			Languages: languages,
+			Languages: languages.LegacyCodes(languageEntities),
+			Catalogue: languages.LanguageEntitiesToDtos(languageEntities),
 		}
 
 		shared.ResOK(c, response)
 	}
 }
+
+func handleGetTranslationCacheStats(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := GetTranslationCacheStats(c.Request.Context(), apiCfg.DB, time.Now())
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, TranslationCacheStatsToDTO(stats))
+	}
+}
+
+func handlePurgeTranslationCache(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query PurgeTranslationCacheQueryDTO
+		if err := c.ShouldBindQuery(&query); err != nil {
+			shared.ResBadRequest(c, "Invalid query parameters")
+			return
+		}
+
+		deleted, err := PurgeTranslationCache(c.Request.Context(), apiCfg.DB, PurgeTranslationCacheArgs{
+			ExpiredOnly: query.ExpiredOnly,
+			Now:         time.Now(),
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, PurgeTranslationCacheResponseDTO{Deleted: deleted})
+	}
+}
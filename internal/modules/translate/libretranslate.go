@@ -0,0 +1,175 @@
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// LibreTranslateProvider talks to a (usually self-hosted) LibreTranslate
+// instance. The API key is optional and only sent when configured.
+type LibreTranslateProvider struct {
+	baseURL      string
+	apiKey       string
+	alternatives int
+	httpClient   *http.Client
+}
+
+const libreTranslateAlternatives = 3
+
+type libreTranslateRequest struct {
+	Q            []string `json:"q"`
+	Source       string   `json:"source"`
+	Target       string   `json:"target"`
+	Format       string   `json:"format"`
+	Alternatives int      `json:"alternatives,omitempty"`
+	ApiKey       string   `json:"api_key,omitempty"`
+}
+
+type libreTranslateResponse struct {
+	TranslatedText []string   `json:"translatedText"`
+	Alternatives   [][]string `json:"alternatives"`
+}
+
+type libreDetectRequest struct {
+	Q      string `json:"q"`
+	ApiKey string `json:"api_key,omitempty"`
+}
+
+type libreDetection struct {
+	Language   string  `json:"language"`
+	Confidence float32 `json:"confidence"`
+}
+
+type libreLanguage struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+func NewLibreTranslateProvider(baseURL string, apiKey string, httpClient *http.Client) *LibreTranslateProvider {
+	return &LibreTranslateProvider{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		apiKey:       apiKey,
+		alternatives: libreTranslateAlternatives,
+		httpClient:   defaultHttpClient(httpClient),
+	}
+}
+
+func (p *LibreTranslateProvider) Name() string {
+	return "libretranslate"
+}
+
+func (p *LibreTranslateProvider) Translate(
+	ctx context.Context,
+	texts []string,
+	from, to language.Tag,
+) ([]ProviderTranslation, error) {
+	var response libreTranslateResponse
+	err := p.post(ctx, "/translate", libreTranslateRequest{
+		Q:            texts,
+		Source:       libreLanguageCode(from),
+		Target:       libreLanguageCode(to),
+		Format:       "text",
+		Alternatives: p.alternatives,
+		ApiKey:       p.apiKey,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	translations := make([]ProviderTranslation, len(response.TranslatedText))
+	for i, text := range response.TranslatedText {
+		translations[i] = ProviderTranslation{Text: text}
+		if i < len(response.Alternatives) {
+			translations[i].Alternatives = response.Alternatives[i]
+		}
+	}
+
+	return translations, nil
+}
+
+func (p *LibreTranslateProvider) Detect(ctx context.Context, text string) (ProviderDetection, error) {
+	var detections []libreDetection
+	err := p.post(ctx, "/detect", libreDetectRequest{
+		Q:      text,
+		ApiKey: p.apiKey,
+	}, &detections)
+	if err != nil {
+		return ProviderDetection{}, err
+	}
+
+	if len(detections) == 0 {
+		return ProviderDetection{}, NewNoDetectionError()
+	}
+
+	tag, err := language.Parse(detections[0].Language)
+	if err != nil {
+		return ProviderDetection{}, err
+	}
+
+	// LibreTranslate reports confidence as a percentage.
+	return ProviderDetection{
+		Language:   tag,
+		Confidence: detections[0].Confidence / 100,
+	}, nil
+}
+
+func (p *LibreTranslateProvider) SupportedLanguages(ctx context.Context) ([]language.Tag, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/languages", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var languages []libreLanguage
+	if err := doJSONRequest(p.httpClient, req, p.Name(), &languages); err != nil {
+		return nil, err
+	}
+
+	tags := make([]language.Tag, 0, len(languages))
+	for _, lang := range languages {
+		tag, err := language.Parse(lang.Code)
+		if err != nil {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (p *LibreTranslateProvider) Close() error {
+	return nil
+}
+
+func (p *LibreTranslateProvider) post(ctx context.Context, path string, body any, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doJSONRequest(p.httpClient, req, p.Name(), out)
+}
+
+// LibreTranslate identifies languages by bare ISO 639-1 codes, with its own
+// codes for Traditional Chinese and Filipino.
+func libreLanguageCode(tag language.Tag) string {
+	switch tag {
+	case language.TraditionalChinese:
+		return "zt"
+	case language.Filipino:
+		return "tl"
+	}
+
+	base, _ := tag.Base()
+	return base.String()
+}
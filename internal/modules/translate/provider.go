@@ -0,0 +1,129 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"lexia/internal/shared"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// Provider is a machine translation backend. Implementations translate a
+// batch of texts in one call and return one result per input, in order.
+type Provider interface {
+	Name() string
+	Translate(ctx context.Context, texts []string, from, to language.Tag) ([]ProviderTranslation, error)
+	Detect(ctx context.Context, text string) (ProviderDetection, error)
+	SupportedLanguages(ctx context.Context) ([]language.Tag, error)
+	Close() error
+}
+
+type ProviderTranslation struct {
+	Text         string
+	Alternatives []string
+}
+
+type ProviderDetection struct {
+	Language   language.Tag
+	Confidence float32
+}
+
+const providerHttpTimeout = 15 * time.Second
+
+// Config selects the translation provider and holds its credentials.
+type Config struct {
+	Provider                    string
+	GoogleCloudProjectID        string
+	GoogleServiceAccountKeyPath string
+	DeeplApiUrl                 string
+	DeeplApiKey                 string
+	LibreTranslateUrl           string
+	LibreTranslateApiKey        string
+	// CacheTTL is how long translations are served from the cache.
+	CacheTTL time.Duration
+}
+
+func ConfigFromEnv(envVars *shared.EnvVariables) Config {
+	return Config{
+		Provider:                    envVars.TranslationProvider,
+		GoogleCloudProjectID:        envVars.GoogleCloudProjectID,
+		GoogleServiceAccountKeyPath: envVars.GoogleServiceAccountKeyPath,
+		DeeplApiUrl:                 envVars.DeeplApiUrl,
+		DeeplApiKey:                 envVars.DeeplApiKey,
+		LibreTranslateUrl:           envVars.LibreTranslateUrl,
+		LibreTranslateApiKey:        envVars.LibreTranslateApiKey,
+		CacheTTL:                    time.Duration(envVars.TranslationCacheTtlHours) * time.Hour,
+	}
+}
+
+// NewProvider builds the translation provider selected by config.Provider.
+func NewProvider(ctx context.Context, config Config) (Provider, error) {
+	switch config.Provider {
+	case shared.TranslationProviderGoogle:
+		return NewGoogleProvider(ctx, config.GoogleCloudProjectID, config.GoogleServiceAccountKeyPath)
+	case shared.TranslationProviderDeepl:
+		return NewDeeplProvider(config.DeeplApiUrl, config.DeeplApiKey, nil), nil
+	case shared.TranslationProviderLibreTranslate:
+		return NewLibreTranslateProvider(config.LibreTranslateUrl, config.LibreTranslateApiKey, nil), nil
+	default:
+		return nil, NewTranslationError(
+			"CONFIGURATION_ERROR",
+			"Unknown translation provider",
+			fmt.Sprintf("Provider '%s' is not supported", config.Provider),
+		)
+	}
+}
+
+// httpStatusError keeps the status code in the message so isRetryableError
+// can tell rate limits and gateway errors apart from client mistakes.
+type httpStatusError struct {
+	provider   string
+	statusCode int
+	body       string
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("%s HTTP %d: %s", e.provider, e.statusCode, e.body)
+}
+
+func doJSONRequest(client *http.Client, req *http.Request, provider string, out any) error {
+	req.Header.Set("Accept", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return &httpStatusError{
+			provider:   provider,
+			statusCode: res.StatusCode,
+			body:       strings.TrimSpace(string(body)),
+		}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s returned an invalid response: %w", provider, err)
+	}
+
+	return nil
+}
+
+func defaultHttpClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+
+	return &http.Client{Timeout: providerHttpTimeout}
+}
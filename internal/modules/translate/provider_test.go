@@ -0,0 +1,194 @@
+package translate
+
+import (
+	"context"
+	"encoding/json"
+	"lexia/ent/schema"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestDeeplProvider_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/translate", r.URL.Path)
+		assert.Equal(t, "DeepL-Auth-Key test-key", r.Header.Get("Authorization"))
+
+		var body deeplTranslateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []string{"hello", "world"}, body.Text)
+		assert.Equal(t, "EN", body.SourceLang)
+		assert.Equal(t, "DE", body.TargetLang)
+
+		w.Write([]byte(`{"translations":[{"detected_source_language":"EN","text":"hallo"},{"detected_source_language":"EN","text":"Welt"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewDeeplProvider(server.URL, "test-key", server.Client())
+	translations, err := provider.Translate(context.Background(), []string{"hello", "world"}, language.English, language.German)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ProviderTranslation{{Text: "hallo"}, {Text: "Welt"}}, translations)
+}
+
+func TestDeeplProvider_TargetEnglishUsesRegionalCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body deeplTranslateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "EN-US", body.TargetLang)
+
+		w.Write([]byte(`{"translations":[{"detected_source_language":"DE","text":"hello"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewDeeplProvider(server.URL, "test-key", server.Client())
+	_, err := provider.Translate(context.Background(), []string{"hallo"}, language.German, language.English)
+	assert.NoError(t, err)
+}
+
+func TestDeeplProvider_Detect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body deeplTranslateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Empty(t, body.SourceLang)
+
+		w.Write([]byte(`{"translations":[{"detected_source_language":"FR","text":"hello"}]}`))
+	}))
+	defer server.Close()
+
+	provider := NewDeeplProvider(server.URL, "test-key", server.Client())
+	detection, err := provider.Detect(context.Background(), "bonjour")
+
+	assert.NoError(t, err)
+	assert.Equal(t, language.French, detection.Language)
+}
+
+func TestDeeplProvider_SupportedLanguages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/languages", r.URL.Path)
+		assert.Equal(t, "target", r.URL.Query().Get("type"))
+
+		w.Write([]byte(`[{"language":"DE","name":"German"},{"language":"EN-GB","name":"English (British)"}]`))
+	}))
+	defer server.Close()
+
+	provider := NewDeeplProvider(server.URL, "test-key", server.Client())
+	tags, err := provider.SupportedLanguages(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []language.Tag{language.German, language.BritishEnglish}, tags)
+}
+
+func TestDeeplProvider_HttpErrorIsRetryable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"message":"Too many requests"}`))
+	}))
+	defer server.Close()
+
+	provider := NewDeeplProvider(server.URL, "test-key", server.Client())
+	_, err := provider.Translate(context.Background(), []string{"hello"}, language.English, language.German)
+
+	assert.Error(t, err)
+	assert.True(t, isRetryableError(err))
+}
+
+func TestLibreTranslateProvider_Translate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/translate", r.URL.Path)
+
+		var body libreTranslateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, []string{"hello"}, body.Q)
+		assert.Equal(t, "en", body.Source)
+		assert.Equal(t, "es", body.Target)
+		assert.Equal(t, "text", body.Format)
+		assert.Equal(t, "secret", body.ApiKey)
+		assert.Equal(t, libreTranslateAlternatives, body.Alternatives)
+
+		w.Write([]byte(`{"translatedText":["hola"],"alternatives":[["buenas","qué tal"]]}`))
+	}))
+	defer server.Close()
+
+	provider := NewLibreTranslateProvider(server.URL, "secret", server.Client())
+	translations, err := provider.Translate(context.Background(), []string{"hello"}, language.English, language.Spanish)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ProviderTranslation{{Text: "hola", Alternatives: []string{"buenas", "qué tal"}}}, translations)
+}
+
+func TestLibreTranslateProvider_OmitsEmptyApiKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.NotContains(t, body, "api_key")
+
+		w.Write([]byte(`{"translatedText":["hola"]}`))
+	}))
+	defer server.Close()
+
+	provider := NewLibreTranslateProvider(server.URL, "", server.Client())
+	_, err := provider.Translate(context.Background(), []string{"hello"}, language.English, language.Spanish)
+	assert.NoError(t, err)
+}
+
+func TestLibreTranslateProvider_Detect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/detect", r.URL.Path)
+
+		w.Write([]byte(`[{"language":"ka","confidence":87.5}]`))
+	}))
+	defer server.Close()
+
+	provider := NewLibreTranslateProvider(server.URL, "", server.Client())
+	detection, err := provider.Detect(context.Background(), "გამარჯობა")
+
+	assert.NoError(t, err)
+	assert.Equal(t, language.Georgian, detection.Language)
+	assert.InDelta(t, 0.875, detection.Confidence, 0.0001)
+}
+
+func TestLibreTranslateProvider_SupportedLanguages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/languages", r.URL.Path)
+
+		w.Write([]byte(`[{"code":"en","name":"English"},{"code":"ka","name":"Georgian"}]`))
+	}))
+	defer server.Close()
+
+	provider := NewLibreTranslateProvider(server.URL, "", server.Client())
+	tags, err := provider.SupportedLanguages(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []language.Tag{language.English, language.Georgian}, tags)
+}
+
+func TestTranslateTextWithProvider_UsesProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body libreTranslateRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		if body.Target == "es" {
+			w.Write([]byte(`{"translatedText":["hola mundo"]}`))
+			return
+		}
+		w.Write([]byte(`{"translatedText":["hello world"]}`))
+	}))
+	defer server.Close()
+
+	provider := NewLibreTranslateProvider(server.URL, "", server.Client())
+	variants, err := TranslateTextWithProvider(
+		context.Background(),
+		provider,
+		"hello world",
+		schema.LanguageEnglish,
+		schema.LanguageSpanish,
+	)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, variants)
+	assert.Equal(t, "hola mundo", variants[0].Text)
+}
@@ -8,9 +8,21 @@ import (
 
 func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
 	translateGroup := rg.Group("/translate")
+	translateGroup.Use(shared.RequireScope(shared.ScopeTranslate))
 	{
 		translateGroup.POST("", handleTranslate(apiCfg))
+		translateGroup.POST("/batch", handleTranslateBatch(apiCfg))
 		translateGroup.POST("/detect", handleDetectLanguage(apiCfg))
 		translateGroup.GET("/languages", handleGetSupportedLanguages(apiCfg))
 	}
 }
+
+// AdminRouter registers maintenance endpoints; rg must already be guarded by
+// shared.AdminMW.
+func AdminRouter(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
+	cacheGroup := rg.Group("/translation-cache")
+	{
+		cacheGroup.GET("", handleGetTranslationCacheStats(apiCfg))
+		cacheGroup.DELETE("", handlePurgeTranslationCache(apiCfg))
+	}
+}
@@ -8,37 +8,6 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestMapLanguageToGoogleCode(t *testing.T) {
-	testCases := []struct {
-		name     string
-		language schema.Language
-		expected string
-	}{
-		{"English", schema.LanguageEnglish, "en"},
-		{"Georgian", schema.LanguageGeorgian, "ka"},
-		{"Spanish", schema.LanguageSpanish, "es"},
-		{"French", schema.LanguageFrench, "fr"},
-		{"German", schema.LanguageGerman, "de"},
-		{"Russian", schema.LanguageRussian, "ru"},
-		{"Japanese", schema.LanguageJapanese, "ja"},
-		{"Chinese", schema.LanguageChinese, "zh"},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			langTag, err := mapLanguageToGoogleCode(tc.language)
-			assert.NoError(t, err)
-			assert.Equal(t, tc.expected, langTag.String())
-		})
-	}
-}
-
-func TestMapLanguageToGoogleCode_UnsupportedLanguage(t *testing.T) {
-	_, err := mapLanguageToGoogleCode("UNSUPPORTED")
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "unsupported language")
-}
-
 func TestTranslateText_ValidationErrors(t *testing.T) {
 	ctx := context.Background()
 
@@ -70,6 +39,13 @@ func TestTranslateText_ValidationErrors(t *testing.T) {
 			to:       schema.LanguageEnglish,
 			errorMsg: "Source and target languages cannot be the same",
 		},
+		{
+			name:     "Same language as legacy code and tag",
+			text:     "Hello",
+			from:     schema.LanguageEnglish,
+			to:       "en",
+			errorMsg: "Source and target languages cannot be the same",
+		},
 		{
 			name:     "Text too long",
 			text:     string(make([]byte, 5001)), // 5001 characters
@@ -81,31 +57,13 @@ func TestTranslateText_ValidationErrors(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := TranslateText(ctx, tc.text, tc.from, tc.to)
+			_, err := NewService(nil, Config{}).TranslateText(ctx, tc.text, tc.from, tc.to)
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), tc.errorMsg)
 		})
 	}
 }
 
-func TestGetSupportedLanguages(t *testing.T) {
-	languages := GetSupportedLanguages()
-
-	expectedLanguages := []schema.Language{
-		schema.LanguageEnglish,
-		schema.LanguageGeorgian,
-		schema.LanguageSpanish,
-		schema.LanguageFrench,
-		schema.LanguageGerman,
-		schema.LanguageRussian,
-		schema.LanguageJapanese,
-		schema.LanguageChinese,
-	}
-
-	assert.ElementsMatch(t, expectedLanguages, languages)
-	assert.Len(t, languages, len(expectedLanguages))
-}
-
 func TestCalculateTranslationConfidence(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -117,25 +75,31 @@ func TestCalculateTranslationConfidence(t *testing.T) {
 			name:           "Identical text",
 			original:       "hello world",
 			backTranslated: "hello world",
-			expectedRange:  [2]float32{0.95, 0.95},
+			expectedRange:  [2]float32{1, 1},
+		},
+		{
+			name:           "Identical apart from case and spacing",
+			original:       "Hello world",
+			backTranslated: "hello  world",
+			expectedRange:  [2]float32{1, 1},
 		},
 		{
 			name:           "Completely different text",
 			original:       "hello world",
 			backTranslated: "goodbye universe",
-			expectedRange:  [2]float32{0.6, 0.6},
+			expectedRange:  [2]float32{0, 0.2},
 		},
 		{
 			name:           "Partially similar text",
 			original:       "hello world",
 			backTranslated: "hello universe",
-			expectedRange:  [2]float32{0.75, 0.75},
+			expectedRange:  [2]float32{0.2, 0.6},
 		},
 		{
 			name:           "Empty back-translation",
 			original:       "hello world",
 			backTranslated: "",
-			expectedRange:  [2]float32{0.6, 0.6},
+			expectedRange:  [2]float32{0, 0},
 		},
 	}
 
@@ -150,6 +114,20 @@ func TestCalculateTranslationConfidence(t *testing.T) {
 	}
 }
 
+func TestChrF_CloserTextScoresHigher(t *testing.T) {
+	reference := "the house is big"
+
+	close := chrF("the house is large", reference)
+	far := chrF("a building stands", reference)
+
+	assert.Greater(t, close, far)
+}
+
+func TestChrF_ShortWords(t *testing.T) {
+	assert.Equal(t, 1.0, chrF("a", "a"))
+	assert.Greater(t, chrF("house", "houses"), chrF("home", "houses"))
+}
+
 func TestIsRetryableError(t *testing.T) {
 	retryableErrors := []string{
 		"timeout occurred",
@@ -198,24 +176,6 @@ func (e *testError) Error() string {
 	return e.message
 }
 
-func TestShouldGenerateFormalVariant(t *testing.T) {
-	formalLanguages := []string{"de", "fr", "es", "ja", "ru"}
-	nonFormalLanguages := []string{"en", "ka", "zh"}
-
-	for _, langCode := range formalLanguages {
-		t.Run("Formal language: "+langCode, func(t *testing.T) {
-			// Note: This test would need language.Make() but we're testing the concept
-			// In actual implementation, we'd pass the language.Tag
-		})
-	}
-
-	for _, langCode := range nonFormalLanguages {
-		t.Run("Non-formal language: "+langCode, func(t *testing.T) {
-			// Note: This test would need language.Make() but we're testing the concept
-		})
-	}
-}
-
 func TestTranslationErrorTypes(t *testing.T) {
 	t.Run("TranslationError creation", func(t *testing.T) {
 		err := NewTranslationError("TEST_CODE", "Test message", "Test details")
@@ -245,3 +205,23 @@ func TestTranslationErrorTypes(t *testing.T) {
 		assert.Contains(t, err.Error(), "credentials")
 	})
 }
+
+func TestNormalizeCacheText(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"Trims", "  hello ", "hello"},
+		{"Keeps case", "Paris", "Paris"},
+		{"Collapses inner whitespace", "good \t morning\n", "good morning"},
+		{"Composes decomposed accents", "café", "café"},
+		{"Keeps non-Latin scripts", "გამარჯობა", "გამარჯობა"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, normalizeCacheText(tc.text))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package translate
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	chrfMaxOrder = 6
+	chrfBeta     = 2.0
+)
+
+// chrF computes the character n-gram F-score (Popović, 2015) of hypothesis
+// against reference, in the range [0, 1]. Whitespace is ignored, as in the
+// reference implementation, and recall is weighted twice as much as precision.
+// Orders longer than either string are skipped so that single short words
+// still get a meaningful score.
+func chrF(hypothesis, reference string) float64 {
+	hyp := chrfCharacters(hypothesis)
+	ref := chrfCharacters(reference)
+
+	if len(hyp) == 0 || len(ref) == 0 {
+		if len(hyp) == len(ref) {
+			return 1
+		}
+		return 0
+	}
+
+	var precisionSum, recallSum float64
+	orders := 0
+
+	for n := 1; n <= chrfMaxOrder; n++ {
+		hypNgrams := characterNgrams(hyp, n)
+		refNgrams := characterNgrams(ref, n)
+		if len(hypNgrams) == 0 || len(refNgrams) == 0 {
+			break
+		}
+
+		hypTotal, refTotal, matches := 0, 0, 0
+		for ngram, count := range hypNgrams {
+			hypTotal += count
+			matches += min(count, refNgrams[ngram])
+		}
+		for _, count := range refNgrams {
+			refTotal += count
+		}
+
+		precisionSum += float64(matches) / float64(hypTotal)
+		recallSum += float64(matches) / float64(refTotal)
+		orders++
+	}
+
+	precision := precisionSum / float64(orders)
+	recall := recallSum / float64(orders)
+	if precision == 0 && recall == 0 {
+		return 0
+	}
+
+	betaSquared := chrfBeta * chrfBeta
+	return (1 + betaSquared) * precision * recall / (betaSquared*precision + recall)
+}
+
+func chrfCharacters(text string) []rune {
+	text = strings.ToLower(text)
+
+	characters := make([]rune, 0, len(text))
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			characters = append(characters, r)
+		}
+	}
+
+	return characters
+}
+
+func characterNgrams(characters []rune, n int) map[string]int {
+	ngrams := map[string]int{}
+	for i := 0; i+n <= len(characters); i++ {
+		ngrams[string(characters[i:i+n])]++
+	}
+
+	return ngrams
+}
+
+// calculateTranslationConfidence scores a translation by how well its
+// back-translation recovers the original text.
+func calculateTranslationConfidence(original, backTranslated string) float32 {
+	return float32(chrF(backTranslated, original))
+}
+
+// sameTranslation reports whether two candidate translations only differ in
+// case, whitespace or surrounding punctuation.
+func sameTranslation(a, b string) bool {
+	return comparableTranslation(a) == comparableTranslation(b)
+}
+
+func comparableTranslation(text string) string {
+	text = strings.TrimFunc(normalizeCacheText(text), func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSpace(r)
+	})
+	return strings.ToLower(text)
+}
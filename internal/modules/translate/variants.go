@@ -0,0 +1,149 @@
+package translate
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	VariantSourcePrimary             = "PRIMARY"
+	VariantSourceProviderAlternative = "PROVIDER_ALTERNATIVE"
+	VariantSourcePivot               = "PIVOT"
+)
+
+const maxTranslationVariants = 5
+
+// Pivot languages are tried in order; English first because every provider
+// has its best coverage to and from it.
+var pivotLanguages = []language.Tag{
+	language.English,
+	language.French,
+	language.Spanish,
+	language.German,
+}
+
+const maxPivotVariants = 2
+
+// buildTranslationVariants collects alternatives from real sources only:
+// alternatives the provider returned with the primary translation and pivot
+// translations that end up different from the primary. Every variant is then
+// scored by back-translating it and comparing the result with the original
+// text. None of the providers offers dictionary senses, so there are no
+// dictionary variants.
+func buildTranslationVariants(
+	ctx context.Context,
+	provider Provider,
+	text string,
+	fromLang, toLang language.Tag,
+	primary ProviderTranslation,
+) []TranslationVariant {
+	variants := []TranslationVariant{{
+		Text:   primary.Text,
+		Source: VariantSourcePrimary,
+	}}
+
+	add := func(candidate TranslationVariant) {
+		if len(variants) >= maxTranslationVariants || candidate.Text == "" {
+			return
+		}
+		for _, existing := range variants {
+			if sameTranslation(existing.Text, candidate.Text) {
+				return
+			}
+		}
+		variants = append(variants, candidate)
+	}
+
+	for _, alternative := range primary.Alternatives {
+		add(TranslationVariant{
+			Text:   alternative,
+			Source: VariantSourceProviderAlternative,
+		})
+	}
+
+	pivotVariants := 0
+	for _, pivotLang := range pivotLanguages {
+		if pivotVariants >= maxPivotVariants || len(variants) >= maxTranslationVariants {
+			break
+		}
+		if pivotLang == fromLang || pivotLang == toLang {
+			continue
+		}
+
+		pivotTranslation, err := translatePivot(ctx, provider, text, fromLang, pivotLang, toLang)
+		if err != nil {
+			log.Println("Error translating through pivot language: ", err)
+			continue
+		}
+
+		before := len(variants)
+		add(TranslationVariant{
+			Text:   pivotTranslation,
+			Source: VariantSourcePivot,
+			Via:    pivotLang.String(),
+		})
+		if len(variants) > before {
+			pivotVariants++
+		}
+	}
+
+	scoreTranslationVariants(ctx, provider, text, fromLang, toLang, variants)
+
+	// Keep the primary translation first and rank the rest by confidence.
+	sort.SliceStable(variants[1:], func(i, j int) bool {
+		return variants[i+1].Confidence > variants[j+1].Confidence
+	})
+
+	return variants
+}
+
+func translatePivot(
+	ctx context.Context,
+	provider Provider,
+	text string,
+	fromLang, pivotLang, toLang language.Tag,
+) (string, error) {
+	pivotResults, err := provider.Translate(ctx, []string{text}, fromLang, pivotLang)
+	if err != nil || len(pivotResults) == 0 {
+		return "", err
+	}
+
+	finalResults, err := provider.Translate(ctx, []string{pivotResults[0].Text}, pivotLang, toLang)
+	if err != nil || len(finalResults) == 0 {
+		return "", err
+	}
+
+	return finalResults[0].Text, nil
+}
+
+// scoreTranslationVariants back-translates all variants in one request. If
+// that fails, the variants are returned with zero confidence rather than a
+// made-up score.
+func scoreTranslationVariants(
+	ctx context.Context,
+	provider Provider,
+	text string,
+	fromLang, toLang language.Tag,
+	variants []TranslationVariant,
+) {
+	texts := make([]string, len(variants))
+	for i, variant := range variants {
+		texts[i] = variant.Text
+	}
+
+	backTranslations, err := provider.Translate(ctx, texts, toLang, fromLang)
+	if err != nil {
+		log.Println("Error back-translating variants: ", err)
+		return
+	}
+
+	for i := range variants {
+		if i >= len(backTranslations) {
+			break
+		}
+		variants[i].Confidence = calculateTranslationConfidence(text, backTranslations[i].Text)
+	}
+}
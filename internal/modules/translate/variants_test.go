@@ -0,0 +1,109 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+// fakeProvider translates by dictionary lookup keyed by "from>to:text".
+type fakeProvider struct {
+	translations map[string]string
+	alternatives map[string][]string
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeProvider) Translate(_ context.Context, texts []string, from, to language.Tag) ([]ProviderTranslation, error) {
+	results := make([]ProviderTranslation, len(texts))
+	for i, text := range texts {
+		key := from.String() + ">" + to.String() + ":" + text
+		translated, ok := p.translations[key]
+		if !ok {
+			return nil, errors.New("no translation for " + key)
+		}
+		results[i] = ProviderTranslation{Text: translated, Alternatives: p.alternatives[key]}
+	}
+	return results, nil
+}
+
+func (p *fakeProvider) Detect(context.Context, string) (ProviderDetection, error) {
+	return ProviderDetection{}, errors.New("not implemented")
+}
+
+func (p *fakeProvider) SupportedLanguages(context.Context) ([]language.Tag, error) {
+	return nil, nil
+}
+
+func (p *fakeProvider) Close() error {
+	return nil
+}
+
+func TestBuildTranslationVariants_RealSourcesOnly(t *testing.T) {
+	provider := &fakeProvider{
+		translations: map[string]string{
+			"en>es:house":    "casa",
+			"es>en:casa":     "house",
+			"es>en:hogar":    "home",
+			"es>en:vivienda": "dwelling",
+			"en>fr:house":    "maison",
+			"fr>es:maison":   "casa",
+			"en>de:house":    "Haus",
+			"de>es:Haus":     "vivienda",
+		},
+		alternatives: map[string][]string{
+			"en>es:house": {"hogar", "Casa"},
+		},
+	}
+
+	variants := buildTranslationVariants(
+		context.Background(),
+		provider,
+		"house",
+		language.English,
+		language.Spanish,
+		ProviderTranslation{Text: "casa", Alternatives: []string{"hogar", "Casa"}},
+	)
+
+	assert.Len(t, variants, 3)
+	assert.Equal(t, TranslationVariant{Text: "casa", Confidence: 1, Source: VariantSourcePrimary}, variants[0])
+
+	sources := map[string]TranslationVariant{}
+	for _, variant := range variants {
+		sources[variant.Text] = variant
+		assert.NotContains(t, variant.Text, "(")
+	}
+
+	assert.Equal(t, VariantSourceProviderAlternative, sources["hogar"].Source)
+	assert.Equal(t, VariantSourcePivot, sources["vivienda"].Source)
+	assert.Equal(t, "de", sources["vivienda"].Via)
+	assert.Greater(t, sources["hogar"].Confidence, float32(0))
+	assert.Less(t, sources["hogar"].Confidence, float32(1))
+}
+
+func TestBuildTranslationVariants_BackTranslationFailure(t *testing.T) {
+	provider := &fakeProvider{translations: map[string]string{}}
+
+	variants := buildTranslationVariants(
+		context.Background(),
+		provider,
+		"house",
+		language.English,
+		language.Spanish,
+		ProviderTranslation{Text: "casa"},
+	)
+
+	assert.Equal(t, []TranslationVariant{{Text: "casa", Source: VariantSourcePrimary}}, variants)
+}
+
+func TestSameTranslation(t *testing.T) {
+	assert.True(t, sameTranslation("Casa", "casa"))
+	assert.True(t, sameTranslation("casa.", " casa"))
+	assert.False(t, sameTranslation("casa", "hogar"))
+	assert.False(t, sameTranslation("casa", "casas"))
+}
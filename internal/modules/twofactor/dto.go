@@ -0,0 +1,23 @@
+package twofactor
+
+import "time"
+
+type codeDTO struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+type TwoFactorStatusDTO struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabledAt"`
+	RecoveryCodesRemaining int        `json:"recoveryCodesRemaining"`
+}
+
+type EnrolmentDTO struct {
+	Secret string `json:"secret"`
+	// URI is the otpauth:// link to show as a QR code.
+	URI string `json:"uri"`
+}
+
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,15 @@
+package twofactor
+
+import (
+	"lexia/internal/apperr"
+	"lexia/internal/shared"
+)
+
+var (
+	ErrAlreadyEnabled = apperr.Conflict(shared.ErrTwoFactorAlreadyEnabled, "Two-factor authentication is already enabled")
+	ErrNotEnabled     = apperr.InvalidState(shared.ErrTwoFactorNotEnabled, "Two-factor authentication is not enabled")
+	ErrNotEnrolled    = apperr.InvalidState("TWO_FACTOR_NOT_ENROLLED", "Two-factor enrolment has not been started")
+	// ErrInvalidCode is returned for a wrong, reused or expired TOTP code and
+	// for unknown or spent recovery codes.
+	ErrInvalidCode = apperr.InvalidInput(shared.ErrInvalidTwoFactorCode, "Two-factor code is invalid")
+)
@@ -0,0 +1,134 @@
+package twofactor
+
+import (
+	"lexia/internal/modules/session"
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+func handleGetStatus(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		status, err := GetStatus(c.Request.Context(), apiCfg.DB, authPayload.UserID)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, status)
+	}
+}
+
+func handleEnrol(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		enrolment, err := Enrol(c.Request.Context(), apiCfg.DB, authPayload.UserID)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, enrolment)
+	}
+}
+
+func handleConfirm(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body codeDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		codes, err := Confirm(c.Request.Context(), apiCfg.DB, CodeArgs{
+			UserID: authPayload.UserID,
+			Code:   body.Code,
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, RecoveryCodesDTO{RecoveryCodes: codes})
+	}
+}
+
+func handleRegenerateRecoveryCodes(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body codeDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		codes, err := RegenerateRecoveryCodes(apiCfg, c.Request.Context(), CodeArgs{
+			UserID: authPayload.UserID,
+			Code:   body.Code,
+			Client: getClientInfo(c),
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, RecoveryCodesDTO{RecoveryCodes: codes})
+	}
+}
+
+func handleDisable(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body codeDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		err = Disable(apiCfg, c.Request.Context(), CodeArgs{
+			UserID: authPayload.UserID,
+			Code:   body.Code,
+			Client: getClientInfo(c),
+		})
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResNoContent(c)
+	}
+}
+
+func getClientInfo(c *gin.Context) session.ClientInfo {
+	return session.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
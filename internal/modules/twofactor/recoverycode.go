@@ -0,0 +1,49 @@
+package twofactor
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"lexia/internal/shared"
+	"strings"
+)
+
+const recoveryCodeCount = 10
+
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+// generateRecoveryCode returns a code like "k3mfq-8xw2p". The alphabet skips
+// characters that are easy to confuse when copied by hand.
+func generateRecoveryCode() (string, error) {
+	bytes := make([]byte, 7)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	code := recoveryCodeEncoding.EncodeToString(bytes)[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode normalises a code as typed by the user and hashes it.
+func hashRecoveryCode(code string) string {
+	normalised := strings.ToLower(code)
+	normalised = strings.NewReplacer("-", "", " ", "").Replace(normalised)
+
+	return shared.HashOpaqueToken(normalised)
+}
+
+// isTotpCode tells TOTP codes apart from recovery codes, so clients can send
+// either in the same field.
+func isTotpCode(code string) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return false
+	}
+
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
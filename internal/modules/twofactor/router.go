@@ -0,0 +1,18 @@
+package twofactor
+
+import (
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
+	twoFactorGroup := rg.Group("/user/2fa")
+	{
+		twoFactorGroup.GET("", handleGetStatus(apiCfg))
+		twoFactorGroup.POST("/enrol", handleEnrol(apiCfg))
+		twoFactorGroup.POST("/confirm", handleConfirm(apiCfg))
+		twoFactorGroup.POST("/recovery-codes", handleRegenerateRecoveryCodes(apiCfg))
+		twoFactorGroup.POST("/disable", handleDisable(apiCfg))
+	}
+}
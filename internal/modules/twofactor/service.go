@@ -0,0 +1,308 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"lexia/ent"
+	"lexia/ent/recoverycode"
+	"lexia/ent/user"
+	"lexia/internal/modules/session"
+	"lexia/internal/modules/signinthrottle"
+	"lexia/internal/shared"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func IsEnabled(userEntity *ent.User) bool {
+	return userEntity.TotpEnabledAt != nil
+}
+
+func GetStatus(ctx context.Context, db *ent.Client, userID uuid.UUID) (*TwoFactorStatusDTO, error) {
+	userEntity, err := db.User.Get(ctx, userID)
+	if err != nil {
+		log.Println("Error getting user: ", err)
+		return nil, err
+	}
+
+	remaining, err := db.RecoveryCode.Query().
+		Where(
+			recoverycode.HasUserWith(user.ID(userID)),
+			recoverycode.UsedAtIsNil(),
+		).
+		Count(ctx)
+	if err != nil {
+		log.Println("Error counting recovery codes: ", err)
+		return nil, err
+	}
+
+	return &TwoFactorStatusDTO{
+		Enabled:                IsEnabled(userEntity),
+		EnabledAt:              userEntity.TotpEnabledAt,
+		RecoveryCodesRemaining: remaining,
+	}, nil
+}
+
+// Enrol starts two-factor setup with a fresh secret. Nothing changes for
+// sign-in until the user confirms a code from their authenticator, and
+// enrolling again before that replaces the secret.
+func Enrol(ctx context.Context, db *ent.Client, userID uuid.UUID) (*EnrolmentDTO, error) {
+	userEntity, err := db.User.Get(ctx, userID)
+	if err != nil {
+		log.Println("Error getting user: ", err)
+		return nil, err
+	}
+
+	if IsEnabled(userEntity) {
+		return nil, ErrAlreadyEnabled
+	}
+
+	secret, err := generateTotpSecret()
+	if err != nil {
+		log.Println("Error generating TOTP secret: ", err)
+		return nil, err
+	}
+
+	err = db.User.UpdateOneID(userID).
+		SetTotpSecret(secret).
+		SetTotpLastStep(0).
+		Exec(ctx)
+	if err != nil {
+		log.Println("Error saving TOTP secret: ", err)
+		return nil, err
+	}
+
+	return &EnrolmentDTO{
+		Secret: secret,
+		URI:    totpURI(userEntity.Email, secret),
+	}, nil
+}
+
+type CodeArgs struct {
+	UserID uuid.UUID
+	Code   string
+	Client session.ClientInfo
+}
+
+// Confirm turns two-factor on once the user proves their authenticator
+// works, and returns the recovery codes. They are shown only this once.
+func Confirm(ctx context.Context, db *ent.Client, args CodeArgs) ([]string, error) {
+	userEntity, err := db.User.Get(ctx, args.UserID)
+	if err != nil {
+		log.Println("Error getting user: ", err)
+		return nil, err
+	}
+
+	if IsEnabled(userEntity) {
+		return nil, ErrAlreadyEnabled
+	}
+
+	if userEntity.TotpSecret == nil {
+		return nil, ErrNotEnrolled
+	}
+
+	step, ok := validateTotpCode(*userEntity.TotpSecret, args.Code, time.Now(), userEntity.TotpLastStep)
+	if !ok {
+		return nil, ErrInvalidCode
+	}
+
+	var codes []string
+	err = shared.WithTx(ctx, db, func(client *ent.Client) error {
+		err := client.User.UpdateOneID(args.UserID).
+			SetTotpEnabledAt(time.Now()).
+			SetTotpLastStep(step).
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		codes, err = replaceRecoveryCodes(ctx, client, args.UserID)
+		return err
+	})
+	if err != nil {
+		log.Println("Error enabling two-factor: ", err)
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Disable turns two-factor off. It takes a current code or a recovery code,
+// so a stolen access token alone cannot remove the second factor.
+func Disable(apiCfg *shared.ApiConfig, ctx context.Context, args CodeArgs) error {
+	userEntity, err := requireEnabled(ctx, apiCfg.DB, args.UserID)
+	if err != nil {
+		return err
+	}
+
+	return withSecondFactor(apiCfg, ctx, userEntity, args, func(client *ent.Client) error {
+		err := client.User.UpdateOneID(args.UserID).
+			ClearTotpSecret().
+			ClearTotpEnabledAt().
+			SetTotpLastStep(0).
+			Exec(ctx)
+		if err != nil {
+			log.Println("Error disabling two-factor: ", err)
+			return err
+		}
+
+		_, err = client.RecoveryCode.Delete().
+			Where(recoverycode.HasUserWith(user.ID(args.UserID))).
+			Exec(ctx)
+		if err != nil {
+			log.Println("Error deleting recovery codes: ", err)
+		}
+
+		return err
+	})
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, used or not.
+func RegenerateRecoveryCodes(apiCfg *shared.ApiConfig, ctx context.Context, args CodeArgs) ([]string, error) {
+	userEntity, err := requireEnabled(ctx, apiCfg.DB, args.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []string
+	err = withSecondFactor(apiCfg, ctx, userEntity, args, func(client *ent.Client) error {
+		var err error
+		codes, err = replaceRecoveryCodes(ctx, client, args.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// withSecondFactor checks the code and runs fn in the same transaction if it
+// is right. Wrong codes count towards the sign-in throttle, as they do when
+// signing in, so an access token cannot be used to guess codes either.
+func withSecondFactor(
+	apiCfg *shared.ApiConfig,
+	ctx context.Context,
+	userEntity *ent.User,
+	args CodeArgs,
+	fn func(client *ent.Client) error,
+) error {
+	throttleKeys := signinthrottle.GetKeys(userEntity.Email, args.Client.IP)
+	if err := signinthrottle.Attempt(apiCfg, ctx, throttleKeys, userEntity.Email, args.Client); err != nil {
+		return err
+	}
+
+	err := shared.WithTx(ctx, apiCfg.DB, func(client *ent.Client) error {
+		if err := VerifySecondFactor(ctx, client, userEntity, args.Code); err != nil {
+			return err
+		}
+		return fn(client)
+	})
+	if errors.Is(err, ErrInvalidCode) {
+		signinthrottle.RecordFailure(apiCfg, ctx, throttleKeys, userEntity.Email, &userEntity.ID, args.Client)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	signinthrottle.Forgive(apiCfg, ctx, throttleKeys)
+	return nil
+}
+
+// VerifySecondFactor checks a TOTP or recovery code for a user with
+// two-factor enabled and spends it. A wrong code is ErrInvalidCode.
+func VerifySecondFactor(ctx context.Context, db *ent.Client, userEntity *ent.User, code string) error {
+	if isTotpCode(code) {
+		if userEntity.TotpSecret == nil {
+			return ErrInvalidCode
+		}
+
+		step, ok := validateTotpCode(*userEntity.TotpSecret, code, time.Now(), userEntity.TotpLastStep)
+		if !ok {
+			return ErrInvalidCode
+		}
+
+		// The step only moves forward, so of two requests racing with the
+		// same code only one updates a row.
+		updated, err := db.User.Update().
+			Where(
+				user.ID(userEntity.ID),
+				user.TotpLastStepLT(step),
+			).
+			SetTotpLastStep(step).
+			Save(ctx)
+		if err != nil {
+			log.Println("Error saving TOTP step: ", err)
+			return err
+		}
+
+		if updated == 0 {
+			return ErrInvalidCode
+		}
+
+		return nil
+	}
+
+	used, err := db.RecoveryCode.Update().
+		Where(
+			recoverycode.HasUserWith(user.ID(userEntity.ID)),
+			recoverycode.CodeHash(hashRecoveryCode(code)),
+			recoverycode.UsedAtIsNil(),
+		).
+		SetUsedAt(time.Now()).
+		Save(ctx)
+	if err != nil {
+		log.Println("Error using recovery code: ", err)
+		return err
+	}
+
+	if used == 0 {
+		return ErrInvalidCode
+	}
+
+	return nil
+}
+
+func requireEnabled(ctx context.Context, db *ent.Client, userID uuid.UUID) (*ent.User, error) {
+	userEntity, err := db.User.Get(ctx, userID)
+	if err != nil {
+		log.Println("Error getting user: ", err)
+		return nil, err
+	}
+
+	if !IsEnabled(userEntity) {
+		return nil, ErrNotEnabled
+	}
+
+	return userEntity, nil
+}
+
+func replaceRecoveryCodes(ctx context.Context, db *ent.Client, userID uuid.UUID) ([]string, error) {
+	_, err := db.RecoveryCode.Delete().
+		Where(recoverycode.HasUserWith(user.ID(userID))).
+		Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	builders := make([]*ent.RecoveryCodeCreate, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		builders[i] = db.RecoveryCode.Create().
+			SetUserID(userID).
+			SetCodeHash(hashRecoveryCode(codes[i]))
+	}
+
+	if err := db.RecoveryCode.CreateBulk(builders...).Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
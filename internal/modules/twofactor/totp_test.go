@@ -0,0 +1,99 @@
+package twofactor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHotpMatchesRFC6238Vectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+	}
+
+	for _, tc := range testCases {
+		if got := hotp(key, totpStep(time.Unix(tc.unix, 0)), 8); got != tc.code {
+			t.Errorf("hotp at %d = %s, want %s", tc.unix, got, tc.code)
+		}
+	}
+}
+
+func TestValidateTotpCode(t *testing.T) {
+	secret, err := generateTotpSecret()
+	if err != nil {
+		t.Fatalf("generateTotpSecret: %v", err)
+	}
+
+	key, _ := secretEncoding.DecodeString(secret)
+	now := time.Unix(1_700_000_000, 0)
+	step := totpStep(now)
+
+	if got, ok := validateTotpCode(secret, hotp(key, step, totpDigits), now, 0); !ok || got != step {
+		t.Errorf("current code rejected")
+	}
+
+	if _, ok := validateTotpCode(strings.ToLower(secret), hotp(key, step, totpDigits), now, 0); !ok {
+		t.Errorf("lower-case secret rejected")
+	}
+
+	if _, ok := validateTotpCode(secret, hotp(key, step-1, totpDigits), now, 0); !ok {
+		t.Errorf("previous step rejected despite skew")
+	}
+
+	if _, ok := validateTotpCode(secret, hotp(key, step-2, totpDigits), now, 0); ok {
+		t.Errorf("code two steps old accepted")
+	}
+
+	if _, ok := validateTotpCode(secret, hotp(key, step, totpDigits), now, step); ok {
+		t.Errorf("replayed code accepted")
+	}
+
+	if _, ok := validateTotpCode(secret, "12345", now, 0); ok {
+		t.Errorf("short code accepted")
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	code, err := generateRecoveryCode()
+	if err != nil {
+		t.Fatalf("generateRecoveryCode: %v", err)
+	}
+
+	if len(code) != 11 || code[5] != '-' {
+		t.Errorf("unexpected recovery code format %q", code)
+	}
+
+	typed := strings.ToUpper(strings.Replace(code, "-", " ", 1))
+	if hashRecoveryCode(typed) != hashRecoveryCode(code) {
+		t.Errorf("recovery code hash depends on case or separator")
+	}
+
+	if isTotpCode(code) {
+		t.Errorf("recovery code detected as TOTP code")
+	}
+
+	if !isTotpCode(" 123456 ") || isTotpCode("12345a") {
+		t.Errorf("isTotpCode misclassifies codes")
+	}
+}
+
+func TestTotpURI(t *testing.T) {
+	uri := totpURI("user@example.com", "ABCDEF")
+
+	if !strings.HasPrefix(uri, "otpauth://totp/Lexia:user@example.com?") {
+		t.Errorf("unexpected label in %q", uri)
+	}
+
+	if !strings.Contains(uri, "secret=ABCDEF") || !strings.Contains(uri, "issuer=Lexia") {
+		t.Errorf("missing parameters in %q", uri)
+	}
+}
@@ -10,9 +10,16 @@ type updateUserDTO struct {
 	Username string `json:"username" validate:"username" binding:"required,min=2,max=50,alphanum"`
 }
 
+type updateUserPreferencesDTO struct {
+	AutofillDefinitions *bool `json:"autofillDefinitions" binding:"required"`
+}
+
 type UserDto struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"createdAt"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
+	ID                  uuid.UUID `json:"id"`
+	CreatedAt           time.Time `json:"createdAt"`
+	Username            string    `json:"username"`
+	Email               string    `json:"email"`
+	EmailVerified       bool      `json:"emailVerified"`
+	TwoFactorEnabled    bool      `json:"twoFactorEnabled"`
+	AutofillDefinitions bool      `json:"autofillDefinitions"`
 }
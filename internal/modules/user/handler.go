@@ -29,7 +29,7 @@ func handleUpdateAuthUser(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 		)
 
 		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
@@ -54,3 +54,34 @@ func handleGetAuthUser(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 		shared.ResOK(c, UserEntityToDto(user))
 	}
 }
+
+func handleUpdateUserPreferences(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var body updateUserPreferencesDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		user, err := UpdateUserPreferences(
+			c.Request.Context(), apiCfg.DB,
+			UpdateUserPreferencesArgs{
+				UserID:              authPayload.UserID,
+				AutofillDefinitions: *body.AutofillDefinitions,
+			},
+		)
+
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, UserEntityToDto(user))
+	}
+}
@@ -0,0 +1,115 @@
+package user
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/folder"
+	"lexia/ent/user"
+	"lexia/ent/word"
+	"lexia/internal/logger"
+	"lexia/internal/shared"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DefaultAccountPurgeInterval = time.Hour
+
+// AccountDeletionGracePeriod is how long a deleted account can still be
+// restored by signing in before it is purged.
+func AccountDeletionGracePeriod(envVars *shared.EnvVariables) time.Duration {
+	return time.Duration(envVars.AccountDeletionGraceDays) * 24 * time.Hour
+}
+
+// StartAccountPurger periodically purges accounts whose deletion grace period
+// has passed. It returns when ctx is cancelled.
+func StartAccountPurger(ctx context.Context, db *ent.Client, gracePeriod time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := PurgeDeletedUsers(ctx, db, gracePeriod); err != nil {
+				logger.Error("Failed to purge deleted accounts: ", err)
+			}
+		}
+	}
+}
+
+// lockDeletedUserQuery locks a user that is still due for purging, so a
+// sign-in cannot cancel the deletion halfway through the purge.
+const lockDeletedUserQuery = `
+SELECT id FROM users
+WHERE id = $1 AND deleted_at <= $2
+FOR UPDATE
+`
+
+// PurgeDeletedUsers removes every user deleted more than gracePeriod ago with
+// their folders and words, and returns how many were removed. Folders and
+// words are deleted explicitly because their foreign keys only set the
+// owner to NULL; sessions, tokens, settings and reviews cascade.
+func PurgeDeletedUsers(ctx context.Context, db *ent.Client, gracePeriod time.Duration) (int, error) {
+	cutoff := time.Now().Add(-gracePeriod)
+
+	userIDs, err := db.User.Query().
+		Where(user.DeletedAtLTE(cutoff)).
+		IDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, userID := range userIDs {
+		if ctx.Err() != nil {
+			return purged, ctx.Err()
+		}
+
+		deleted := false
+		err := shared.WithTx(ctx, db, func(client *ent.Client) error {
+			locked, err := lockDeletedUser(ctx, client, userID, cutoff)
+			if err != nil || !locked {
+				return err
+			}
+
+			_, err = client.Word.Delete().
+				Where(word.HasFolderWith(folder.HasUserWith(user.ID(userID)))).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+
+			_, err = client.Folder.Delete().
+				Where(folder.HasUserWith(user.ID(userID))).
+				Exec(ctx)
+			if err != nil {
+				return err
+			}
+
+			deleted = true
+			return client.User.DeleteOneID(userID).Exec(ctx)
+		})
+		if err != nil {
+			logger.Error("Failed to purge deleted user ", userID, ": ", err)
+			continue
+		}
+
+		if deleted {
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+func lockDeletedUser(ctx context.Context, client *ent.Client, userID uuid.UUID, cutoff time.Time) (bool, error) {
+	rows, err := client.QueryContext(ctx, lockDeletedUserQuery, userID, cutoff)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	return rows.Next(), rows.Err()
+}
@@ -11,5 +11,6 @@ func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
 	{
 		userGroup.GET("/auth", handleGetAuthUser(apiCfg))
 		userGroup.PUT("/auth", handleUpdateAuthUser(apiCfg))
+		userGroup.PUT("/preferences", handleUpdateUserPreferences(apiCfg))
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"lexia/ent"
 	"lexia/ent/user"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -12,7 +13,10 @@ import (
 type CreateUserArgs struct {
 	Username string
 	Email    string
-	Password string
+	// Password is a hash, or empty for users signing up through an
+	// identity provider.
+	Password      string
+	EmailVerified bool
 }
 
 func CreateUser(
@@ -25,6 +29,7 @@ func CreateUser(
 		SetUsername(args.Username).
 		SetEmail(args.Email).
 		SetPassword(args.Password).
+		SetEmailVerified(args.EmailVerified).
 		Save(ctx)
 
 	if err != nil {
@@ -94,6 +99,28 @@ func UpdateUserByID(
 	return updatedUser, nil
 }
 
+type UpdateUserPreferencesArgs struct {
+	UserID              uuid.UUID
+	AutofillDefinitions bool
+}
+
+func UpdateUserPreferences(
+	ctx context.Context,
+	db *ent.Client,
+	args UpdateUserPreferencesArgs,
+) (*ent.User, error) {
+	updatedUser, err := db.User.UpdateOneID(args.UserID).
+		SetAutofillDefinitions(args.AutofillDefinitions).
+		Save(ctx)
+
+	if err != nil {
+		log.Println("Error updating user preferences: ", err)
+		return nil, err
+	}
+
+	return updatedUser, nil
+}
+
 func UserExistsByEmail(
 	ctx context.Context,
 	db *ent.Client,
@@ -110,3 +137,95 @@ func UserExistsByEmail(
 
 	return count > 0, nil
 }
+
+func UpdateUserPassword(
+	ctx context.Context,
+	db *ent.Client,
+	userID uuid.UUID,
+	passwordHash string,
+) error {
+	err := db.User.UpdateOneID(userID).
+		SetPassword(passwordHash).
+		Exec(ctx)
+
+	if err != nil {
+		log.Println("Error updating user password: ", err)
+		return err
+	}
+
+	return nil
+}
+
+func MarkUserEmailVerified(
+	ctx context.Context,
+	db *ent.Client,
+	userID uuid.UUID,
+) error {
+	err := db.User.UpdateOneID(userID).
+		SetEmailVerified(true).
+		Exec(ctx)
+
+	if err != nil {
+		log.Println("Error marking user email verified: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateUserEmail changes the email and marks it unverified until the new
+// address is verified.
+func UpdateUserEmail(
+	ctx context.Context,
+	db *ent.Client,
+	userID uuid.UUID,
+	email string,
+) (*ent.User, error) {
+	updatedUser, err := db.User.UpdateOneID(userID).
+		SetEmail(email).
+		SetEmailVerified(false).
+		Save(ctx)
+
+	if err != nil {
+		log.Println("Error updating user email: ", err)
+		return nil, err
+	}
+
+	return updatedUser, nil
+}
+
+func MarkUserDeleted(
+	ctx context.Context,
+	db *ent.Client,
+	userID uuid.UUID,
+	deletedAt time.Time,
+) error {
+	err := db.User.UpdateOneID(userID).
+		SetDeletedAt(deletedAt).
+		Exec(ctx)
+
+	if err != nil {
+		log.Println("Error marking user deleted: ", err)
+		return err
+	}
+
+	return nil
+}
+
+// RestoreUser cancels a pending account deletion.
+func RestoreUser(
+	ctx context.Context,
+	db *ent.Client,
+	userID uuid.UUID,
+) error {
+	err := db.User.UpdateOneID(userID).
+		ClearDeletedAt().
+		Exec(ctx)
+
+	if err != nil {
+		log.Println("Error restoring user: ", err)
+		return err
+	}
+
+	return nil
+}
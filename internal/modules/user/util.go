@@ -6,9 +6,12 @@ import (
 
 func UserEntityToDto(userEntity *ent.User) UserDto {
 	return UserDto{
-		ID:        userEntity.ID,
-		CreatedAt: userEntity.CreateTime,
-		Username:  userEntity.Username,
-		Email:     userEntity.Email,
+		ID:                  userEntity.ID,
+		CreatedAt:           userEntity.CreateTime,
+		Username:            userEntity.Username,
+		Email:               userEntity.Email,
+		EmailVerified:       userEntity.EmailVerified,
+		TwoFactorEnabled:    userEntity.TotpEnabledAt != nil,
+		AutofillDefinitions: userEntity.AutofillDefinitions,
 	}
 }
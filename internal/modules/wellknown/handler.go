@@ -0,0 +1,18 @@
+package wellknown
+
+import (
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+// jwksMaxAge lets verifiers cache the key set for a while. A new key should
+// be published at least this long before it starts signing.
+const jwksMaxAge = "public, max-age=300"
+
+func handleGetJWKS(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", jwksMaxAge)
+		shared.ResOK(c, apiCfg.Tokens.JWKS())
+	}
+}
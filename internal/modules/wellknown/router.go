@@ -0,0 +1,14 @@
+package wellknown
+
+import (
+	"lexia/internal/shared"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
+	wellKnownGroup := rg.Group("/.well-known")
+	{
+		wellKnownGroup.GET("/jwks.json", handleGetJWKS(apiCfg))
+	}
+}
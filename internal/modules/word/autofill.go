@@ -0,0 +1,325 @@
+package word
+
+import (
+	"context"
+	"errors"
+	"lexia/ent"
+	"lexia/ent/autofilljob"
+	"lexia/ent/folder"
+	"lexia/ent/schema"
+	"lexia/ent/user"
+	"lexia/ent/word"
+	"lexia/internal/authz"
+	"lexia/internal/jobqueue"
+	"lexia/internal/service"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	autofillBatchSize    = 50
+	autofillPollInterval = 5 * time.Second
+	// autofillJobStaleAfter is how long a running job may go without saving
+	// progress before it is considered abandoned. One batch takes well under
+	// a minute, even with retries.
+	autofillJobStaleAfter = 5 * time.Minute
+)
+
+type (
+	StartAutofillJobArgs = service.StartAutofillJobArgs
+	GetAutofillJobArgs   = service.GetAutofillJobArgs
+)
+
+// shouldAutofill decides whether an empty definition is filled on create:
+// an explicit per-request choice wins over the preference of the user
+// adding the word, who may be an editor of someone else's folder, and the
+// folder must have both languages set.
+func (s *entService) shouldAutofill(ctx context.Context, folderEntity *ent.Folder, userID uuid.UUID, requested *bool) bool {
+	if folderEntity.LanguageFrom == nil || folderEntity.LanguageTo == nil {
+		return false
+	}
+
+	if requested != nil {
+		return *requested
+	}
+
+	userEntity, err := s.db.User.Query().
+		Where(user.ID(userID)).
+		Select(user.FieldAutofillDefinitions).
+		Only(ctx)
+	if err != nil {
+		log.Println("Error getting autofill preference: ", err)
+		return false
+	}
+
+	return userEntity.AutofillDefinitions
+}
+
+// autofillDefinition returns the primary translation of text, or an empty
+// string if the provider fails. A failed lookup never blocks creating the
+// word.
+func (s *entService) autofillDefinition(ctx context.Context, folderEntity *ent.Folder, text string) string {
+	variants, err := s.translations.TranslateText(ctx, text, *folderEntity.LanguageFrom, *folderEntity.LanguageTo)
+	if err != nil {
+		log.Println("Error auto-filling definition: ", err)
+		return ""
+	}
+
+	if len(variants) == 0 {
+		return ""
+	}
+
+	return variants[0].Text
+}
+
+// StartAutofillJob queues a job that fills every empty definition in the
+// folder; the autofill worker runs it. If a job for the folder is still
+// pending or running, that job is returned instead of starting another one.
+// The database allows only one such job per folder, so of two requests at
+// once the one that loses gets the other's job.
+func (s *entService) StartAutofillJob(
+	ctx context.Context,
+	args StartAutofillJobArgs,
+) (*ent.AutofillJob, error) {
+	folderEntity, err := authz.CanEditFolder(ctx, s.db, args.UserID, args.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if folderEntity.LanguageFrom == nil || folderEntity.LanguageTo == nil {
+		return nil, ErrLanguagePairRequired
+	}
+
+	activeJob, err := s.getActiveAutofillJob(ctx, args.FolderID)
+	if err == nil {
+		return activeJob, nil
+	}
+	if !ent.IsNotFound(err) {
+		log.Println("Error finding active autofill job: ", err)
+		return nil, err
+	}
+
+	total, err := s.db.Word.Query().
+		Where(
+			word.HasFolderWith(folder.ID(args.FolderID)),
+			word.Definition(""),
+		).
+		Count(ctx)
+	if err != nil {
+		log.Println("Error counting words to autofill: ", err)
+		return nil, err
+	}
+
+	job, err := s.db.AutofillJob.Create().
+		SetFolderID(args.FolderID).
+		SetTotal(int32(total)).
+		Save(ctx)
+	if ent.IsConstraintError(err) {
+		// Another request started a job since the check above.
+		activeJob, err := s.getActiveAutofillJob(ctx, args.FolderID)
+		if err != nil {
+			log.Println("Error finding active autofill job: ", err)
+			return nil, err
+		}
+		return activeJob, nil
+	}
+	if err != nil {
+		log.Println("Error creating autofill job: ", err)
+		return nil, err
+	}
+	job.Edges.Folder = folderEntity
+
+	s.autofill.Notify()
+
+	return job, nil
+}
+
+func (s *entService) getActiveAutofillJob(ctx context.Context, folderID uuid.UUID) (*ent.AutofillJob, error) {
+	return s.db.AutofillJob.Query().
+		Where(
+			autofilljob.HasFolderWith(folder.ID(folderID)),
+			autofilljob.StatusIn(schema.AutofillJobStatusPending, schema.AutofillJobStatusRunning),
+		).
+		WithFolder().
+		Only(ctx)
+}
+
+func (s *entService) GetAutofillJob(
+	ctx context.Context,
+	args GetAutofillJobArgs,
+) (*ent.AutofillJob, error) {
+	if _, err := authz.CanReadFolder(ctx, s.db, args.UserID, args.FolderID); err != nil {
+		return nil, err
+	}
+
+	job, err := s.db.AutofillJob.Query().
+		Where(
+			autofilljob.ID(args.JobID),
+			autofilljob.HasFolderWith(folder.ID(args.FolderID)),
+		).
+		WithFolder().
+		Only(ctx)
+	if err != nil {
+		if ent.IsNotFound(err) {
+			return nil, ErrAutofillJobNotFound
+		}
+		log.Println("Error getting autofill job: ", err)
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// NewAutofillWorker returns the runner for autofill jobs. StartAutofillJob
+// wakes it up.
+func NewAutofillWorker(db *ent.Client, translations service.TranslationService) *jobqueue.Runner {
+	return jobqueue.NewRunner(
+		autofillQueue{db: db, translations: translations},
+		jobqueue.Config{
+			Name:         "autofill",
+			PollInterval: autofillPollInterval,
+			StaleAfter:   autofillJobStaleAfter,
+		},
+	)
+}
+
+// autofillQueue is the jobqueue.Queue of autofill jobs.
+type autofillQueue struct {
+	db           *ent.Client
+	translations service.TranslationService
+}
+
+func (q autofillQueue) NextPending(ctx context.Context) (uuid.UUID, bool, error) {
+	jobID, err := q.db.AutofillJob.Query().
+		Where(autofilljob.StatusEQ(schema.AutofillJobStatusPending)).
+		Order(ent.Asc(autofilljob.FieldCreateTime)).
+		FirstID(ctx)
+	if ent.IsNotFound(err) {
+		return uuid.Nil, false, nil
+	}
+
+	return jobID, err == nil, err
+}
+
+func (q autofillQueue) Claim(ctx context.Context, jobID uuid.UUID) (bool, error) {
+	claimed, err := q.db.AutofillJob.Update().
+		Where(
+			autofilljob.ID(jobID),
+			autofilljob.StatusEQ(schema.AutofillJobStatusPending),
+		).
+		SetStatus(schema.AutofillJobStatusRunning).
+		Save(ctx)
+
+	return claimed > 0, err
+}
+
+func (q autofillQueue) Run(ctx context.Context, jobID uuid.UUID) error {
+	folderEntity, err := q.db.AutofillJob.Query().
+		Where(autofilljob.ID(jobID)).
+		QueryFolder().
+		Only(ctx)
+	if err != nil {
+		return err
+	}
+
+	if folderEntity.LanguageFrom == nil || folderEntity.LanguageTo == nil {
+		return ErrLanguagePairRequired
+	}
+
+	words, err := q.db.Word.Query().
+		Where(
+			word.HasFolderWith(folder.ID(folderEntity.ID)),
+			word.Definition(""),
+		).
+		Order(ent.Asc(word.FieldCreateTime)).
+		All(ctx)
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(words); start += autofillBatchSize {
+		if ctx.Err() != nil {
+			return errors.New("Interrupted by server shutdown")
+		}
+
+		batch := words[start:min(start+autofillBatchSize, len(words))]
+
+		texts := make([]string, len(batch))
+		for i, wordEntity := range batch {
+			texts[i] = wordEntity.Text
+		}
+
+		results, err := q.translations.TranslateBatch(ctx, texts, *folderEntity.LanguageFrom, *folderEntity.LanguageTo)
+		if err != nil {
+			return err
+		}
+
+		var filled, failed int32
+		for i, result := range results {
+			if result.Err != nil || result.Translation == "" {
+				failed++
+				continue
+			}
+
+			// Only fill definitions that are still empty, in case the user
+			// wrote one while the job was running.
+			updated, err := q.db.Word.Update().
+				Where(word.ID(batch[i].ID), word.Definition("")).
+				SetDefinition(result.Translation).
+				Save(ctx)
+			if err != nil {
+				log.Println("Error saving autofilled definition: ", err)
+				failed++
+				continue
+			}
+			filled += int32(updated)
+		}
+
+		// Saving progress also moves the job's update time, which tells
+		// FailStale it is still running.
+		err = q.db.AutofillJob.UpdateOneID(jobID).
+			AddProcessed(int32(len(batch))).
+			AddFilled(filled).
+			AddFailed(failed).
+			Exec(ctx)
+		if err != nil {
+			log.Println("Error updating autofill job progress: ", err)
+		}
+	}
+
+	return nil
+}
+
+func (q autofillQueue) Finish(ctx context.Context, jobID uuid.UUID, jobErr error) error {
+	update := q.db.AutofillJob.UpdateOneID(jobID).
+		SetFinishedAt(time.Now())
+
+	if jobErr != nil {
+		update = update.
+			SetStatus(schema.AutofillJobStatusFailed).
+			SetError(jobErr.Error())
+	} else {
+		update = update.SetStatus(schema.AutofillJobStatusCompleted)
+	}
+
+	return update.Exec(ctx)
+}
+
+// FailStale fails jobs left behind by a server that stopped while running
+// them. Jobs other replicas are running save progress after every batch, so
+// they are never this far behind.
+func (q autofillQueue) FailStale(ctx context.Context, before time.Time, now time.Time) error {
+	_, err := q.db.AutofillJob.Update().
+		Where(
+			autofilljob.StatusEQ(schema.AutofillJobStatusRunning),
+			autofilljob.UpdateTimeLT(before),
+		).
+		SetStatus(schema.AutofillJobStatusFailed).
+		SetError(jobqueue.StaleJobError).
+		SetFinishedAt(now).
+		Save(ctx)
+
+	return err
+}
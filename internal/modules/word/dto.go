@@ -1,15 +1,34 @@
 package word
 
 import (
+	"lexia/internal/modules/folder"
+	"lexia/internal/modules/translate"
+	"lexia/internal/pagination"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type CreateWordDTO struct {
-	Text       string    `json:"text" validate:"required,min=1,max=500"`
-	Definition string    `json:"definition" validate:"max=2000"`
-	FolderID   uuid.UUID `json:"folderId" validate:"required"`
+	Text               string    `json:"text" validate:"required,min=1,max=500"`
+	Definition         string    `json:"definition" validate:"max=2000"`
+	FolderID           uuid.UUID `json:"folderId" validate:"required"`
+	AutofillDefinition *bool     `json:"autofillDefinition"`
+}
+
+type CreateWordFromTranslationDTO struct {
+	Text         string `json:"text" binding:"required,min=1,max=500"`
+	VariantIndex int    `json:"variantIndex" binding:"min=0"`
+}
+
+type WordFromTranslationDTO struct {
+	Word          WordWithFolderDTO              `json:"word"`
+	ChosenVariant translate.TranslationVariant   `json:"chosenVariant"`
+	OtherVariants []translate.TranslationVariant `json:"otherVariants"`
+}
+
+type WordFromTranslationConflictDTO struct {
+	Word *WordWithFolderPathDTO `json:"word"`
 }
 
 type UpdateWordDTO struct {
@@ -17,6 +36,26 @@ type UpdateWordDTO struct {
 	Definition *string `json:"definition" validate:"omitempty,max=2000"`
 }
 
+type ListWordsQueryDTO struct {
+	pagination.QueryDTO
+	CreatedAfter  *time.Time `form:"createdAfter"`
+	CreatedBefore *time.Time `form:"createdBefore"`
+	HasDefinition *bool      `form:"hasDefinition"`
+}
+
+type SearchWordsQueryDTO struct {
+	Query string `form:"q" binding:"required,max=200"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type WordSearchResultDTO struct {
+	Word              WordWithFolderPathDTO `json:"word"`
+	TextSnippet       string                `json:"textSnippet"`
+	DefinitionSnippet string                `json:"definitionSnippet"`
+	Rank              float64               `json:"rank"`
+	Fuzzy             bool                  `json:"fuzzy"`
+}
+
 type WordDTO struct {
 	ID         uuid.UUID `json:"id"`
 	CreatedAt  time.Time `json:"createdAt"`
@@ -52,7 +91,18 @@ type WordWithFolderPathDTO struct {
 	FolderPath []FolderPathItemDTO `json:"folderPath"`
 }
 
-type FolderPathItemDTO struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+type FolderPathItemDTO = folder.FolderPathItemDTO
+
+type AutofillJobDTO struct {
+	ID         uuid.UUID  `json:"id"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	FolderID   uuid.UUID  `json:"folderId"`
+	Status     string     `json:"status"`
+	Total      int32      `json:"total"`
+	Processed  int32      `json:"processed"`
+	Filled     int32      `json:"filled"`
+	Failed     int32      `json:"failed"`
+	Error      *string    `json:"error"`
+	FinishedAt *time.Time `json:"finishedAt"`
 }
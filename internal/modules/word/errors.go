@@ -0,0 +1,23 @@
+package word
+
+import (
+	"fmt"
+	"lexia/internal/apperr"
+)
+
+var (
+	ErrLanguagePairRequired = apperr.InvalidState("LANGUAGE_PAIR_REQUIRED", "Folder must have languageFrom and languageTo set")
+	ErrAutofillJobNotFound  = apperr.NotFound("AUTOFILL_JOB_NOT_FOUND", "Autofill job not found")
+	ErrSearchQueryRequired  = apperr.InvalidInput("SEARCH_QUERY_REQUIRED", "Search query is required")
+)
+
+// ErrWordAlreadyExists carries the existing word so clients can show it.
+func ErrWordAlreadyExists(word WordWithFolderPathDTO) *apperr.Error {
+	err := apperr.Conflict("WORD_ALREADY_EXISTS", "Word already exists")
+	err.Details = WordFromTranslationConflictDTO{Word: &word}
+	return err
+}
+
+func errVariantIndexOutOfRange(variants int) *apperr.Error {
+	return apperr.InvalidInput("VARIANT_INDEX_OUT_OF_RANGE", fmt.Sprintf("variantIndex must be between 0 and %d", variants-1))
+}
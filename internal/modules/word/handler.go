@@ -1,6 +1,8 @@
 package word
 
 import (
+	"lexia/internal/modules/translate"
+	"lexia/internal/pagination"
 	"lexia/internal/shared"
 	"net/http"
 
@@ -22,34 +24,41 @@ func handleCreateWord(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		word, err := CreateWord(
+		// Autofill calls the translation provider, so a token without the
+		// translate scope only adds the word.
+		autofill := body.AutofillDefinition
+		if !authPayload.HasScope(shared.ScopeTranslate) {
+			autofill = new(bool)
+		}
+
+		word, err := apiCfg.Words.CreateWord(
 			c.Request.Context(),
-			apiCfg.DB,
 			CreateWordArgs{
 				Text:       body.Text,
 				Definition: body.Definition,
 				FolderID:   body.FolderID,
 				UserID:     authPayload.UserID,
+				Autofill:   autofill,
 			},
 		)
 
 		if err != nil {
-			shared.ResInternalServerErrorDef(c)
-			return
-		}
-
-		wordWithFolder, err := GetWordByIDWithFolder(c.Request.Context(), apiCfg.DB, word.ID)
-		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
-		shared.ResCreated(c, WordEntityWithFolderToDTO(wordWithFolder))
+		shared.ResCreated(c, WordEntityWithFolderToDTO(word))
 	}
 }
 
 func handleGetWord(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
 		wordIDStr := c.Param("wordId")
 		wordID, err := uuid.Parse(wordIDStr)
 		if err != nil {
@@ -57,9 +66,9 @@ func handleGetWord(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		word, err := GetWordByIDWithFolder(c.Request.Context(), apiCfg.DB, wordID)
+		word, err := apiCfg.Words.GetWord(c.Request.Context(), wordID, authPayload.UserID)
 		if err != nil {
-			shared.ResNotFound(c, "Word not found")
+			shared.ResError(c, err)
 			return
 		}
 
@@ -82,19 +91,30 @@ func handleGetWordsByFolder(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		words, err := GetWordsByFolderID(
+		var query ListWordsQueryDTO
+		if validationErr := shared.BindQueryAndValidate(c, &query); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		words, err := apiCfg.Words.GetWordsByFolderID(
 			c.Request.Context(),
-			apiCfg.DB,
-			folderID,
-			authPayload.UserID,
+			ListWordsArgs{
+				FolderID:      folderID,
+				UserID:        authPayload.UserID,
+				Page:          query.Params(),
+				CreatedAfter:  query.CreatedAfter,
+				CreatedBefore: query.CreatedBefore,
+				HasDefinition: query.HasDefinition,
+			},
 		)
 
 		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
-		shared.ResOK(c, WordEntitiesToDTOs(words))
+		shared.ResOK(c, pagination.PageToDTO(words, WordEntityToDTO))
 	}
 }
 
@@ -119,9 +139,8 @@ func handleUpdateWord(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		word, err := UpdateWord(
+		word, err := apiCfg.Words.UpdateWord(
 			c.Request.Context(),
-			apiCfg.DB,
 			UpdateWordArgs{
 				WordID:     wordID,
 				UserID:     authPayload.UserID,
@@ -131,17 +150,11 @@ func handleUpdateWord(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 		)
 
 		if err != nil {
-			shared.ResInternalServerErrorDef(c)
-			return
-		}
-
-		wordWithFolder, err := GetWordByIDWithFolder(c.Request.Context(), apiCfg.DB, word.ID)
-		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
-		shared.ResOK(c, WordEntityWithFolderToDTO(wordWithFolder))
+		shared.ResOK(c, WordEntityWithFolderToDTO(word))
 	}
 }
 
@@ -160,15 +173,14 @@ func handleDeleteWord(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		err = DeleteWord(
+		err = apiCfg.Words.DeleteWord(
 			c.Request.Context(),
-			apiCfg.DB,
 			wordID,
 			authPayload.UserID,
 		)
 
 		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
@@ -176,6 +188,38 @@ func handleDeleteWord(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 	}
 }
 
+func handleSearchWords(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		var query SearchWordsQueryDTO
+		if validationErr := shared.BindQueryAndValidate(c, &query); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		results, err := apiCfg.Words.SearchWords(
+			c.Request.Context(),
+			SearchWordsArgs{
+				UserID: authPayload.UserID,
+				Query:  query.Query,
+				Limit:  query.Limit,
+			},
+		)
+
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, WordSearchResultsToDTOs(results))
+	}
+}
+
 func handleCheckWordDuplicate(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authPayload, err := shared.GetAuthPayload(c)
@@ -190,15 +234,14 @@ func handleCheckWordDuplicate(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 			return
 		}
 
-		duplicateWord, err := CheckWordDuplicate(
+		duplicateWord, err := apiCfg.Words.CheckWordDuplicate(
 			c.Request.Context(),
-			apiCfg.DB,
 			text,
 			authPayload.UserID,
 		)
 
 		if err != nil {
-			shared.ResInternalServerErrorDef(c)
+			shared.ResError(c, err)
 			return
 		}
 
@@ -207,10 +250,141 @@ func handleCheckWordDuplicate(apiCfg *shared.ApiConfig) gin.HandlerFunc {
 		}
 
 		if duplicateWord != nil {
-			wordDTO := WordEntityWithFolderPathToDTO(duplicateWord)
+			folderPath, err := apiCfg.Words.GetWordFolderPath(c.Request.Context(), duplicateWord)
+			if err != nil {
+				shared.ResError(c, err)
+				return
+			}
+
+			wordDTO := WordEntityWithFolderPathToDTO(duplicateWord, folderPath)
 			response.Word = &wordDTO
 		}
 
 		shared.ResOK(c, response)
 	}
 }
+
+func handleCreateWordFromTranslation(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		folderID, err := uuid.Parse(c.Param("folderId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid folder ID")
+			return
+		}
+
+		var body CreateWordFromTranslationDTO
+		if validationErr := shared.BindAndValidate(c, &body); validationErr != nil {
+			shared.ResValidationError(c, validationErr)
+			return
+		}
+
+		result, err := apiCfg.Words.CreateWordFromTranslation(
+			c.Request.Context(),
+			CreateWordFromTranslationArgs{
+				Text:         body.Text,
+				VariantIndex: body.VariantIndex,
+				FolderID:     folderID,
+				UserID:       authPayload.UserID,
+			},
+		)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		if result.Duplicate != nil {
+			folderPath, err := apiCfg.Words.GetWordFolderPath(c.Request.Context(), result.Duplicate)
+			if err != nil {
+				shared.ResError(c, err)
+				return
+			}
+
+			shared.ResError(c, ErrWordAlreadyExists(WordEntityWithFolderPathToDTO(result.Duplicate, folderPath)))
+			return
+		}
+
+		otherVariants := result.OtherVariants
+		if otherVariants == nil {
+			otherVariants = []translate.TranslationVariant{}
+		}
+
+		shared.ResCreated(c, WordFromTranslationDTO{
+			Word:          WordEntityWithFolderToDTO(result.Word),
+			ChosenVariant: result.ChosenVariant,
+			OtherVariants: otherVariants,
+		})
+	}
+}
+
+func handleStartAutofillJob(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		folderID, err := uuid.Parse(c.Param("folderId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid folder ID")
+			return
+		}
+
+		job, err := apiCfg.Words.StartAutofillJob(
+			c.Request.Context(),
+			StartAutofillJobArgs{
+				FolderID: folderID,
+				UserID:   authPayload.UserID,
+			},
+		)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResAccepted(c, AutofillJobEntityToDTO(job))
+	}
+}
+
+func handleGetAutofillJob(apiCfg *shared.ApiConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := shared.GetAuthPayload(c)
+		if err != nil {
+			shared.ResUnauthorized(c, err.Error())
+			return
+		}
+
+		folderID, err := uuid.Parse(c.Param("folderId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid folder ID")
+			return
+		}
+
+		jobID, err := uuid.Parse(c.Param("jobId"))
+		if err != nil {
+			shared.ResBadRequest(c, "Invalid job ID")
+			return
+		}
+
+		job, err := apiCfg.Words.GetAutofillJob(
+			c.Request.Context(),
+			GetAutofillJobArgs{
+				JobID:    jobID,
+				FolderID: folderID,
+				UserID:   authPayload.UserID,
+			},
+		)
+		if err != nil {
+			shared.ResError(c, err)
+			return
+		}
+
+		shared.ResOK(c, AutofillJobEntityToDTO(job))
+	}
+}
@@ -7,17 +7,26 @@ import (
 )
 
 func Router(apiCfg *shared.ApiConfig, rg *gin.RouterGroup) {
+	read := shared.RequireScope(shared.ScopeWordsRead)
+	write := shared.RequireScope(shared.ScopeWordsWrite)
+	// These endpoints call the translation provider on top of writing words.
+	translateAndWrite := shared.RequireScope(shared.ScopeWordsWrite, shared.ScopeTranslate)
+
 	wordGroup := rg.Group("/words")
 	{
-		wordGroup.POST("", handleCreateWord(apiCfg))
-		wordGroup.GET("/:wordId", handleGetWord(apiCfg))
-		wordGroup.PUT("/:wordId", handleUpdateWord(apiCfg))
-		wordGroup.DELETE("/:wordId", handleDeleteWord(apiCfg))
-		wordGroup.GET("/check-duplicate", handleCheckWordDuplicate(apiCfg))
+		wordGroup.POST("", write, handleCreateWord(apiCfg))
+		wordGroup.GET("/:wordId", read, handleGetWord(apiCfg))
+		wordGroup.PUT("/:wordId", write, handleUpdateWord(apiCfg))
+		wordGroup.DELETE("/:wordId", write, handleDeleteWord(apiCfg))
+		wordGroup.GET("/check-duplicate", read, handleCheckWordDuplicate(apiCfg))
+		wordGroup.GET("/search", read, handleSearchWords(apiCfg))
 	}
 
 	folderGroup := rg.Group("/folders")
 	{
-		folderGroup.GET("/:folderId/words", handleGetWordsByFolder(apiCfg))
+		folderGroup.GET("/:folderId/words", read, handleGetWordsByFolder(apiCfg))
+		folderGroup.POST("/:folderId/words/from-translation", translateAndWrite, handleCreateWordFromTranslation(apiCfg))
+		folderGroup.POST("/:folderId/words/autofill", translateAndWrite, handleStartAutofillJob(apiCfg))
+		folderGroup.GET("/:folderId/words/autofill/:jobId", read, handleGetAutofillJob(apiCfg))
 	}
 }
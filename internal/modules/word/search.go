@@ -0,0 +1,169 @@
+package word
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/word"
+	foldermodule "lexia/internal/modules/folder"
+	"lexia/internal/modules/languages"
+	"lexia/internal/service"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+type (
+	SearchWordsArgs  = service.SearchWordsArgs
+	WordSearchResult = service.WordSearchResult
+)
+
+// searchWordsQuery ranks the user's words against the query. Text and
+// definition are stemmed with the word's search configuration, the text
+// weighing more. Full-text matches come first by ts_rank, followed by words
+// that only resemble the query by trigram similarity, which catches typos.
+//
+// The query is parsed once for each configuration the user's folders use,
+// so that matching a document against it can use the word_search_document
+// index. The document expression has to stay the same as in that index.
+var searchWordsQuery = `
+SELECT
+	w.id,
+	w.folder_words,
+	ts_headline(w.search_config, w.text, q.query, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>'),
+	ts_headline(w.search_config, w.definition, q.query, 'MaxFragments=2, StartSel=<mark>, StopSel=</mark>'),
+	d.document @@ q.query AS matched,
+	CASE WHEN d.document @@ q.query
+		THEN ts_rank(d.document, q.query)
+		ELSE GREATEST(similarity(w.text, $2), word_similarity($2, w.definition))
+	END AS rank
+FROM (
+	SELECT DISTINCT ` + languages.TextSearchConfigSQL("language_from") + ` AS config
+	FROM folders
+	WHERE user_folders = $1
+) c
+CROSS JOIN LATERAL (SELECT websearch_to_tsquery(c.config, $2) AS query) q
+JOIN words w ON w.search_config = c.config
+JOIN folders f ON f.id = w.folder_words
+CROSS JOIN LATERAL (
+	SELECT setweight(to_tsvector(w.search_config, w.text), 'A') || setweight(to_tsvector(w.search_config, w.definition), 'B') AS document
+) d
+WHERE f.user_folders = $1
+	AND (d.document @@ q.query OR w.text % $2 OR $2 <% w.definition)
+ORDER BY matched DESC, rank DESC, w.id
+LIMIT $3
+`
+
+// wordSearchConfig is the search configuration for words in the folder.
+func wordSearchConfig(folderEntity *ent.Folder) string {
+	if folderEntity.LanguageFrom == nil {
+		return languages.TextSearchConfig("")
+	}
+
+	return languages.TextSearchConfig(*folderEntity.LanguageFrom)
+}
+
+// SearchWords searches the text and definition of the words in every folder
+// the user owns.
+func (s *entService) SearchWords(
+	ctx context.Context,
+	args SearchWordsArgs,
+) ([]*WordSearchResult, error) {
+	query := strings.TrimSpace(args.Query)
+	if query == "" {
+		return nil, ErrSearchQueryRequired
+	}
+
+	limit := args.Limit
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+
+	rows, err := s.db.QueryContext(ctx, searchWordsQuery, args.UserID, query, limit)
+	if err != nil {
+		log.Println("Error searching words: ", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		results   []*WordSearchResult
+		wordIDs   []uuid.UUID
+		folderIDs []uuid.UUID
+	)
+	for rows.Next() {
+		var (
+			result   WordSearchResult
+			wordID   uuid.UUID
+			folderID uuid.UUID
+			matched  bool
+		)
+		if err := rows.Scan(
+			&wordID,
+			&folderID,
+			&result.TextSnippet,
+			&result.DefinitionSnippet,
+			&matched,
+			&result.Rank,
+		); err != nil {
+			return nil, err
+		}
+
+		result.Fuzzy = !matched
+		results = append(results, &result)
+		wordIDs = append(wordIDs, wordID)
+		folderIDs = append(folderIDs, folderID)
+	}
+	if err := rows.Err(); err != nil {
+		log.Println("Error searching words: ", err)
+		return nil, err
+	}
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	words, err := s.db.Word.Query().
+		Where(word.IDIn(wordIDs...)).
+		WithFolder().
+		All(ctx)
+	if err != nil {
+		log.Println("Error loading found words: ", err)
+		return nil, err
+	}
+
+	wordsByID := make(map[uuid.UUID]*ent.Word, len(words))
+	for _, wordEntity := range words {
+		wordsByID[wordEntity.ID] = wordEntity
+	}
+
+	// Results often share folders, so each path is looked up once
+	folderPaths := map[uuid.UUID][]FolderPathItemDTO{}
+	found := results[:0]
+	for i, result := range results {
+		result.Word = wordsByID[wordIDs[i]]
+		if result.Word == nil {
+			// Deleted since the search ran
+			continue
+		}
+
+		folderPath, ok := folderPaths[folderIDs[i]]
+		if !ok {
+			folderPath, err = foldermodule.GetFolderPath(ctx, s.db, folderIDs[i])
+			if err != nil {
+				log.Println("Error getting folder path: ", err)
+				return nil, err
+			}
+			folderPaths[folderIDs[i]] = folderPath
+		}
+		result.FolderPath = folderPath
+
+		found = append(found, result)
+	}
+
+	return found, nil
+}
@@ -2,130 +2,191 @@ package word
 
 import (
 	"context"
-	"fmt"
 	"lexia/ent"
 	"lexia/ent/folder"
+	"lexia/ent/predicate"
 	"lexia/ent/user"
 	"lexia/ent/word"
+	"lexia/ent/wordreview"
+	"lexia/internal/authz"
+	"lexia/internal/jobqueue"
+	foldermodule "lexia/internal/modules/folder"
+	"lexia/internal/pagination"
+	"lexia/internal/service"
+	"lexia/internal/shared"
 	"log"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
 
 	"github.com/google/uuid"
 )
 
-type CreateWordArgs struct {
-	Text       string
-	Definition string
-	FolderID   uuid.UUID
-	UserID     uuid.UUID
+type (
+	CreateWordArgs                  = service.CreateWordArgs
+	CreateWordFromTranslationArgs   = service.CreateWordFromTranslationArgs
+	CreateWordFromTranslationResult = service.CreateWordFromTranslationResult
+	ListWordsArgs                   = service.ListWordsArgs
+	UpdateWordArgs                  = service.UpdateWordArgs
+)
+
+type entService struct {
+	db           *ent.Client
+	translations service.TranslationService
+	autofill     *jobqueue.Runner
 }
 
-type UpdateWordArgs struct {
-	WordID     uuid.UUID
-	UserID     uuid.UUID
-	Text       *string
-	Definition *string
+// NewService returns the word service backed by db. Definitions are filled
+// in through translations, and autofill jobs are handed to autofill.
+func NewService(db *ent.Client, translations service.TranslationService, autofill *jobqueue.Runner) service.WordService {
+	return &entService{
+		db:           db,
+		translations: translations,
+		autofill:     autofill,
+	}
 }
 
-func CreateWord(
+func (s *entService) CreateWord(
 	ctx context.Context,
-	db *ent.Client,
 	args CreateWordArgs,
 ) (*ent.Word, error) {
-	folderEntity, err := db.Folder.Query().
-		Where(folder.ID(args.FolderID)).
-		WithUser().
-		Only(ctx)
+	folderEntity, err := authz.CanEditFolder(ctx, s.db, args.UserID, args.FolderID)
 	if err != nil {
-		log.Println("Error finding folder: ", err)
 		return nil, err
 	}
 
-	if folderEntity.Edges.User.ID != args.UserID {
-		return nil, fmt.Errorf("folder does not belong to user")
+	definition := args.Definition
+	if definition == "" && s.shouldAutofill(ctx, folderEntity, args.UserID, args.Autofill) {
+		definition = s.autofillDefinition(ctx, folderEntity, args.Text)
 	}
 
-	newWord, err := db.Word.Create().
-		SetID(uuid.New()).
-		SetText(args.Text).
-		SetDefinition(args.Definition).
-		SetFolderID(args.FolderID).
-		Save(ctx)
+	var newWord *ent.Word
+	err = shared.WithTx(ctx, s.db, func(client *ent.Client) error {
+		var err error
+		newWord, err = client.Word.Create().
+			SetID(uuid.New()).
+			SetText(args.Text).
+			SetDefinition(definition).
+			SetSearchConfig(wordSearchConfig(folderEntity)).
+			SetFolderID(args.FolderID).
+			Save(ctx)
+		return err
+	})
 
 	if err != nil {
 		log.Println("Error creating word: ", err)
 		return nil, err
 	}
 
+	newWord.Edges.Folder = folderEntity
+
 	return newWord, nil
 }
 
-func GetWordByID(
+// GetWord returns the word with its folder if the user may read it.
+func (s *entService) GetWord(
 	ctx context.Context,
-	db *ent.Client,
 	wordID uuid.UUID,
+	userID uuid.UUID,
 ) (*ent.Word, error) {
-	word, err := db.Word.Get(ctx, wordID)
+	return authz.CanReadWord(ctx, s.db, userID, wordID)
+}
 
-	if ent.IsNotFound(err) {
-		log.Println("Word not found with ID: ", wordID)
-		return nil, err
+// wordSorter lists what word listings can be sorted by. Words are due when
+// the user's own review says so, or from when they were added if the user
+// never reviewed them.
+func wordSorter(userID uuid.UUID) pagination.Sorter[*ent.Word] {
+	return pagination.Sorter[*ent.Word]{
+		Fields: []pagination.Field[*ent.Word]{
+			pagination.TimeField("createdAt", pagination.Column(word.FieldCreateTime), func(w *ent.Word) time.Time {
+				return w.CreateTime
+			}),
+			pagination.TimeField("updatedAt", pagination.Column(word.FieldUpdateTime), func(w *ent.Word) time.Time {
+				return w.UpdateTime
+			}),
+			pagination.StringField("text", pagination.Column(word.FieldText), func(w *ent.Word) string {
+				return w.Text
+			}),
+			pagination.TimeField("dueAt", DueAtExpr(userID), func(w *ent.Word) time.Time {
+				if review := UserReview(w); review != nil {
+					return review.DueAt
+				}
+				return w.CreateTime
+			}),
+		},
+		ID: func(w *ent.Word) uuid.UUID {
+			return w.ID
+		},
 	}
+}
 
-	if err != nil {
-		log.Println("Error getting word by ID: ", err)
-		return nil, err
+// DueAtExpr is when the word is due for the user: the due date of their
+// review, or when the word was added if they never reviewed it.
+func DueAtExpr(userID uuid.UUID) func(s *sql.Selector) string {
+	return func(s *sql.Selector) string {
+		builder := sql.Dialect(s.Dialect())
+		reviews := builder.Table(wordreview.Table)
+		// Order expressions take no arguments, so the ID is written into the
+		// query. The text form of a UUID is only hex digits and dashes.
+		dueAt, _ := builder.Select(reviews.C(wordreview.FieldDueAt)).
+			From(reviews).
+			Where(sql.And(
+				sql.ColumnsEQ(reviews.C(wordreview.WordColumn), s.C(word.FieldID)),
+				sql.ExprP(reviews.C(wordreview.UserColumn)+" = '"+userID.String()+"'"),
+			)).
+			Query()
+
+		return "COALESCE((" + dueAt + "), " + s.C(word.FieldCreateTime) + ")"
 	}
-
-	return word, nil
 }
 
-func GetWordByIDWithFolder(
-	ctx context.Context,
-	db *ent.Client,
-	wordID uuid.UUID,
-) (*ent.Word, error) {
-	word, err := db.Word.Query().
-		Where(word.ID(wordID)).
-		WithFolder().
-		Only(ctx)
-
-	if ent.IsNotFound(err) {
-		log.Println("Word not found with ID: ", wordID)
-		return nil, err
+// WithUserReview loads only the user's own review of each word, for
+// UserReview to return.
+func WithUserReview(userID uuid.UUID) func(q *ent.WordReviewQuery) {
+	return func(q *ent.WordReviewQuery) {
+		q.Where(wordreview.HasUserWith(user.ID(userID)))
 	}
+}
 
-	if err != nil {
-		log.Println("Error getting word by ID with folder: ", err)
-		return nil, err
+// UserReview returns the review loaded with WithUserReview, or nil if the
+// user never reviewed the word.
+func UserReview(w *ent.Word) *ent.WordReview {
+	if len(w.Edges.Reviews) == 0 {
+		return nil
 	}
-
-	return word, nil
+	return w.Edges.Reviews[0]
 }
 
-func GetWordsByFolderID(
+func (s *entService) GetWordsByFolderID(
 	ctx context.Context,
-	db *ent.Client,
-	folderID uuid.UUID,
-	userID uuid.UUID,
-) ([]*ent.Word, error) {
-	folderEntity, err := db.Folder.Query().
-		Where(folder.ID(folderID)).
-		WithUser().
-		Only(ctx)
-	if err != nil {
-		log.Println("Error finding folder: ", err)
+	args ListWordsArgs,
+) (*pagination.Page[*ent.Word], error) {
+	if _, err := authz.CanReadFolder(ctx, s.db, args.UserID, args.FolderID); err != nil {
 		return nil, err
 	}
 
-	if folderEntity.Edges.User.ID != userID {
-		return nil, fmt.Errorf("folder does not belong to user")
+	predicates := []predicate.Word{word.HasFolderWith(folder.ID(args.FolderID))}
+	if args.CreatedAfter != nil {
+		predicates = append(predicates, word.CreateTimeGT(*args.CreatedAfter))
+	}
+	if args.CreatedBefore != nil {
+		predicates = append(predicates, word.CreateTimeLT(*args.CreatedBefore))
+	}
+	if args.HasDefinition != nil {
+		if *args.HasDefinition {
+			predicates = append(predicates, word.DefinitionNEQ(""))
+		} else {
+			predicates = append(predicates, word.DefinitionEQ(""))
+		}
 	}
 
-	words, err := db.Word.Query().
-		Where(word.HasFolderWith(folder.ID(folderID))).
+	query := s.db.Word.Query().
+		Where(predicates...).
 		WithFolder().
-		All(ctx)
+		WithReviews(WithUserReview(args.UserID))
 
+	words, err := pagination.Paginate(ctx, query, wordSorter(args.UserID), args.Page)
 	if err != nil {
 		log.Println("Error getting words by folder ID: ", err)
 		return nil, err
@@ -134,27 +195,16 @@ func GetWordsByFolderID(
 	return words, nil
 }
 
-func UpdateWord(
+func (s *entService) UpdateWord(
 	ctx context.Context,
-	db *ent.Client,
 	args UpdateWordArgs,
 ) (*ent.Word, error) {
-	wordEntity, err := db.Word.Query().
-		Where(word.ID(args.WordID)).
-		WithFolder(func(q *ent.FolderQuery) {
-			q.WithUser()
-		}).
-		Only(ctx)
+	wordEntity, err := authz.CanEditWord(ctx, s.db, args.UserID, args.WordID)
 	if err != nil {
-		log.Println("Error finding word: ", err)
 		return nil, err
 	}
 
-	if wordEntity.Edges.Folder.Edges.User.ID != args.UserID {
-		return nil, fmt.Errorf("word does not belong to user")
-	}
-
-	updateQuery := db.Word.UpdateOneID(args.WordID)
+	updateQuery := s.db.Word.UpdateOneID(args.WordID)
 
 	if args.Text != nil {
 		updateQuery = updateQuery.SetText(*args.Text)
@@ -171,31 +221,23 @@ func UpdateWord(
 		return nil, err
 	}
 
+	updatedWord.Edges.Folder = wordEntity.Edges.Folder
+
 	return updatedWord, nil
 }
 
-func DeleteWord(
+func (s *entService) DeleteWord(
 	ctx context.Context,
-	db *ent.Client,
 	wordID uuid.UUID,
 	userID uuid.UUID,
 ) error {
-	wordEntity, err := db.Word.Query().
-		Where(word.ID(wordID)).
-		WithFolder(func(q *ent.FolderQuery) {
-			q.WithUser()
-		}).
-		Only(ctx)
-	if err != nil {
-		log.Println("Error finding word: ", err)
+	if _, err := authz.CanEditWord(ctx, s.db, userID, wordID); err != nil {
 		return err
 	}
 
-	if wordEntity.Edges.Folder.Edges.User.ID != userID {
-		return fmt.Errorf("word does not belong to user")
-	}
-
-	err = db.Word.DeleteOneID(wordID).Exec(ctx)
+	err := shared.WithTx(ctx, s.db, func(client *ent.Client) error {
+		return client.Word.DeleteOneID(wordID).Exec(ctx)
+	})
 
 	if err != nil {
 		log.Println("Error deleting word: ", err)
@@ -205,7 +247,34 @@ func DeleteWord(
 	return nil
 }
 
-func CheckWordDuplicate(
+func (s *entService) GetWordFolderPath(
+	ctx context.Context,
+	wordEntity *ent.Word,
+) ([]FolderPathItemDTO, error) {
+	if wordEntity.Edges.Folder == nil {
+		return nil, nil
+	}
+
+	folderPath, err := foldermodule.GetFolderPath(ctx, s.db, wordEntity.Edges.Folder.ID)
+	if err != nil {
+		log.Println("Error getting folder path: ", err)
+		return nil, err
+	}
+
+	return folderPath, nil
+}
+
+func (s *entService) CheckWordDuplicate(
+	ctx context.Context,
+	text string,
+	userID uuid.UUID,
+) (*ent.Word, error) {
+	return checkWordDuplicate(ctx, s.db, text, userID)
+}
+
+// checkWordDuplicate takes the client to query so the check can run inside
+// a transaction.
+func checkWordDuplicate(
 	ctx context.Context,
 	db *ent.Client,
 	text string,
@@ -216,20 +285,8 @@ func CheckWordDuplicate(
 			word.Text(text),
 			word.HasFolderWith(folder.HasUserWith(user.ID(userID))),
 		).
-		WithFolder(func(q *ent.FolderQuery) {
-			q.WithUser().
-				WithParent(func(pq *ent.FolderQuery) {
-					pq.WithParent(func(ppq *ent.FolderQuery) {
-						ppq.WithParent(func(pppq *ent.FolderQuery) {
-							pppq.WithParent(func(pppq *ent.FolderQuery) {
-								pppq.WithParent(func(pppq *ent.FolderQuery) {
-									pppq.WithParent()
-								})
-							})
-						})
-					})
-				})
-		}).First(ctx)
+		WithFolder().
+		First(ctx)
 
 	if ent.IsNotFound(err) {
 		return nil, nil
@@ -242,3 +299,105 @@ func CheckWordDuplicate(
 
 	return wordEntity, nil
 }
+
+// CreateWordFromTranslation translates text with the folder's language pair
+// and saves it as a word whose definition is the chosen variant. The
+// translation happens before the transaction so no connection is held open
+// while waiting on the provider. The final duplicate check and the insert
+// share one transaction that holds the owner's user row locked, so two
+// requests adding the same text for one owner take turns. When the folder's
+// owner already has the word, Duplicate is set and nothing is created.
+func (s *entService) CreateWordFromTranslation(
+	ctx context.Context,
+	args CreateWordFromTranslationArgs,
+) (*CreateWordFromTranslationResult, error) {
+	folderEntity, err := authz.CanEditFolder(ctx, s.db, args.UserID, args.FolderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Duplicates are looked up in the owner's words, since an editor adds
+	// to someone else's collection.
+	ownerID := args.UserID
+	if folderEntity.Edges.User != nil {
+		ownerID = folderEntity.Edges.User.ID
+	}
+
+	if folderEntity.LanguageFrom == nil || folderEntity.LanguageTo == nil {
+		return nil, ErrLanguagePairRequired
+	}
+
+	text := strings.TrimSpace(args.Text)
+
+	// Checking before translating saves a provider call for words the user
+	// already has; the check is repeated inside the transaction below.
+	duplicate, err := s.CheckWordDuplicate(ctx, text, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if duplicate != nil {
+		return &CreateWordFromTranslationResult{Duplicate: duplicate}, nil
+	}
+
+	variants, err := s.translations.TranslateText(ctx, text, *folderEntity.LanguageFrom, *folderEntity.LanguageTo)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.VariantIndex < 0 || args.VariantIndex >= len(variants) {
+		return nil, errVariantIndexOutOfRange(len(variants))
+	}
+
+	result := &CreateWordFromTranslationResult{
+		ChosenVariant: variants[args.VariantIndex],
+	}
+	for i, variant := range variants {
+		if i != args.VariantIndex {
+			result.OtherVariants = append(result.OtherVariants, variant)
+		}
+	}
+
+	err = shared.WithTx(ctx, s.db, func(client *ent.Client) error {
+		_, err := client.User.Query().
+			Where(user.ID(ownerID)).
+			ForUpdate().
+			Only(ctx)
+		if err != nil {
+			log.Println("Error locking word owner: ", err)
+			return err
+		}
+
+		duplicate, err := checkWordDuplicate(ctx, client, text, ownerID)
+		if err != nil {
+			return err
+		}
+
+		if duplicate != nil {
+			result.Duplicate = duplicate
+			return nil
+		}
+
+		result.Word, err = client.Word.Create().
+			SetText(text).
+			SetDefinition(result.ChosenVariant.Text).
+			SetSearchConfig(wordSearchConfig(folderEntity)).
+			SetFolderID(args.FolderID).
+			Save(ctx)
+		if err != nil {
+			log.Println("Error creating word from translation: ", err)
+			return err
+		}
+
+		result.Word, err = client.Word.Query().
+			Where(word.ID(result.Word.ID)).
+			WithFolder().
+			Only(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
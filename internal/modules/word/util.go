@@ -39,46 +39,52 @@ func WordEntityWithFolderToDTO(wordEntity *ent.Word) WordWithFolderDTO {
 	return dto
 }
 
-func WordEntitiesToDTOs(wordEntities []*ent.Word) []WordDTO {
-	dtos := make([]WordDTO, len(wordEntities))
-	for i, wordEntity := range wordEntities {
-		dtos[i] = WordEntityToDTO(wordEntity)
+func WordEntityWithFolderPathToDTO(wordEntity *ent.Word, folderPath []FolderPathItemDTO) WordWithFolderPathDTO {
+	return WordWithFolderPathDTO{
+		ID:         wordEntity.ID,
+		CreatedAt:  wordEntity.CreateTime,
+		UpdatedAt:  wordEntity.UpdateTime,
+		Text:       wordEntity.Text,
+		Definition: wordEntity.Definition,
+		FolderPath: folderPath,
 	}
-	return dtos
 }
 
-func buildFolderPath(folder *ent.Folder) []FolderPathItemDTO {
-	var path []FolderPathItemDTO
-	current := folder
-
-	for current != nil {
-		path = append([]FolderPathItemDTO{{
-			ID:   current.ID,
-			Name: current.Name,
-		}}, path...)
-
-		if current.Edges.Parent != nil && len(current.Edges.Parent) > 0 {
-			current = current.Edges.Parent[0]
-		} else {
-			current = nil
-		}
+func AutofillJobEntityToDTO(jobEntity *ent.AutofillJob) AutofillJobDTO {
+	var folderID uuid.UUID
+	if jobEntity.Edges.Folder != nil {
+		folderID = jobEntity.Edges.Folder.ID
 	}
 
-	return path
+	return AutofillJobDTO{
+		ID:         jobEntity.ID,
+		CreatedAt:  jobEntity.CreateTime,
+		UpdatedAt:  jobEntity.UpdateTime,
+		FolderID:   folderID,
+		Status:     string(jobEntity.Status),
+		Total:      jobEntity.Total,
+		Processed:  jobEntity.Processed,
+		Filled:     jobEntity.Filled,
+		Failed:     jobEntity.Failed,
+		Error:      jobEntity.Error,
+		FinishedAt: jobEntity.FinishedAt,
+	}
 }
 
-func WordEntityWithFolderPathToDTO(wordEntity *ent.Word) WordWithFolderPathDTO {
-	dto := WordWithFolderPathDTO{
-		ID:         wordEntity.ID,
-		CreatedAt:  wordEntity.CreateTime,
-		UpdatedAt:  wordEntity.UpdateTime,
-		Text:       wordEntity.Text,
-		Definition: wordEntity.Definition,
+func WordSearchResultToDTO(result *WordSearchResult) WordSearchResultDTO {
+	return WordSearchResultDTO{
+		Word:              WordEntityWithFolderPathToDTO(result.Word, result.FolderPath),
+		TextSnippet:       result.TextSnippet,
+		DefinitionSnippet: result.DefinitionSnippet,
+		Rank:              result.Rank,
+		Fuzzy:             result.Fuzzy,
 	}
+}
 
-	if wordEntity.Edges.Folder != nil {
-		dto.FolderPath = buildFolderPath(wordEntity.Edges.Folder)
+func WordSearchResultsToDTOs(results []*WordSearchResult) []WordSearchResultDTO {
+	dtos := make([]WordSearchResultDTO, len(results))
+	for i, result := range results {
+		dtos[i] = WordSearchResultToDTO(result)
 	}
-
-	return dto
+	return dtos
 }
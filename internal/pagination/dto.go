@@ -0,0 +1,36 @@
+package pagination
+
+// QueryDTO is embedded in the query DTOs of paginated endpoints.
+type QueryDTO struct {
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+	Sort   string `form:"sort"`
+	Order  string `form:"order"`
+}
+
+func (q QueryDTO) Params() Params {
+	return Params{
+		Limit:  q.Limit,
+		Cursor: q.Cursor,
+		Sort:   q.Sort,
+		Order:  Order(q.Order),
+	}
+}
+
+type PageDTO[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+func PageToDTO[T any, R any](page *Page[T], convert func(T) R) PageDTO[R] {
+	items := make([]R, len(page.Items))
+	for i, item := range page.Items {
+		items[i] = convert(item)
+	}
+
+	dto := PageDTO[R]{Items: items}
+	if page.NextCursor != "" {
+		dto.NextCursor = &page.NextCursor
+	}
+	return dto
+}
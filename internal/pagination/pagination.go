@@ -0,0 +1,239 @@
+// Package pagination pages ent queries by keyset. Results are ordered by a
+// sort field and then by ID, and the cursor holds both values of the last
+// row, so the next page starts right after it even when rows were added or
+// removed in between.
+package pagination
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"lexia/internal/apperr"
+	"strconv"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+var ErrInvalidCursor = apperr.InvalidInput("INVALID_CURSOR", "Invalid cursor")
+
+type Order string
+
+const (
+	Asc  Order = "asc"
+	Desc Order = "desc"
+)
+
+// Params is the page a client asks for. Zero values pick the defaults.
+type Params struct {
+	Limit  int
+	Cursor string
+	Sort   string
+	Order  Order
+}
+
+// Page holds one page of results. NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+}
+
+// Field is something results can be sorted by: a column, or any SQL
+// expression, together with how to read its value from an entity.
+type Field[T any] struct {
+	Name string
+	// Expr returns the SQL expression to order by, qualified for s.
+	Expr   func(s *sql.Selector) string
+	encode func(T) string
+	decode func(string) (any, error)
+}
+
+// Column sorts by a column of the queried table.
+func Column(name string) func(s *sql.Selector) string {
+	return func(s *sql.Selector) string {
+		return s.C(name)
+	}
+}
+
+func TimeField[T any](name string, expr func(s *sql.Selector) string, value func(T) time.Time) Field[T] {
+	return Field[T]{
+		Name: name,
+		Expr: expr,
+		encode: func(item T) string {
+			return value(item).Format(time.RFC3339Nano)
+		},
+		decode: func(raw string) (any, error) {
+			return time.Parse(time.RFC3339Nano, raw)
+		},
+	}
+}
+
+func StringField[T any](name string, expr func(s *sql.Selector) string, value func(T) string) Field[T] {
+	return Field[T]{
+		Name:   name,
+		Expr:   expr,
+		encode: value,
+		decode: func(raw string) (any, error) {
+			return raw, nil
+		},
+	}
+}
+
+// Sorter lists the fields one kind of entity can be sorted by. The first
+// field is the default.
+type Sorter[T any] struct {
+	Fields []Field[T]
+	ID     func(T) uuid.UUID
+}
+
+func (s Sorter[T]) field(name string) (Field[T], error) {
+	if name == "" {
+		return s.Fields[0], nil
+	}
+	for _, field := range s.Fields {
+		if field.Name == name {
+			return field, nil
+		}
+	}
+
+	names := make([]string, len(s.Fields))
+	for i, field := range s.Fields {
+		names[i] = field.Name
+	}
+	return Field[T]{}, apperr.InvalidInput("INVALID_SORT", "Sort must be one of: "+strings.Join(names, ", "))
+}
+
+// Query is the part of a generated ent query Paginate needs.
+type Query[Q any, T any, P ~func(*sql.Selector), O ~func(*sql.Selector)] interface {
+	Where(...P) Q
+	Order(...O) Q
+	Limit(int) Q
+	All(context.Context) ([]T, error)
+}
+
+type cursor struct {
+	Sort  string    `json:"s"`
+	Order Order     `json:"o"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// Paginate orders query by the requested field, starts it after the cursor
+// and returns at most params.Limit items. Filters should already be applied.
+func Paginate[Q Query[Q, T, P, O], T any, P ~func(*sql.Selector), O ~func(*sql.Selector)](
+	ctx context.Context,
+	query Q,
+	sorter Sorter[T],
+	params Params,
+) (*Page[T], error) {
+	field, err := sorter.field(params.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	order := params.Order
+	if order == "" {
+		order = Asc
+	}
+	if order != Asc && order != Desc {
+		return nil, apperr.InvalidInput("INVALID_ORDER", "Order must be asc or desc")
+	}
+
+	limit := params.Limit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit < 1 || limit > MaxLimit {
+		return nil, apperr.InvalidInput("INVALID_LIMIT", "Limit must be between 1 and "+strconv.Itoa(MaxLimit))
+	}
+
+	if params.Cursor != "" {
+		after, err := decodeCursor(params.Cursor, field, order)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(P(after))
+	}
+
+	items, err := query.
+		Order(O(orderBy(field, order))).
+		Limit(limit + 1).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		last := page.Items[limit-1]
+		page.NextCursor = encodeCursor(cursor{
+			Sort:  field.Name,
+			Order: order,
+			Value: field.encode(last),
+			ID:    sorter.ID(last),
+		})
+	}
+
+	return page, nil
+}
+
+func orderBy[T any](field Field[T], order Order) func(*sql.Selector) {
+	return func(s *sql.Selector) {
+		direction := " ASC"
+		if order == Desc {
+			direction = " DESC"
+		}
+		s.OrderExpr(sql.Expr(field.Expr(s) + direction))
+		s.OrderExpr(sql.Expr(s.C("id") + direction))
+	}
+}
+
+// decodeCursor returns the predicate selecting the rows after the cursor. A
+// cursor only works with the sort and order it was made for.
+func decodeCursor[T any](raw string, field Field[T], order Order) (func(*sql.Selector), error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if c.Sort != field.Name || c.Order != order {
+		return nil, ErrInvalidCursor
+	}
+
+	value, err := field.decode(c.Value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	comparison := " > "
+	if order == Desc {
+		comparison = " < "
+	}
+
+	return func(s *sql.Selector) {
+		s.Where(sql.P(func(b *sql.Builder) {
+			b.WriteString("(" + field.Expr(s) + ", " + s.C("id") + ")" + comparison + "(")
+			b.Arg(value)
+			b.Comma()
+			b.Arg(c.ID)
+			b.WriteString(")")
+		}))
+	}, nil
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,116 @@
+package pagination
+
+import (
+	"errors"
+	"lexia/internal/apperr"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/sql"
+	"github.com/google/uuid"
+)
+
+type item struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	Name      string
+}
+
+var testSorter = Sorter[item]{
+	Fields: []Field[item]{
+		TimeField("createdAt", Column("create_time"), func(i item) time.Time {
+			return i.CreatedAt
+		}),
+		StringField("name", Column("name"), func(i item) string {
+			return i.Name
+		}),
+	},
+	ID: func(i item) uuid.UUID {
+		return i.ID
+	},
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	last := item{
+		ID:        uuid.New(),
+		CreatedAt: time.Date(2026, 10, 17, 12, 30, 0, 123456000, time.UTC),
+	}
+	field := testSorter.Fields[0]
+
+	raw := encodeCursor(cursor{
+		Sort:  field.Name,
+		Order: Desc,
+		Value: field.encode(last),
+		ID:    last.ID,
+	})
+
+	after, err := decodeCursor(raw, field, Desc)
+	if err != nil {
+		t.Fatalf("decodeCursor: %v", err)
+	}
+
+	s := sql.Dialect(dialect.Postgres).Select("*").From(sql.Table("items"))
+	after(s)
+	orderBy(field, Desc)(s)
+	query, args := s.Query()
+
+	want := `SELECT * FROM "items" WHERE ("items"."create_time", "items"."id") < ($1, $2) ORDER BY "items"."create_time" DESC, "items"."id" DESC`
+	if query != want {
+		t.Errorf("query = %s\nwant    %s", query, want)
+	}
+	if len(args) != 2 || !args[0].(time.Time).Equal(last.CreatedAt) || args[1] != last.ID {
+		t.Errorf("args = %v, want [%v %v]", args, last.CreatedAt, last.ID)
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	createdAt := testSorter.Fields[0]
+	valid := encodeCursor(cursor{Sort: "createdAt", Order: Asc, Value: time.Now().Format(time.RFC3339Nano), ID: uuid.New()})
+
+	tests := []struct {
+		name  string
+		raw   string
+		field Field[item]
+		order Order
+	}{
+		{name: "not base64", raw: "%%%", field: createdAt, order: Asc},
+		{name: "not json", raw: "bm90IGpzb24", field: createdAt, order: Asc},
+		{name: "other sort", raw: valid, field: testSorter.Fields[1], order: Asc},
+		{name: "other order", raw: valid, field: createdAt, order: Desc},
+		{
+			name:  "bad value",
+			raw:   encodeCursor(cursor{Sort: "createdAt", Order: Asc, Value: "yesterday", ID: uuid.New()}),
+			field: createdAt,
+			order: Asc,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.raw, tt.field, tt.order); err != ErrInvalidCursor {
+				t.Errorf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestSorterField(t *testing.T) {
+	field, err := testSorter.field("")
+	if err != nil || field.Name != "createdAt" {
+		t.Errorf("default field = %q, %v; want createdAt", field.Name, err)
+	}
+
+	field, err = testSorter.field("name")
+	if err != nil || field.Name != "name" {
+		t.Errorf("field(name) = %q, %v; want name", field.Name, err)
+	}
+
+	_, err = testSorter.field("size")
+	if !errors.Is(err, apperr.ErrInvalidInput) {
+		t.Fatalf("field(size) err = %v, want invalid input", err)
+	}
+	if err.Error() != "Sort must be one of: createdAt, name" {
+		t.Errorf("message = %q", err.Error())
+	}
+}
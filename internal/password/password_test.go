@@ -0,0 +1,182 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func mustNew(t testing.TB, config Config) *Hasher {
+	t.Helper()
+
+	hasher, err := New(config)
+	if err != nil {
+		t.Fatalf("creating hasher: %v", err)
+	}
+	return hasher
+}
+
+func bcryptConfig(cost int) Config {
+	config := DefaultConfig()
+	config.Algorithm = AlgorithmBcrypt
+	config.BcryptCost = cost
+	return config
+}
+
+func TestHashAndVerify(t *testing.T) {
+	for _, config := range []Config{DefaultConfig(), bcryptConfig(bcrypt.MinCost)} {
+		t.Run(config.Algorithm, func(t *testing.T) {
+			hasher := mustNew(t, config)
+
+			hash, err := hasher.Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("hashing: %v", err)
+			}
+
+			match, needsRehash, err := hasher.Verify("correct horse battery staple", hash)
+			if err != nil || !match || needsRehash {
+				t.Fatalf("Verify = %v, %v, %v; want match without rehash", match, needsRehash, err)
+			}
+
+			match, _, err = hasher.Verify("wrong password", hash)
+			if err != nil || match {
+				t.Fatalf("Verify wrong password = %v, %v; want no match", match, err)
+			}
+		})
+	}
+}
+
+func TestDummyHashUsesConfig(t *testing.T) {
+	for _, config := range []Config{DefaultConfig(), bcryptConfig(bcrypt.MinCost)} {
+		t.Run(config.Algorithm, func(t *testing.T) {
+			hasher := mustNew(t, config)
+
+			// Checking the dummy hash only costs as much as a real one when
+			// it was made with the same parameters
+			match, needsRehash, err := hasher.Verify("dummy password", hasher.dummyHash)
+			if err != nil || !match || needsRehash {
+				t.Fatalf("Verify dummy = %v, %v, %v; want match without rehash", match, needsRehash, err)
+			}
+
+			match, _, err = hasher.Verify("dummy password", "")
+			if err != nil || match {
+				t.Fatalf("Verify empty hash = %v, %v; want no match", match, err)
+			}
+		})
+	}
+}
+
+func TestArgon2idHashFormat(t *testing.T) {
+	hash, err := mustNew(t, DefaultConfig()).Hash("password123")
+	if err != nil {
+		t.Fatalf("hashing: %v", err)
+	}
+
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$") {
+		t.Errorf("unexpected hash format %q", hash)
+	}
+}
+
+func TestArgon2idHandlesLongPasswords(t *testing.T) {
+	hasher := mustNew(t, DefaultConfig())
+	long := strings.Repeat("a", 100)
+
+	hash, err := hasher.Hash(long)
+	if err != nil {
+		t.Fatalf("hashing: %v", err)
+	}
+
+	// bcrypt only looks at the first 72 bytes; argon2id must not.
+	if match, _, _ := hasher.Verify(strings.Repeat("a", 72)+strings.Repeat("b", 28), hash); match {
+		t.Fatal("expected passwords differing after 72 bytes not to match")
+	}
+}
+
+func TestVerifyRequestsRehash(t *testing.T) {
+	legacyBcrypt, err := mustNew(t, bcryptConfig(bcrypt.MinCost)).Hash("password123")
+	if err != nil {
+		t.Fatalf("hashing: %v", err)
+	}
+
+	weakerConfig := DefaultConfig()
+	weakerConfig.Argon2.Iterations = 1
+	weakerArgon2, err := mustNew(t, weakerConfig).Hash("password123")
+	if err != nil {
+		t.Fatalf("hashing: %v", err)
+	}
+
+	testCases := []struct {
+		name   string
+		config Config
+		hash   string
+	}{
+		{"bcrypt to argon2id", DefaultConfig(), legacyBcrypt},
+		{"bcrypt cost change", bcryptConfig(bcrypt.MinCost + 1), legacyBcrypt},
+		{"argon2id parameter change", DefaultConfig(), weakerArgon2},
+		{"argon2id to bcrypt", bcryptConfig(bcrypt.MinCost), weakerArgon2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			match, needsRehash, err := mustNew(t, tc.config).Verify("password123", tc.hash)
+			if err != nil || !match || !needsRehash {
+				t.Fatalf("Verify = %v, %v, %v; want match with rehash", match, needsRehash, err)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsMalformedHashes(t *testing.T) {
+	hasher := mustNew(t, DefaultConfig())
+
+	if match, _, err := hasher.Verify("password123", ""); match || err != nil {
+		t.Errorf("empty hash: Verify = %v, %v; want no match and no error", match, err)
+	}
+
+	for _, hash := range []string{
+		"plaintext",
+		"$argon2id$v=19$m=19456,t=2,p=1$c2FsdA",
+		"$argon2id$v=18$m=19456,t=2,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=2,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=19456,t=2,p=1$!!!$a2V5",
+	} {
+		if match, _, err := hasher.Verify("password123", hash); match || err == nil {
+			t.Errorf("%q: Verify = %v, %v; want an error", hash, match, err)
+		}
+	}
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	for _, config := range []Config{
+		{Algorithm: "md5"},
+		{Algorithm: AlgorithmArgon2id},
+		bcryptConfig(bcrypt.MaxCost + 1),
+	} {
+		if _, err := New(config); err == nil {
+			t.Errorf("expected %+v to be rejected", config)
+		}
+	}
+}
+
+// BenchmarkHash measures the default parameters, which should stay well
+// under the 250ms sign-in budget on the smallest deployment.
+func BenchmarkHash(b *testing.B) {
+	hasher := mustNew(b, DefaultConfig())
+
+	for b.Loop() {
+		if _, err := hasher.Hash("password123"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkHashBcrypt(b *testing.B) {
+	hasher := mustNew(b, bcryptConfig(DefaultBcryptCost))
+
+	for b.Loop() {
+		if _, err := hasher.Hash("password123"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
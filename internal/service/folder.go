@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/internal/pagination"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateFolderArgs struct {
+	UserID       uuid.UUID
+	Name         string
+	Type         schema.FolderType
+	LanguageFrom *schema.Language
+	LanguageTo   *schema.Language
+	ParentID     *uuid.UUID
+}
+
+type UpdateFolderArgs struct {
+	FolderID uuid.UUID
+	UserID   uuid.UUID
+	Name     *string
+	ParentID *uuid.UUID
+}
+
+type ShareFolderArgs struct {
+	FolderID uuid.UUID
+	UserID   uuid.UUID
+	Email    string
+	Role     schema.FolderShareRole
+}
+
+type UnshareFolderArgs struct {
+	FolderID     uuid.UUID
+	UserID       uuid.UUID
+	SharedUserID uuid.UUID
+}
+
+// ListFoldersArgs selects a page of a user's folders. The created bounds are
+// exclusive.
+type ListFoldersArgs struct {
+	UserID        uuid.UUID
+	Page          pagination.Params
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+type FolderTreeNode struct {
+	ID           uuid.UUID
+	ParentID     *uuid.UUID
+	Depth        int
+	Name         string
+	Type         schema.FolderType
+	LanguageFrom *schema.Language
+	LanguageTo   *schema.Language
+	CreateTime   time.Time
+	UpdateTime   time.Time
+	// WordCount is the stored count, which like Folder.wordCount covers the
+	// whole subtree. TotalWordCount is the same number, kept for clients
+	// that read it.
+	WordCount      int
+	TotalWordCount int
+	Children       []*FolderTreeNode
+}
+
+type FolderPathItem struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// FolderService manages folders on behalf of a user. Every method checks that
+// the user may access the folders involved.
+type FolderService interface {
+	CreateFolder(ctx context.Context, args CreateFolderArgs) (*ent.Folder, error)
+	GetFolderByID(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) (*ent.Folder, error)
+	GetUserFolders(ctx context.Context, args ListFoldersArgs) (*pagination.Page[*ent.Folder], error)
+	GetRootFolders(ctx context.Context, args ListFoldersArgs) (*pagination.Page[*ent.Folder], error)
+	GetFolderTree(ctx context.Context, userID uuid.UUID) ([]*FolderTreeNode, error)
+	GetFoldersByParentID(ctx context.Context, parentID uuid.UUID, userID uuid.UUID) ([]*ent.Folder, error)
+	UpdateFolder(ctx context.Context, args UpdateFolderArgs) (*ent.Folder, error)
+	MoveFolder(ctx context.Context, folderID uuid.UUID, newParentID *uuid.UUID, userID uuid.UUID) (*ent.Folder, error)
+	DeleteFolder(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) error
+
+	GetSharedFolders(ctx context.Context, userID uuid.UUID) ([]*ent.Folder, error)
+	GetFolderShares(ctx context.Context, folderID uuid.UUID, userID uuid.UUID) ([]*ent.FolderShare, error)
+	ShareFolder(ctx context.Context, args ShareFolderArgs) (*ent.FolderShare, error)
+	UnshareFolder(ctx context.Context, args UnshareFolderArgs) error
+}
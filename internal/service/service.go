@@ -0,0 +1,5 @@
+// Package service declares the services handlers depend on, along with the
+// arguments and results they exchange. The ent-backed implementations live in
+// the modules and are built once at startup; handlers only see the
+// interfaces, so they can be tested against in-memory fakes.
+package service
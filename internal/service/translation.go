@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/internal/apperr"
+)
+
+// TranslationVariant is one candidate translation. Source tells where it came
+// from (PRIMARY, PROVIDER_ALTERNATIVE or PIVOT) and Via names the
+// pivot language for PIVOT variants. Confidence is the chrF score of the
+// variant's back-translation against the original text, or 0 when the
+// back-translation could not be obtained.
+type TranslationVariant struct {
+	Text       string  `json:"text"`
+	Confidence float32 `json:"confidence"`
+	Source     string  `json:"source"`
+	Via        string  `json:"via,omitempty"`
+}
+
+type BatchTranslationResult struct {
+	Text        string
+	Translation string
+	Err         *TranslationError
+}
+
+type TranslationError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details string `json:"details,omitempty"`
+}
+
+func (e *TranslationError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("%s: %s (%s)", e.Code, e.Message, e.Details)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Is reports errors caused by the request as apperr.ErrInvalidInput. Every
+// other code is a provider or configuration failure and becomes a 500.
+func (e *TranslationError) Is(target error) bool {
+	if target != apperr.ErrInvalidInput {
+		return false
+	}
+
+	switch e.Code {
+	case "EMPTY_TEXT", "SAME_LANGUAGE", "TEXT_TOO_LONG", "TOO_MANY_ITEMS",
+		"UNSUPPORTED_LANGUAGE", "UNSUPPORTED_DETECTED_LANGUAGE":
+		return true
+	default:
+		return false
+	}
+}
+
+func (e *TranslationError) ErrorCode() string {
+	return e.Code
+}
+
+// ErrorMessage is the message sent to clients. Configuration problems are
+// not spelled out to them.
+func (e *TranslationError) ErrorMessage() string {
+	switch e.Code {
+	case "CREDENTIALS_ERROR", "CONFIGURATION_ERROR":
+		return "Translation service configuration error"
+	default:
+		return e.Message
+	}
+}
+
+// TranslationService translates through the configured provider, serving
+// repeated lookups from the translation cache.
+type TranslationService interface {
+	TranslateText(ctx context.Context, text string, from schema.Language, to schema.Language) ([]TranslationVariant, error)
+	TranslateBatch(ctx context.Context, texts []string, from schema.Language, to schema.Language) ([]BatchTranslationResult, error)
+	DetectLanguage(ctx context.Context, text string) (schema.Language, float32, error)
+	ListLanguages(ctx context.Context, provider string) ([]*ent.CatalogLanguage, error)
+	// Close releases the provider. It is called once on shutdown.
+	Close() error
+}
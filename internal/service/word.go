@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"lexia/ent"
+	"lexia/internal/pagination"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateWordArgs struct {
+	Text       string
+	Definition string
+	FolderID   uuid.UUID
+	UserID     uuid.UUID
+	// Autofill overrides the user's autofillDefinitions preference when set.
+	Autofill *bool
+}
+
+type UpdateWordArgs struct {
+	WordID     uuid.UUID
+	UserID     uuid.UUID
+	Text       *string
+	Definition *string
+}
+
+// ListWordsArgs selects a page of a folder's words. The created bounds are
+// exclusive.
+type ListWordsArgs struct {
+	FolderID      uuid.UUID
+	UserID        uuid.UUID
+	Page          pagination.Params
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	HasDefinition *bool
+}
+
+type SearchWordsArgs struct {
+	UserID uuid.UUID
+	Query  string
+	Limit  int
+}
+
+// WordSearchResult is one search hit. The snippets are the word's text and
+// the matching part of its definition with matched terms in <mark> tags.
+// Fuzzy hits only resemble the query; their rank is the trigram similarity.
+type WordSearchResult struct {
+	Word              *ent.Word
+	FolderPath        []FolderPathItem
+	TextSnippet       string
+	DefinitionSnippet string
+	Rank              float64
+	Fuzzy             bool
+}
+
+type CreateWordFromTranslationArgs struct {
+	Text         string
+	VariantIndex int
+	FolderID     uuid.UUID
+	UserID       uuid.UUID
+}
+
+// CreateWordFromTranslationResult holds either the created word with the
+// variants it was chosen from, or Duplicate when the word already existed.
+type CreateWordFromTranslationResult struct {
+	Word          *ent.Word
+	Duplicate     *ent.Word
+	ChosenVariant TranslationVariant
+	OtherVariants []TranslationVariant
+}
+
+// Worker does background work until ctx is cancelled.
+type Worker interface {
+	Run(ctx context.Context)
+}
+
+// QueueWorker runs jobs queued in the database. Notify wakes it up after a
+// job is queued; it never blocks.
+type QueueWorker interface {
+	Worker
+	Notify()
+}
+
+type StartAutofillJobArgs struct {
+	FolderID uuid.UUID
+	UserID   uuid.UUID
+}
+
+type GetAutofillJobArgs struct {
+	JobID    uuid.UUID
+	FolderID uuid.UUID
+	UserID   uuid.UUID
+}
+
+// WordService manages words on behalf of a user. Access is checked against
+// the folder a word belongs to.
+type WordService interface {
+	// CreateWord and UpdateWord return the word with its folder loaded.
+	CreateWord(ctx context.Context, args CreateWordArgs) (*ent.Word, error)
+	GetWord(ctx context.Context, wordID uuid.UUID, userID uuid.UUID) (*ent.Word, error)
+	GetWordsByFolderID(ctx context.Context, args ListWordsArgs) (*pagination.Page[*ent.Word], error)
+	UpdateWord(ctx context.Context, args UpdateWordArgs) (*ent.Word, error)
+	DeleteWord(ctx context.Context, wordID uuid.UUID, userID uuid.UUID) error
+	GetWordFolderPath(ctx context.Context, wordEntity *ent.Word) ([]FolderPathItem, error)
+	CheckWordDuplicate(ctx context.Context, text string, userID uuid.UUID) (*ent.Word, error)
+	SearchWords(ctx context.Context, args SearchWordsArgs) ([]*WordSearchResult, error)
+	CreateWordFromTranslation(ctx context.Context, args CreateWordFromTranslationArgs) (*CreateWordFromTranslationResult, error)
+
+	StartAutofillJob(ctx context.Context, args StartAutofillJobArgs) (*ent.AutofillJob, error)
+	GetAutofillJob(ctx context.Context, args GetAutofillJobArgs) (*ent.AutofillJob, error)
+}
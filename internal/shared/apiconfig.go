@@ -1,11 +1,36 @@
 package shared
 
-import "lexia/ent"
+import (
+	"lexia/ent"
+	"lexia/internal/mailer"
+	"lexia/internal/password"
+	"lexia/internal/service"
+	"lexia/internal/throttle"
+)
 
 type ResourceConfig struct {
-	DB *ent.Client
+	// Env is parsed once at startup; handlers read settings from it.
+	Env            *EnvVariables
+	DB             *ent.Client
+	Mailer         mailer.Mailer
+	Throttler      *throttle.Throttler
+	PasswordHasher *password.Hasher
+}
+
+// Services are built once at startup and shared by every request.
+type Services struct {
+	Tokens       TokenService
+	Folders      service.FolderService
+	Words        service.WordService
+	Translations service.TranslationService
+	// AutofillWorker runs the jobs Words queues. Its owner runs it.
+	AutofillWorker service.Worker
+	// SrsOptimizer runs the FSRS optimization jobs the daily optimizer
+	// queues. Its owner runs it.
+	SrsOptimizer service.QueueWorker
 }
 
 type ApiConfig struct {
 	*ResourceConfig
+	*Services
 }
@@ -1,6 +1,9 @@
 package shared
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"lexia/ent"
 	"log"
 
@@ -30,3 +33,32 @@ func InitializeDatabase() (*ent.Client, error) {
 
 	return client, nil
 }
+
+// WithTx runs fn inside a database transaction, committing on success and
+// rolling back on error. If db is already bound to a transaction, fn joins it
+// instead of starting a new one, so transactional service functions compose.
+func WithTx(ctx context.Context, db *ent.Client, fn func(client *ent.Client) error) error {
+	tx, err := db.Tx(ctx)
+	if errors.Is(err, ent.ErrTxStarted) {
+		return fn(db)
+	}
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if v := recover(); v != nil {
+			tx.Rollback()
+			panic(v)
+		}
+	}()
+
+	if err := fn(tx.Client()); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w: rolling back transaction: %v", err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -4,20 +4,97 @@ import (
 	"errors"
 	"fmt"
 	"lexia/internal/logger"
+	"lexia/internal/mailer"
+	"lexia/internal/password"
+	"lexia/internal/throttle"
+	"math"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
 	EnvEnvironment                 = "ENVIRONMENT"
 	EnvPort                        = "PORT"
 	EnvDbConnectionString          = "DB_CONNECTION_STRING"
-	EnvAccessTokenSecret           = "ACCESS_TOKEN_SECRET"
+	EnvJwtKeysDir                  = "JWT_KEYS_DIR"
+	EnvJwtSigningKeyID             = "JWT_SIGNING_KEY_ID"
+	EnvJwtIssuer                   = "JWT_ISSUER"
 	EnvAccessTokenExpSeconds       = "ACCESS_TOKEN_EXP_SECONDS"
+	EnvRefreshTokenExpSeconds      = "REFRESH_TOKEN_EXP_SECONDS"
 	EnvGoogleCloudProjectID        = "GOOGLE_CLOUD_PROJECT_ID"
 	EnvGoogleServiceAccountKeyPath = "GOOGLE_SERVICE_ACCOUNT_KEY_PATH"
+	EnvTranslationProvider         = "TRANSLATION_PROVIDER"
+	EnvDeeplApiKey                 = "DEEPL_API_KEY"
+	EnvDeeplApiUrl                 = "DEEPL_API_URL"
+	EnvLibreTranslateUrl           = "LIBRETRANSLATE_URL"
+	EnvLibreTranslateApiKey        = "LIBRETRANSLATE_API_KEY"
+	EnvTranslationCacheTtlHours    = "TRANSLATION_CACHE_TTL_HOURS"
+	EnvAppUrl                      = "APP_URL"
+	EnvMailerBackend               = "MAILER_BACKEND"
+	EnvMailFrom                    = "MAIL_FROM"
+	EnvSmtpHost                    = "SMTP_HOST"
+	EnvSmtpPort                    = "SMTP_PORT"
+	EnvSmtpUsername                = "SMTP_USERNAME"
+	EnvSmtpPassword                = "SMTP_PASSWORD"
+	EnvThrottleBackend             = "THROTTLE_BACKEND"
+	EnvPasswordHashAlgorithm       = "PASSWORD_HASH_ALGORITHM"
+	EnvArgon2MemoryKib             = "ARGON2_MEMORY_KIB"
+	EnvArgon2Iterations            = "ARGON2_ITERATIONS"
+	EnvArgon2Parallelism           = "ARGON2_PARALLELISM"
+	EnvBcryptCost                  = "BCRYPT_COST"
+	EnvTrustedProxies              = "TRUSTED_PROXIES"
+	EnvAccountDeletionGraceDays    = "ACCOUNT_DELETION_GRACE_DAYS"
+)
+
+// OIDC providers are configured with OIDC_<PROVIDER>_CLIENT_IDS (a comma
+// separated list of accepted audiences), OIDC_<PROVIDER>_ISSUER and
+// OIDC_<PROVIDER>_JWKS_URL. A provider is enabled once it has client IDs.
+const (
+	envOidcPrefix          = "OIDC_"
+	envOidcClientIDsSuffix = "_CLIENT_IDS"
+	envOidcIssuerSuffix    = "_ISSUER"
+	envOidcJwksUrlSuffix   = "_JWKS_URL"
+)
+
+const (
+	OidcProviderGoogle  = "google"
+	OidcProviderApple   = "apple"
+	OidcProviderGeneric = "generic"
+)
+
+// defaultOidcProviders holds the well-known settings of the built-in
+// providers. The generic provider has none and needs both set explicitly.
+var defaultOidcProviders = map[string]OidcProviderConfig{
+	OidcProviderGoogle: {
+		Issuer:  "https://accounts.google.com",
+		JwksUrl: "https://www.googleapis.com/oauth2/v3/certs",
+	},
+	OidcProviderApple: {
+		Issuer:  "https://appleid.apple.com",
+		JwksUrl: "https://appleid.apple.com/auth/keys",
+	},
+	OidcProviderGeneric: {},
+}
+
+const (
+	TranslationProviderGoogle         = "google"
+	TranslationProviderDeepl          = "deepl"
+	TranslationProviderLibreTranslate = "libretranslate"
+)
+
+const (
+	DefaultDeeplApiUrl              = "https://api-free.deepl.com"
+	DefaultJwtIssuer                = "lexia"
+	DefaultRefreshTokenExpSeconds   = 60 * 60 * 24 * 30
+	DefaultTranslationCacheTtlHours = 24 * 30
+	DefaultAppUrl                   = "http://localhost:3000"
+	DefaultMailFrom                 = "Lexia <noreply@lexia.local>"
+	DefaultSmtpPort                 = 587
+	DefaultAccountDeletionGraceDays = 30
 )
 
 func LoadEnv() {
@@ -35,15 +112,42 @@ func LoadEnv() {
 	}
 }
 
+type OidcProviderConfig struct {
+	Issuer    string
+	JwksUrl   string
+	ClientIDs []string
+}
+
 type EnvVariables struct {
 	IsDevelopment               bool
 	IsProduction                bool
 	Port                        string
 	DbConnectionString          string
-	AccessTokenSecret           string
+	JwtKeysDir                  string
+	JwtSigningKeyID             string
+	JwtIssuer                   string
 	AccessTokenExpSeconds       int64
+	RefreshTokenExpSeconds      int64
 	GoogleCloudProjectID        string
 	GoogleServiceAccountKeyPath string
+	TranslationProvider         string
+	DeeplApiKey                 string
+	DeeplApiUrl                 string
+	LibreTranslateUrl           string
+	LibreTranslateApiKey        string
+	TranslationCacheTtlHours    int64
+	AppUrl                      string
+	MailerBackend               string
+	MailFrom                    string
+	SmtpHost                    string
+	SmtpPort                    int64
+	SmtpUsername                string
+	SmtpPassword                string
+	ThrottleBackend             string
+	TrustedProxies              []string
+	PasswordHash                password.Config
+	AccountDeletionGraceDays    int64
+	OidcProviders               map[string]OidcProviderConfig
 }
 
 func ParseEnv() (*EnvVariables, error) {
@@ -62,35 +166,266 @@ func ParseEnv() (*EnvVariables, error) {
 		return nil, err
 	}
 
-	accessTokenSecret, err := getEnv(EnvAccessTokenSecret)
+	jwtKeysDir, err := getEnv(EnvJwtKeysDir)
 	if err != nil {
 		return nil, err
 	}
 
+	jwtIssuer := os.Getenv(EnvJwtIssuer)
+	if jwtIssuer == "" {
+		jwtIssuer = DefaultJwtIssuer
+	}
+
 	accessTokenExpSeconds, err := getEnvInt(EnvAccessTokenExpSeconds)
 	if err != nil {
 		return nil, err
 	}
 
-	googleCloudProjectID, err := getEnv(EnvGoogleCloudProjectID)
+	refreshTokenExpSeconds := int64(DefaultRefreshTokenExpSeconds)
+	if os.Getenv(EnvRefreshTokenExpSeconds) != "" {
+		refreshTokenExpSeconds, err = getEnvInt(EnvRefreshTokenExpSeconds)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	translationProvider := os.Getenv(EnvTranslationProvider)
+	if translationProvider == "" {
+		translationProvider = TranslationProviderGoogle
+	}
+
+	var googleCloudProjectID, deeplApiKey, libreTranslateUrl string
+	switch translationProvider {
+	case TranslationProviderGoogle:
+		googleCloudProjectID, err = getEnv(EnvGoogleCloudProjectID)
+	case TranslationProviderDeepl:
+		deeplApiKey, err = getEnv(EnvDeeplApiKey)
+	case TranslationProviderLibreTranslate:
+		libreTranslateUrl, err = getEnv(EnvLibreTranslateUrl)
+	default:
+		msg := fmt.Sprintf("%v has unknown value %q", EnvTranslationProvider, translationProvider)
+
+		logger.Fatal(msg)
+		err = errors.New(msg)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	googleServiceAccountKeyPath := os.Getenv(EnvGoogleServiceAccountKeyPath)
 
+	translationCacheTtlHours := int64(DefaultTranslationCacheTtlHours)
+	if os.Getenv(EnvTranslationCacheTtlHours) != "" {
+		translationCacheTtlHours, err = getEnvInt(EnvTranslationCacheTtlHours)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deeplApiUrl := os.Getenv(EnvDeeplApiUrl)
+	if deeplApiUrl == "" {
+		deeplApiUrl = DefaultDeeplApiUrl
+	}
+
+	appUrl := os.Getenv(EnvAppUrl)
+	if appUrl == "" {
+		appUrl = DefaultAppUrl
+	}
+
+	mailerBackend := os.Getenv(EnvMailerBackend)
+	if mailerBackend == "" {
+		mailerBackend = mailer.BackendLog
+	}
+
+	mailFrom := os.Getenv(EnvMailFrom)
+	if mailFrom == "" {
+		mailFrom = DefaultMailFrom
+	}
+
+	var smtpHost string
+	smtpPort := int64(DefaultSmtpPort)
+	switch mailerBackend {
+	case mailer.BackendLog:
+		// The log backend writes every link, token included, to the log
+		if environment == "production" {
+			msg := fmt.Sprintf("%v must be %q in production", EnvMailerBackend, mailer.BackendSMTP)
+
+			logger.Fatal(msg)
+			return nil, errors.New(msg)
+		}
+	case mailer.BackendSMTP:
+		smtpHost, err = getEnv(EnvSmtpHost)
+		if err != nil {
+			return nil, err
+		}
+
+		if os.Getenv(EnvSmtpPort) != "" {
+			smtpPort, err = getEnvInt(EnvSmtpPort)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		msg := fmt.Sprintf("%v has unknown value %q", EnvMailerBackend, mailerBackend)
+
+		logger.Fatal(msg)
+		return nil, errors.New(msg)
+	}
+
+	accountDeletionGraceDays := int64(DefaultAccountDeletionGraceDays)
+	if os.Getenv(EnvAccountDeletionGraceDays) != "" {
+		accountDeletionGraceDays, err = getEnvInt(EnvAccountDeletionGraceDays)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	throttleBackend := os.Getenv(EnvThrottleBackend)
+	if throttleBackend == "" {
+		throttleBackend = throttle.BackendMemory
+	}
+	if throttleBackend != throttle.BackendMemory && throttleBackend != throttle.BackendPostgres {
+		msg := fmt.Sprintf("%v has unknown value %q", EnvThrottleBackend, throttleBackend)
+
+		logger.Fatal(msg)
+		return nil, errors.New(msg)
+	}
+
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv(EnvTrustedProxies), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
+	passwordHash, err := parsePasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	oidcProviders, err := parseOidcProviders()
+	if err != nil {
+		return nil, err
+	}
+
 	return &EnvVariables{
 		IsDevelopment:               environment == "development",
 		IsProduction:                environment == "production",
 		Port:                        port,
 		DbConnectionString:          dbConnectionString,
-		AccessTokenSecret:           accessTokenSecret,
+		JwtKeysDir:                  jwtKeysDir,
+		JwtSigningKeyID:             os.Getenv(EnvJwtSigningKeyID),
+		JwtIssuer:                   jwtIssuer,
 		AccessTokenExpSeconds:       accessTokenExpSeconds,
+		RefreshTokenExpSeconds:      refreshTokenExpSeconds,
 		GoogleCloudProjectID:        googleCloudProjectID,
 		GoogleServiceAccountKeyPath: googleServiceAccountKeyPath,
+		TranslationProvider:         translationProvider,
+		DeeplApiKey:                 deeplApiKey,
+		DeeplApiUrl:                 deeplApiUrl,
+		LibreTranslateUrl:           libreTranslateUrl,
+		LibreTranslateApiKey:        os.Getenv(EnvLibreTranslateApiKey),
+		TranslationCacheTtlHours:    translationCacheTtlHours,
+		AppUrl:                      strings.TrimRight(appUrl, "/"),
+		MailerBackend:               mailerBackend,
+		MailFrom:                    mailFrom,
+		SmtpHost:                    smtpHost,
+		SmtpPort:                    smtpPort,
+		SmtpUsername:                os.Getenv(EnvSmtpUsername),
+		SmtpPassword:                os.Getenv(EnvSmtpPassword),
+		ThrottleBackend:             throttleBackend,
+		TrustedProxies:              trustedProxies,
+		PasswordHash:                passwordHash,
+		AccountDeletionGraceDays:    accountDeletionGraceDays,
+		OidcProviders:               oidcProviders,
 	}, nil
 }
 
+// parsePasswordHash reads the password hashing settings. Every setting is
+// optional and falls back to the benchmarked defaults of the password
+// package.
+func parsePasswordHash() (password.Config, error) {
+	config := password.DefaultConfig()
+
+	if algorithm := os.Getenv(EnvPasswordHashAlgorithm); algorithm != "" {
+		config.Algorithm = algorithm
+	}
+
+	// The bounds keep each value within its field, so a typo fails startup
+	// instead of wrapping around to a weak setting.
+	settings := []struct {
+		key      string
+		min, max int64
+		target   func(value int64)
+	}{
+		{EnvArgon2MemoryKib, 1, math.MaxUint32, func(value int64) { config.Argon2.Memory = uint32(value) }},
+		{EnvArgon2Iterations, 1, math.MaxUint32, func(value int64) { config.Argon2.Iterations = uint32(value) }},
+		{EnvArgon2Parallelism, 1, math.MaxUint8, func(value int64) { config.Argon2.Parallelism = uint8(value) }},
+		{EnvBcryptCost, int64(bcrypt.MinCost), int64(bcrypt.MaxCost), func(value int64) { config.BcryptCost = int(value) }},
+	}
+	for _, setting := range settings {
+		if os.Getenv(setting.key) == "" {
+			continue
+		}
+
+		value, err := getEnvInt(setting.key)
+		if err != nil {
+			return password.Config{}, err
+		}
+
+		if value < setting.min || value > setting.max {
+			msg := fmt.Sprintf("%v must be between %d and %d", setting.key, setting.min, setting.max)
+
+			logger.Fatal(msg)
+			return password.Config{}, errors.New(msg)
+		}
+		setting.target(value)
+	}
+
+	return config, nil
+}
+
+func parseOidcProviders() (map[string]OidcProviderConfig, error) {
+	providers := map[string]OidcProviderConfig{}
+
+	for name, defaults := range defaultOidcProviders {
+		prefix := envOidcPrefix + strings.ToUpper(name)
+
+		var clientIDs []string
+		for _, clientID := range strings.Split(os.Getenv(prefix+envOidcClientIDsSuffix), ",") {
+			if clientID = strings.TrimSpace(clientID); clientID != "" {
+				clientIDs = append(clientIDs, clientID)
+			}
+		}
+
+		if len(clientIDs) == 0 {
+			continue
+		}
+
+		config := defaults
+		config.ClientIDs = clientIDs
+
+		if issuer := os.Getenv(prefix + envOidcIssuerSuffix); issuer != "" {
+			config.Issuer = issuer
+		}
+
+		if jwksUrl := os.Getenv(prefix + envOidcJwksUrlSuffix); jwksUrl != "" {
+			config.JwksUrl = jwksUrl
+		}
+
+		if config.Issuer == "" || config.JwksUrl == "" {
+			msg := fmt.Sprintf("%v and %v are required for the %v OIDC provider", prefix+envOidcIssuerSuffix, prefix+envOidcJwksUrlSuffix, name)
+
+			logger.Fatal(msg)
+			return nil, errors.New(msg)
+		}
+
+		providers[name] = config
+	}
+
+	return providers, nil
+}
+
 func getEnvInt(key string) (int64, error) {
 	val, err := getEnv(key)
 	if err != nil {
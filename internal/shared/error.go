@@ -1,14 +1,32 @@
 package shared
 
 const (
-	ErrInternal               = "INTERNAL"
-	ErrNotFound               = "NOT_FOUND"
-	ErrUnauthorized           = "UNAUTHORIZED"
-	ErrInvalidJSON            = "INVALID_JSON"
-	ErrInvalidRequest         = "INVALID_REQUEST"
-	ErrInvalidToken           = "INVALID_TOKEN"
-	ErrMissingToken           = "MISSING_TOKEN"
-	ErrUserNotFound           = "USER_NOT_FOUND"
-	ErrInvalidEmailOrPassword = "INVALID_EMAIL_OR_PASSWORD"
-	ErrEmailAlreadyExists     = "EMAIL_ALREADY_EXISTS"
+	ErrInternal                = "INTERNAL"
+	ErrNotFound                = "NOT_FOUND"
+	ErrUnauthorized            = "UNAUTHORIZED"
+	ErrForbidden               = "FORBIDDEN"
+	ErrConflict                = "CONFLICT"
+	ErrInvalidState            = "INVALID_STATE"
+	ErrTooManyRequests         = "TOO_MANY_REQUESTS"
+	ErrValidationFailed        = "VALIDATION_FAILED"
+	ErrInvalidJSON             = "INVALID_JSON"
+	ErrInvalidRequest          = "INVALID_REQUEST"
+	ErrInvalidToken            = "INVALID_TOKEN"
+	ErrMissingToken            = "MISSING_TOKEN"
+	ErrSessionRevoked          = "SESSION_REVOKED"
+	ErrInsufficientScope       = "INSUFFICIENT_SCOPE"
+	ErrInvalidRefreshToken     = "INVALID_REFRESH_TOKEN"
+	ErrUserNotFound            = "USER_NOT_FOUND"
+	ErrInvalidEmailOrPassword  = "INVALID_EMAIL_OR_PASSWORD"
+	ErrInvalidPassword         = "INVALID_PASSWORD"
+	ErrTooManyAttempts         = "TOO_MANY_ATTEMPTS"
+	ErrEmailAlreadyExists      = "EMAIL_ALREADY_EXISTS"
+	ErrEmailNotVerified        = "EMAIL_NOT_VERIFIED"
+	ErrInvalidOrExpiredToken   = "INVALID_OR_EXPIRED_TOKEN"
+	ErrInvalidIDToken          = "INVALID_ID_TOKEN"
+	ErrIdentityAlreadyLinked   = "IDENTITY_ALREADY_LINKED"
+	ErrLastSignInMethod        = "LAST_SIGN_IN_METHOD"
+	ErrTwoFactorAlreadyEnabled = "TWO_FACTOR_ALREADY_ENABLED"
+	ErrTwoFactorNotEnabled     = "TWO_FACTOR_NOT_ENABLED"
+	ErrInvalidTwoFactorCode    = "INVALID_TWO_FACTOR_CODE"
 )
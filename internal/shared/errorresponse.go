@@ -0,0 +1,169 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"lexia/ent"
+	"lexia/internal/apperr"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse is the body of every error response. Error is a message for
+// people, Code a stable machine-readable code and RequestID the ID to quote
+// when reporting a problem.
+type ErrorResponse struct {
+	Error     string `json:"error"`
+	Code      string `json:"code"`
+	RequestID string `json:"requestId,omitempty"`
+	Details   any    `json:"details,omitempty"`
+}
+
+// codedError is implemented by errors that carry their own code and client
+// message, such as apperr.Error, HttpError and translation errors.
+type codedError interface {
+	ErrorCode() string
+	ErrorMessage() string
+}
+
+type detailedError interface {
+	ErrorDetails() any
+}
+
+var errorCodePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// kindStatuses maps apperr kinds to statuses. Invalid state is a 400 like
+// invalid input; the code tells them apart.
+var kindStatuses = []struct {
+	kind   error
+	status int
+	code   string
+}{
+	{apperr.ErrInvalidInput, http.StatusBadRequest, ErrInvalidRequest},
+	{apperr.ErrUnauthorized, http.StatusUnauthorized, ErrUnauthorized},
+	{apperr.ErrForbidden, http.StatusForbidden, ErrForbidden},
+	{apperr.ErrNotFound, http.StatusNotFound, ErrNotFound},
+	{apperr.ErrConflict, http.StatusConflict, ErrConflict},
+	{apperr.ErrInvalidState, http.StatusBadRequest, ErrInvalidState},
+	{apperr.ErrTooManyRequests, http.StatusTooManyRequests, ErrTooManyRequests},
+}
+
+func statusErrorCode(status int) string {
+	for _, kindStatus := range kindStatuses {
+		if kindStatus.status == status {
+			return kindStatus.code
+		}
+	}
+
+	if status == http.StatusInternalServerError {
+		return ErrInternal
+	}
+
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+// entError turns the ent errors handlers commonly let through into typed
+// errors, so a missing row is a 404 rather than a 500.
+func entError(err error) error {
+	var validationErr *ent.ValidationError
+	switch {
+	case ent.IsNotFound(err):
+		return apperr.NotFound(ErrNotFound, "Not found")
+	case ent.IsConstraintError(err):
+		return apperr.Conflict(ErrConflict, "Conflicts with existing data")
+	case errors.As(err, &validationErr):
+		return apperr.InvalidInput(ErrInvalidRequest, fmt.Sprintf("Invalid %s", validationErr.Name))
+	default:
+		return err
+	}
+}
+
+// errorResponse maps err to its status and envelope. Errors of no known kind
+// are 500s, and their message is only shown when they carry a code.
+func errorResponse(err error) (int, ErrorResponse) {
+	err = entError(err)
+
+	status := http.StatusInternalServerError
+	response := ErrorResponse{Error: ErrInternal, Code: ErrInternal}
+
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		status = httpErr.Code
+	} else {
+		for _, kindStatus := range kindStatuses {
+			if errors.Is(err, kindStatus.kind) {
+				status = kindStatus.status
+				response.Error = err.Error()
+				response.Code = kindStatus.code
+				break
+			}
+		}
+	}
+
+	var coded codedError
+	if errors.As(err, &coded) {
+		response.Error = coded.ErrorMessage()
+		response.Code = coded.ErrorCode()
+	}
+
+	var detailed detailedError
+	if errors.As(err, &detailed) {
+		response.Details = detailed.ErrorDetails()
+	}
+
+	return status, response
+}
+
+// ErrorMW writes the error envelope for the error a handler or middleware
+// reported with ResError, unless a response was already written. It maps
+// every error type the services return, so handlers never pick statuses for
+// errors themselves.
+func ErrorMW() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status, response := errorResponse(err)
+		response.RequestID = GetRequestID(c)
+
+		if status >= http.StatusInternalServerError {
+			log.Println("Error handling request ", response.RequestID, ": ", err)
+		}
+
+		if retryAfter := errorRetryAfter(err); retryAfter > 0 {
+			setRetryAfter(c, retryAfter)
+		}
+
+		c.JSON(status, response)
+	}
+}
+
+func errorRetryAfter(err error) time.Duration {
+	var appErr *apperr.Error
+	if errors.As(err, &appErr) {
+		return appErr.RetryAfter
+	}
+
+	var httpErr *HttpError
+	if errors.As(err, &httpErr) {
+		return httpErr.RetryAfter
+	}
+
+	return 0
+}
+
+// setRetryAfter rounds up to whole seconds so clients never retry early.
+func setRetryAfter(c *gin.Context, retryAfter time.Duration) {
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	c.Header("Retry-After", strconv.FormatInt(seconds, 10))
+}
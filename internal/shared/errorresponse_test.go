@@ -0,0 +1,115 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"lexia/ent"
+	"lexia/internal/apperr"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestErrorResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{
+			name:    "typed error",
+			err:     apperr.NotFound("FOLDER_NOT_FOUND", "Folder not found"),
+			status:  http.StatusNotFound,
+			code:    "FOLDER_NOT_FOUND",
+			message: "Folder not found",
+		},
+		{
+			name:    "wrapped typed error",
+			err:     fmt.Errorf("moving folder: %w", apperr.InvalidState("CIRCULAR_REFERENCE", "Circular")),
+			status:  http.StatusBadRequest,
+			code:    "CIRCULAR_REFERENCE",
+			message: "Circular",
+		},
+		{
+			name:    "conflict",
+			err:     apperr.Conflict("WORD_ALREADY_EXISTS", "Word already exists"),
+			status:  http.StatusConflict,
+			code:    "WORD_ALREADY_EXISTS",
+			message: "Word already exists",
+		},
+		{
+			name:    "rate limited",
+			err:     apperr.TooManyRequests(ErrTooManyAttempts, "Too many attempts", time.Minute),
+			status:  http.StatusTooManyRequests,
+			code:    ErrTooManyAttempts,
+			message: "Too many attempts",
+		},
+		{
+			name:    "http error with code message",
+			err:     Forbidden(ErrInsufficientScope),
+			status:  http.StatusForbidden,
+			code:    ErrInsufficientScope,
+			message: ErrInsufficientScope,
+		},
+		{
+			name:    "http error with sentence message",
+			err:     BadRequest("Invalid folder ID"),
+			status:  http.StatusBadRequest,
+			code:    ErrInvalidRequest,
+			message: "Invalid folder ID",
+		},
+		{
+			name:    "ent not found",
+			err:     &ent.NotFoundError{},
+			status:  http.StatusNotFound,
+			code:    ErrNotFound,
+			message: "Not found",
+		},
+		{
+			name:    "ent validation error",
+			err:     &ent.ValidationError{Name: "text"},
+			status:  http.StatusBadRequest,
+			code:    ErrInvalidRequest,
+			message: "Invalid text",
+		},
+		{
+			name:    "untyped error",
+			err:     errors.New("connection refused"),
+			status:  http.StatusInternalServerError,
+			code:    ErrInternal,
+			message: ErrInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, response := errorResponse(tt.err)
+			if status != tt.status {
+				t.Errorf("status = %d, want %d", status, tt.status)
+			}
+			if response.Code != tt.code {
+				t.Errorf("code = %q, want %q", response.Code, tt.code)
+			}
+			if response.Error != tt.message {
+				t.Errorf("error = %q, want %q", response.Error, tt.message)
+			}
+		})
+	}
+}
+
+func TestErrorResponseDetails(t *testing.T) {
+	err := &ValidationErrorResponse{
+		Message: "Validation failed",
+		Errors:  []ValidationError{{Field: "name", Message: "name is required"}},
+	}
+
+	status, response := errorResponse(err)
+	if status != http.StatusBadRequest || response.Code != ErrValidationFailed {
+		t.Fatalf("got %d %q, want 400 %q", status, response.Code, ErrValidationFailed)
+	}
+	if details, ok := response.Details.([]ValidationError); !ok || len(details) != 1 {
+		t.Errorf("details = %#v, want the field errors", response.Details)
+	}
+}
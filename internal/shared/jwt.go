@@ -3,100 +3,136 @@ package shared
 import (
 	"errors"
 	"log"
+	"slices"
 	"time"
 
-	"github.com/dgrijalva/jwt-go"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 type TokenClaims struct {
-	UserID uuid.UUID
-	Email  string
+	UserID    uuid.UUID
+	Email     string
+	SessionID uuid.UUID
+	// PersonalAccessTokenID and Scopes are set instead of SessionID when the
+	// request is authenticated with a personal access token.
+	PersonalAccessTokenID uuid.UUID
+	Scopes                []string
 }
 
-func GenerateAccessToken(tokenClaims *TokenClaims) (string, error) {
-	env, err := ParseEnv()
-	if err != nil {
-		return "", err
-	}
+func (claims *TokenClaims) IsPersonalAccessToken() bool {
+	return claims.PersonalAccessTokenID != uuid.Nil
+}
 
-	return generateJWT(tokenClaims, env.AccessTokenSecret)
+// HasScope reports whether the request may do what scope allows. Sessions
+// carry every scope.
+func (claims *TokenClaims) HasScope(scope string) bool {
+	return !claims.IsPersonalAccessToken() || slices.Contains(claims.Scopes, scope)
 }
 
-func VerifyAccessToken(tokenString string) (*TokenClaims, error) {
-	env, err := ParseEnv()
-	if err != nil {
-		return nil, err
-	}
+// accessTokenClaims is the JWT form of TokenClaims: the user is the
+// subject and the session travels in sid.
+type accessTokenClaims struct {
+	jwt.RegisteredClaims
+	Email     string `json:"email"`
+	SessionID string `json:"sid"`
+}
 
-	return verifyJWT(tokenString, env.AccessTokenSecret)
+// TokenService signs and verifies access tokens.
+type TokenService interface {
+	GenerateAccessToken(tokenClaims *TokenClaims) (string, error)
+	VerifyAccessToken(tokenString string) (*TokenClaims, error)
+	// JWKS returns the public keys access tokens can be verified with.
+	JWKS() JSONWebKeySet
 }
 
-func verifyJWT(tokenString string, secretKey string) (*TokenClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-		return []byte(secretKey), nil
-	})
+type TokenConfig struct {
+	KeysDir      string
+	SigningKeyID string
+	Issuer       string
+	ExpiresIn    time.Duration
+}
 
-	if err != nil {
-		log.Println("Error parsing token: ", err)
-		return nil, errors.New(ErrInvalidToken)
+func TokenConfigFromEnv(envVars *EnvVariables) TokenConfig {
+	return TokenConfig{
+		KeysDir:      envVars.JwtKeysDir,
+		SigningKeyID: envVars.JwtSigningKeyID,
+		Issuer:       envVars.JwtIssuer,
+		ExpiresIn:    time.Duration(envVars.AccessTokenExpSeconds) * time.Second,
 	}
+}
 
-	if !token.Valid {
-		log.Println("Token is invalid")
-		return nil, errors.New(ErrInvalidToken)
-	}
+type jwtTokenService struct {
+	keyRing   *KeyRing
+	issuer    string
+	expiresIn time.Duration
+}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		log.Println("Error parsing claims from token")
-		return nil, errors.New(ErrInvalidToken)
+// NewTokenService loads the key ring access tokens are signed with.
+func NewTokenService(config TokenConfig) (TokenService, error) {
+	keyRing, err := LoadKeyRing(config.KeysDir, config.SigningKeyID)
+	if err != nil {
+		return nil, err
 	}
 
-	userId, ok := claims["userId"].(string)
-	if !ok {
-		log.Println("Error parsing userId from token")
-		return nil, errors.New(ErrInvalidToken)
-	}
+	return &jwtTokenService{
+		keyRing:   keyRing,
+		issuer:    config.Issuer,
+		expiresIn: config.ExpiresIn,
+	}, nil
+}
 
-	email, ok := claims["email"].(string)
-	if !ok {
-		log.Println("Error parsing email from token")
-		return nil, errors.New(ErrInvalidToken)
-	}
+func (s *jwtTokenService) JWKS() JSONWebKeySet {
+	return s.keyRing.JWKS()
+}
 
-	userIdUUID, err := uuid.Parse(userId)
-	if err != nil {
-		log.Println("Error parsing userId to UUID: ", err)
-		return nil, errors.New(ErrInvalidToken)
+func (s *jwtTokenService) GenerateAccessToken(tokenClaims *TokenClaims) (string, error) {
+	now := time.Now()
+	claims := accessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   tokenClaims.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.expiresIn)),
+			ID:        uuid.NewString(),
+		},
+		Email:     tokenClaims.Email,
+		SessionID: tokenClaims.SessionID.String(),
 	}
 
-	return &TokenClaims{
-		UserID: userIdUUID,
-		Email:  email,
-	}, nil
+	return s.keyRing.sign(claims)
 }
 
-func generateJWT(tokenClaims *TokenClaims, secretKey string) (string, error) {
-	env, err := ParseEnv()
+func (s *jwtTokenService) VerifyAccessToken(tokenString string) (*TokenClaims, error) {
+	var claims accessTokenClaims
+	_, err := jwt.ParseWithClaims(
+		tokenString,
+		&claims,
+		s.keyRing.verificationKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
-		return "", err
+		log.Println("Error parsing token: ", err)
+		return nil, errors.New(ErrInvalidToken)
 	}
 
-	expDuration := time.Second * time.Duration(env.AccessTokenExpSeconds)
-
-	claims := jwt.MapClaims{
-		"userId": tokenClaims.UserID.String(),
-		"email":  tokenClaims.Email,
-		"exp":    time.Now().Add(expDuration).Unix(),
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		log.Println("Error parsing subject to UUID: ", err)
+		return nil, errors.New(ErrInvalidToken)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	tokenString, err := token.SignedString([]byte(secretKey))
+	sessionID, err := uuid.Parse(claims.SessionID)
 	if err != nil {
-		return "", err
+		log.Println("Error parsing sessionId to UUID: ", err)
+		return nil, errors.New(ErrInvalidToken)
 	}
 
-	return tokenString, nil
+	return &TokenClaims{
+		UserID:    userID,
+		Email:     claims.Email,
+		SessionID: sessionID,
+	}, nil
 }
@@ -0,0 +1,201 @@
+package shared
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSAKeyBits = 2048
+
+// KeyRing holds the keys access tokens are signed with. One key signs new
+// tokens; every key in the ring verifies, so a retired key can stay in the
+// ring until the tokens it signed have expired.
+type KeyRing struct {
+	signingKeyID string
+	keys         map[string]*ringKey
+}
+
+type ringKey struct {
+	id     string
+	method jwt.SigningMethod
+	signer crypto.Signer
+}
+
+// LoadKeyRing reads every <kid>.pem private key in dir. The key named by
+// signingKeyID signs new tokens; when it is empty the last key ID in sort
+// order does, so date-named keys rotate by adding a newer file.
+func LoadKeyRing(dir string, signingKeyID string) (*KeyRing, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*ringKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := parseRingKey(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return newKeyRing(keys, signingKeyID)
+}
+
+func newKeyRing(keys []*ringKey, signingKeyID string) (*KeyRing, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("key ring has no keys")
+	}
+
+	ring := &KeyRing{keys: make(map[string]*ringKey, len(keys))}
+	for _, key := range keys {
+		ring.keys[key.id] = key
+	}
+
+	if signingKeyID == "" {
+		ids := make([]string, 0, len(ring.keys))
+		for id := range ring.keys {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		signingKeyID = ids[len(ids)-1]
+	}
+
+	if _, ok := ring.keys[signingKeyID]; !ok {
+		return nil, fmt.Errorf("signing key %q is not in the key ring", signingKeyID)
+	}
+	ring.signingKeyID = signingKeyID
+
+	return ring, nil
+}
+
+func parseRingKey(id string, data []byte) (*ringKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var privateKey any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		if key.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA keys must have at least %d bits", minRSAKeyBits)
+		}
+		return &ringKey{id: id, method: jwt.SigningMethodRS256, signer: key}, nil
+	case ed25519.PrivateKey:
+		return &ringKey{id: id, method: jwt.SigningMethodEdDSA, signer: key}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", privateKey)
+	}
+}
+
+// SigningKeyID is the kid of the key new tokens are signed with.
+func (ring *KeyRing) SigningKeyID() string {
+	return ring.signingKeyID
+}
+
+func (ring *KeyRing) sign(claims jwt.Claims) (string, error) {
+	key := ring.keys[ring.signingKeyID]
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+
+	return token.SignedString(key.signer)
+}
+
+// verificationKey is a jwt.Keyfunc. It only accepts a token signed with the
+// algorithm of the key its kid names, so a public key can never be used as
+// an HMAC secret.
+func (ring *KeyRing) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ring.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for kid %q", token.Method.Alg(), kid)
+	}
+
+	return key.signer.Public(), nil
+}
+
+// JSONWebKey is the public half of a ring key in RFC 7517 form.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JWKS returns the public keys of the ring, sorted by kid.
+func (ring *KeyRing) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(ring.keys))}
+
+	for _, key := range ring.keys {
+		jwk := JSONWebKey{
+			Kid: key.id,
+			Use: "sig",
+			Alg: key.method.Alg(),
+		}
+
+		switch publicKey := key.signer.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	slices.SortFunc(set.Keys, func(a, b JSONWebKey) int {
+		return strings.Compare(a.Kid, b.Kid)
+	})
+
+	return set
+}
@@ -0,0 +1,216 @@
+package shared
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writePEM(t *testing.T, dir string, kid string, block *pem.Block) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatalf("writing key: %v", err)
+	}
+}
+
+func writeEd25519Key(t *testing.T, dir string, kid string) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("encoding key: %v", err)
+	}
+
+	writePEM(t, dir, kid, &pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func writeRSAKey(t *testing.T, dir string, kid string, bits int) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	writePEM(t, dir, kid, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+func testClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		Subject:   "user-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+}
+
+func verify(ring *KeyRing, token string) error {
+	_, err := jwt.Parse(token, ring.verificationKey)
+	return err
+}
+
+func TestLoadKeyRingSignsWithLatestKey(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "2026-01-01", 2048)
+	writeEd25519Key(t, dir, "2026-06-01")
+
+	ring, err := LoadKeyRing(dir, "")
+	if err != nil {
+		t.Fatalf("loading key ring: %v", err)
+	}
+
+	if ring.SigningKeyID() != "2026-06-01" {
+		t.Errorf("expected the latest key to sign, got %q", ring.SigningKeyID())
+	}
+
+	pinned, err := LoadKeyRing(dir, "2026-01-01")
+	if err != nil {
+		t.Fatalf("loading key ring: %v", err)
+	}
+
+	token, err := pinned.sign(testClaims())
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+	if parsed.Header["kid"] != "2026-01-01" || parsed.Method.Alg() != "RS256" {
+		t.Errorf("unexpected header %v", parsed.Header)
+	}
+}
+
+func TestLoadKeyRingRejectsBadKeys(t *testing.T) {
+	testCases := []struct {
+		name  string
+		write func(dir string)
+	}{
+		{"empty directory", func(dir string) {}},
+		{"short RSA key", func(dir string) { writeRSAKey(t, dir, "short", 1024) }},
+		{"not PEM", func(dir string) {
+			os.WriteFile(filepath.Join(dir, "garbage.pem"), []byte("garbage"), 0o600)
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tc.write(dir)
+
+			if _, err := LoadKeyRing(dir, ""); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	writeEd25519Key(t, dir, "present")
+	if _, err := LoadKeyRing(dir, "missing"); err == nil {
+		t.Fatal("expected an unknown signing key to be rejected")
+	}
+}
+
+func TestKeyRingVerifiesRetiredKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeEd25519Key(t, dir, "old")
+
+	oldRing, err := LoadKeyRing(dir, "")
+	if err != nil {
+		t.Fatalf("loading key ring: %v", err)
+	}
+	token, err := oldRing.sign(testClaims())
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	writeEd25519Key(t, dir, "zz-new")
+	rotatedRing, err := LoadKeyRing(dir, "")
+	if err != nil {
+		t.Fatalf("loading key ring: %v", err)
+	}
+
+	if rotatedRing.SigningKeyID() != "zz-new" {
+		t.Fatalf("expected the new key to sign, got %q", rotatedRing.SigningKeyID())
+	}
+	if err := verify(rotatedRing, token); err != nil {
+		t.Errorf("expected a token from the retired key to verify: %v", err)
+	}
+
+	os.Remove(filepath.Join(dir, "old.pem"))
+	prunedRing, err := LoadKeyRing(dir, "")
+	if err != nil {
+		t.Fatalf("loading key ring: %v", err)
+	}
+	if err := verify(prunedRing, token); err == nil {
+		t.Error("expected a token from a removed key to be rejected")
+	}
+}
+
+func TestKeyRingRejectsAlgorithmConfusion(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "rsa", 2048)
+
+	ring, err := LoadKeyRing(dir, "")
+	if err != nil {
+		t.Fatalf("loading key ring: %v", err)
+	}
+
+	publicKey := ring.keys["rsa"].signer.Public().(*rsa.PublicKey)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	token.Header["kid"] = "rsa"
+	forged, err := token.SignedString(x509.MarshalPKCS1PublicKey(publicKey))
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	if err := verify(ring, forged); err == nil {
+		t.Fatal("expected an HS256 token signed with the public key to be rejected")
+	}
+
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims())
+	unsigned.Header["kid"] = "rsa"
+	none, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	if err := verify(ring, none); err == nil {
+		t.Fatal("expected an unsigned token to be rejected")
+	}
+}
+
+func TestKeyRingJWKS(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "a-rsa", 2048)
+	writeEd25519Key(t, dir, "b-ed25519")
+
+	ring, err := LoadKeyRing(dir, "")
+	if err != nil {
+		t.Fatalf("loading key ring: %v", err)
+	}
+
+	keys := ring.JWKS().Keys
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(keys))
+	}
+
+	if keys[0].Kid != "a-rsa" || keys[0].Kty != "RSA" || keys[0].Alg != "RS256" || keys[0].N == "" || keys[0].E != "AQAB" {
+		t.Errorf("unexpected RSA key %+v", keys[0])
+	}
+	if keys[1].Kid != "b-ed25519" || keys[1].Kty != "OKP" || keys[1].Crv != "Ed25519" || keys[1].Alg != "EdDSA" || keys[1].X == "" {
+		t.Errorf("unexpected Ed25519 key %+v", keys[1])
+	}
+}
@@ -1,36 +1,197 @@
 package shared
 
 import (
+	"context"
 	"errors"
+	"lexia/ent"
+	"lexia/ent/personalaccesstoken"
+	"lexia/ent/session"
+	"lexia/ent/user"
+	"log"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-func AuthMW() gin.HandlerFunc {
+// sessionTouchInterval limits how often a session's lastUsedAt is written,
+// so every authenticated request does not turn into a database write.
+const sessionTouchInterval = time.Minute
+
+const authPayloadKey = "authPayload"
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	requestIDKey    = "requestID"
+)
+
+// requestIDPattern limits the IDs taken from clients or proxies to ones that
+// are safe to log and echo back.
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestIDMW gives every request an ID, reusing the X-Request-ID header a
+// proxy set, and echoes it in the response so errors can be traced in logs.
+func RequestIDMW() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		c.Set(requestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// AuthMW accepts a valid access token only while the session it was issued
+// for is still active, so revoking a session logs its device out at once.
+// Personal access tokens are accepted too; see RequireScope for how their
+// scopes are enforced.
+func AuthMW(db *ent.Client, tokens TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		accessToken, err := GetAccessTokenFromRequest(c)
 		if err != nil {
 			ResUnauthorized(c, err.Error())
+			c.Abort()
 			return
 		}
 
-		if _, err := VerifyAccessToken(accessToken); err != nil {
-			ResUnauthorized(c, ErrInvalidToken)
+		var claims *TokenClaims
+		if strings.HasPrefix(accessToken, PersonalAccessTokenPrefix) {
+			claims, err = authenticatePersonalAccessToken(c.Request.Context(), db, accessToken)
+		} else {
+			claims, err = authenticateSession(c.Request.Context(), db, tokens, accessToken)
+		}
+		if err != nil {
+			ResUnauthorized(c, err.Error())
+			c.Abort()
 			return
 		}
 
+		SetAuthPayload(c, claims)
 		c.Next()
 	}
 }
 
-func GetAuthPayload(c *gin.Context) (*TokenClaims, error) {
-	accessToken, err := GetAccessTokenFromRequest(c)
+// SetAuthPayload records the authenticated caller. Besides AuthMW, tests use
+// it to call handlers as a given user.
+func SetAuthPayload(c *gin.Context, claims *TokenClaims) {
+	c.Set(authPayloadKey, claims)
+}
+
+func authenticateSession(ctx context.Context, db *ent.Client, tokens TokenService, accessToken string) (*TokenClaims, error) {
+	claims, err := tokens.VerifyAccessToken(accessToken)
+	if err != nil {
+		return nil, errors.New(ErrInvalidToken)
+	}
+
+	now := time.Now()
+	sessionEntity, err := db.Session.Query().
+		Where(
+			session.ID(claims.SessionID),
+			session.HasUserWith(user.ID(claims.UserID), user.DeletedAtIsNil()),
+			session.RevokedAtIsNil(),
+			session.ExpiresAtGT(now),
+		).
+		Only(ctx)
+	if err != nil {
+		if !ent.IsNotFound(err) {
+			log.Println("Error finding session: ", err)
+		}
+		return nil, errors.New(ErrSessionRevoked)
+	}
+
+	if now.Sub(sessionEntity.LastUsedAt) > sessionTouchInterval {
+		err := db.Session.UpdateOneID(sessionEntity.ID).
+			SetLastUsedAt(now).
+			Exec(ctx)
+		if err != nil {
+			log.Println("Error updating session last used time: ", err)
+		}
+	}
+
+	return claims, nil
+}
+
+func authenticatePersonalAccessToken(ctx context.Context, db *ent.Client, token string) (*TokenClaims, error) {
+	now := time.Now()
+	tokenEntity, err := db.PersonalAccessToken.Query().
+		Where(
+			personalaccesstoken.TokenHash(HashOpaqueToken(token)),
+			personalaccesstoken.HasUserWith(user.DeletedAtIsNil()),
+			personalaccesstoken.Or(
+				personalaccesstoken.ExpiresAtIsNil(),
+				personalaccesstoken.ExpiresAtGT(now),
+			),
+		).
+		WithUser().
+		Only(ctx)
 	if err != nil {
-		return nil, err
+		if !ent.IsNotFound(err) {
+			log.Println("Error finding personal access token: ", err)
+		}
+		return nil, errors.New(ErrInvalidToken)
+	}
+
+	if tokenEntity.LastUsedAt == nil || now.Sub(*tokenEntity.LastUsedAt) > sessionTouchInterval {
+		err := db.PersonalAccessToken.UpdateOneID(tokenEntity.ID).
+			SetLastUsedAt(now).
+			Exec(ctx)
+		if err != nil {
+			log.Println("Error updating personal access token last used time: ", err)
+		}
+	}
+
+	return &TokenClaims{
+		UserID:                tokenEntity.Edges.User.ID,
+		Email:                 tokenEntity.Edges.User.Email,
+		PersonalAccessTokenID: tokenEntity.ID,
+		Scopes:                tokenEntity.Scopes,
+	}, nil
+}
+
+// AdminMW only lets through users flagged as admins. It must run after AuthMW.
+func AdminMW(db *ent.Client) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authPayload, err := GetAuthPayload(c)
+		if err != nil {
+			ResUnauthorized(c, err.Error())
+			c.Abort()
+			return
+		}
+
+		userEntity, err := db.User.Get(c.Request.Context(), authPayload.UserID)
+		if err != nil || !userEntity.IsAdmin {
+			ResForbidden(c, ErrForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetAuthPayload returns the caller AuthMW authenticated. Personal access
+// tokens are refused unless the route declared its scopes with RequireScope.
+func GetAuthPayload(c *gin.Context) (*TokenClaims, error) {
+	authPayload, ok := c.Get(authPayloadKey)
+	if !ok {
+		return nil, errors.New(ErrMissingToken)
+	}
+
+	claims := authPayload.(*TokenClaims)
+	if claims.IsPersonalAccessToken() && !c.GetBool(scopeCheckedKey) {
+		return nil, errors.New(ErrInsufficientScope)
 	}
 
-	return VerifyAccessToken(accessToken)
+	return claims, nil
 }
 
 func GetAccessTokenFromRequest(c *gin.Context) (string, error) {
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,6 +10,8 @@ import (
 type HttpError struct {
 	Code    int
 	Message string
+	// RetryAfter is sent as the Retry-After header when set.
+	RetryAfter time.Duration
 }
 
 type HttpRes struct {
@@ -20,18 +23,25 @@ func (e HttpError) Error() string {
 	return e.Message
 }
 
-func ResHttpError(c *gin.Context, httpError *HttpError) {
-	c.JSON(httpError.Code, map[string]string{"error": httpError.Message})
+// ErrorCode is the machine-readable code for the error. Messages that are
+// already codes, like INVALID_PASSWORD, double as the code; other messages
+// get the generic code for the status.
+func (e HttpError) ErrorCode() string {
+	if errorCodePattern.MatchString(e.Message) {
+		return e.Message
+	}
+
+	return statusErrorCode(e.Code)
 }
 
-func ResTryHttpError(c *gin.Context, err error) {
-	httpError, ok := err.(*HttpError)
-	if !ok {
-		ResInternalServerErrorDef(c)
-		return
-	}
+func (e HttpError) ErrorMessage() string {
+	return e.Message
+}
 
-	c.JSON(httpError.Code, map[string]string{"error": httpError.Message})
+// ResError responds with the error envelope for err; see ErrorMW.
+func ResError(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
 }
 
 func ResOK(c *gin.Context, payload any) {
@@ -55,43 +65,43 @@ func ResNoContent(c *gin.Context) {
 }
 
 func ResBadRequest(c *gin.Context, msg string) {
-	c.JSON(http.StatusBadRequest, map[string]string{"error": msg})
+	ResError(c, BadRequest(msg))
 }
 
 func ResUnauthorized(c *gin.Context, msg string) {
-	c.JSON(http.StatusUnauthorized, map[string]string{"error": msg})
+	ResError(c, Unauthorized(msg))
 }
 
 func ResForbidden(c *gin.Context, msg string) {
-	c.JSON(http.StatusForbidden, map[string]string{"error": msg})
+	ResError(c, Forbidden(msg))
 }
 
 func ResNotFound(c *gin.Context, msg string) {
-	c.JSON(http.StatusNotFound, map[string]string{"error": msg})
+	ResError(c, NotFound(msg))
 }
 
 func ResMethodNotAllowed(c *gin.Context, msg string) {
-	c.JSON(http.StatusMethodNotAllowed, map[string]string{"error": msg})
+	ResError(c, MethodNotAllowed(msg))
 }
 
 func ResNotAcceptable(c *gin.Context, msg string) {
-	c.JSON(http.StatusNotAcceptable, map[string]string{"error": msg})
+	ResError(c, NotAcceptable(msg))
 }
 
 func ResConflict(c *gin.Context, msg string) {
-	c.JSON(http.StatusConflict, map[string]string{"error": msg})
+	ResError(c, Conflict(msg))
 }
 
 func ResInternalServerError(c *gin.Context, msg string) {
-	c.JSON(http.StatusInternalServerError, map[string]string{"error": msg})
+	ResError(c, InternalServerError(msg))
 }
 
 func ResInternalServerErrorDef(c *gin.Context) {
-	c.JSON(http.StatusInternalServerError, map[string]string{"error": ErrInternal})
+	ResError(c, InternalServerErrorDef())
 }
 
 func ResNotImplemented(c *gin.Context, msg string) {
-	c.JSON(http.StatusNotImplemented, map[string]string{"error": msg})
+	ResError(c, NotImplemented(msg))
 }
 
 func OK(payload any) *HttpRes {
@@ -178,6 +188,14 @@ func Conflict(msg string) *HttpError {
 	}
 }
 
+func TooManyRequests(msg string, retryAfter time.Duration) *HttpError {
+	return &HttpError{
+		Message:    msg,
+		Code:       http.StatusTooManyRequests,
+		RetryAfter: retryAfter,
+	}
+}
+
 func InternalServerError(msg string) *HttpError {
 	return &HttpError{
 		Message: msg,
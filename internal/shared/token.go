@@ -0,0 +1,31 @@
+package shared
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, which lets
+// AuthMW tell them from JWTs and secret scanners recognise leaked ones.
+const PersonalAccessTokenPrefix = "lexia_pat_"
+
+// GenerateOpaqueToken returns a random URL-safe token with 256 bits of
+// entropy, used for refresh tokens and single-use links.
+func GenerateOpaqueToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// HashOpaqueToken hashes a token from GenerateOpaqueToken for storage. The
+// tokens are random enough that a plain SHA-256 keeps them unusable if the
+// table leaks.
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
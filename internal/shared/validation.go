@@ -2,6 +2,7 @@ package shared
 
 import (
 	"fmt"
+	"lexia/internal/apperr"
 	"regexp"
 	"strings"
 
@@ -23,9 +24,31 @@ type ValidationError struct {
 	Value   any    `json:"value,omitempty"`
 }
 
+// ValidationErrorResponse is sent as an invalid input error with the field
+// errors as its details.
 type ValidationErrorResponse struct {
-	Message string            `json:"message"`
-	Errors  []ValidationError `json:"errors"`
+	Message string
+	Errors  []ValidationError
+}
+
+func (e *ValidationErrorResponse) Error() string {
+	return e.Message
+}
+
+func (e *ValidationErrorResponse) Is(target error) bool {
+	return target == apperr.ErrInvalidInput
+}
+
+func (e *ValidationErrorResponse) ErrorCode() string {
+	return ErrValidationFailed
+}
+
+func (e *ValidationErrorResponse) ErrorMessage() string {
+	return e.Message
+}
+
+func (e *ValidationErrorResponse) ErrorDetails() any {
+	return e.Errors
 }
 
 func ValidateUsername(fl validator.FieldLevel) bool {
@@ -76,12 +99,21 @@ func ValidateStruct(s any) *ValidationErrorResponse {
 
 func BindAndValidate(c *gin.Context, obj any) *ValidationErrorResponse {
 	if err := c.ShouldBindJSON(obj); err != nil {
-		return handleBindingError(err)
+		return handleBindingError(err, "body", "Invalid JSON format or request body")
+	}
+	return nil
+}
+
+func BindQueryAndValidate(c *gin.Context, obj any) *ValidationErrorResponse {
+	if err := c.ShouldBindQuery(obj); err != nil {
+		return handleBindingError(err, "query", "Invalid query parameters")
 	}
 	return nil
 }
 
-func handleBindingError(err error) *ValidationErrorResponse {
+// handleBindingError lists the fields that failed validation, or reports the
+// whole body or query as invalid when it could not be parsed at all.
+func handleBindingError(err error, source string, sourceMessage string) *ValidationErrorResponse {
 	var validationErrors []ValidationError
 
 	if validationErrs, ok := err.(validator.ValidationErrors); ok {
@@ -94,8 +126,8 @@ func handleBindingError(err error) *ValidationErrorResponse {
 		}
 	} else {
 		validationErrors = append(validationErrors, ValidationError{
-			Field:   "body",
-			Message: "Invalid JSON format or request body",
+			Field:   source,
+			Message: sourceMessage,
 		})
 	}
 
@@ -153,5 +185,5 @@ func getValidationMessage(e validator.FieldError) string {
 }
 
 func ResValidationError(c *gin.Context, validationError *ValidationErrorResponse) {
-	c.JSON(400, validationError)
+	ResError(c, validationError)
 }
@@ -0,0 +1,128 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops expired keys, so keys that
+// never come back do not pile up.
+const sweepInterval = 10 * time.Minute
+
+type memoryEntry struct {
+	state     State
+	expiresAt time.Time
+}
+
+// MemoryStore keeps failures in process memory. It is only correct when a
+// single instance serves all sign-ins.
+type MemoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]memoryEntry{}}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string, now time.Time) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || !entry.expiresAt.After(now) {
+		return State{}, nil
+	}
+
+	return entry.state, nil
+}
+
+func (s *MemoryStore) RecordFailure(_ context.Context, key string, now time.Time, ttl time.Duration) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	entry, ok := s.entries[key]
+	if !ok || !entry.expiresAt.After(now) {
+		entry = memoryEntry{}
+	}
+
+	entry.state.Failures++
+	entry.state.LastFailureAt = now
+	entry.expiresAt = now.Add(ttl)
+	s.entries[key] = entry
+
+	return entry.state, nil
+}
+
+func (s *MemoryStore) RecordAttempt(
+	_ context.Context,
+	key string,
+	now time.Time,
+	ttl time.Duration,
+	wait func(State) time.Duration,
+) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	entry, ok := s.entries[key]
+	if !ok || !entry.expiresAt.After(now) {
+		entry = memoryEntry{}
+	}
+
+	if w := wait(entry.state); w > 0 {
+		return w, nil
+	}
+
+	entry.state.Failures++
+	entry.state.LastFailureAt = now
+	entry.expiresAt = now.Add(ttl)
+	s.entries[key] = entry
+
+	return 0, nil
+}
+
+func (s *MemoryStore) RemoveFailure(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, ok := s.entries[key]; ok && entry.state.Failures > 0 {
+		entry.state.Failures--
+		s.entries[key] = entry
+	}
+	return nil
+}
+
+func (s *MemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// Clear forgets every key.
+func (s *MemoryStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries = map[string]memoryEntry{}
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, entry := range s.entries {
+		if !entry.expiresAt.After(now) {
+			delete(s.entries, key)
+		}
+	}
+}
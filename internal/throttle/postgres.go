@@ -0,0 +1,199 @@
+package throttle
+
+import (
+	"context"
+	"lexia/ent"
+	"log"
+	"sync"
+	"time"
+)
+
+const throttleGetQuery = `
+SELECT failures, last_failure_at
+FROM throttle_entries
+WHERE id = $1 AND expires_at > $2
+`
+
+// throttleRecordFailureQuery increments a key in one statement, so replicas
+// recording failures for the same key at once never lose a count.
+const throttleRecordFailureQuery = `
+INSERT INTO throttle_entries (id, failures, last_failure_at, expires_at)
+VALUES ($1, 1, $2, $3)
+ON CONFLICT (id) DO UPDATE SET
+	failures = CASE
+		WHEN throttle_entries.expires_at <= $2 THEN 1
+		ELSE throttle_entries.failures + 1
+	END,
+	last_failure_at = $2,
+	expires_at = $3
+RETURNING failures, last_failure_at
+`
+
+// throttleEnsureQuery adds an already expired row for a new key, so that
+// throttleLockQuery always has a row to lock.
+const throttleEnsureQuery = `
+INSERT INTO throttle_entries (id, failures, last_failure_at, expires_at)
+VALUES ($1, 0, $2, $2)
+ON CONFLICT (id) DO NOTHING
+`
+
+const throttleLockQuery = `
+SELECT failures, last_failure_at, expires_at
+FROM throttle_entries
+WHERE id = $1
+FOR UPDATE
+`
+
+const throttleSetQuery = `
+UPDATE throttle_entries
+SET failures = $2, last_failure_at = $3, expires_at = $4
+WHERE id = $1
+`
+
+const throttleRemoveFailureQuery = `
+UPDATE throttle_entries
+SET failures = failures - 1
+WHERE id = $1 AND failures > 0
+`
+
+const throttleResetQuery = `DELETE FROM throttle_entries WHERE id = $1`
+
+const throttleSweepQuery = `DELETE FROM throttle_entries WHERE expires_at <= $1`
+
+// PostgresStore shares failures between replicas through the
+// throttle_entries table.
+type PostgresStore struct {
+	db *ent.Client
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresStore(db *ent.Client) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Get(ctx context.Context, key string, now time.Time) (State, error) {
+	rows, err := s.db.QueryContext(ctx, throttleGetQuery, key, now)
+	if err != nil {
+		return State{}, err
+	}
+	defer rows.Close()
+
+	var state State
+	if rows.Next() {
+		if err := rows.Scan(&state.Failures, &state.LastFailureAt); err != nil {
+			return State{}, err
+		}
+	}
+
+	return state, rows.Err()
+}
+
+func (s *PostgresStore) RecordFailure(ctx context.Context, key string, now time.Time, ttl time.Duration) (State, error) {
+	s.sweep(ctx, now)
+
+	rows, err := s.db.QueryContext(ctx, throttleRecordFailureQuery, key, now, now.Add(ttl))
+	if err != nil {
+		return State{}, err
+	}
+	defer rows.Close()
+
+	var state State
+	if rows.Next() {
+		if err := rows.Scan(&state.Failures, &state.LastFailureAt); err != nil {
+			return State{}, err
+		}
+	}
+
+	return state, rows.Err()
+}
+
+// RecordAttempt holds the key's row locked from reading its state until the
+// attempt is recorded, so replicas serving attempts for the same key at once
+// take turns.
+func (s *PostgresStore) RecordAttempt(
+	ctx context.Context,
+	key string,
+	now time.Time,
+	ttl time.Duration,
+	wait func(State) time.Duration,
+) (time.Duration, error) {
+	s.sweep(ctx, now)
+
+	tx, err := s.db.Tx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	client := tx.Client()
+	if _, err := client.ExecContext(ctx, throttleEnsureQuery, key, now); err != nil {
+		return 0, err
+	}
+
+	state, expiresAt, err := lockThrottleEntry(ctx, client, key)
+	if err != nil {
+		return 0, err
+	}
+	if !expiresAt.After(now) {
+		state = State{}
+	}
+
+	if w := wait(state); w > 0 {
+		return w, tx.Commit()
+	}
+
+	_, err = client.ExecContext(ctx, throttleSetQuery, key, state.Failures+1, now, now.Add(ttl))
+	if err != nil {
+		return 0, err
+	}
+
+	return 0, tx.Commit()
+}
+
+func lockThrottleEntry(ctx context.Context, client *ent.Client, key string) (State, time.Time, error) {
+	rows, err := client.QueryContext(ctx, throttleLockQuery, key)
+	if err != nil {
+		return State{}, time.Time{}, err
+	}
+	defer rows.Close()
+
+	var (
+		state     State
+		expiresAt time.Time
+	)
+	if rows.Next() {
+		if err := rows.Scan(&state.Failures, &state.LastFailureAt, &expiresAt); err != nil {
+			return State{}, time.Time{}, err
+		}
+	}
+
+	return state, expiresAt, rows.Err()
+}
+
+func (s *PostgresStore) RemoveFailure(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, throttleRemoveFailureQuery, key)
+	return err
+}
+
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, throttleResetQuery, key)
+	return err
+}
+
+// sweep deletes expired keys at most once per sweepInterval per process.
+// Failing to sweep only leaves stale rows behind, so errors are logged.
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	if _, err := s.db.ExecContext(ctx, throttleSweepQuery, now); err != nil {
+		log.Println("Error sweeping throttle entries: ", err)
+	}
+}
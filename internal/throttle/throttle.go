@@ -0,0 +1,161 @@
+// Package throttle slows down repeated failures, such as wrong passwords,
+// with exponential backoff per key.
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"lexia/ent"
+)
+
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+// Policy decides how long a key has to wait after its failures. The first
+// FreeAttempts failures cost nothing; each further one doubles the wait,
+// starting at BaseDelay and capped at MaxDelay. Failures are forgotten
+// ResetAfter the last one.
+type Policy struct {
+	FreeAttempts int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	ResetAfter   time.Duration
+}
+
+// Delay is the wait after the given number of consecutive failures.
+func (p Policy) Delay(failures int) time.Duration {
+	if failures < p.FreeAttempts {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+// State is what a store remembers about a key.
+type State struct {
+	Failures      int
+	LastFailureAt time.Time
+}
+
+// Store keeps failure counts. Implementations must be safe for concurrent
+// use and must update a key atomically.
+type Store interface {
+	// Get returns the state of key, or the zero State if it has none or it
+	// expired.
+	Get(ctx context.Context, key string, now time.Time) (State, error)
+	// RecordFailure adds a failure to key and returns its new state. The
+	// count restarts at one when the key expired; a recorded key expires at
+	// now plus ttl.
+	RecordFailure(ctx context.Context, key string, now time.Time, ttl time.Duration) (State, error)
+	// RecordAttempt calls wait with the state of key and, when it returns
+	// zero, records a failure as RecordFailure does. It returns what wait
+	// returned. Reading and updating the key is atomic, so of concurrent
+	// attempts each sees the ones before it.
+	RecordAttempt(ctx context.Context, key string, now time.Time, ttl time.Duration, wait func(State) time.Duration) (time.Duration, error)
+	// RemoveFailure takes one failure off key, if it has any.
+	RemoveFailure(ctx context.Context, key string) error
+	Reset(ctx context.Context, key string) error
+}
+
+type Config struct {
+	Backend string
+	DB      *ent.Client
+}
+
+func NewStore(config Config) (Store, error) {
+	switch config.Backend {
+	case BackendMemory, "":
+		return NewMemoryStore(), nil
+	case BackendPostgres:
+		return NewPostgresStore(config.DB), nil
+	default:
+		return nil, fmt.Errorf("unknown throttle backend %q", config.Backend)
+	}
+}
+
+// Key is one thing being throttled, for example an account or an IP.
+type Key struct {
+	ID     string
+	Policy Policy
+}
+
+type Throttler struct {
+	store Store
+	now   func() time.Time
+}
+
+func New(store Store) *Throttler {
+	return &Throttler{store: store, now: time.Now}
+}
+
+// Wait returns how long until every key may try again, zero if they all
+// may try now.
+func (t *Throttler) Wait(ctx context.Context, keys ...Key) (time.Duration, error) {
+	now := t.now()
+
+	var wait time.Duration
+	for _, key := range keys {
+		state, err := t.store.Get(ctx, key.ID, now)
+		if err != nil {
+			return 0, err
+		}
+
+		wait = max(wait, waitFor(key.Policy, state, now))
+	}
+
+	return wait, nil
+}
+
+// Attempt counts an attempt as a failure of every key before it is made,
+// so concurrent attempts cannot all get through while the keys have no wait
+// yet. It returns how long the caller has to wait instead, zero if the
+// attempt may go ahead. Keys are tried in order and the first one that has
+// to wait refuses the attempt, which the keys after it do not count. An
+// attempt that succeeds is taken back with Forgive or Reset.
+func (t *Throttler) Attempt(ctx context.Context, keys ...Key) (time.Duration, error) {
+	now := t.now()
+
+	for _, key := range keys {
+		wait, err := t.store.RecordAttempt(ctx, key.ID, now, key.Policy.ttl(), func(state State) time.Duration {
+			return waitFor(key.Policy, state, now)
+		})
+		if err != nil || wait > 0 {
+			return wait, err
+		}
+	}
+
+	return 0, nil
+}
+
+// Forgive takes back one attempt of key counted by Attempt.
+func (t *Throttler) Forgive(ctx context.Context, key Key) error {
+	return t.store.RemoveFailure(ctx, key.ID)
+}
+
+// Reset forgets the failures of key, typically after a success.
+func (t *Throttler) Reset(ctx context.Context, key Key) error {
+	return t.store.Reset(ctx, key.ID)
+}
+
+// ttl keeps a key at least until its longest wait is over, so the count is
+// still there when that wait ends.
+func (p Policy) ttl() time.Duration {
+	return max(p.ResetAfter, p.MaxDelay)
+}
+
+func waitFor(policy Policy, state State, now time.Time) time.Duration {
+	if state.Failures == 0 {
+		return 0
+	}
+
+	return max(state.LastFailureAt.Add(policy.Delay(state.Failures)).Sub(now), 0)
+}
@@ -0,0 +1,184 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var testPolicy = Policy{
+	FreeAttempts: 3,
+	BaseDelay:    time.Second,
+	MaxDelay:     10 * time.Second,
+	ResetAfter:   time.Minute,
+}
+
+func TestPolicyDelay(t *testing.T) {
+	expected := map[int]time.Duration{
+		0:  0,
+		2:  0,
+		3:  time.Second,
+		4:  2 * time.Second,
+		5:  4 * time.Second,
+		6:  8 * time.Second,
+		7:  10 * time.Second,
+		50: 10 * time.Second,
+	}
+
+	for failures, delay := range expected {
+		if got := testPolicy.Delay(failures); got != delay {
+			t.Errorf("Delay(%d) = %v, want %v", failures, got, delay)
+		}
+	}
+}
+
+func newTestThrottler() (*Throttler, *time.Time) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	throttler := New(NewMemoryStore())
+	throttler.now = func() time.Time { return now }
+	return throttler, &now
+}
+
+func TestThrottlerBacksOffAndResets(t *testing.T) {
+	ctx := context.Background()
+	throttler, now := newTestThrottler()
+	key := Key{ID: "account", Policy: testPolicy}
+
+	for i := 0; i < 2; i++ {
+		if _, err := throttler.Attempt(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if wait, _ := throttler.Wait(ctx, key); wait != 0 {
+		t.Fatalf("expected free attempts to cost nothing, got %v", wait)
+	}
+
+	if _, err := throttler.Attempt(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if wait, _ := throttler.Wait(ctx, key); wait != time.Second {
+		t.Fatalf("expected a 1s wait after the third attempt, got %v", wait)
+	}
+
+	*now = now.Add(500 * time.Millisecond)
+	if wait, _ := throttler.Wait(ctx, key); wait != 500*time.Millisecond {
+		t.Fatalf("expected 500ms left, got %v", wait)
+	}
+
+	*now = now.Add(time.Second)
+	if wait, _ := throttler.Attempt(ctx, key); wait != 0 {
+		t.Fatalf("expected the wait to be over, got %v", wait)
+	}
+	if wait, _ := throttler.Wait(ctx, key); wait != 2*time.Second {
+		t.Fatalf("expected the wait to double after the fourth attempt, got %v", wait)
+	}
+
+	if err := throttler.Reset(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	throttler.Attempt(ctx, key)
+	if wait, _ := throttler.Wait(ctx, key); wait != 0 {
+		t.Fatalf("expected the count to restart after a reset, got %v", wait)
+	}
+}
+
+func TestThrottlerForgetsOldFailures(t *testing.T) {
+	ctx := context.Background()
+	throttler, now := newTestThrottler()
+	key := Key{ID: "account", Policy: testPolicy}
+
+	for i := 0; i < testPolicy.FreeAttempts; i++ {
+		throttler.Attempt(ctx, key)
+	}
+
+	*now = now.Add(testPolicy.ResetAfter)
+	if wait, _ := throttler.Wait(ctx, key); wait != 0 {
+		t.Fatalf("expected failures to expire, got %v", wait)
+	}
+
+	throttler.Attempt(ctx, key)
+	if wait, _ := throttler.Wait(ctx, key); wait != 0 {
+		t.Fatalf("expected the count to restart after expiry, got %v", wait)
+	}
+}
+
+func TestThrottlerWaitsForSlowestKey(t *testing.T) {
+	ctx := context.Background()
+	throttler, _ := newTestThrottler()
+	account := Key{ID: "account", Policy: testPolicy}
+	ip := Key{ID: "ip", Policy: Policy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour, ResetAfter: time.Hour}}
+
+	if wait, err := throttler.Attempt(ctx, account, ip); err != nil || wait != 0 {
+		t.Fatalf("expected the first attempt to go ahead, got %v, %v", wait, err)
+	}
+
+	if wait, _ := throttler.Wait(ctx, account); wait != 0 {
+		t.Fatalf("expected the account to have no wait, got %v", wait)
+	}
+	if wait, _ := throttler.Wait(ctx, account, ip); wait != time.Minute {
+		t.Fatalf("expected the IP wait to apply, got %v", wait)
+	}
+}
+
+func TestThrottlerAttemptCountsBeforeRefusing(t *testing.T) {
+	ctx := context.Background()
+	throttler, _ := newTestThrottler()
+	key := Key{ID: "account", Policy: testPolicy}
+
+	for i := 0; i < testPolicy.FreeAttempts; i++ {
+		wait, err := throttler.Attempt(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wait != 0 {
+			t.Fatalf("expected attempt %d to go ahead, got %v", i+1, wait)
+		}
+	}
+
+	if wait, _ := throttler.Attempt(ctx, key); wait != time.Second {
+		t.Fatalf("expected the attempt after the free ones to wait 1s, got %v", wait)
+	}
+
+	// Refused attempts are not counted, so the wait does not grow.
+	if wait, _ := throttler.Attempt(ctx, key); wait != time.Second {
+		t.Fatalf("expected a refused attempt to leave the wait alone, got %v", wait)
+	}
+}
+
+func TestThrottlerAttemptStopsAtFirstRefusingKey(t *testing.T) {
+	ctx := context.Background()
+	throttler, _ := newTestThrottler()
+	ip := Key{ID: "ip", Policy: Policy{FreeAttempts: 1, BaseDelay: time.Minute, MaxDelay: time.Hour, ResetAfter: time.Hour}}
+	account := Key{ID: "account", Policy: testPolicy}
+
+	throttler.Attempt(ctx, ip, account)
+	if wait, _ := throttler.Attempt(ctx, ip, account); wait != time.Minute {
+		t.Fatalf("expected the IP to refuse, got %v", wait)
+	}
+
+	state, err := throttler.store.Get(ctx, account.ID, throttler.now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Failures != 1 {
+		t.Fatalf("expected the account to have counted only the first attempt, got %d", state.Failures)
+	}
+}
+
+func TestThrottlerForgiveTakesBackAttempt(t *testing.T) {
+	ctx := context.Background()
+	throttler, _ := newTestThrottler()
+	key := Key{ID: "ip", Policy: testPolicy}
+
+	for i := 0; i < 10; i++ {
+		throttler.Attempt(ctx, key)
+		if err := throttler.Forgive(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if wait, _ := throttler.Wait(ctx, key); wait != 0 {
+		t.Fatalf("expected forgiven attempts not to count, got %v", wait)
+	}
+}
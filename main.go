@@ -2,12 +2,22 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"lexia/ent"
 	"lexia/internal/logger"
+	"lexia/internal/mailer"
 	"lexia/internal/modules"
+	"lexia/internal/modules/folder"
+	"lexia/internal/modules/languages"
+	"lexia/internal/modules/review"
+	"lexia/internal/modules/user"
+	"lexia/internal/password"
 	"lexia/internal/shared"
+	"lexia/internal/throttle"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 	_ "time/tzdata"
@@ -28,15 +38,66 @@ func main() {
 
 	defer db.Close()
 
+	folder.RegisterWordCountHooks(db)
+
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], envVars, db)
+		return
+	}
+
+	if err := languages.SyncLanguages(context.Background(), db); err != nil {
+		panic(err)
+	}
+
+	mail, err := mailer.New(mailer.Config{
+		Backend: envVars.MailerBackend,
+		SMTP: mailer.SMTPConfig{
+			Host:     envVars.SmtpHost,
+			Port:     int(envVars.SmtpPort),
+			Username: envVars.SmtpUsername,
+			Password: envVars.SmtpPassword,
+			From:     envVars.MailFrom,
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	mailQueue := mailer.NewQueue(mail, mailer.DefaultQueueSize)
+	go mailQueue.Run()
+
+	throttleStore, err := throttle.NewStore(throttle.Config{
+		Backend: envVars.ThrottleBackend,
+		DB:      db,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	passwordHasher, err := password.New(envVars.PasswordHash)
+	if err != nil {
+		panic(err)
+	}
+
 	resouceConfig := &shared.ResourceConfig{
-		DB: db,
+		Env:            envVars,
+		DB:             db,
+		Mailer:         mailQueue,
+		Throttler:      throttle.New(throttleStore),
+		PasswordHasher: passwordHasher,
+	}
+
+	services, err := modules.NewServices(db, envVars)
+	if err != nil {
+		panic(err)
 	}
 
 	apiCfg := shared.ApiConfig{
 		ResourceConfig: resouceConfig,
+		Services:       services,
 	}
 
-	server, err := modules.CreateWebserver(&apiCfg)
+	server, err := modules.CreateWebserver(&apiCfg, envVars)
 	if err != nil {
 		panic(err)
 	}
@@ -49,6 +110,30 @@ func main() {
 		Handler: server,
 	}
 
+	workerCtx, cancelWorkers := context.WithCancel(context.Background())
+	defer cancelWorkers()
+
+	// The background jobs and loops write to the database until they stop,
+	// so shutdown waits for them before closing it.
+	var workers sync.WaitGroup
+	workers.Add(4)
+	go func() {
+		defer workers.Done()
+		services.AutofillWorker.Run(workerCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		services.SrsOptimizer.Run(workerCtx)
+	}()
+	go func() {
+		defer workers.Done()
+		review.StartFsrsOptimizer(workerCtx, db, services.SrsOptimizer, review.DefaultOptimizerInterval)
+	}()
+	go func() {
+		defer workers.Done()
+		user.StartAccountPurger(workerCtx, db, user.AccountDeletionGracePeriod(envVars), user.DefaultAccountPurgeInterval)
+	}()
+
 	go func() {
 		logger.Info("Starting HTTP server on :" + envVars.Port)
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -59,6 +144,8 @@ func main() {
 	<-signalChan
 	logger.Info("Received shutdown signal, shutting down server...")
 
+	cancelWorkers()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -66,5 +153,31 @@ func main() {
 		logger.Fatal("Server forced to shutdown: ", err)
 	}
 
+	workers.Wait()
+	mailQueue.Close()
+
+	if err := services.Translations.Close(); err != nil {
+		logger.Error("Error closing translation provider: ", err)
+	}
+
 	logger.Info("Server exited gracefully")
 }
+
+func runCommand(command string, envVars *shared.EnvVariables, db *ent.Client) {
+	switch command {
+	case "recount":
+		updated, err := folder.RecountWordCounts(context.Background(), db)
+		if err != nil {
+			logger.Fatal("Failed to recount folder word counts: ", err)
+		}
+		logger.Info(fmt.Sprintf("Recounted folder word counts, %d folders updated", updated))
+	case "purge-accounts":
+		purged, err := user.PurgeDeletedUsers(context.Background(), db, user.AccountDeletionGracePeriod(envVars))
+		if err != nil {
+			logger.Fatal("Failed to purge deleted accounts: ", err)
+		}
+		logger.Info(fmt.Sprintf("Purged %d deleted accounts", purged))
+	default:
+		logger.Fatal("Unknown command: " + command)
+	}
+}
@@ -0,0 +1,145 @@
+package e2etest
+
+import (
+	"fmt"
+	"lexia/test/helpers"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccessTokenTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+	authToken  string
+}
+
+func (suite *AccessTokenTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+	suite.authToken = helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+}
+
+func TestAccessTokenTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessTokenTestSuite))
+}
+
+func (suite *AccessTokenTestSuite) sessionHeaders() map[string]string {
+	return map[string]string{"Authorization": suite.authToken}
+}
+
+func (suite *AccessTokenTestSuite) createToken(body map[string]any) map[string]any {
+	resp := suite.httpClient.POST("/api/v1/user/tokens", body, suite.sessionHeaders())
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode, resp.GetBodyAsString())
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	return response
+}
+
+func (suite *AccessTokenTestSuite) TestCreateAndListTokens() {
+	created := suite.createToken(map[string]any{
+		"name":          "Import script",
+		"scopes":        []string{"words:read", "words:write", "words:read"},
+		"expiresInDays": 30,
+	})
+
+	token := created["token"].(string)
+	assert.True(suite.T(), strings.HasPrefix(token, "lexia_pat_"))
+	assert.True(suite.T(), strings.HasPrefix(token, created["tokenPrefix"].(string)))
+	assert.Equal(suite.T(), []any{"words:read", "words:write"}, created["scopes"])
+	assert.NotNil(suite.T(), created["expiresAt"])
+
+	resp := suite.httpClient.GET("/api/v1/user/tokens", suite.sessionHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var tokens []map[string]any
+	suite.Require().NoError(resp.ParseJSON(&tokens))
+	suite.Require().Len(tokens, 1)
+	assert.Equal(suite.T(), "Import script", tokens[0]["name"])
+	assert.NotContains(suite.T(), tokens[0], "token")
+}
+
+func (suite *AccessTokenTestSuite) TestCreateTokenWithUnknownScope() {
+	resp := suite.httpClient.POST("/api/v1/user/tokens", map[string]any{
+		"name":   "Bad",
+		"scopes": []string{"admin"},
+	}, suite.sessionHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *AccessTokenTestSuite) TestTokenWithScopeCanCallRoute() {
+	created := suite.createToken(map[string]any{
+		"name":   "Reader",
+		"scopes": []string{"folders:read"},
+	})
+
+	resp := suite.httpClient.GET("/api/v1/folders", authHeaders(created["token"].(string)))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode, resp.GetBodyAsString())
+
+	var tokens []map[string]any
+	resp = suite.httpClient.GET("/api/v1/user/tokens", suite.sessionHeaders())
+	suite.Require().NoError(resp.ParseJSON(&tokens))
+	assert.NotNil(suite.T(), tokens[0]["lastUsedAt"])
+}
+
+func (suite *AccessTokenTestSuite) TestTokenWithoutScopeIsForbidden() {
+	created := suite.createToken(map[string]any{
+		"name":   "Reader",
+		"scopes": []string{"folders:read"},
+	})
+
+	resp := suite.httpClient.POST("/api/v1/folders", map[string]any{
+		"name": "Nope",
+		"type": "FOLDER_COLLECTION",
+	}, authHeaders(created["token"].(string)))
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+	assert.Contains(suite.T(), resp.GetBodyAsString(), "INSUFFICIENT_SCOPE")
+}
+
+func (suite *AccessTokenTestSuite) TestTokenCannotReachUnscopedRoutes() {
+	created := suite.createToken(map[string]any{
+		"name":   "Everything",
+		"scopes": []string{"folders:read", "folders:write", "words:read", "words:write", "reviews:read", "reviews:write", "translate"},
+	})
+	headers := authHeaders(created["token"].(string))
+
+	for _, path := range []string{"/api/v1/user/auth", "/api/v1/user/sessions", "/api/v1/user/tokens"} {
+		resp := suite.httpClient.GET(path, headers)
+		assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode, path)
+		assert.Contains(suite.T(), resp.GetBodyAsString(), "INSUFFICIENT_SCOPE", path)
+	}
+}
+
+func (suite *AccessTokenTestSuite) TestDeletedTokenIsRejected() {
+	created := suite.createToken(map[string]any{
+		"name":   "Temporary",
+		"scopes": []string{"folders:read"},
+	})
+
+	resp := suite.httpClient.DELETE(fmt.Sprintf("/api/v1/user/tokens/%s", created["id"]), suite.sessionHeaders())
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/folders", authHeaders(created["token"].(string)))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *AccessTokenTestSuite) TestExpiredTokenIsRejected() {
+	created := suite.createToken(map[string]any{
+		"name":          "Short lived",
+		"scopes":        []string{"folders:read"},
+		"expiresInDays": 1,
+	})
+
+	_, err := suite.GetDBClient().PersonalAccessToken.Update().
+		SetExpiresAt(time.Now().Add(-time.Minute)).
+		Save(suite.GetContext())
+	suite.Require().NoError(err)
+
+	resp := suite.httpClient.GET("/api/v1/folders", authHeaders(created["token"].(string)))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
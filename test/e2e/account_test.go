@@ -0,0 +1,261 @@
+package e2etest
+
+import (
+	"lexia/ent/folder"
+	"lexia/ent/user"
+	"lexia/ent/word"
+	usermodule "lexia/internal/modules/user"
+	"lexia/internal/shared"
+	"lexia/test/helpers"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccountTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+}
+
+func (suite *AccountTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+
+	resp := suite.httpClient.POST("/api/v1/auth/signup", map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+		"username": "testuser",
+	})
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func TestAccountTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountTestSuite))
+}
+
+func (suite *AccountTestSuite) signIn(email string, password string) *helpers.Response {
+	return suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+		"email":    email,
+		"password": password,
+	})
+}
+
+func (suite *AccountTestSuite) accessToken() string {
+	resp := suite.signIn("test@example.com", "password123")
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	return response["accessToken"].(string)
+}
+
+func (suite *AccountTestSuite) deleteAccount(accessToken string, password string) *helpers.Response {
+	return suite.httpClient.Do(helpers.Request{
+		Method:  http.MethodDelete,
+		Path:    "/api/v1/user/auth",
+		Body:    map[string]string{"password": password},
+		Headers: authHeaders(accessToken),
+	})
+}
+
+func (suite *AccountTestSuite) assertError(resp *helpers.Response, expected string) {
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Equal(suite.T(), expected, response["code"])
+}
+
+func (suite *AccountTestSuite) TestChangePassword() {
+	currentToken := suite.accessToken()
+	otherToken := suite.accessToken()
+
+	resp := suite.httpClient.PUT("/api/v1/user/auth/password", map[string]string{
+		"currentPassword": "wrongPassword",
+		"newPassword":     "newPassword456",
+	}, authHeaders(currentToken))
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+	suite.assertError(resp, shared.ErrInvalidPassword)
+
+	resp = suite.httpClient.PUT("/api/v1/user/auth/password", map[string]string{
+		"currentPassword": "password123",
+		"newPassword":     "newPassword456",
+	}, authHeaders(currentToken))
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	// The session that changed the password stays signed in, others do not
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(currentToken))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(otherToken))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.signIn("test@example.com", "password123")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.signIn("test@example.com", "newPassword456")
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+func (suite *AccountTestSuite) TestChangePasswordRequiresStrongPassword() {
+	resp := suite.httpClient.PUT("/api/v1/user/auth/password", map[string]string{
+		"currentPassword": "password123",
+		"newPassword":     "short",
+	}, authHeaders(suite.accessToken()))
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *AccountTestSuite) TestChangeEmail() {
+	accessToken := suite.accessToken()
+	suite.GetMailer().Reset()
+
+	resp := suite.httpClient.PUT("/api/v1/user/auth/email", map[string]string{
+		"email":    "new@example.com",
+		"password": "password123",
+	}, authHeaders(accessToken))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Equal(suite.T(), "new@example.com", response["email"])
+	assert.Equal(suite.T(), false, response["emailVerified"])
+
+	messages := suite.GetMailer().Messages()
+	suite.Require().Len(messages, 2)
+	assert.Equal(suite.T(), "new@example.com", messages[0].To)
+	assert.Contains(suite.T(), messages[0].Body, "/verify-email?token=")
+	assert.Equal(suite.T(), "test@example.com", messages[1].To)
+	assert.Contains(suite.T(), messages[1].Body, "new@example.com")
+
+	match := mailTokenPattern.FindStringSubmatch(messages[0].Body)
+	suite.Require().Len(match, 2)
+	token, err := url.QueryUnescape(match[1])
+	suite.Require().NoError(err)
+
+	resp = suite.httpClient.POST("/api/v1/auth/email/verify", map[string]string{"token": token})
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	resp = suite.signIn("test@example.com", "password123")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.signIn("new@example.com", "password123")
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Equal(suite.T(), true, response["user"].(map[string]any)["emailVerified"])
+}
+
+func (suite *AccountTestSuite) TestChangeEmailRejectsTakenEmail() {
+	resp := suite.httpClient.POST("/api/v1/auth/signup", map[string]string{
+		"email":    "other@example.com",
+		"password": "password123",
+		"username": "otheruser",
+	})
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	accessToken := suite.accessToken()
+
+	resp = suite.httpClient.PUT("/api/v1/user/auth/email", map[string]string{
+		"email":    "other@example.com",
+		"password": "password123",
+	}, authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	suite.assertError(resp, shared.ErrEmailAlreadyExists)
+
+	resp = suite.httpClient.PUT("/api/v1/user/auth/email", map[string]string{
+		"email":    "new@example.com",
+		"password": "wrongPassword",
+	}, authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *AccountTestSuite) createWords(userID uuid.UUID) {
+	db := suite.GetDBClient()
+	ctx := suite.GetContext()
+
+	words, err := db.Folder.Create().
+		SetName("Words").
+		SetWordCount(0).
+		SetType("WORD_COLLECTION").
+		SetUserID(userID).
+		Save(ctx)
+	suite.Require().NoError(err)
+
+	for _, text := range []string{"one", "two"} {
+		_, err := db.Word.Create().
+			SetID(uuid.New()).
+			SetText(text).
+			SetDefinition("").
+			SetFolderID(words.ID).
+			Save(ctx)
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *AccountTestSuite) TestDeleteAccount() {
+	db := suite.GetDBClient()
+	ctx := suite.GetContext()
+
+	authUser := db.User.Query().Where(user.EmailEQ("test@example.com")).OnlyX(ctx)
+	suite.createWords(authUser.ID)
+	accessToken := suite.accessToken()
+
+	resp := suite.deleteAccount(accessToken, "wrongPassword")
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+
+	resp = suite.deleteAccount(accessToken, "password123")
+	suite.Require().Equal(http.StatusAccepted, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	purgeAt, err := time.Parse(time.RFC3339, response["purgeAt"].(string))
+	suite.Require().NoError(err)
+	assert.WithinDuration(suite.T(), time.Now().Add(30*24*time.Hour), purgeAt, time.Minute)
+
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	gracePeriod := 30 * 24 * time.Hour
+
+	// Nothing is purged during the grace period
+	purged, err := usermodule.PurgeDeletedUsers(ctx, db, gracePeriod)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, purged)
+
+	_, err = db.User.UpdateOneID(authUser.ID).
+		SetDeletedAt(time.Now().Add(-gracePeriod - time.Minute)).
+		Save(ctx)
+	suite.Require().NoError(err)
+
+	purged, err = usermodule.PurgeDeletedUsers(ctx, db, gracePeriod)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, purged)
+
+	assert.Equal(suite.T(), 0, db.User.Query().Where(user.ID(authUser.ID)).CountX(ctx))
+	assert.Equal(suite.T(), 0, db.Folder.Query().Where(folder.NameEQ("Words")).CountX(ctx))
+	assert.Equal(suite.T(), 0, db.Word.Query().Where(word.TextIn("one", "two")).CountX(ctx))
+
+	resp = suite.signIn("test@example.com", "password123")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *AccountTestSuite) TestSignInCancelsDeletion() {
+	db := suite.GetDBClient()
+	ctx := suite.GetContext()
+
+	resp := suite.deleteAccount(suite.accessToken(), "password123")
+	suite.Require().Equal(http.StatusAccepted, resp.StatusCode)
+
+	accessToken := suite.accessToken()
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	purged, err := usermodule.PurgeDeletedUsers(ctx, db, 0)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, purged)
+
+	authUser := db.User.Query().Where(user.EmailEQ("test@example.com")).OnlyX(ctx)
+	assert.Nil(suite.T(), authUser.DeletedAt)
+}
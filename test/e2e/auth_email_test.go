@@ -0,0 +1,221 @@
+package e2etest
+
+import (
+	"lexia/internal/mailer"
+	"lexia/internal/shared"
+	"lexia/test/helpers"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var mailTokenPattern = regexp.MustCompile(`token=([^\s]+)`)
+
+type AuthEmailTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+}
+
+func (suite *AuthEmailTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+
+	resp := suite.httpClient.POST("/api/v1/auth/signup", map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+		"username": "testuser",
+	})
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+func TestAuthEmailTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthEmailTestSuite))
+}
+
+func (suite *AuthEmailTestSuite) lastMail() mailer.Message {
+	messages := suite.GetMailer().Messages()
+	suite.Require().NotEmpty(messages)
+	return messages[len(messages)-1]
+}
+
+func (suite *AuthEmailTestSuite) tokenFromMail(message mailer.Message) string {
+	match := mailTokenPattern.FindStringSubmatch(message.Body)
+	suite.Require().Len(match, 2)
+
+	token, err := url.QueryUnescape(match[1])
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *AuthEmailTestSuite) getUser(accessToken string) map[string]any {
+	resp := suite.httpClient.GET("/api/v1/user/auth", authHeaders(accessToken))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	return response
+}
+
+func (suite *AuthEmailTestSuite) signIn(password string) *helpers.Response {
+	return suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+		"email":    "test@example.com",
+		"password": password,
+	})
+}
+
+func (suite *AuthEmailTestSuite) accessToken() string {
+	resp := suite.signIn("password123")
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	return response["accessToken"].(string)
+}
+
+func (suite *AuthEmailTestSuite) assertError(resp *helpers.Response, expected string) {
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Equal(suite.T(), expected, response["code"])
+}
+
+func (suite *AuthEmailTestSuite) TestSignUpSendsVerificationMail() {
+	message := suite.lastMail()
+	assert.Equal(suite.T(), "test@example.com", message.To)
+	assert.Contains(suite.T(), message.Body, "/verify-email?token=")
+
+	resp := suite.signIn("password123")
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Equal(suite.T(), false, response["user"].(map[string]any)["emailVerified"])
+}
+
+func (suite *AuthEmailTestSuite) TestVerifyEmail() {
+	token := suite.tokenFromMail(suite.lastMail())
+
+	resp := suite.httpClient.POST("/api/v1/auth/email/verify", map[string]string{"token": token})
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	accessToken := suite.accessToken()
+	assert.Equal(suite.T(), true, suite.getUser(accessToken)["emailVerified"])
+
+	// Tokens are single use
+	resp = suite.httpClient.POST("/api/v1/auth/email/verify", map[string]string{"token": token})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	suite.assertError(resp, shared.ErrInvalidOrExpiredToken)
+}
+
+func (suite *AuthEmailTestSuite) TestVerifyEmailInvalidToken() {
+	resp := suite.httpClient.POST("/api/v1/auth/email/verify", map[string]string{"token": "not-a-token"})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/auth/email/verify", map[string]string{})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *AuthEmailTestSuite) TestResendVerificationInvalidatesPreviousLink() {
+	firstToken := suite.tokenFromMail(suite.lastMail())
+
+	resp := suite.httpClient.POST("/api/v1/auth/email/verify/resend", map[string]string{"email": "test@example.com"})
+	assert.Equal(suite.T(), http.StatusAccepted, resp.StatusCode)
+	assert.Len(suite.T(), suite.GetMailer().Messages(), 2)
+	secondToken := suite.tokenFromMail(suite.lastMail())
+
+	resp = suite.httpClient.POST("/api/v1/auth/email/verify", map[string]string{"token": firstToken})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/auth/email/verify", map[string]string{"token": secondToken})
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	// Verified accounts get no further mails
+	resp = suite.httpClient.POST("/api/v1/auth/email/verify/resend", map[string]string{"email": "test@example.com"})
+	assert.Equal(suite.T(), http.StatusAccepted, resp.StatusCode)
+	assert.Len(suite.T(), suite.GetMailer().Messages(), 2)
+}
+
+func (suite *AuthEmailTestSuite) TestForgotPasswordUnknownEmail() {
+	suite.GetMailer().Reset()
+
+	resp := suite.httpClient.POST("/api/v1/auth/password/forgot", map[string]string{"email": "nobody@example.com"})
+	assert.Equal(suite.T(), http.StatusAccepted, resp.StatusCode)
+	assert.Empty(suite.T(), suite.GetMailer().Messages())
+}
+
+func (suite *AuthEmailTestSuite) TestResetPassword() {
+	accessToken := suite.accessToken()
+
+	resp := suite.httpClient.POST("/api/v1/auth/password/forgot", map[string]string{"email": "test@example.com"})
+	assert.Equal(suite.T(), http.StatusAccepted, resp.StatusCode)
+
+	message := suite.lastMail()
+	assert.Equal(suite.T(), "test@example.com", message.To)
+	assert.Contains(suite.T(), message.Body, "/reset-password?token=")
+	token := suite.tokenFromMail(message)
+
+	resp = suite.httpClient.POST("/api/v1/auth/password/reset", map[string]string{
+		"token":    token,
+		"password": "newPassword456",
+	})
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	// Existing sessions are signed out
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.signIn("password123")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.signIn("newPassword456")
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Equal(suite.T(), true, response["user"].(map[string]any)["emailVerified"])
+
+	resp = suite.httpClient.POST("/api/v1/auth/password/reset", map[string]string{
+		"token":    token,
+		"password": "anotherPassword789",
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	suite.assertError(resp, shared.ErrInvalidOrExpiredToken)
+}
+
+func (suite *AuthEmailTestSuite) TestResetPasswordExpiredToken() {
+	resp := suite.httpClient.POST("/api/v1/auth/password/forgot", map[string]string{"email": "test@example.com"})
+	assert.Equal(suite.T(), http.StatusAccepted, resp.StatusCode)
+	token := suite.tokenFromMail(suite.lastMail())
+
+	_, err := suite.GetDBClient().OneTimeToken.Update().
+		SetExpiresAt(time.Now().Add(-time.Minute)).
+		Save(suite.GetContext())
+	suite.Require().NoError(err)
+
+	resp = suite.httpClient.POST("/api/v1/auth/password/reset", map[string]string{
+		"token":    token,
+		"password": "newPassword456",
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp = suite.signIn("password123")
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+func (suite *AuthEmailTestSuite) TestResetPasswordRejectsVerificationToken() {
+	token := suite.tokenFromMail(suite.lastMail())
+
+	resp := suite.httpClient.POST("/api/v1/auth/password/reset", map[string]string{
+		"token":    token,
+		"password": "newPassword456",
+	})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	count, err := suite.GetDBClient().OneTimeToken.Query().Count(suite.GetContext())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, count)
+}
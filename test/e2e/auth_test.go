@@ -3,9 +3,11 @@ package e2etest
 import (
 	"lexia/test/helpers"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthTestSuite struct {
@@ -156,6 +158,41 @@ func (suite *AuthTestSuite) TestSignInInvalidCredentials() {
 	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
 }
 
+func (suite *AuthTestSuite) TestSignInUpgradesLegacyPasswordHash() {
+	resp := suite.httpClient.POST("/api/v1/auth/signup", map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+		"username": "testuser",
+	})
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	ctx := suite.GetContext()
+	db := suite.GetDBClient()
+
+	userEntity, err := db.User.Query().Only(ctx)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), strings.HasPrefix(userEntity.Password, "$argon2id$"))
+
+	legacyHash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.User.UpdateOneID(userEntity.ID).SetPassword(string(legacyHash)).Exec(ctx))
+
+	signInData := map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+	}
+
+	resp = suite.httpClient.POST("/api/v1/auth/signin", signInData)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	userEntity, err = db.User.Get(ctx, userEntity.ID)
+	suite.Require().NoError(err)
+	assert.True(suite.T(), strings.HasPrefix(userEntity.Password, "$argon2id$"))
+
+	resp = suite.httpClient.POST("/api/v1/auth/signin", signInData)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
 func (suite *AuthTestSuite) TestCORSHeaders() {
 	resp := suite.httpClient.Do(helpers.Request{
 		Method: "OPTIONS",
@@ -0,0 +1,370 @@
+package e2etest
+
+import (
+	"fmt"
+	"lexia/ent/user"
+	"lexia/test/helpers"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthzTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient   *helpers.HTTPClient
+	ownerHeaders map[string]string
+	otherHeaders map[string]string
+	ownerID      uuid.UUID
+	otherID      uuid.UUID
+}
+
+func (suite *AuthzTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+
+	suite.ownerID, suite.ownerHeaders = suite.signUp("owner@example.com", "owneruser")
+	suite.otherID, suite.otherHeaders = suite.signUp("other@example.com", "otheruser")
+}
+
+func TestAuthzTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthzTestSuite))
+}
+
+func (suite *AuthzTestSuite) signUp(email string, username string) (uuid.UUID, map[string]string) {
+	headers := helpers.SignUpUser(suite.T(), suite.httpClient, email, username)
+
+	userID := suite.GetDBClient().User.Query().
+		Where(user.EmailEQ(email)).
+		OnlyIDX(suite.GetContext())
+
+	return userID, headers
+}
+
+// ownerTree creates a folder collection holding a word collection with one
+// word, all owned by the owner.
+func (suite *AuthzTestSuite) ownerTree() (collectionID string, wordsID string, wordID string) {
+	collectionID = helpers.CreateFolder(suite.T(), suite.httpClient, suite.ownerHeaders, map[string]any{
+		"name": "Collection",
+		"type": "FOLDER_COLLECTION",
+	})
+
+	wordsID = helpers.CreateFolder(suite.T(), suite.httpClient, suite.ownerHeaders, map[string]any{
+		"name":         "Words",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+		"languageTo":   "GEORGIAN",
+		"parentId":     collectionID,
+	})
+
+	wordID = helpers.CreateWord(suite.T(), suite.httpClient, suite.ownerHeaders, map[string]any{
+		"text":       "private",
+		"definition": "owner only",
+		"folderId":   wordsID,
+	})["id"].(string)
+
+	return collectionID, wordsID, wordID
+}
+
+func (suite *AuthzTestSuite) share(folderID string, email string, role string) {
+	resp := suite.httpClient.PUT(fmt.Sprintf("/api/v1/folders/%s/shares", folderID), map[string]any{
+		"email": email,
+		"role":  role,
+	}, suite.ownerHeaders)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+}
+
+// folderAndWordRoutes lists every folder, word and review route that takes a
+// folder or word ID, with a valid body so only the access check can fail.
+func folderAndWordRoutes(collectionID string, wordsID string, wordID string, ownerID string) []helpers.Request {
+	return []helpers.Request{
+		{Method: http.MethodGet, Path: "/api/v1/folders/" + collectionID},
+		{Method: http.MethodGet, Path: "/api/v1/folders/" + collectionID + "/subfolders"},
+		{Method: http.MethodGet, Path: "/api/v1/folders/" + collectionID + "/shares"},
+		{Method: http.MethodPost, Path: "/api/v1/folders", Body: map[string]any{
+			"name": "Intruder", "type": "FOLDER_COLLECTION", "parentId": collectionID,
+		}},
+		{Method: http.MethodPut, Path: "/api/v1/folders/" + wordsID, Body: map[string]any{"name": "Renamed"}},
+		{Method: http.MethodPut, Path: "/api/v1/folders/" + wordsID + "/move", Body: map[string]any{"parentId": nil}},
+		{Method: http.MethodPut, Path: "/api/v1/folders/" + collectionID + "/shares", Body: map[string]any{
+			"email": "other@example.com", "role": "EDITOR",
+		}},
+		{Method: http.MethodDelete, Path: "/api/v1/folders/" + collectionID + "/shares/" + ownerID},
+		{Method: http.MethodDelete, Path: "/api/v1/folders/" + wordsID},
+		{Method: http.MethodGet, Path: "/api/v1/folders/" + wordsID + "/words"},
+		{Method: http.MethodPost, Path: "/api/v1/folders/" + wordsID + "/words/from-translation", Body: map[string]any{"text": "hello"}},
+		{Method: http.MethodPost, Path: "/api/v1/folders/" + wordsID + "/words/autofill"},
+		{Method: http.MethodGet, Path: "/api/v1/folders/" + wordsID + "/words/autofill/" + uuid.NewString()},
+		{Method: http.MethodPost, Path: "/api/v1/words", Body: map[string]any{
+			"text": "intruder", "definition": "", "folderId": wordsID,
+		}},
+		{Method: http.MethodGet, Path: "/api/v1/words/" + wordID},
+		{Method: http.MethodPut, Path: "/api/v1/words/" + wordID, Body: map[string]any{"text": "hacked"}},
+		{Method: http.MethodDelete, Path: "/api/v1/words/" + wordID},
+		{Method: http.MethodGet, Path: "/api/v1/reviews/due?folderId=" + wordsID},
+		{Method: http.MethodPost, Path: "/api/v1/reviews/" + wordID, Body: map[string]any{"grade": 3}},
+	}
+}
+
+func (suite *AuthzTestSuite) TestCrossTenantAccessIsForbidden() {
+	collectionID, wordsID, wordID := suite.ownerTree()
+
+	for _, route := range folderAndWordRoutes(collectionID, wordsID, wordID, suite.ownerID.String()) {
+		route.Headers = suite.otherHeaders
+		resp := suite.httpClient.Do(route)
+		assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode, "%s %s", route.Method, route.Path)
+	}
+
+	// Listings only show the caller's own folders and words
+	for _, path := range []string{
+		"/api/v1/folders",
+		"/api/v1/folders/root",
+	} {
+		resp := suite.httpClient.GET(path, suite.otherHeaders)
+		suite.Require().Equal(http.StatusOK, resp.StatusCode, path)
+
+		var page pageResponse
+		suite.Require().NoError(resp.ParseJSON(&page))
+		assert.Empty(suite.T(), page.Items, path)
+	}
+	for _, path := range []string{
+		"/api/v1/folders/tree",
+		"/api/v1/folders/shared",
+	} {
+		resp := suite.httpClient.GET(path, suite.otherHeaders)
+		suite.Require().Equal(http.StatusOK, resp.StatusCode, path)
+
+		var folders []map[string]any
+		suite.Require().NoError(resp.ParseJSON(&folders))
+		assert.Empty(suite.T(), folders, path)
+	}
+
+	resp := suite.httpClient.GET("/api/v1/words/check-duplicate?text=private", suite.otherHeaders)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var duplicate map[string]any
+	suite.Require().NoError(resp.ParseJSON(&duplicate))
+	assert.Equal(suite.T(), false, duplicate["isDuplicate"])
+
+	// Nothing the other user tried changed the owner's data
+	resp = suite.httpClient.GET("/api/v1/words/"+wordID, suite.ownerHeaders)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var word map[string]any
+	suite.Require().NoError(resp.ParseJSON(&word))
+	assert.Equal(suite.T(), "private", word["text"])
+
+	resp = suite.httpClient.GET("/api/v1/folders/"+wordsID, suite.ownerHeaders)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var folder map[string]any
+	suite.Require().NoError(resp.ParseJSON(&folder))
+	assert.Equal(suite.T(), "Words", folder["name"])
+	assert.Equal(suite.T(), collectionID, folder["parentId"])
+	assert.Equal(suite.T(), float64(1), folder["wordCount"])
+}
+
+func (suite *AuthzTestSuite) TestMissingFoldersAndWordsAreNotFound() {
+	routes := folderAndWordRoutes(uuid.NewString(), uuid.NewString(), uuid.NewString(), suite.otherID.String())
+
+	for _, route := range routes {
+		route.Headers = suite.ownerHeaders
+		resp := suite.httpClient.Do(route)
+		assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode, "%s %s", route.Method, route.Path)
+	}
+}
+
+func (suite *AuthzTestSuite) TestViewerCanOnlyRead() {
+	collectionID, wordsID, wordID := suite.ownerTree()
+	suite.share(collectionID, "other@example.com", "VIEWER")
+
+	// The share on the collection covers the word collection inside it
+	for _, path := range []string{
+		"/api/v1/folders/" + collectionID,
+		"/api/v1/folders/" + collectionID + "/subfolders",
+		"/api/v1/folders/" + wordsID,
+		"/api/v1/folders/" + wordsID + "/words",
+		"/api/v1/words/" + wordID,
+	} {
+		resp := suite.httpClient.GET(path, suite.otherHeaders)
+		assert.Equal(suite.T(), http.StatusOK, resp.StatusCode, path)
+	}
+
+	resp := suite.httpClient.GET("/api/v1/folders/shared", suite.otherHeaders)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var folders []map[string]any
+	suite.Require().NoError(resp.ParseJSON(&folders))
+	suite.Require().Len(folders, 1)
+	assert.Equal(suite.T(), collectionID, folders[0]["id"])
+
+	resp = suite.httpClient.PUT("/api/v1/words/"+wordID, map[string]any{"text": "changed"}, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/words", map[string]any{
+		"text":       "new",
+		"definition": "",
+		"folderId":   wordsID,
+	}, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+
+	resp = suite.httpClient.PUT("/api/v1/folders/"+wordsID, map[string]any{"name": "Renamed"}, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *AuthzTestSuite) TestEditorCanChangeWordsButNotStructure() {
+	collectionID, wordsID, wordID := suite.ownerTree()
+	suite.share(wordsID, "other@example.com", "EDITOR")
+
+	resp := suite.httpClient.PUT("/api/v1/words/"+wordID, map[string]any{"text": "edited"}, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	newWordID := helpers.CreateWord(suite.T(), suite.httpClient, suite.otherHeaders, map[string]any{
+		"text":       "added",
+		"definition": "by the editor",
+		"folderId":   wordsID,
+	})["id"].(string)
+
+	resp = suite.httpClient.DELETE("/api/v1/words/"+newWordID, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	resp = suite.httpClient.PUT("/api/v1/folders/"+wordsID, map[string]any{"name": "Renamed"}, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	// The share does not reach up to the parent collection
+	resp = suite.httpClient.GET("/api/v1/folders/"+collectionID, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+
+	resp = suite.httpClient.PUT("/api/v1/folders/"+wordsID+"/move", map[string]any{"parentId": nil}, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+
+	resp = suite.httpClient.DELETE("/api/v1/folders/"+wordsID, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+
+	resp = suite.httpClient.PUT("/api/v1/folders/"+wordsID+"/shares", map[string]any{
+		"email": "owner@example.com",
+		"role":  "VIEWER",
+	}, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *AuthzTestSuite) TestShareManagement() {
+	collectionID, _, _ := suite.ownerTree()
+	sharesPath := fmt.Sprintf("/api/v1/folders/%s/shares", collectionID)
+
+	resp := suite.httpClient.PUT(sharesPath, map[string]any{
+		"email": "owner@example.com",
+		"role":  "VIEWER",
+	}, suite.ownerHeaders)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp = suite.httpClient.PUT(sharesPath, map[string]any{
+		"email": "nobody@example.com",
+		"role":  "VIEWER",
+	}, suite.ownerHeaders)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	resp = suite.httpClient.PUT(sharesPath, map[string]any{
+		"email": "other@example.com",
+		"role":  "OWNER",
+	}, suite.ownerHeaders)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	// Sharing again changes the role instead of adding a second share
+	suite.share(collectionID, "other@example.com", "VIEWER")
+	suite.share(collectionID, "other@example.com", "EDITOR")
+
+	resp = suite.httpClient.GET(sharesPath, suite.ownerHeaders)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var shares []map[string]any
+	suite.Require().NoError(resp.ParseJSON(&shares))
+	suite.Require().Len(shares, 1)
+	assert.Equal(suite.T(), suite.otherID.String(), shares[0]["userId"])
+	assert.Equal(suite.T(), "otheruser", shares[0]["username"])
+	assert.Equal(suite.T(), "EDITOR", shares[0]["role"])
+
+	// Shared users can leave a share themselves
+	resp = suite.httpClient.DELETE(sharesPath+"/"+suite.otherID.String(), suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/folders/"+collectionID, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+
+	resp = suite.httpClient.DELETE(sharesPath+"/"+suite.otherID.String(), suite.ownerHeaders)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *AuthzTestSuite) TestAdminCanAccessEveryFolder() {
+	collectionID, wordsID, wordID := suite.ownerTree()
+
+	_, err := suite.GetDBClient().User.UpdateOneID(suite.otherID).
+		SetIsAdmin(true).
+		Save(suite.GetContext())
+	suite.Require().NoError(err)
+
+	resp := suite.httpClient.GET("/api/v1/folders/"+collectionID, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/folders/"+collectionID+"/shares", suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	// Subfolders created by an admin belong to the owner of the tree
+	subfolderID := helpers.CreateFolder(suite.T(), suite.httpClient, suite.otherHeaders, map[string]any{
+		"name":     "Added by admin",
+		"type":     "FOLDER_COLLECTION",
+		"parentId": collectionID,
+	})
+
+	resp = suite.httpClient.GET("/api/v1/folders/"+subfolderID, suite.ownerHeaders)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	resp = suite.httpClient.DELETE("/api/v1/words/"+wordID, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	resp = suite.httpClient.DELETE("/api/v1/folders/"+wordsID, suite.otherHeaders)
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+}
+
+func (suite *AuthzTestSuite) dueWords(folderID string, headers map[string]string) []map[string]any {
+	path := "/api/v1/reviews/due"
+	if folderID != "" {
+		path += "?folderId=" + folderID
+	}
+	resp := suite.httpClient.GET(path, headers)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode, path)
+
+	var dueWords []map[string]any
+	suite.Require().NoError(resp.ParseJSON(&dueWords))
+	return dueWords
+}
+
+func (suite *AuthzTestSuite) TestSharedWordsAreReviewedPerUser() {
+	_, wordsID, wordID := suite.ownerTree()
+	suite.share(wordsID, "other@example.com", "VIEWER")
+
+	suite.Require().Len(suite.dueWords(wordsID, suite.otherHeaders), 1)
+
+	resp := suite.httpClient.POST("/api/v1/reviews/"+wordID, map[string]any{"grade": 5}, suite.otherHeaders)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	// The viewer's review is theirs alone
+	assert.Empty(suite.T(), suite.dueWords(wordsID, suite.otherHeaders))
+
+	ownerDue := suite.dueWords("", suite.ownerHeaders)
+	suite.Require().Len(ownerDue, 1)
+	assert.Nil(suite.T(), ownerDue[0]["review"])
+
+	// The owner's first review starts from scratch
+	resp = suite.httpClient.POST("/api/v1/reviews/"+wordID, map[string]any{"grade": 5}, suite.ownerHeaders)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var review map[string]any
+	suite.Require().NoError(resp.ParseJSON(&review))
+	assert.Equal(suite.T(), float64(1), review["repetitions"])
+	assert.Equal(suite.T(), float64(1), review["interval"])
+
+	assert.Empty(suite.T(), suite.dueWords(wordsID, suite.otherHeaders))
+}
@@ -0,0 +1,117 @@
+package e2etest
+
+import (
+	"fmt"
+	"lexia/test/helpers"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type ErrorResponseTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+	headers    map[string]string
+}
+
+func (suite *ErrorResponseTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+
+	resp := suite.httpClient.POST("/api/v1/auth/signup", map[string]string{
+		"email":    "errors@example.com",
+		"password": "password123",
+		"username": "errorsuser",
+	})
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+		"email":    "errors@example.com",
+		"password": "password123",
+	})
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	suite.headers = authHeaders(response["accessToken"].(string))
+}
+
+func TestErrorResponseSuite(t *testing.T) {
+	helpers.RunE2ETestSuite(t, new(ErrorResponseTestSuite))
+}
+
+func (suite *ErrorResponseTestSuite) parse(resp *helpers.Response) map[string]any {
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	return response
+}
+
+func (suite *ErrorResponseTestSuite) TestNotFoundHasCodeAndRequestID() {
+	resp := suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s", uuid.New()), suite.headers)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	response := suite.parse(resp)
+	assert.Equal(suite.T(), "Folder not found", response["error"])
+	assert.Equal(suite.T(), "FOLDER_NOT_FOUND", response["code"])
+	assert.NotEmpty(suite.T(), response["requestId"])
+	assert.Equal(suite.T(), resp.Headers.Get("X-Request-ID"), response["requestId"])
+}
+
+func (suite *ErrorResponseTestSuite) TestRequestIDIsTakenFromRequest() {
+	headers := map[string]string{"X-Request-ID": "client-trace-123"}
+	for key, value := range suite.headers {
+		headers[key] = value
+	}
+
+	resp := suite.httpClient.GET(fmt.Sprintf("/api/v1/words/%s", uuid.New()), headers)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+	assert.Equal(suite.T(), "client-trace-123", resp.Headers.Get("X-Request-ID"))
+
+	response := suite.parse(resp)
+	assert.Equal(suite.T(), "WORD_NOT_FOUND", response["code"])
+	assert.Equal(suite.T(), "client-trace-123", response["requestId"])
+}
+
+func (suite *ErrorResponseTestSuite) TestValidationErrorsHaveDetails() {
+	resp := suite.httpClient.POST("/api/v1/folders", map[string]any{}, suite.headers)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	response := suite.parse(resp)
+	assert.Equal(suite.T(), "VALIDATION_FAILED", response["code"])
+	assert.NotEmpty(suite.T(), response["details"])
+}
+
+func (suite *ErrorResponseTestSuite) TestInvalidStateHasCode() {
+	resp := suite.httpClient.POST("/api/v1/folders", map[string]any{
+		"name": "Collection",
+		"type": "FOLDER_COLLECTION",
+	}, suite.headers)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	parentID := suite.parse(resp)["id"].(string)
+
+	resp = suite.httpClient.POST("/api/v1/folders", map[string]any{
+		"name":         "Words",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+		"parentId":     parentID,
+	}, suite.headers)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp = suite.httpClient.DELETE(fmt.Sprintf("/api/v1/folders/%s", parentID), suite.headers)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	response := suite.parse(resp)
+	assert.Equal(suite.T(), "FOLDER_HAS_SUBFOLDERS", response["code"])
+	assert.Equal(suite.T(), "Cannot delete folder that contains subfolders", response["error"])
+}
+
+func (suite *ErrorResponseTestSuite) TestUnknownRouteUsesEnvelope() {
+	resp := suite.httpClient.GET("/api/v1/does-not-exist")
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	response := suite.parse(resp)
+	assert.Equal(suite.T(), "NOT_FOUND", response["code"])
+	assert.NotEmpty(suite.T(), response["requestId"])
+}
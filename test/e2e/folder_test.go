@@ -217,11 +217,11 @@ func (suite *FolderTestSuite) TestGetUserFolders() {
 	resp := suite.httpClient.GET("/api/v1/folders", suite.getAuthHeaders())
 	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
 
-	var response []map[string]interface{}
+	var response pageResponse
 	err := resp.ParseJSON(&response)
 	assert.NoError(suite.T(), err)
 
-	assert.Len(suite.T(), response, 2)
+	assert.Len(suite.T(), response.Items, 2)
 }
 
 func (suite *FolderTestSuite) TestGetRootFolders() {
@@ -246,14 +246,14 @@ func (suite *FolderTestSuite) TestGetRootFolders() {
 	resp := suite.httpClient.GET("/api/v1/folders/root", suite.getAuthHeaders())
 	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
 
-	var response []map[string]interface{}
+	var response pageResponse
 	err := resp.ParseJSON(&response)
 	assert.NoError(suite.T(), err)
 
-	assert.Len(suite.T(), response, 1)
-	assert.Equal(suite.T(), "Root Folder", response[0]["name"])
+	assert.Len(suite.T(), response.Items, 1)
+	assert.Equal(suite.T(), "Root Folder", response.Items[0]["name"])
 
-	subfolders := response[0]["subfolders"].([]interface{})
+	subfolders := response.Items[0]["subfolders"].([]interface{})
 	assert.Len(suite.T(), subfolders, 1)
 	assert.Equal(suite.T(), "Child Folder", subfolders[0].(map[string]interface{})["name"])
 }
@@ -454,7 +454,7 @@ func (suite *FolderTestSuite) TestDeleteFolderWithSubfolders() {
 func (suite *FolderTestSuite) TestDeleteNonExistentFolder() {
 	nonExistentID := uuid.New().String()
 	resp := suite.httpClient.DELETE(fmt.Sprintf("/api/v1/folders/%s", nonExistentID), suite.getAuthHeaders())
-	assert.Equal(suite.T(), http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
 }
 
 func (suite *FolderTestSuite) TestCircularReferencePreventionInMove() {
@@ -495,7 +495,11 @@ func (suite *FolderTestSuite) TestCircularReferencePreventionInMove() {
 	}
 
 	moveResp := suite.httpClient.PUT(fmt.Sprintf("/api/v1/folders/%s/move", grandparentID), moveData, suite.getAuthHeaders())
-	assert.Equal(suite.T(), http.StatusInternalServerError, moveResp.StatusCode)
+	assert.Equal(suite.T(), http.StatusBadRequest, moveResp.StatusCode)
+
+	var moveResponse map[string]interface{}
+	moveResp.ParseJSON(&moveResponse)
+	assert.Equal(suite.T(), "CIRCULAR_REFERENCE", moveResponse["code"])
 }
 
 func (suite *FolderTestSuite) TestUnauthorizedAccess() {
@@ -570,13 +574,13 @@ func (suite *FolderTestSuite) TestCreateFolderHierarchy() {
 	assert.Equal(suite.T(), georgianID, basicResponse["parentId"])
 
 	rootResp := suite.httpClient.GET("/api/v1/folders/root", suite.getAuthHeaders())
-	var rootResponse []map[string]interface{}
+	var rootResponse pageResponse
 	rootResp.ParseJSON(&rootResponse)
 
-	assert.Len(suite.T(), rootResponse, 1)
-	assert.Equal(suite.T(), "Languages", rootResponse[0]["name"])
+	assert.Len(suite.T(), rootResponse.Items, 1)
+	assert.Equal(suite.T(), "Languages", rootResponse.Items[0]["name"])
 
-	subfolders := rootResponse[0]["subfolders"].([]interface{})
+	subfolders := rootResponse.Items[0]["subfolders"].([]interface{})
 	assert.Len(suite.T(), subfolders, 1)
 
 	georgianFolder := subfolders[0].(map[string]interface{})
@@ -589,3 +593,195 @@ func (suite *FolderTestSuite) TestCreateFolderHierarchy() {
 	assert.Equal(suite.T(), "Basic Words", basicWordsFolder["name"])
 	assert.Equal(suite.T(), "WORD_COLLECTION", basicWordsFolder["type"])
 }
+
+func (suite *FolderTestSuite) createFolder(data map[string]interface{}) string {
+	return helpers.CreateFolder(suite.T(), suite.httpClient, suite.getAuthHeaders(), data)
+}
+
+func (suite *FolderTestSuite) createWord(folderID string, text string) string {
+	word := helpers.CreateWord(suite.T(), suite.httpClient, suite.getAuthHeaders(), map[string]interface{}{
+		"text":     text,
+		"folderId": folderID,
+	})
+
+	return word["id"].(string)
+}
+
+func (suite *FolderTestSuite) getFolderWordCount(folderID string) float64 {
+	resp := suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s", folderID), suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	return response["wordCount"].(float64)
+}
+
+func (suite *FolderTestSuite) TestWordCountTracksCreateAndDelete() {
+	folderID := suite.createFolder(map[string]interface{}{
+		"name":         "Vocabulary",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+	})
+
+	firstWordID := suite.createWord(folderID, "hello")
+	suite.createWord(folderID, "world")
+	assert.Equal(suite.T(), float64(2), suite.getFolderWordCount(folderID))
+
+	resp := suite.httpClient.DELETE(fmt.Sprintf("/api/v1/words/%s", firstWordID), suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+	assert.Equal(suite.T(), float64(1), suite.getFolderWordCount(folderID))
+}
+
+func (suite *FolderTestSuite) TestWordCountIncludesSubtreeForCollections() {
+	rootID := suite.createFolder(map[string]interface{}{
+		"name": "Languages",
+		"type": "FOLDER_COLLECTION",
+	})
+	nestedID := suite.createFolder(map[string]interface{}{
+		"name":     "Georgian",
+		"type":     "FOLDER_COLLECTION",
+		"parentId": rootID,
+	})
+	wordsID := suite.createFolder(map[string]interface{}{
+		"name":         "Basic Words",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "GEORGIAN",
+		"parentId":     nestedID,
+	})
+
+	suite.createWord(wordsID, "gamarjoba")
+	suite.createWord(wordsID, "madloba")
+
+	assert.Equal(suite.T(), float64(2), suite.getFolderWordCount(wordsID))
+	assert.Equal(suite.T(), float64(2), suite.getFolderWordCount(nestedID))
+	assert.Equal(suite.T(), float64(2), suite.getFolderWordCount(rootID))
+}
+
+func (suite *FolderTestSuite) TestWordCountFollowsMovedFolder() {
+	sourceID := suite.createFolder(map[string]interface{}{
+		"name": "Source",
+		"type": "FOLDER_COLLECTION",
+	})
+	targetID := suite.createFolder(map[string]interface{}{
+		"name": "Target",
+		"type": "FOLDER_COLLECTION",
+	})
+	wordsID := suite.createFolder(map[string]interface{}{
+		"name":         "Words",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+		"parentId":     sourceID,
+	})
+
+	suite.createWord(wordsID, "hello")
+	assert.Equal(suite.T(), float64(1), suite.getFolderWordCount(sourceID))
+
+	resp := suite.httpClient.PUT(fmt.Sprintf("/api/v1/folders/%s/move", wordsID), map[string]interface{}{
+		"parentId": targetID,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	assert.Equal(suite.T(), float64(0), suite.getFolderWordCount(sourceID))
+	assert.Equal(suite.T(), float64(1), suite.getFolderWordCount(targetID))
+	assert.Equal(suite.T(), float64(1), suite.getFolderWordCount(wordsID))
+}
+
+func (suite *FolderTestSuite) TestGetFolderTree() {
+	parentID := ""
+	folderIDs := make([]string, 0, 4)
+	for i := 0; i < 4; i++ {
+		data := map[string]interface{}{
+			"name": fmt.Sprintf("Level %d", i),
+			"type": "FOLDER_COLLECTION",
+		}
+		if parentID != "" {
+			data["parentId"] = parentID
+		}
+		parentID = suite.createFolder(data)
+		folderIDs = append(folderIDs, parentID)
+	}
+
+	wordsID := suite.createFolder(map[string]interface{}{
+		"name":         "Deep Words",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+		"parentId":     parentID,
+	})
+	suite.createWord(wordsID, "deep")
+	suite.createWord(wordsID, "deeper")
+
+	suite.createFolder(map[string]interface{}{
+		"name": "Empty Root",
+		"type": "FOLDER_COLLECTION",
+	})
+
+	resp := suite.httpClient.GET("/api/v1/folders/tree", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response []map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), response, 2)
+
+	node := response[0]
+	for depth, folderID := range folderIDs {
+		assert.Equal(suite.T(), folderID, node["id"])
+		assert.Equal(suite.T(), float64(depth), node["depth"])
+		// Words deep in the subtree are counted once at every level
+		assert.Equal(suite.T(), float64(2), node["wordCount"])
+		assert.Equal(suite.T(), float64(2), node["totalWordCount"])
+
+		children := node["children"].([]interface{})
+		assert.Len(suite.T(), children, 1)
+		node = children[0].(map[string]interface{})
+	}
+
+	assert.Equal(suite.T(), wordsID, node["id"])
+	assert.Equal(suite.T(), float64(2), node["wordCount"])
+	assert.Equal(suite.T(), float64(2), node["totalWordCount"])
+	assert.Empty(suite.T(), node["children"])
+
+	emptyRoot := response[1]
+	assert.Equal(suite.T(), "Empty Root", emptyRoot["name"])
+	assert.Equal(suite.T(), float64(0), emptyRoot["totalWordCount"])
+}
+
+func (suite *FolderTestSuite) TestCreateWordCollectionWithCatalogueLanguages() {
+	resp := suite.httpClient.POST("/api/v1/folders", map[string]interface{}{
+		"name":         "Italian Vocabulary",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "it",
+		"languageTo":   "ENGLISH",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), "it", response["languageFrom"])
+	assert.Equal(suite.T(), "ENGLISH", response["languageTo"])
+
+	// Tags of languages that had a legacy code are reported with that code
+	resp = suite.httpClient.POST("/api/v1/folders", map[string]interface{}{
+		"name":         "Georgian Vocabulary",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ka",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	err = resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "GEORGIAN", response["languageFrom"])
+}
+
+func (suite *FolderTestSuite) TestCreateWordCollectionWithUnknownLanguage() {
+	resp := suite.httpClient.POST("/api/v1/folders", map[string]interface{}{
+		"name":         "Unknown Language",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "KLINGON",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
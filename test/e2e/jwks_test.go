@@ -0,0 +1,81 @@
+package e2etest
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"lexia/internal/shared"
+	"lexia/test/helpers"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type JWKSTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+}
+
+func (suite *JWKSTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+}
+
+func TestJWKSTestSuite(t *testing.T) {
+	suite.Run(t, new(JWKSTestSuite))
+}
+
+func (suite *JWKSTestSuite) TestAccessTokenVerifiesAgainstJWKS() {
+	authToken := helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+	accessToken := strings.TrimPrefix(authToken, "Bearer ")
+
+	resp := suite.httpClient.GET("/.well-known/jwks.json")
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	assert.Contains(suite.T(), resp.Headers.Get("Cache-Control"), "max-age")
+
+	var jwks shared.JSONWebKeySet
+	suite.Require().NoError(resp.ParseJSON(&jwks))
+	suite.Require().Len(jwks.Keys, 1)
+
+	key := jwks.Keys[0]
+	assert.Equal(suite.T(), helpers.TestSigningKeyID, key.Kid)
+	assert.Equal(suite.T(), "OKP", key.Kty)
+	assert.Equal(suite.T(), "EdDSA", key.Alg)
+
+	publicKey, err := base64.RawURLEncoding.DecodeString(key.X)
+	suite.Require().NoError(err)
+
+	var claims jwt.RegisteredClaims
+	token, err := jwt.ParseWithClaims(accessToken, &claims, func(token *jwt.Token) (any, error) {
+		return ed25519.PublicKey(publicKey), nil
+	}, jwt.WithValidMethods([]string{"EdDSA"}), jwt.WithIssuer(shared.DefaultJwtIssuer))
+	suite.Require().NoError(err)
+
+	assert.Equal(suite.T(), helpers.TestSigningKeyID, token.Header["kid"])
+	assert.NotEmpty(suite.T(), claims.Subject)
+	assert.NotEmpty(suite.T(), claims.ID)
+	assert.NotNil(suite.T(), claims.ExpiresAt)
+}
+
+func (suite *JWKSTestSuite) TestForeignTokenIsRejected() {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	suite.Require().NoError(err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
+		"iss":   shared.DefaultJwtIssuer,
+		"sub":   "00000000-0000-0000-0000-000000000000",
+		"sid":   "00000000-0000-0000-0000-000000000000",
+		"email": "test@example.com",
+		"exp":   9999999999,
+	})
+	token.Header["kid"] = helpers.TestSigningKeyID
+
+	forged, err := token.SignedString(privateKey)
+	suite.Require().NoError(err)
+
+	resp := suite.httpClient.GET("/api/v1/user/auth", authHeaders(forged))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,275 @@
+package e2etest
+
+import (
+	"lexia/ent/user"
+	"lexia/internal/shared"
+	"lexia/test/helpers"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	envOidcGenericClientIDs = "OIDC_GENERIC_CLIENT_IDS"
+	envOidcGenericIssuer    = "OIDC_GENERIC_ISSUER"
+	envOidcGenericJwksUrl   = "OIDC_GENERIC_JWKS_URL"
+)
+
+type OIDCTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+	issuer     *helpers.MockOIDCIssuer
+}
+
+// SetupSuite configures the provider before the server reads its settings.
+func (suite *OIDCTestSuite) SetupSuite() {
+	suite.issuer = helpers.NewMockOIDCIssuer(suite.T())
+	os.Setenv(envOidcGenericClientIDs, helpers.MockOIDCClientID)
+	os.Setenv(envOidcGenericIssuer, suite.issuer.Issuer())
+	os.Setenv(envOidcGenericJwksUrl, suite.issuer.JwksURL())
+
+	suite.E2ETestSuite.SetupSuite()
+}
+
+func (suite *OIDCTestSuite) TearDownSuite() {
+	os.Unsetenv(envOidcGenericClientIDs)
+	os.Unsetenv(envOidcGenericIssuer)
+	os.Unsetenv(envOidcGenericJwksUrl)
+	suite.issuer.Close()
+
+	suite.E2ETestSuite.TearDownSuite()
+}
+
+func (suite *OIDCTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+}
+
+func TestOIDCTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCTestSuite))
+}
+
+func (suite *OIDCTestSuite) idToken(subject string, email string, emailVerified bool) string {
+	return suite.issuer.IDToken(suite.T(), subject, map[string]any{
+		"email":          email,
+		"email_verified": emailVerified,
+		"name":           "Jane Doe",
+	})
+}
+
+func (suite *OIDCTestSuite) oidcSignIn(idToken string) *helpers.Response {
+	return suite.httpClient.POST("/api/v1/auth/oidc/generic", map[string]string{
+		"idToken": idToken,
+	})
+}
+
+func (suite *OIDCTestSuite) parseTokenPayload(resp *helpers.Response) (string, map[string]any) {
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	return response["accessToken"].(string), response["user"].(map[string]any)
+}
+
+func (suite *OIDCTestSuite) getIdentities(accessToken string) []any {
+	resp := suite.httpClient.GET("/api/v1/user/identities", authHeaders(accessToken))
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var identities []any
+	suite.Require().NoError(resp.ParseJSON(&identities))
+	return identities
+}
+
+func (suite *OIDCTestSuite) TestSignInCreatesUser() {
+	_, firstUser := suite.parseTokenPayload(suite.oidcSignIn(suite.idToken("subject-1", "Jane@Example.com", true)))
+	assert.Equal(suite.T(), "jane@example.com", firstUser["email"])
+	assert.Equal(suite.T(), "JaneDoe", firstUser["username"])
+	assert.Equal(suite.T(), true, firstUser["emailVerified"])
+
+	accessToken, secondUser := suite.parseTokenPayload(suite.oidcSignIn(suite.idToken("subject-1", "jane@example.com", true)))
+	assert.Equal(suite.T(), firstUser["id"], secondUser["id"])
+
+	identities := suite.getIdentities(accessToken)
+	suite.Require().Len(identities, 1)
+	assert.Equal(suite.T(), "generic", identities[0].(map[string]any)["provider"])
+
+	resp := suite.httpClient.GET("/api/v1/user/auth", authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	// Users created through a provider have no password to sign in with
+	resp = suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+		"email":    "jane@example.com",
+		"password": "password123",
+	})
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *OIDCTestSuite) TestSignInLinksVerifiedEmail() {
+	helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+	_, err := suite.GetDBClient().User.Update().
+		Where(user.EmailEQ("test@example.com")).
+		SetEmailVerified(true).
+		Save(suite.GetContext())
+	suite.Require().NoError(err)
+
+	accessToken, user := suite.parseTokenPayload(suite.oidcSignIn(suite.idToken("subject-1", "test@example.com", true)))
+	assert.Equal(suite.T(), "testuser", user["username"])
+	assert.Equal(suite.T(), true, user["emailVerified"])
+	assert.Len(suite.T(), suite.getIdentities(accessToken), 1)
+
+	resp := suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+	})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+func (suite *OIDCTestSuite) TestSignInRejectsUnverifiedEmailOfExistingUser() {
+	helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+
+	resp := suite.oidcSignIn(suite.idToken("subject-1", "test@example.com", false))
+	assert.Equal(suite.T(), http.StatusConflict, resp.StatusCode)
+
+	count, err := suite.GetDBClient().Identity.Query().Count(suite.GetContext())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, count)
+}
+
+// Someone who signs up with another person's email must not end up sharing
+// the account once its owner signs in through a provider.
+func (suite *OIDCTestSuite) TestSignInRejectsUnverifiedExistingAccount() {
+	resp := suite.httpClient.POST("/api/v1/auth/signup", map[string]string{
+		"email":    "victim@example.com",
+		"password": "attacker123",
+		"username": "attacker",
+	})
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	resp = suite.oidcSignIn(suite.idToken("victim-subject", "victim@example.com", true))
+	assert.Equal(suite.T(), http.StatusConflict, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Equal(suite.T(), "EMAIL_NOT_VERIFIED", response["code"])
+
+	count, err := suite.GetDBClient().Identity.Query().Count(suite.GetContext())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, count)
+
+	victim, err := suite.GetDBClient().User.Query().
+		Where(user.EmailEQ("victim@example.com")).
+		Only(suite.GetContext())
+	suite.Require().NoError(err)
+	assert.False(suite.T(), victim.EmailVerified)
+}
+
+func (suite *OIDCTestSuite) TestSignInRejectsInvalidTokens() {
+	testCases := []struct {
+		name   string
+		claims map[string]any
+	}{
+		{"wrong audience", map[string]any{"aud": "someone-else"}},
+		{"wrong issuer", map[string]any{"iss": "https://evil.example.com"}},
+		{"expired", map[string]any{"exp": time.Now().Add(-time.Minute).Unix()}},
+	}
+
+	for _, tc := range testCases {
+		suite.T().Run(tc.name, func(t *testing.T) {
+			tc.claims["email"] = "jane@example.com"
+			resp := suite.oidcSignIn(suite.issuer.IDToken(t, "subject-1", tc.claims))
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		})
+	}
+
+	resp := suite.oidcSignIn("not.a.token")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/auth/oidc/generic", map[string]string{
+		"idToken": suite.idToken("subject-1", "jane@example.com", true),
+		"nonce":   "expected-nonce",
+	})
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/auth/oidc/unknown", map[string]string{
+		"idToken": suite.idToken("subject-1", "jane@example.com", true),
+	})
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *OIDCTestSuite) TestSignInWithNonce() {
+	idToken := suite.issuer.IDToken(suite.T(), "subject-1", map[string]any{
+		"email": "jane@example.com",
+		"nonce": "expected-nonce",
+	})
+
+	resp := suite.httpClient.POST("/api/v1/auth/oidc/generic", map[string]string{
+		"idToken": idToken,
+		"nonce":   "expected-nonce",
+	})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+func (suite *OIDCTestSuite) TestLinkAndUnlinkIdentity() {
+	accessToken := helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+	headers := map[string]string{"Authorization": accessToken}
+
+	// The provider email does not have to match the account email
+	resp := suite.httpClient.POST("/api/v1/user/identities/generic", map[string]string{
+		"idToken": suite.idToken("subject-1", "someone@example.com", false),
+	}, headers)
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode)
+
+	var linked map[string]any
+	suite.Require().NoError(resp.ParseJSON(&linked))
+	assert.Equal(suite.T(), "someone@example.com", linked["email"])
+
+	// Linking again is a no-op
+	resp = suite.httpClient.POST("/api/v1/user/identities/generic", map[string]string{
+		"idToken": suite.idToken("subject-1", "someone@example.com", false),
+	}, headers)
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	_, user := suite.parseTokenPayload(suite.oidcSignIn(suite.idToken("subject-1", "someone@example.com", false)))
+	assert.Equal(suite.T(), "test@example.com", user["email"])
+
+	resp = suite.httpClient.DELETE("/api/v1/user/identities/"+linked["id"].(string), headers)
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/user/identities", headers)
+	var identities []any
+	suite.Require().NoError(resp.ParseJSON(&identities))
+	assert.Empty(suite.T(), identities)
+
+	resp = suite.httpClient.DELETE("/api/v1/user/identities/"+linked["id"].(string), headers)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *OIDCTestSuite) TestLinkIdentityOwnedByAnotherUser() {
+	suite.parseTokenPayload(suite.oidcSignIn(suite.idToken("subject-1", "jane@example.com", true)))
+
+	accessToken := helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+	resp := suite.httpClient.POST("/api/v1/user/identities/generic", map[string]string{
+		"idToken": suite.idToken("subject-1", "jane@example.com", true),
+	}, map[string]string{"Authorization": accessToken})
+	assert.Equal(suite.T(), http.StatusConflict, resp.StatusCode)
+}
+
+func (suite *OIDCTestSuite) TestCannotUnlinkLastSignInMethod() {
+	accessToken, _ := suite.parseTokenPayload(suite.oidcSignIn(suite.idToken("subject-1", "jane@example.com", true)))
+	identities := suite.getIdentities(accessToken)
+	suite.Require().Len(identities, 1)
+
+	resp := suite.httpClient.DELETE(
+		"/api/v1/user/identities/"+identities[0].(map[string]any)["id"].(string),
+		authHeaders(accessToken),
+	)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Equal(suite.T(), shared.ErrLastSignInMethod, response["code"])
+}
@@ -0,0 +1,197 @@
+package e2etest
+
+import (
+	"fmt"
+	"lexia/test/helpers"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pageResponse is the body of a paginated listing.
+type pageResponse struct {
+	Items      []map[string]interface{} `json:"items"`
+	NextCursor *string                  `json:"nextCursor"`
+}
+
+type PaginationTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+	headers    map[string]string
+}
+
+func (suite *PaginationTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+
+	suite.headers = helpers.SignUpUser(suite.T(), suite.httpClient, "pages@example.com", "pagesuser")
+}
+
+func TestPaginationSuite(t *testing.T) {
+	helpers.RunE2ETestSuite(t, new(PaginationTestSuite))
+}
+
+func (suite *PaginationTestSuite) createFolder(data map[string]interface{}) string {
+	return helpers.CreateFolder(suite.T(), suite.httpClient, suite.headers, data)
+}
+
+func (suite *PaginationTestSuite) createWordCollection(name string) string {
+	return suite.createFolder(map[string]interface{}{
+		"name":         name,
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+	})
+}
+
+func (suite *PaginationTestSuite) createWord(folderID string, text string, definition string) map[string]interface{} {
+	return helpers.CreateWord(suite.T(), suite.httpClient, suite.headers, map[string]interface{}{
+		"text":               text,
+		"definition":         definition,
+		"folderId":           folderID,
+		"autofillDefinition": false,
+	})
+}
+
+func (suite *PaginationTestSuite) getPage(path string) pageResponse {
+	resp := suite.httpClient.GET(path, suite.headers)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode, resp.GetBodyAsString())
+
+	var page pageResponse
+	suite.Require().NoError(resp.ParseJSON(&page))
+	return page
+}
+
+func texts(items []map[string]interface{}, key string) []string {
+	values := make([]string, len(items))
+	for i, item := range items {
+		values[i] = item[key].(string)
+	}
+	return values
+}
+
+func (suite *PaginationTestSuite) TestWordsFollowCursor() {
+	folderID := suite.createWordCollection("Vocabulary")
+	for _, text := range []string{"one", "two", "three", "four", "five"} {
+		suite.createWord(folderID, text, "")
+	}
+
+	var seen []string
+	path := fmt.Sprintf("/api/v1/folders/%s/words?limit=2", folderID)
+	for pages := 0; pages < 3; pages++ {
+		page := suite.getPage(path)
+		seen = append(seen, texts(page.Items, "text")...)
+
+		if pages < 2 {
+			suite.Require().NotNil(page.NextCursor)
+			assert.Len(suite.T(), page.Items, 2)
+			path = fmt.Sprintf("/api/v1/folders/%s/words?limit=2&cursor=%s", folderID, url.QueryEscape(*page.NextCursor))
+		} else {
+			assert.Nil(suite.T(), page.NextCursor)
+		}
+	}
+
+	assert.Equal(suite.T(), []string{"one", "two", "three", "four", "five"}, seen)
+}
+
+func (suite *PaginationTestSuite) TestWordsSortByText() {
+	folderID := suite.createWordCollection("Vocabulary")
+	for _, text := range []string{"banana", "cherry", "apple"} {
+		suite.createWord(folderID, text, "")
+	}
+
+	page := suite.getPage(fmt.Sprintf("/api/v1/folders/%s/words?sort=text", folderID))
+	assert.Equal(suite.T(), []string{"apple", "banana", "cherry"}, texts(page.Items, "text"))
+
+	page = suite.getPage(fmt.Sprintf("/api/v1/folders/%s/words?sort=text&order=desc&limit=2", folderID))
+	assert.Equal(suite.T(), []string{"cherry", "banana"}, texts(page.Items, "text"))
+	suite.Require().NotNil(page.NextCursor)
+
+	page = suite.getPage(fmt.Sprintf("/api/v1/folders/%s/words?sort=text&order=desc&limit=2&cursor=%s", folderID, url.QueryEscape(*page.NextCursor)))
+	assert.Equal(suite.T(), []string{"apple"}, texts(page.Items, "text"))
+}
+
+func (suite *PaginationTestSuite) TestWordsSortByDueAt() {
+	folderID := suite.createWordCollection("Vocabulary")
+	reviewed := suite.createWord(folderID, "reviewed", "")
+	suite.createWord(folderID, "new", "")
+
+	resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/reviews/%s", reviewed["id"]), map[string]interface{}{
+		"grade": 5,
+	}, suite.headers)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	// The review is due tomorrow, while the new word has been due since it was added
+	page := suite.getPage(fmt.Sprintf("/api/v1/folders/%s/words?sort=dueAt", folderID))
+	assert.Equal(suite.T(), []string{"new", "reviewed"}, texts(page.Items, "text"))
+}
+
+func (suite *PaginationTestSuite) TestWordsFilters() {
+	folderID := suite.createWordCollection("Vocabulary")
+	first := suite.createWord(folderID, "first", "a definition")
+	suite.createWord(folderID, "second", "")
+
+	page := suite.getPage(fmt.Sprintf("/api/v1/folders/%s/words?hasDefinition=true", folderID))
+	assert.Equal(suite.T(), []string{"first"}, texts(page.Items, "text"))
+
+	page = suite.getPage(fmt.Sprintf("/api/v1/folders/%s/words?hasDefinition=false", folderID))
+	assert.Equal(suite.T(), []string{"second"}, texts(page.Items, "text"))
+
+	createdAt, err := time.Parse(time.RFC3339Nano, first["createdAt"].(string))
+	suite.Require().NoError(err)
+
+	page = suite.getPage(fmt.Sprintf("/api/v1/folders/%s/words?createdAfter=%s", folderID, url.QueryEscape(createdAt.Format(time.RFC3339Nano))))
+	assert.Equal(suite.T(), []string{"second"}, texts(page.Items, "text"))
+}
+
+func (suite *PaginationTestSuite) TestFoldersSortByName() {
+	suite.createWordCollection("Zulu")
+	suite.createWordCollection("Alpha")
+	suite.createWordCollection("Mike")
+
+	page := suite.getPage("/api/v1/folders/root?sort=name&limit=2")
+	assert.Equal(suite.T(), []string{"Alpha", "Mike"}, texts(page.Items, "name"))
+	suite.Require().NotNil(page.NextCursor)
+
+	page = suite.getPage("/api/v1/folders/root?sort=name&limit=2&cursor=" + url.QueryEscape(*page.NextCursor))
+	assert.Equal(suite.T(), []string{"Zulu"}, texts(page.Items, "name"))
+	assert.Nil(suite.T(), page.NextCursor)
+
+	page = suite.getPage("/api/v1/folders?sort=name&order=desc")
+	assert.Equal(suite.T(), []string{"Zulu", "Mike", "Alpha"}, texts(page.Items, "name"))
+}
+
+func (suite *PaginationTestSuite) TestInvalidParameters() {
+	suite.createWordCollection("Alpha")
+	suite.createWordCollection("Beta")
+
+	page := suite.getPage("/api/v1/folders?limit=1")
+	suite.Require().NotNil(page.NextCursor)
+
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{"limit too high", "?limit=1000", "INVALID_LIMIT"},
+		{"limit not a number", "?limit=many", "VALIDATION_FAILED"},
+		{"unknown sort", "?sort=size", "INVALID_SORT"},
+		{"unknown order", "?order=up", "INVALID_ORDER"},
+		{"garbage cursor", "?cursor=abc", "INVALID_CURSOR"},
+		{"cursor for another sort", "?sort=name&cursor=" + url.QueryEscape(*page.NextCursor), "INVALID_CURSOR"},
+		{"bad date", "?createdAfter=yesterday", "VALIDATION_FAILED"},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			resp := suite.httpClient.GET("/api/v1/folders"+tc.query, suite.headers)
+			assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+			var response map[string]interface{}
+			suite.Require().NoError(resp.ParseJSON(&response))
+			assert.Equal(suite.T(), tc.code, response["code"])
+		})
+	}
+}
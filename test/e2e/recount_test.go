@@ -0,0 +1,76 @@
+package e2etest
+
+import (
+	"lexia/ent/folder"
+	foldermodule "lexia/internal/modules/folder"
+	"lexia/test/helpers"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type RecountTestSuite struct {
+	helpers.E2ETestSuite
+}
+
+func TestRecountTestSuite(t *testing.T) {
+	suite.Run(t, new(RecountTestSuite))
+}
+
+func (suite *RecountTestSuite) TestRecountRepairsWordCounts() {
+	db := suite.GetDBClient()
+	ctx := suite.GetContext()
+
+	user, err := db.User.Create().
+		SetUsername("recount").
+		SetEmail("recount@example.com").
+		SetPassword("hash").
+		Save(ctx)
+	suite.Require().NoError(err)
+
+	root, err := db.Folder.Create().
+		SetName("Root").
+		SetWordCount(0).
+		SetType("FOLDER_COLLECTION").
+		SetUserID(user.ID).
+		Save(ctx)
+	suite.Require().NoError(err)
+
+	words, err := db.Folder.Create().
+		SetName("Words").
+		SetWordCount(0).
+		SetType("WORD_COLLECTION").
+		SetUserID(user.ID).
+		AddParentIDs(root.ID).
+		Save(ctx)
+	suite.Require().NoError(err)
+
+	for _, text := range []string{"one", "two", "three"} {
+		_, err := db.Word.Create().
+			SetID(uuid.New()).
+			SetText(text).
+			SetDefinition("").
+			SetFolderID(words.ID).
+			Save(ctx)
+		suite.Require().NoError(err)
+	}
+
+	// Simulate counts left behind before the hooks existed.
+	_, err = db.Folder.Update().SetWordCount(0).Save(ctx)
+	suite.Require().NoError(err)
+
+	updated, err := foldermodule.RecountWordCounts(ctx, db)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 2, updated)
+
+	rootAfter := db.Folder.Query().Where(folder.ID(root.ID)).OnlyX(ctx)
+	wordsAfter := db.Folder.Query().Where(folder.ID(words.ID)).OnlyX(ctx)
+	assert.Equal(suite.T(), int32(3), rootAfter.WordCount)
+	assert.Equal(suite.T(), int32(3), wordsAfter.WordCount)
+
+	updated, err = foldermodule.RecountWordCounts(ctx, db)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, updated)
+}
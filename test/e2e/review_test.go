@@ -0,0 +1,349 @@
+package e2etest
+
+import (
+	"fmt"
+	"lexia/ent/word"
+	"lexia/ent/wordreview"
+	"lexia/test/helpers"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReviewTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+	authToken  string
+}
+
+func (suite *ReviewTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+	suite.authToken = helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+}
+
+func TestReviewTestSuite(t *testing.T) {
+	suite.Run(t, new(ReviewTestSuite))
+}
+
+func (suite *ReviewTestSuite) getAuthHeaders() map[string]string {
+	return map[string]string{
+		"Authorization": suite.authToken,
+	}
+}
+
+func (suite *ReviewTestSuite) createWordCollection(name string, parentID *string) string {
+	folderData := map[string]interface{}{
+		"name":         name,
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+		"languageTo":   "GEORGIAN",
+	}
+	if parentID != nil {
+		folderData["parentId"] = *parentID
+	}
+
+	return helpers.CreateFolder(suite.T(), suite.httpClient, suite.getAuthHeaders(), folderData)
+}
+
+func (suite *ReviewTestSuite) createWord(folderID string, text string) string {
+	word := helpers.CreateWord(suite.T(), suite.httpClient, suite.getAuthHeaders(), map[string]interface{}{
+		"text":       text,
+		"definition": "definition of " + text,
+		"folderId":   folderID,
+	})
+
+	return word["id"].(string)
+}
+
+func (suite *ReviewTestSuite) getDueWords(query string) []map[string]interface{} {
+	resp := suite.httpClient.GET("/api/v1/reviews/due"+query, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response []map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	return response
+}
+
+func (suite *ReviewTestSuite) TestNewWordsAreDue() {
+	folderID := suite.createWordCollection("Vocabulary", nil)
+	suite.createWord(folderID, "hello")
+	suite.createWord(folderID, "world")
+
+	dueWords := suite.getDueWords("")
+	assert.Len(suite.T(), dueWords, 2)
+
+	for _, dueWord := range dueWords {
+		assert.Nil(suite.T(), dueWord["review"])
+		word := dueWord["word"].(map[string]interface{})
+		assert.Equal(suite.T(), folderID, word["folderId"])
+	}
+}
+
+func (suite *ReviewTestSuite) TestSubmitReviewReschedules() {
+	folderID := suite.createWordCollection("Vocabulary", nil)
+	wordID := suite.createWord(folderID, "hello")
+
+	resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/reviews/%s", wordID), map[string]interface{}{
+		"grade": 5,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), wordID, response["wordId"])
+	assert.Equal(suite.T(), float64(1), response["interval"])
+	assert.Equal(suite.T(), float64(1), response["repetitions"])
+	assert.InDelta(suite.T(), 2.6, response["ease"], 0.0001)
+	assert.NotEmpty(suite.T(), response["dueAt"])
+	assert.NotEmpty(suite.T(), response["lastReviewedAt"])
+
+	dueWords := suite.getDueWords("")
+	assert.Len(suite.T(), dueWords, 0)
+}
+
+func (suite *ReviewTestSuite) TestSubmitReviewFailedGradeCountsLapse() {
+	folderID := suite.createWordCollection("Vocabulary", nil)
+	wordID := suite.createWord(folderID, "hello")
+
+	resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/reviews/%s", wordID), map[string]interface{}{
+		"grade": 1,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), float64(1), response["lapses"])
+	assert.Equal(suite.T(), float64(0), response["repetitions"])
+}
+
+func (suite *ReviewTestSuite) TestDueWordsScopedToFolderSubtree() {
+	collectionID := helpers.CreateFolder(suite.T(), suite.httpClient, suite.getAuthHeaders(), map[string]interface{}{
+		"name": "Languages",
+		"type": "FOLDER_COLLECTION",
+	})
+	nestedFolderID := suite.createWordCollection("Nested", &collectionID)
+	otherFolderID := suite.createWordCollection("Other", nil)
+
+	nestedWordID := suite.createWord(nestedFolderID, "nested")
+	suite.createWord(otherFolderID, "other")
+
+	dueWords := suite.getDueWords("?folderId=" + collectionID)
+	assert.Len(suite.T(), dueWords, 1)
+	word := dueWords[0]["word"].(map[string]interface{})
+	assert.Equal(suite.T(), nestedWordID, word["id"])
+
+	dueWords = suite.getDueWords("?folderId=" + otherFolderID)
+	assert.Len(suite.T(), dueWords, 1)
+
+	dueWords = suite.getDueWords("?limit=1")
+	assert.Len(suite.T(), dueWords, 1)
+}
+
+func (suite *ReviewTestSuite) TestDueWordsOrderedByDueDate() {
+	folderID := suite.createWordCollection("Vocabulary", nil)
+	suite.createWord(folderID, "first")
+	reviewedID := suite.createWord(folderID, "reviewed")
+
+	resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/reviews/%s", reviewedID), map[string]interface{}{
+		"grade": 3,
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	// The review fell due before the other word was added
+	_, err := suite.GetDBClient().WordReview.Update().
+		Where(wordreview.HasWordWith(word.ID(uuid.MustParse(reviewedID)))).
+		SetDueAt(time.Now().Add(-time.Hour)).
+		Save(suite.GetContext())
+	suite.Require().NoError(err)
+
+	dueWords := suite.getDueWords("")
+	suite.Require().Len(dueWords, 2)
+	assert.Equal(suite.T(), "reviewed", dueWords[0]["word"].(map[string]interface{})["text"])
+	assert.Equal(suite.T(), "first", dueWords[1]["word"].(map[string]interface{})["text"])
+
+	dueWords = suite.getDueWords("?limit=1")
+	suite.Require().Len(dueWords, 1)
+	assert.Equal(suite.T(), "reviewed", dueWords[0]["word"].(map[string]interface{})["text"])
+}
+
+func (suite *ReviewTestSuite) TestConcurrentReviewsAreAppliedInTurn() {
+	folderID := suite.createWordCollection("Vocabulary", nil)
+	wordID := suite.createWord(folderID, "hello")
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/reviews/%s", wordID), map[string]interface{}{
+				"grade": 5,
+			}, suite.getAuthHeaders())
+			assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+		}()
+	}
+	wg.Wait()
+
+	review, err := suite.GetDBClient().WordReview.Query().
+		Where(wordreview.HasWordWith(word.ID(uuid.MustParse(wordID)))).
+		Only(suite.GetContext())
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), int32(5), review.Repetitions)
+}
+
+func (suite *ReviewTestSuite) TestReviewValidationErrors() {
+	folderID := suite.createWordCollection("Vocabulary", nil)
+	wordID := suite.createWord(folderID, "hello")
+
+	testCases := []struct {
+		name           string
+		path           string
+		body           map[string]interface{}
+		expectedStatus int
+	}{
+		{"missing grade", "/api/v1/reviews/" + wordID, map[string]interface{}{}, http.StatusBadRequest},
+		{"grade too high", "/api/v1/reviews/" + wordID, map[string]interface{}{"grade": 6}, http.StatusBadRequest},
+		{"invalid word ID", "/api/v1/reviews/invalid", map[string]interface{}{"grade": 3}, http.StatusBadRequest},
+		{"unknown word", "/api/v1/reviews/" + uuid.New().String(), map[string]interface{}{"grade": 3}, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			resp := suite.httpClient.POST(tc.path, tc.body, suite.getAuthHeaders())
+			assert.Equal(suite.T(), tc.expectedStatus, resp.StatusCode)
+		})
+	}
+
+	resp := suite.httpClient.GET("/api/v1/reviews/due?folderId=invalid", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/reviews/due?folderId="+uuid.New().String(), suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *ReviewTestSuite) TestReviewWithoutAuth() {
+	resp := suite.httpClient.GET("/api/v1/reviews/due")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *ReviewTestSuite) TestGetDefaultSrsSettings() {
+	resp := suite.httpClient.GET("/api/v1/user/srs-settings", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), "SM2", response["scheduler"])
+	assert.Equal(suite.T(), 0.9, response["desiredRetention"])
+	assert.Len(suite.T(), response["fsrsWeights"], 17)
+	assert.Nil(suite.T(), response["optimizedAt"])
+}
+
+func (suite *ReviewTestSuite) TestUpdateSrsSettings() {
+	resp := suite.httpClient.PUT("/api/v1/user/srs-settings", map[string]interface{}{
+		"scheduler":        "FSRS",
+		"desiredRetention": 0.85,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), "FSRS", response["scheduler"])
+	assert.Equal(suite.T(), 0.85, response["desiredRetention"])
+
+	resp = suite.httpClient.GET("/api/v1/user/srs-settings", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	err = resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "FSRS", response["scheduler"])
+}
+
+func (suite *ReviewTestSuite) TestUpdateSrsSettingsValidation() {
+	testCases := []struct {
+		name string
+		body map[string]interface{}
+	}{
+		{"unknown scheduler", map[string]interface{}{"scheduler": "LEITNER"}},
+		{"retention too low", map[string]interface{}{"desiredRetention": 0.5}},
+		{"retention too high", map[string]interface{}{"desiredRetention": 1}},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			resp := suite.httpClient.PUT("/api/v1/user/srs-settings", tc.body, suite.getAuthHeaders())
+			assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func (suite *ReviewTestSuite) TestSubmitReviewWithFsrsScheduler() {
+	resp := suite.httpClient.PUT("/api/v1/user/srs-settings", map[string]interface{}{
+		"scheduler": "FSRS",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	folderID := suite.createWordCollection("Vocabulary", nil)
+	wordID := suite.createWord(folderID, "hello")
+
+	resp = suite.httpClient.POST(fmt.Sprintf("/api/v1/reviews/%s", wordID), map[string]interface{}{
+		"grade": 5,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	// The first "easy" FSRS review schedules the word further out than SM-2's one day.
+	assert.Greater(suite.T(), response["interval"], float64(1))
+}
+
+func (suite *ReviewTestSuite) TestOptimizeSrsSettingsRequiresHistory() {
+	resp := suite.httpClient.POST("/api/v1/user/srs-settings/optimize", nil, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *ReviewTestSuite) TestOptimizeSrsSettings() {
+	folderID := suite.createWordCollection("Optimize", nil)
+
+	// Every review of a word but its first can be learned from, so ten words
+	// reviewed six times each give the optimizer exactly enough history.
+	for i := 0; i < 10; i++ {
+		wordID := suite.createWord(folderID, fmt.Sprintf("word-%d", i))
+		for j := 0; j < 6; j++ {
+			resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/reviews/%s", wordID), map[string]interface{}{
+				"grade": 4,
+			}, suite.getAuthHeaders())
+			assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+		}
+	}
+
+	resp := suite.httpClient.POST("/api/v1/user/srs-settings/optimize", nil, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), float64(50), response["reviewCount"])
+	assert.Contains(suite.T(), response, "workloadChangePercent")
+
+	settings, ok := response["settings"].(map[string]interface{})
+	suite.Require().True(ok)
+	assert.NotEmpty(suite.T(), settings["fsrsWeights"])
+	assert.NotNil(suite.T(), settings["optimizedAt"])
+}
@@ -0,0 +1,152 @@
+package e2etest
+
+import (
+	"lexia/test/helpers"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type SearchTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+	headers    map[string]string
+}
+
+func (suite *SearchTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+	suite.headers = helpers.SignUpUser(suite.T(), suite.httpClient, "search@example.com", "searchuser")
+}
+
+func TestSearchSuite(t *testing.T) {
+	helpers.RunE2ETestSuite(t, new(SearchTestSuite))
+}
+
+func (suite *SearchTestSuite) createFolder(data map[string]interface{}, headers map[string]string) string {
+	return helpers.CreateFolder(suite.T(), suite.httpClient, headers, data)
+}
+
+func (suite *SearchTestSuite) createWord(folderID string, text string, definition string, headers map[string]string) {
+	helpers.CreateWord(suite.T(), suite.httpClient, headers, map[string]interface{}{
+		"text":               text,
+		"definition":         definition,
+		"folderId":           folderID,
+		"autofillDefinition": false,
+	})
+}
+
+func (suite *SearchTestSuite) search(query string) []map[string]interface{} {
+	resp := suite.httpClient.GET("/api/v1/words/search?q="+url.QueryEscape(query), suite.headers)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode, resp.GetBodyAsString())
+
+	var results []map[string]interface{}
+	suite.Require().NoError(resp.ParseJSON(&results))
+	return results
+}
+
+func resultTexts(results []map[string]interface{}) []string {
+	values := make([]string, len(results))
+	for i, result := range results {
+		values[i] = result["word"].(map[string]interface{})["text"].(string)
+	}
+	return values
+}
+
+func (suite *SearchTestSuite) TestStemsByFolderLanguage() {
+	languagesID := suite.createFolder(map[string]interface{}{
+		"name": "Languages",
+		"type": "FOLDER_COLLECTION",
+	}, suite.headers)
+	englishID := suite.createFolder(map[string]interface{}{
+		"name":         "English",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+		"parentId":     languagesID,
+	}, suite.headers)
+
+	suite.createWord(englishID, "running", "moving quickly on foot", suite.headers)
+	suite.createWord(englishID, "table", "furniture with a flat top", suite.headers)
+
+	results := suite.search("run")
+	suite.Require().Len(results, 1)
+
+	result := results[0]
+	assert.Equal(suite.T(), "<mark>running</mark>", result["textSnippet"])
+	assert.Equal(suite.T(), false, result["fuzzy"])
+	assert.Greater(suite.T(), result["rank"], float64(0))
+
+	folderPath := result["word"].(map[string]interface{})["folderPath"].([]interface{})
+	suite.Require().Len(folderPath, 2)
+	assert.Equal(suite.T(), "Languages", folderPath[0].(map[string]interface{})["name"])
+	assert.Equal(suite.T(), "English", folderPath[1].(map[string]interface{})["name"])
+}
+
+func (suite *SearchTestSuite) TestSearchesDefinitions() {
+	folderID := suite.createFolder(map[string]interface{}{
+		"name":         "Vocabulary",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+	}, suite.headers)
+
+	suite.createWord(folderID, "chair", "furniture to sit on", suite.headers)
+	suite.createWord(folderID, "furniture", "things in a room", suite.headers)
+
+	// A match on the text ranks above one in the definition
+	results := suite.search("furniture")
+	assert.Equal(suite.T(), []string{"furniture", "chair"}, resultTexts(results))
+	assert.Contains(suite.T(), results[1]["definitionSnippet"], "<mark>furniture</mark>")
+}
+
+func (suite *SearchTestSuite) TestFuzzyFallback() {
+	folderID := suite.createFolder(map[string]interface{}{
+		"name":         "Georgian",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "GEORGIAN",
+	}, suite.headers)
+
+	suite.createWord(folderID, "გამარჯობა", "hello", suite.headers)
+	suite.createWord(folderID, "წყალი", "water", suite.headers)
+
+	results := suite.search("გამარჯბა")
+	suite.Require().Len(results, 1)
+	assert.Equal(suite.T(), "გამარჯობა", resultTexts(results)[0])
+	assert.Equal(suite.T(), true, results[0]["fuzzy"])
+}
+
+func (suite *SearchTestSuite) TestOnlySearchesOwnWords() {
+	otherHeaders := helpers.SignUpUser(suite.T(), suite.httpClient, "other-search@example.com", "othersearch")
+	otherFolderID := suite.createFolder(map[string]interface{}{
+		"name":         "Private",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+	}, otherHeaders)
+	suite.createWord(otherFolderID, "secret", "not for others", otherHeaders)
+
+	assert.Empty(suite.T(), suite.search("secret"))
+}
+
+func (suite *SearchTestSuite) TestInvalidQuery() {
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{"missing query", "", "VALIDATION_FAILED"},
+		{"blank query", "?q=%20%20", "SEARCH_QUERY_REQUIRED"},
+		{"limit too high", "?q=word&limit=1000", "VALIDATION_FAILED"},
+	}
+
+	for _, tc := range tests {
+		suite.Run(tc.name, func() {
+			resp := suite.httpClient.GET("/api/v1/words/search"+tc.query, suite.headers)
+			assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+			var response map[string]interface{}
+			suite.Require().NoError(resp.ParseJSON(&response))
+			assert.Equal(suite.T(), tc.code, response["code"])
+		})
+	}
+}
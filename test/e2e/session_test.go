@@ -0,0 +1,215 @@
+package e2etest
+
+import (
+	"fmt"
+	"lexia/test/helpers"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+}
+
+func (suite *SessionTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+
+	resp := suite.httpClient.POST("/api/v1/auth/signup", map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+		"username": "testuser",
+	})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+func TestSessionTestSuite(t *testing.T) {
+	suite.Run(t, new(SessionTestSuite))
+}
+
+func (suite *SessionTestSuite) signIn(email string, deviceName string) (string, string) {
+	resp := suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+		"email":      email,
+		"password":   "password123",
+		"deviceName": deviceName,
+	})
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	err := resp.ParseJSON(&response)
+	suite.Require().NoError(err)
+
+	accessToken, ok := response["accessToken"].(string)
+	suite.Require().True(ok)
+	refreshToken, ok := response["refreshToken"].(string)
+	suite.Require().True(ok)
+	suite.Require().NotEmpty(refreshToken)
+
+	return accessToken, refreshToken
+}
+
+func authHeaders(accessToken string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + accessToken}
+}
+
+func (suite *SessionTestSuite) refresh(refreshToken string) *helpers.Response {
+	return suite.httpClient.POST("/api/v1/auth/refresh", map[string]string{
+		"refreshToken": refreshToken,
+	})
+}
+
+func (suite *SessionTestSuite) TestRefreshRotatesToken() {
+	_, refreshToken := suite.signIn("test@example.com", "Laptop")
+
+	resp := suite.refresh(refreshToken)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	newAccessToken := response["accessToken"].(string)
+	newRefreshToken := response["refreshToken"].(string)
+	assert.NotEqual(suite.T(), refreshToken, newRefreshToken)
+	assert.Equal(suite.T(), "test@example.com", response["user"].(map[string]any)["email"])
+
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(newAccessToken))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	resp = suite.refresh(newRefreshToken)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+func (suite *SessionTestSuite) TestRefreshTokenReuseRevokesSession() {
+	_, refreshToken := suite.signIn("test@example.com", "Laptop")
+
+	resp := suite.refresh(refreshToken)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+	accessToken := response["accessToken"].(string)
+	rotatedRefreshToken := response["refreshToken"].(string)
+
+	// Replaying the old token revokes the session it was rotated on
+	resp = suite.refresh(refreshToken)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.refresh(rotatedRefreshToken)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *SessionTestSuite) TestRefreshInvalidToken() {
+	resp := suite.refresh("not-a-refresh-token")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/auth/refresh", map[string]string{})
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *SessionTestSuite) TestLogoutRevokesSession() {
+	accessToken, refreshToken := suite.signIn("test@example.com", "Laptop")
+
+	resp := suite.httpClient.GET("/api/v1/user/auth", authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/auth/logout", map[string]string{
+		"refreshToken": refreshToken,
+	})
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.refresh(refreshToken)
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	// Logging out twice is harmless
+	resp = suite.httpClient.POST("/api/v1/auth/logout", map[string]string{
+		"refreshToken": refreshToken,
+	})
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+}
+
+func (suite *SessionTestSuite) TestListAndRevokeSessions() {
+	laptopToken, _ := suite.signIn("test@example.com", "Laptop")
+	phoneToken, _ := suite.signIn("test@example.com", "Phone")
+
+	resp := suite.httpClient.GET("/api/v1/user/sessions", authHeaders(laptopToken))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var sessions []map[string]any
+	err := resp.ParseJSON(&sessions)
+	assert.NoError(suite.T(), err)
+
+	// The signup session plus the two sign-ins
+	assert.Len(suite.T(), sessions, 3)
+
+	var phoneSessionID string
+	for _, session := range sessions {
+		assert.NotEmpty(suite.T(), session["lastUsedAt"])
+		assert.NotEmpty(suite.T(), session["userAgent"])
+		switch session["deviceName"] {
+		case "Laptop":
+			assert.Equal(suite.T(), true, session["current"])
+		case "Phone":
+			assert.Equal(suite.T(), false, session["current"])
+			phoneSessionID = session["id"].(string)
+		}
+	}
+	suite.Require().NotEmpty(phoneSessionID)
+
+	resp = suite.httpClient.DELETE("/api/v1/user/sessions/"+phoneSessionID, authHeaders(laptopToken))
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(phoneToken))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/user/sessions", authHeaders(laptopToken))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	err = resp.ParseJSON(&sessions)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), sessions, 2)
+
+	resp = suite.httpClient.DELETE("/api/v1/user/sessions/"+phoneSessionID, authHeaders(laptopToken))
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
+
+func (suite *SessionTestSuite) TestCannotRevokeOtherUsersSession() {
+	resp := suite.httpClient.POST("/api/v1/auth/signup", map[string]string{
+		"email":    "other@example.com",
+		"password": "password123",
+		"username": "otheruser",
+	})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	otherToken, _ := suite.signIn("other@example.com", "Other")
+	accessToken, _ := suite.signIn("test@example.com", "Laptop")
+
+	resp = suite.httpClient.GET("/api/v1/user/sessions", authHeaders(otherToken))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var sessions []map[string]any
+	err := resp.ParseJSON(&sessions)
+	assert.NoError(suite.T(), err)
+	suite.Require().NotEmpty(sessions)
+
+	resp = suite.httpClient.DELETE(fmt.Sprintf("/api/v1/user/sessions/%s", sessions[0]["id"]), authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(otherToken))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	resp = suite.httpClient.DELETE(fmt.Sprintf("/api/v1/user/sessions/%s", uuid.New()), authHeaders(accessToken))
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
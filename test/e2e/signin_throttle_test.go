@@ -0,0 +1,193 @@
+package e2etest
+
+import (
+	"lexia/ent/auditlog"
+	"lexia/ent/schema"
+	"lexia/internal/throttle"
+	"lexia/test/helpers"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SignInThrottleTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+}
+
+func (suite *SignInThrottleTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+	helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+}
+
+func TestSignInThrottleTestSuite(t *testing.T) {
+	suite.Run(t, new(SignInThrottleTestSuite))
+}
+
+func (suite *SignInThrottleTestSuite) signIn(email string, password string) *helpers.Response {
+	return suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+		"email":    email,
+		"password": password,
+	})
+}
+
+func (suite *SignInThrottleTestSuite) TestRepeatedFailuresAreThrottled() {
+	for i := 0; i < 5; i++ {
+		resp := suite.signIn("test@example.com", "wrong-password")
+		suite.Require().Equal(http.StatusUnauthorized, resp.StatusCode)
+	}
+
+	resp := suite.signIn("test@example.com", "password123")
+	assert.Equal(suite.T(), http.StatusTooManyRequests, resp.StatusCode)
+	assert.Contains(suite.T(), resp.GetBodyAsString(), "TOO_MANY_ATTEMPTS")
+	assert.Equal(suite.T(), "1", resp.Headers.Get("Retry-After"))
+
+	time.Sleep(1100 * time.Millisecond)
+
+	resp = suite.signIn("test@example.com", "password123")
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	// A successful sign-in clears the account's failures.
+	resp = suite.signIn("test@example.com", "wrong-password")
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *SignInThrottleTestSuite) TestUnknownEmailsAreThrottledToo() {
+	for i := 0; i < 5; i++ {
+		suite.signIn("nobody@example.com", "wrong-password")
+	}
+
+	resp := suite.signIn("nobody@example.com", "wrong-password")
+	assert.Equal(suite.T(), http.StatusTooManyRequests, resp.StatusCode)
+
+	// Other accounts from the same IP are not affected yet.
+	resp = suite.signIn("test@example.com", "password123")
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+func (suite *SignInThrottleTestSuite) TestFailuresAreAudited() {
+	for i := 0; i < 5; i++ {
+		suite.signIn("test@example.com", "wrong-password")
+	}
+	suite.signIn("test@example.com", "wrong-password")
+
+	ctx := suite.GetContext()
+	db := suite.GetDBClient()
+
+	failed, err := db.AuditLog.Query().
+		Where(
+			auditlog.EventEQ(schema.AuditEventSignInFailed),
+			auditlog.Email("test@example.com"),
+			auditlog.UserIdNotNil(),
+		).
+		Count(ctx)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 5, failed)
+
+	throttled, err := db.AuditLog.Query().
+		Where(auditlog.EventEQ(schema.AuditEventSignInThrottled)).
+		Count(ctx)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, throttled)
+}
+
+func (suite *SignInThrottleTestSuite) TestPostgresStoreCountsConcurrentFailures() {
+	store := throttle.NewPostgresStore(suite.GetDBClient())
+	ctx := suite.GetContext()
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.RecordFailure(ctx, "signin:account:race@example.com", now, time.Hour)
+			assert.NoError(suite.T(), err)
+		}()
+	}
+	wg.Wait()
+
+	state, err := store.Get(ctx, "signin:account:race@example.com", now)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 20, state.Failures)
+
+	state, err = store.Get(ctx, "signin:account:race@example.com", now.Add(2*time.Hour))
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, state.Failures)
+
+	state, err = store.RecordFailure(ctx, "signin:account:race@example.com", now.Add(2*time.Hour), time.Hour)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 1, state.Failures)
+
+	suite.Require().NoError(store.Reset(ctx, "signin:account:race@example.com"))
+	state, err = store.Get(ctx, "signin:account:race@example.com", now)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 0, state.Failures)
+}
+
+func (suite *SignInThrottleTestSuite) TestConcurrentFailuresGetOnlyFreeAttempts() {
+	var (
+		wg           sync.WaitGroup
+		mu           sync.Mutex
+		unauthorized int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := suite.signIn("test@example.com", "wrong-password")
+			if resp.StatusCode == http.StatusUnauthorized {
+				mu.Lock()
+				unauthorized++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(suite.T(), 5, unauthorized)
+}
+
+func (suite *SignInThrottleTestSuite) TestPostgresStoreRecordsConcurrentAttempts() {
+	store := throttle.NewPostgresStore(suite.GetDBClient())
+	ctx := suite.GetContext()
+	now := time.Now()
+	wait := func(state throttle.State) time.Duration {
+		if state.Failures >= 5 {
+			return time.Minute
+		}
+		return 0
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w, err := store.RecordAttempt(ctx, "signin:account:attempts@example.com", now, time.Hour, wait)
+			assert.NoError(suite.T(), err)
+			if w == 0 {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(suite.T(), 5, allowed)
+
+	suite.Require().NoError(store.RemoveFailure(ctx, "signin:account:attempts@example.com"))
+	state, err := store.Get(ctx, "signin:account:attempts@example.com", now)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), 4, state.Failures)
+}
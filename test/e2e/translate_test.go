@@ -3,6 +3,7 @@ package e2etest
 import (
 	"lexia/test/helpers"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -58,6 +59,7 @@ func (suite *TranslateTestSuite) TestTranslateEnglishToGeorgian() {
 	assert.Contains(suite.T(), firstTranslation, "text")
 	assert.Contains(suite.T(), firstTranslation, "confidence")
 	assert.Greater(suite.T(), firstTranslation["confidence"].(float64), 0.0)
+	assert.Equal(suite.T(), "PRIMARY", firstTranslation["source"])
 }
 
 func (suite *TranslateTestSuite) TestTranslateWithoutAuth() {
@@ -104,3 +106,136 @@ func (suite *TranslateTestSuite) TestTranslateSpanishToEnglish() {
 	assert.True(suite.T(), ok)
 	assert.Greater(suite.T(), len(translations), 0)
 }
+
+func (suite *TranslateTestSuite) TestTranslateBatch() {
+	batchData := map[string]interface{}{
+		"texts":        []string{"hello", "", "goodbye", "hello"},
+		"languageFrom": "ENGLISH",
+		"languageTo":   "SPANISH",
+	}
+
+	resp := suite.httpClient.POST("/api/v1/translate/batch", batchData, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	results := response["results"].([]interface{})
+	assert.Len(suite.T(), results, 4)
+
+	for i, result := range results {
+		item := result.(map[string]interface{})
+		assert.Equal(suite.T(), float64(i), item["index"])
+	}
+
+	empty := results[1].(map[string]interface{})
+	assert.Equal(suite.T(), "EMPTY_TEXT", empty["error"].(map[string]interface{})["code"])
+	assert.NotContains(suite.T(), empty, "translation")
+
+	first := results[0].(map[string]interface{})
+	duplicate := results[3].(map[string]interface{})
+	assert.NotEmpty(suite.T(), first["translation"])
+	assert.Equal(suite.T(), first["translation"], duplicate["translation"])
+}
+
+func (suite *TranslateTestSuite) TestTranslateBatchPerItemLengthLimit() {
+	batchData := map[string]interface{}{
+		"texts":        []string{strings.Repeat("a", 5001), "   "},
+		"languageFrom": "ENGLISH",
+		"languageTo":   "SPANISH",
+	}
+
+	resp := suite.httpClient.POST("/api/v1/translate/batch", batchData, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	results := response["results"].([]interface{})
+	assert.Equal(suite.T(), "TEXT_TOO_LONG", results[0].(map[string]interface{})["error"].(map[string]interface{})["code"])
+	assert.Equal(suite.T(), "EMPTY_TEXT", results[1].(map[string]interface{})["error"].(map[string]interface{})["code"])
+}
+
+func (suite *TranslateTestSuite) TestTranslateBatchValidation() {
+	tooMany := make([]string, 501)
+	for i := range tooMany {
+		tooMany[i] = "word"
+	}
+
+	testCases := []map[string]interface{}{
+		{"texts": []string{}, "languageFrom": "ENGLISH", "languageTo": "SPANISH"},
+		{"texts": tooMany, "languageFrom": "ENGLISH", "languageTo": "SPANISH"},
+		{"texts": []string{"hello"}, "languageFrom": "ENGLISH", "languageTo": "ENGLISH"},
+	}
+
+	for _, batchData := range testCases {
+		resp := suite.httpClient.POST("/api/v1/translate/batch", batchData, suite.getAuthHeaders())
+		assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	}
+}
+
+func (suite *TranslateTestSuite) TestGetSupportedLanguages() {
+	resp := suite.httpClient.GET("/api/v1/translate/languages", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response struct {
+		Languages []string                 `json:"languages"`
+		Catalogue []map[string]interface{} `json:"catalogue"`
+	}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), response.Languages, 8)
+	assert.GreaterOrEqual(suite.T(), len(response.Catalogue), 100)
+
+	byTag := map[string]map[string]interface{}{}
+	for _, language := range response.Catalogue {
+		byTag[language["tag"].(string)] = language
+	}
+
+	assert.Equal(suite.T(), "GEORGIAN", byTag["ka"]["code"])
+	assert.Equal(suite.T(), "it", byTag["it"]["code"])
+	assert.Equal(suite.T(), "Italian", byTag["it"]["name"])
+	assert.Equal(suite.T(), "Italiano", byTag["it"]["nativeName"])
+	assert.Equal(suite.T(), "RTL", byTag["ar"]["direction"])
+	assert.Equal(suite.T(), "Arab", byTag["ar"]["script"])
+}
+
+func (suite *TranslateTestSuite) TestGetSupportedLanguagesByProvider() {
+	resp := suite.httpClient.GET("/api/v1/translate/languages?provider=deepl", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response struct {
+		Catalogue []map[string]interface{} `json:"catalogue"`
+	}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), response.Catalogue)
+
+	for _, language := range response.Catalogue {
+		assert.Contains(suite.T(), language["providers"], "deepl")
+		assert.NotEqual(suite.T(), "ka", language["tag"])
+	}
+
+	resp = suite.httpClient.GET("/api/v1/translate/languages?provider=unknown", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *TranslateTestSuite) TestTranslateUnsupportedLanguage() {
+	resp := suite.httpClient.POST("/api/v1/translate", map[string]interface{}{
+		"text":         "Hello",
+		"languageFrom": "ENGLISH",
+		"languageTo":   "KLINGON",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *TranslateTestSuite) TestTranslateSameLanguageAcrossLegacyCodeAndTag() {
+	resp := suite.httpClient.POST("/api/v1/translate", map[string]interface{}{
+		"text":         "Hello",
+		"languageFrom": "ENGLISH",
+		"languageTo":   "en",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,180 @@
+package e2etest
+
+import (
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/ent/translationcacheentry"
+	"lexia/internal/shared"
+	"lexia/test/helpers"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TranslationCacheTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+	authToken  string
+}
+
+func (suite *TranslationCacheTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+	suite.authToken = helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+}
+
+func TestTranslationCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(TranslationCacheTestSuite))
+}
+
+func (suite *TranslationCacheTestSuite) getAuthHeaders() map[string]string {
+	return map[string]string{
+		"Authorization": suite.authToken,
+	}
+}
+
+func (suite *TranslationCacheTestSuite) provider() string {
+	if provider := os.Getenv(shared.EnvTranslationProvider); provider != "" {
+		return provider
+	}
+	return shared.TranslationProviderGoogle
+}
+
+func (suite *TranslationCacheTestSuite) seedEntry(text string, expiresAt time.Time) *ent.TranslationCacheEntry {
+	entry, err := suite.GetDBClient().TranslationCacheEntry.Create().
+		SetNormalizedText(text).
+		SetLanguageFrom("en").
+		SetLanguageTo("es").
+		SetProvider(suite.provider()).
+		SetVariants([]schema.CachedTranslationVariant{
+			{Text: "hola (cached)", Confidence: 0.95},
+		}).
+		SetExpiresAt(expiresAt).
+		Save(suite.GetContext())
+	suite.Require().NoError(err)
+
+	return entry
+}
+
+func (suite *TranslationCacheTestSuite) makeAdmin() {
+	err := suite.GetDBClient().User.Update().
+		SetIsAdmin(true).
+		Exec(suite.GetContext())
+	suite.Require().NoError(err)
+}
+
+func (suite *TranslationCacheTestSuite) TestTranslateServesCachedEntry() {
+	entry := suite.seedEntry("hello", time.Now().Add(time.Hour))
+
+	resp := suite.httpClient.POST("/api/v1/translate", map[string]interface{}{
+		"text":         "  hello ",
+		"languageFrom": "ENGLISH",
+		"languageTo":   "SPANISH",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	translations := response["translations"].([]interface{})
+	assert.Len(suite.T(), translations, 1)
+	assert.Equal(suite.T(), "hola (cached)", translations[0].(map[string]interface{})["text"])
+
+	updated, err := suite.GetDBClient().TranslationCacheEntry.Get(suite.GetContext(), entry.ID)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, updated.HitCount)
+	assert.NotNil(suite.T(), updated.LastHitAt)
+}
+
+func (suite *TranslationCacheTestSuite) TestTranslateBatchServesCachedEntries() {
+	hello := suite.seedEntry("hello", time.Now().Add(time.Hour))
+	goodbye := suite.seedEntry("goodbye", time.Now().Add(time.Hour))
+
+	resp := suite.httpClient.POST("/api/v1/translate/batch", map[string]interface{}{
+		"texts":        []string{"hello", "goodbye", " hello "},
+		"languageFrom": "ENGLISH",
+		"languageTo":   "SPANISH",
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	suite.Require().NoError(resp.ParseJSON(&response))
+
+	results := response["results"].([]interface{})
+	suite.Require().Len(results, 3)
+	for _, result := range results {
+		assert.Equal(suite.T(), "hola (cached)", result.(map[string]interface{})["translation"])
+	}
+
+	// Repeated texts count as one hit
+	for _, entry := range []*ent.TranslationCacheEntry{hello, goodbye} {
+		updated, err := suite.GetDBClient().TranslationCacheEntry.Get(suite.GetContext(), entry.ID)
+		suite.Require().NoError(err)
+		assert.Equal(suite.T(), 1, updated.HitCount)
+		assert.NotNil(suite.T(), updated.LastHitAt)
+	}
+}
+
+func (suite *TranslationCacheTestSuite) TestCacheAdminRequiresAdmin() {
+	resp := suite.httpClient.GET("/api/v1/admin/translation-cache", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+
+	resp = suite.httpClient.DELETE("/api/v1/admin/translation-cache", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *TranslationCacheTestSuite) TestCacheAdminStats() {
+	suite.makeAdmin()
+	suite.seedEntry("hello", time.Now().Add(time.Hour))
+	suite.seedEntry("goodbye", time.Now().Add(-time.Hour))
+
+	resp := suite.httpClient.GET("/api/v1/admin/translation-cache", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), float64(2), response["entries"])
+	assert.Equal(suite.T(), float64(1), response["expiredEntries"])
+	assert.Contains(suite.T(), response, "sessionHits")
+	assert.Contains(suite.T(), response, "sessionMisses")
+}
+
+func (suite *TranslationCacheTestSuite) TestCacheAdminPurgeExpiredOnly() {
+	suite.makeAdmin()
+	live := suite.seedEntry("hello", time.Now().Add(time.Hour))
+	suite.seedEntry("goodbye", time.Now().Add(-time.Hour))
+
+	resp := suite.httpClient.DELETE("/api/v1/admin/translation-cache?expiredOnly=true", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), float64(1), response["deleted"])
+
+	remaining, err := suite.GetDBClient().TranslationCacheEntry.Query().
+		Where(translationcacheentry.ID(live.ID)).
+		Exist(suite.GetContext())
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), remaining)
+}
+
+func (suite *TranslationCacheTestSuite) TestCacheAdminPurgeAll() {
+	suite.makeAdmin()
+	suite.seedEntry("hello", time.Now().Add(time.Hour))
+	suite.seedEntry("goodbye", time.Now().Add(-time.Hour))
+
+	resp := suite.httpClient.DELETE("/api/v1/admin/translation-cache", suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	count, err := suite.GetDBClient().TranslationCacheEntry.Query().Count(suite.GetContext())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, count)
+}
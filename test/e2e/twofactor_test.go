@@ -0,0 +1,296 @@
+package e2etest
+
+import (
+	"lexia/internal/shared"
+	"lexia/test/helpers"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TwoFactorTestSuite struct {
+	helpers.E2ETestSuite
+	httpClient *helpers.HTTPClient
+	authToken  string
+}
+
+func (suite *TwoFactorTestSuite) SetupTest() {
+	suite.E2ETestSuite.SetupTest()
+	suite.httpClient = helpers.NewTestHTTPClient(suite.T(), suite.GetTestServerURL())
+	suite.authToken = helpers.GetTestAuthToken(suite.T(), suite.httpClient)
+}
+
+func TestTwoFactorTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorTestSuite))
+}
+
+func (suite *TwoFactorTestSuite) headers() map[string]string {
+	return map[string]string{"Authorization": suite.authToken}
+}
+
+// enable turns two-factor on for the test user and returns the secret and
+// recovery codes.
+func (suite *TwoFactorTestSuite) enable() (string, []string) {
+	resp := suite.httpClient.POST("/api/v1/user/2fa/enrol", nil, suite.headers())
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var enrolment map[string]any
+	suite.Require().NoError(resp.ParseJSON(&enrolment))
+	secret := enrolment["secret"].(string)
+
+	resp = suite.httpClient.POST("/api/v1/user/2fa/confirm", map[string]string{
+		"code": helpers.TotpCode(suite.T(), secret, time.Now()),
+	}, suite.headers())
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+	suite.Require().NoError(resp.ParseJSON(&response))
+
+	return secret, response.RecoveryCodes
+}
+
+// nextCode returns a code the server has not seen yet. Codes are accepted
+// one step either side of now and each step only once.
+func (suite *TwoFactorTestSuite) nextCode(secret string) string {
+	return helpers.TotpCode(suite.T(), secret, time.Now().Add(30*time.Second))
+}
+
+func (suite *TwoFactorTestSuite) signIn() map[string]any {
+	resp := suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+		"email":    "test@example.com",
+		"password": "password123",
+	})
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	return response
+}
+
+func (suite *TwoFactorTestSuite) challenge() string {
+	response := suite.signIn()
+	suite.Require().Equal("mfa_required", response["status"])
+	suite.Require().NotContains(response, "accessToken")
+	return response["challengeToken"].(string)
+}
+
+func (suite *TwoFactorTestSuite) completeSignIn(challengeToken string, code string) *helpers.Response {
+	return suite.httpClient.POST("/api/v1/auth/signin/mfa", map[string]string{
+		"challengeToken": challengeToken,
+		"code":           code,
+	})
+}
+
+func (suite *TwoFactorTestSuite) getStatus() map[string]any {
+	resp := suite.httpClient.GET("/api/v1/user/2fa", suite.headers())
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var status map[string]any
+	suite.Require().NoError(resp.ParseJSON(&status))
+	return status
+}
+
+func (suite *TwoFactorTestSuite) TestEnrolAndConfirm() {
+	assert.Equal(suite.T(), false, suite.getStatus()["enabled"])
+
+	resp := suite.httpClient.POST("/api/v1/user/2fa/enrol", nil, suite.headers())
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var enrolment map[string]any
+	suite.Require().NoError(resp.ParseJSON(&enrolment))
+	secret := enrolment["secret"].(string)
+	assert.Contains(suite.T(), enrolment["uri"], "otpauth://totp/Lexia:test@example.com?")
+	assert.Contains(suite.T(), enrolment["uri"], "secret="+secret)
+
+	// Enrolment alone does not change sign-in
+	assert.Contains(suite.T(), suite.signIn(), "accessToken")
+
+	resp = suite.httpClient.POST("/api/v1/user/2fa/confirm", map[string]string{"code": "000000"}, suite.headers())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/user/2fa/confirm", map[string]string{
+		"code": helpers.TotpCode(suite.T(), secret, time.Now()),
+	}, suite.headers())
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Len(suite.T(), response.RecoveryCodes, 10)
+
+	status := suite.getStatus()
+	assert.Equal(suite.T(), true, status["enabled"])
+	assert.Equal(suite.T(), float64(10), status["recoveryCodesRemaining"])
+
+	resp = suite.httpClient.POST("/api/v1/user/2fa/enrol", nil, suite.headers())
+	assert.Equal(suite.T(), http.StatusConflict, resp.StatusCode)
+
+	resp = suite.httpClient.GET("/api/v1/user/auth", suite.headers())
+	var user map[string]any
+	suite.Require().NoError(resp.ParseJSON(&user))
+	assert.Equal(suite.T(), true, user["twoFactorEnabled"])
+}
+
+func (suite *TwoFactorTestSuite) TestSignInWithTotp() {
+	secret, _ := suite.enable()
+
+	code := suite.nextCode(secret)
+	resp := suite.completeSignIn(suite.challenge(), code)
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response map[string]any
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.NotEmpty(suite.T(), response["accessToken"])
+	assert.NotEmpty(suite.T(), response["refreshToken"])
+
+	resp = suite.httpClient.GET("/api/v1/user/auth", authHeaders(response["accessToken"].(string)))
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	// The same code cannot be used twice
+	resp = suite.completeSignIn(suite.challenge(), code)
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	var errResponse map[string]any
+	suite.Require().NoError(resp.ParseJSON(&errResponse))
+	assert.Equal(suite.T(), shared.ErrInvalidTwoFactorCode, errResponse["code"])
+}
+
+func (suite *TwoFactorTestSuite) TestSignInWithRecoveryCode() {
+	_, recoveryCodes := suite.enable()
+
+	resp := suite.completeSignIn(suite.challenge(), recoveryCodes[0])
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	resp = suite.completeSignIn(suite.challenge(), recoveryCodes[0])
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	assert.Equal(suite.T(), float64(9), suite.getStatus()["recoveryCodesRemaining"])
+}
+
+func (suite *TwoFactorTestSuite) TestChallengeIsSpentAfterFailedAttempts() {
+	secret, _ := suite.enable()
+	challengeToken := suite.challenge()
+
+	for range 5 {
+		resp := suite.completeSignIn(challengeToken, "000000")
+		assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+	}
+
+	resp := suite.completeSignIn(challengeToken, suite.nextCode(secret))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+
+	resp = suite.completeSignIn("not-a-challenge", suite.nextCode(secret))
+	assert.Equal(suite.T(), http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (suite *TwoFactorTestSuite) TestRegenerateRecoveryCodes() {
+	_, recoveryCodes := suite.enable()
+
+	resp := suite.httpClient.POST("/api/v1/user/2fa/recovery-codes", map[string]string{
+		"code": recoveryCodes[0],
+	}, suite.headers())
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var response struct {
+		RecoveryCodes []string `json:"recoveryCodes"`
+	}
+	suite.Require().NoError(resp.ParseJSON(&response))
+	assert.Len(suite.T(), response.RecoveryCodes, 10)
+
+	resp = suite.completeSignIn(suite.challenge(), recoveryCodes[1])
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp = suite.completeSignIn(suite.challenge(), response.RecoveryCodes[0])
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+}
+
+func (suite *TwoFactorTestSuite) TestDisable() {
+	resp := suite.httpClient.POST("/api/v1/user/2fa/disable", map[string]string{"code": "000000"}, suite.headers())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	secret, _ := suite.enable()
+
+	resp = suite.httpClient.POST("/api/v1/user/2fa/disable", map[string]string{"code": "000000"}, suite.headers())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/user/2fa/disable", map[string]string{
+		"code": suite.nextCode(secret),
+	}, suite.headers())
+	assert.Equal(suite.T(), http.StatusNoContent, resp.StatusCode)
+
+	status := suite.getStatus()
+	assert.Equal(suite.T(), false, status["enabled"])
+	assert.Equal(suite.T(), float64(0), status["recoveryCodesRemaining"])
+
+	assert.Contains(suite.T(), suite.signIn(), "accessToken")
+}
+
+func (suite *TwoFactorTestSuite) TestWrongCodesAreThrottledAcrossChallenges() {
+	suite.enable()
+
+	wrongCodes := 0
+	var throttled *helpers.Response
+	for range 10 {
+		resp := suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+			"email":    "test@example.com",
+			"password": "password123",
+		})
+		if resp.StatusCode == http.StatusTooManyRequests {
+			throttled = resp
+			break
+		}
+		suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+		var response map[string]any
+		suite.Require().NoError(resp.ParseJSON(&response))
+		challengeToken := response["challengeToken"].(string)
+
+		// A correct password must not clear the failed codes, so signing in
+		// again only hands out a new challenge.
+		for throttled == nil {
+			resp = suite.completeSignIn(challengeToken, "000000")
+			if resp.StatusCode == http.StatusTooManyRequests {
+				throttled = resp
+			}
+			if resp.StatusCode != http.StatusBadRequest {
+				break
+			}
+			wrongCodes++
+		}
+		if throttled != nil {
+			break
+		}
+	}
+
+	suite.Require().NotNil(throttled)
+	var errResponse map[string]any
+	suite.Require().NoError(throttled.ParseJSON(&errResponse))
+	assert.Equal(suite.T(), shared.ErrTooManyAttempts, errResponse["code"])
+	assert.Equal(suite.T(), 5, wrongCodes)
+}
+
+func (suite *TwoFactorTestSuite) TestCodeChecksShareTheSignInThrottle() {
+	suite.enable()
+
+	for range 3 {
+		resp := suite.httpClient.POST("/api/v1/user/2fa/disable", map[string]string{"code": "000000"}, suite.headers())
+		suite.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+	}
+	for range 2 {
+		resp := suite.httpClient.POST("/api/v1/user/2fa/recovery-codes", map[string]string{"code": "000000"}, suite.headers())
+		suite.Require().Equal(http.StatusBadRequest, resp.StatusCode)
+	}
+
+	resp := suite.httpClient.POST("/api/v1/user/2fa/disable", map[string]string{"code": "000000"}, suite.headers())
+	assert.Equal(suite.T(), http.StatusTooManyRequests, resp.StatusCode)
+
+	var errResponse map[string]any
+	suite.Require().NoError(resp.ParseJSON(&errResponse))
+	assert.Equal(suite.T(), shared.ErrTooManyAttempts, errResponse["code"])
+}
@@ -2,9 +2,15 @@ package e2etest
 
 import (
 	"fmt"
+	"lexia/ent"
+	"lexia/ent/schema"
+	"lexia/internal/shared"
 	"lexia/test/helpers"
 	"net/http"
+	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -118,8 +124,8 @@ func (suite *WordTestSuite) TestCreateWordValidationErrors() {
 				"definition": "a greeting",
 				"folderId":   folderID,
 			},
-			expectedStatus: 500, // Database constraint violation
-			expectedMsg:    "",
+			expectedStatus: 400,
+			expectedMsg:    "Invalid text",
 		},
 		{
 			name: "empty text",
@@ -128,8 +134,8 @@ func (suite *WordTestSuite) TestCreateWordValidationErrors() {
 				"definition": "a greeting",
 				"folderId":   folderID,
 			},
-			expectedStatus: 500, // Database constraint violation
-			expectedMsg:    "",
+			expectedStatus: 400,
+			expectedMsg:    "Invalid text",
 		},
 		{
 			name: "missing folderId",
@@ -137,7 +143,7 @@ func (suite *WordTestSuite) TestCreateWordValidationErrors() {
 				"text":       "hello",
 				"definition": "a greeting",
 			},
-			expectedStatus: 500, // Database constraint violation (null UUID)
+			expectedStatus: 404, // The nil folder ID matches no folder
 			expectedMsg:    "",
 		},
 		{
@@ -161,7 +167,7 @@ func (suite *WordTestSuite) TestCreateWordValidationErrors() {
 				var response map[string]interface{}
 				err := resp.ParseJSON(&response)
 				assert.NoError(t, err)
-				assert.Contains(t, response["message"], tc.expectedMsg)
+				assert.Contains(t, response["error"], tc.expectedMsg)
 			}
 		})
 	}
@@ -177,7 +183,7 @@ func (suite *WordTestSuite) TestCreateWordWithNonExistentFolder() {
 	}
 
 	resp := suite.httpClient.POST("/api/v1/words", wordData, suite.getAuthHeaders())
-	assert.Equal(suite.T(), http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
 }
 
 func (suite *WordTestSuite) TestCreateWordUnauthorized() {
@@ -279,14 +285,15 @@ func (suite *WordTestSuite) TestGetWordsByFolder() {
 	resp := suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s/words", folderID), suite.getAuthHeaders())
 	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
 
-	var response []map[string]interface{}
+	var response pageResponse
 	err := resp.ParseJSON(&response)
 	assert.NoError(suite.T(), err)
 
-	assert.Len(suite.T(), response, 3)
+	assert.Len(suite.T(), response.Items, 3)
+	assert.Nil(suite.T(), response.NextCursor)
 
-	wordTexts := make([]string, len(response))
-	for i, word := range response {
+	wordTexts := make([]string, len(response.Items))
+	for i, word := range response.Items {
 		wordTexts[i] = word["text"].(string)
 		assert.NotEmpty(suite.T(), word["id"])
 		assert.NotEmpty(suite.T(), word["createdAt"])
@@ -321,7 +328,7 @@ func (suite *WordTestSuite) TestGetWordsByNonExistentFolder() {
 	nonExistentFolderID := uuid.New().String()
 
 	resp := suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s/words", nonExistentFolderID), suite.getAuthHeaders())
-	assert.Equal(suite.T(), http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
 }
 
 func (suite *WordTestSuite) TestUpdateWord() {
@@ -438,8 +445,8 @@ func (suite *WordTestSuite) TestUpdateWordValidationErrors() {
 			updateData: map[string]interface{}{
 				"text": "",
 			},
-			expectedStatus: 500,
-			expectedMsg:    "INTERNAL",
+			expectedStatus: 400,
+			expectedMsg:    "Invalid text",
 		},
 	}
 
@@ -490,7 +497,7 @@ func (suite *WordTestSuite) TestUpdateWordNotFound() {
 	}
 
 	resp := suite.httpClient.PUT(fmt.Sprintf("/api/v1/words/%s", nonExistentWordID), updateData, suite.getAuthHeaders())
-	assert.Equal(suite.T(), http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
 }
 
 func (suite *WordTestSuite) TestUpdateWordInvalidID() {
@@ -558,7 +565,7 @@ func (suite *WordTestSuite) TestDeleteWordNotFound() {
 	nonExistentWordID := uuid.New().String()
 
 	resp := suite.httpClient.DELETE(fmt.Sprintf("/api/v1/words/%s", nonExistentWordID), suite.getAuthHeaders())
-	assert.Equal(suite.T(), http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
 }
 
 func (suite *WordTestSuite) TestDeleteWordInvalidID() {
@@ -632,16 +639,16 @@ func (suite *WordTestSuite) TestWordOperationsWithDifferentUsers() {
 	wordID := createResponse["id"].(string)
 
 	getResp := user2HttpClient.GET(fmt.Sprintf("/api/v1/folders/%s/words", folderID), user2Headers)
-	assert.Equal(suite.T(), http.StatusInternalServerError, getResp.StatusCode)
+	assert.Equal(suite.T(), http.StatusForbidden, getResp.StatusCode)
 
 	updateData := map[string]interface{}{
 		"text": "hacked word",
 	}
 	updateResp := user2HttpClient.PUT(fmt.Sprintf("/api/v1/words/%s", wordID), updateData, user2Headers)
-	assert.Equal(suite.T(), http.StatusInternalServerError, updateResp.StatusCode)
+	assert.Equal(suite.T(), http.StatusForbidden, updateResp.StatusCode)
 
 	deleteResp := user2HttpClient.DELETE(fmt.Sprintf("/api/v1/words/%s", wordID), user2Headers)
-	assert.Equal(suite.T(), http.StatusInternalServerError, deleteResp.StatusCode)
+	assert.Equal(suite.T(), http.StatusForbidden, deleteResp.StatusCode)
 
 	verifyResp := suite.httpClient.GET(fmt.Sprintf("/api/v1/words/%s", wordID), suite.getAuthHeaders())
 	assert.Equal(suite.T(), http.StatusOK, verifyResp.StatusCode)
@@ -673,10 +680,10 @@ func (suite *WordTestSuite) TestBulkWordOperations() {
 	resp := suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s/words", folderID), suite.getAuthHeaders())
 	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
 
-	var wordsResponse []map[string]interface{}
+	var wordsResponse pageResponse
 	err := resp.ParseJSON(&wordsResponse)
 	assert.NoError(suite.T(), err)
-	assert.Len(suite.T(), wordsResponse, 5)
+	assert.Len(suite.T(), wordsResponse.Items, 5)
 
 	for _, wordID := range createdWordIDs {
 		deleteResp := suite.httpClient.DELETE(fmt.Sprintf("/api/v1/words/%s", wordID), suite.getAuthHeaders())
@@ -686,10 +693,10 @@ func (suite *WordTestSuite) TestBulkWordOperations() {
 	finalResp := suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s/words", folderID), suite.getAuthHeaders())
 	assert.Equal(suite.T(), http.StatusOK, finalResp.StatusCode)
 
-	var finalWordsResponse []map[string]interface{}
+	var finalWordsResponse pageResponse
 	err = finalResp.ParseJSON(&finalWordsResponse)
 	assert.NoError(suite.T(), err)
-	assert.Len(suite.T(), finalWordsResponse, 0)
+	assert.Len(suite.T(), finalWordsResponse.Items, 0)
 }
 
 func (suite *WordTestSuite) TestCheckWordDuplicate() {
@@ -884,3 +891,400 @@ func (suite *WordTestSuite) TestCheckWordDuplicateUserIsolation() {
 	assert.True(suite.T(), result["isDuplicate"].(bool))
 	assert.NotNil(suite.T(), result["word"])
 }
+
+func (suite *WordTestSuite) seedTranslation(text string, variants ...string) {
+	provider := os.Getenv(shared.EnvTranslationProvider)
+	if provider == "" {
+		provider = shared.TranslationProviderGoogle
+	}
+
+	cachedVariants := make([]schema.CachedTranslationVariant, len(variants))
+	for i, variant := range variants {
+		cachedVariants[i] = schema.CachedTranslationVariant{Text: variant, Confidence: 0.9, Source: "PRIMARY"}
+	}
+
+	_, err := suite.GetDBClient().TranslationCacheEntry.Create().
+		SetNormalizedText(text).
+		SetLanguageFrom("en").
+		SetLanguageTo("ka").
+		SetProvider(provider).
+		SetVariants(cachedVariants).
+		SetExpiresAt(time.Now().Add(time.Hour)).
+		Save(suite.GetContext())
+	suite.Require().NoError(err)
+}
+
+func (suite *WordTestSuite) TestCreateWordFromTranslation() {
+	folderID := suite.createTestFolder()
+	suite.seedTranslation("hello", "გამარჯობა", "სალამი")
+
+	resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/folders/%s/words/from-translation", folderID), map[string]interface{}{
+		"text":         "hello",
+		"variantIndex": 1,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	word := response["word"].(map[string]interface{})
+	assert.Equal(suite.T(), "hello", word["text"])
+	assert.Equal(suite.T(), "სალამი", word["definition"])
+	assert.Equal(suite.T(), folderID, word["folder"].(map[string]interface{})["id"])
+
+	assert.Equal(suite.T(), "სალამი", response["chosenVariant"].(map[string]interface{})["text"])
+	otherVariants := response["otherVariants"].([]interface{})
+	assert.Len(suite.T(), otherVariants, 1)
+	assert.Equal(suite.T(), "გამარჯობა", otherVariants[0].(map[string]interface{})["text"])
+}
+
+func (suite *WordTestSuite) TestCreateWordFromTranslationDuplicate() {
+	folderID := suite.createTestFolder()
+
+	resp := suite.httpClient.POST("/api/v1/words", map[string]interface{}{
+		"text":       "hello",
+		"definition": "a greeting",
+		"folderId":   folderID,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	resp = suite.httpClient.POST(fmt.Sprintf("/api/v1/folders/%s/words/from-translation", folderID), map[string]interface{}{
+		"text": "hello",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusConflict, resp.StatusCode)
+
+	var response map[string]interface{}
+	err := resp.ParseJSON(&response)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), "WORD_ALREADY_EXISTS", response["code"])
+	details := response["details"].(map[string]interface{})
+	word := details["word"].(map[string]interface{})
+	assert.Equal(suite.T(), "a greeting", word["definition"])
+	assert.NotEmpty(suite.T(), word["folderPath"])
+}
+
+func (suite *WordTestSuite) TestConcurrentCreateWordFromTranslationAddsOneWord() {
+	folderID := suite.createTestFolder()
+	suite.seedTranslation("hello", "გამარჯობა")
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/folders/%s/words/from-translation", folderID), map[string]interface{}{
+				"text": "hello",
+			}, suite.getAuthHeaders())
+			assert.Contains(suite.T(), []int{http.StatusCreated, http.StatusConflict}, resp.StatusCode)
+		}()
+	}
+	wg.Wait()
+
+	resp := suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s/words", folderID), suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+
+	var words pageResponse
+	suite.Require().NoError(resp.ParseJSON(&words))
+	assert.Len(suite.T(), words.Items, 1)
+}
+
+func (suite *WordTestSuite) TestCreateWordFromTranslationRequiresLanguagePair() {
+	resp := suite.httpClient.POST("/api/v1/folders", map[string]interface{}{
+		"name":         "No Target",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var folder map[string]interface{}
+	err := resp.ParseJSON(&folder)
+	assert.NoError(suite.T(), err)
+
+	resp = suite.httpClient.POST(fmt.Sprintf("/api/v1/folders/%s/words/from-translation", folder["id"]), map[string]interface{}{
+		"text": "hello",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *WordTestSuite) TestCreateWordFromTranslationVariantOutOfRange() {
+	folderID := suite.createTestFolder()
+	suite.seedTranslation("hello", "გამარჯობა")
+
+	resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/folders/%s/words/from-translation", folderID), map[string]interface{}{
+		"text":         "hello",
+		"variantIndex": 3,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+
+	resp = suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s/words", folderID), suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var words pageResponse
+	err := resp.ParseJSON(&words)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), words.Items)
+}
+
+func (suite *WordTestSuite) TestCreateWordFromTranslationOtherUsersFolder() {
+	folderID := suite.createTestFolder()
+
+	resp := suite.httpClient.POST("/api/v1/auth/signup", map[string]string{
+		"email":    "other@example.com",
+		"password": "password123",
+		"username": "otheruser",
+	})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	resp = suite.httpClient.POST("/api/v1/auth/signin", map[string]string{
+		"email":    "other@example.com",
+		"password": "password123",
+	})
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var signinResponse map[string]interface{}
+	err := resp.ParseJSON(&signinResponse)
+	assert.NoError(suite.T(), err)
+	otherHeaders := map[string]string{"Authorization": "Bearer " + signinResponse["accessToken"].(string)}
+
+	resp = suite.httpClient.POST(fmt.Sprintf("/api/v1/folders/%s/words/from-translation", folderID), map[string]interface{}{
+		"text": "hello",
+	}, otherHeaders)
+	assert.Equal(suite.T(), http.StatusForbidden, resp.StatusCode)
+}
+
+func (suite *WordTestSuite) TestCreateWordAutofillDefinition() {
+	folderID := suite.createTestFolder()
+	suite.seedTranslation("hello", "გამარჯობა")
+
+	resp := suite.httpClient.POST("/api/v1/words", map[string]interface{}{
+		"text":               "hello",
+		"folderId":           folderID,
+		"autofillDefinition": true,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	var word map[string]interface{}
+	err := resp.ParseJSON(&word)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "გამარჯობა", word["definition"])
+}
+
+func (suite *WordTestSuite) TestCreateWordAutofillKeepsExplicitDefinition() {
+	folderID := suite.createTestFolder()
+	suite.seedTranslation("hello", "გამარჯობა")
+
+	resp := suite.httpClient.POST("/api/v1/words", map[string]interface{}{
+		"text":               "hello",
+		"definition":         "my own definition",
+		"folderId":           folderID,
+		"autofillDefinition": true,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	var word map[string]interface{}
+	err := resp.ParseJSON(&word)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "my own definition", word["definition"])
+}
+
+func (suite *WordTestSuite) TestCreateWordAutofillFromUserPreference() {
+	folderID := suite.createTestFolder()
+	suite.seedTranslation("hello", "გამარჯობა")
+
+	resp := suite.httpClient.PUT("/api/v1/user/preferences", map[string]interface{}{
+		"autofillDefinitions": true,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var user map[string]interface{}
+	err := resp.ParseJSON(&user)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), true, user["autofillDefinitions"])
+
+	resp = suite.httpClient.POST("/api/v1/words", map[string]interface{}{
+		"text":     "hello",
+		"folderId": folderID,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	var word map[string]interface{}
+	err = resp.ParseJSON(&word)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "გამარჯობა", word["definition"])
+
+	// An explicit false overrides the preference
+	resp = suite.httpClient.POST("/api/v1/words", map[string]interface{}{
+		"text":               "hello",
+		"folderId":           folderID,
+		"autofillDefinition": false,
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	err = resp.ParseJSON(&word)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "", word["definition"])
+}
+
+func (suite *WordTestSuite) TestCreateWordSkipsAutofillWithoutTranslateScope() {
+	folderID := suite.createTestFolder()
+	suite.seedTranslation("hello", "გამარჯობა")
+
+	resp := suite.httpClient.POST("/api/v1/user/tokens", map[string]interface{}{
+		"name":   "Writer",
+		"scopes": []string{"words:write"},
+	}, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusCreated, resp.StatusCode, resp.GetBodyAsString())
+
+	var token map[string]interface{}
+	suite.Require().NoError(resp.ParseJSON(&token))
+
+	resp = suite.httpClient.POST("/api/v1/words", map[string]interface{}{
+		"text":               "hello",
+		"folderId":           folderID,
+		"autofillDefinition": true,
+	}, authHeaders(token["token"].(string)))
+	assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+
+	var word map[string]interface{}
+	err := resp.ParseJSON(&word)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "", word["definition"])
+}
+
+func (suite *WordTestSuite) TestAutofillJob() {
+	folderID := suite.createTestFolder()
+	suite.seedTranslation("hello", "გამარჯობა")
+	suite.seedTranslation("water", "წყალი")
+
+	for _, wordData := range []map[string]interface{}{
+		{"text": "hello", "folderId": folderID},
+		{"text": "water", "folderId": folderID},
+		{"text": "bread", "definition": "პური", "folderId": folderID},
+	} {
+		resp := suite.httpClient.POST("/api/v1/words", wordData, suite.getAuthHeaders())
+		assert.Equal(suite.T(), http.StatusCreated, resp.StatusCode)
+	}
+
+	resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/folders/%s/words/autofill", folderID), nil, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusAccepted, resp.StatusCode)
+
+	var job map[string]interface{}
+	err := resp.ParseJSON(&job)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), folderID, job["folderId"])
+	assert.Equal(suite.T(), float64(2), job["total"])
+
+	jobURL := fmt.Sprintf("/api/v1/folders/%s/words/autofill/%s", folderID, job["id"])
+	assert.Eventually(suite.T(), func() bool {
+		resp := suite.httpClient.GET(jobURL, suite.getAuthHeaders())
+		if resp.StatusCode != http.StatusOK {
+			return false
+		}
+		if err := resp.ParseJSON(&job); err != nil {
+			return false
+		}
+		return job["status"] == "COMPLETED"
+	}, 10*time.Second, 100*time.Millisecond)
+
+	assert.Equal(suite.T(), float64(2), job["processed"])
+	assert.Equal(suite.T(), float64(2), job["filled"])
+	assert.Equal(suite.T(), float64(0), job["failed"])
+	assert.NotNil(suite.T(), job["finishedAt"])
+
+	resp = suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s/words", folderID), suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var words pageResponse
+	err = resp.ParseJSON(&words)
+	assert.NoError(suite.T(), err)
+
+	definitions := map[string]interface{}{}
+	for _, word := range words.Items {
+		definitions[word["text"].(string)] = word["definition"]
+	}
+	assert.Equal(suite.T(), "გამარჯობა", definitions["hello"])
+	assert.Equal(suite.T(), "წყალი", definitions["water"])
+	assert.Equal(suite.T(), "პური", definitions["bread"])
+}
+
+func (suite *WordTestSuite) TestFailStaleAutofillJobs() {
+	now := time.Now()
+
+	// A folder has at most one running job, so each gets its own folder
+	createRunningJob := func(updatedAt time.Time) uuid.UUID {
+		job, err := suite.GetDBClient().AutofillJob.Create().
+			SetFolderID(uuid.MustParse(suite.createTestFolder())).
+			SetStatus(schema.AutofillJobStatusRunning).
+			SetUpdateTime(updatedAt).
+			Save(suite.GetContext())
+		suite.Require().NoError(err)
+		return job.ID
+	}
+
+	// Only a job that stopped saving progress was abandoned; the other may be
+	// running on another replica
+	staleID := createRunningJob(now.Add(-time.Hour))
+	activeID := createRunningJob(now.Add(-time.Minute))
+
+	// The worker sweeps stale jobs every time it polls
+	var stale *ent.AutofillJob
+	assert.Eventually(suite.T(), func() bool {
+		var err error
+		stale, err = suite.GetDBClient().AutofillJob.Get(suite.GetContext(), staleID)
+		return err == nil && stale.Status == schema.AutofillJobStatusFailed
+	}, 15*time.Second, 100*time.Millisecond)
+	suite.Require().NotNil(stale)
+	assert.NotNil(suite.T(), stale.FinishedAt)
+
+	active, err := suite.GetDBClient().AutofillJob.Get(suite.GetContext(), activeID)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), schema.AutofillJobStatusRunning, active.Status)
+}
+
+func (suite *WordTestSuite) TestAutofillJobIsOneAtATime() {
+	folderID := suite.createTestFolder()
+
+	running, err := suite.GetDBClient().AutofillJob.Create().
+		SetFolderID(uuid.MustParse(folderID)).
+		SetStatus(schema.AutofillJobStatusRunning).
+		Save(suite.GetContext())
+	suite.Require().NoError(err)
+
+	// The database refuses a second active job even when nothing checked first
+	_, err = suite.GetDBClient().AutofillJob.Create().
+		SetFolderID(uuid.MustParse(folderID)).
+		Save(suite.GetContext())
+	assert.True(suite.T(), ent.IsConstraintError(err))
+
+	resp := suite.httpClient.POST(fmt.Sprintf("/api/v1/folders/%s/words/autofill", folderID), nil, suite.getAuthHeaders())
+	suite.Require().Equal(http.StatusAccepted, resp.StatusCode)
+
+	var job map[string]interface{}
+	suite.Require().NoError(resp.ParseJSON(&job))
+	assert.Equal(suite.T(), running.ID.String(), job["id"])
+}
+
+func (suite *WordTestSuite) TestAutofillJobRequiresLanguagePair() {
+	resp := suite.httpClient.POST("/api/v1/folders", map[string]interface{}{
+		"name":         "No Target Language",
+		"type":         "WORD_COLLECTION",
+		"languageFrom": "ENGLISH",
+	}, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+
+	var folder map[string]interface{}
+	err := resp.ParseJSON(&folder)
+	assert.NoError(suite.T(), err)
+
+	resp = suite.httpClient.POST(fmt.Sprintf("/api/v1/folders/%s/words/autofill", folder["id"]), nil, suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusBadRequest, resp.StatusCode)
+}
+
+func (suite *WordTestSuite) TestAutofillJobNotFound() {
+	folderID := suite.createTestFolder()
+
+	resp := suite.httpClient.GET(fmt.Sprintf("/api/v1/folders/%s/words/autofill/%s", folderID, uuid.New()), suite.getAuthHeaders())
+	assert.Equal(suite.T(), http.StatusNotFound, resp.StatusCode)
+}
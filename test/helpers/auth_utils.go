@@ -7,19 +7,33 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+const TestPassword = "password123"
+
 func GetTestAuthToken(t *testing.T, httpClient *HTTPClient) string {
+	return "Bearer " + signUpAndSignIn(t, httpClient, "test@example.com", "testuser")
+}
+
+// SignUpUser creates a user with TestPassword, signs them in and returns
+// the headers that authenticate their requests.
+func SignUpUser(t *testing.T, httpClient *HTTPClient, email string, username string) map[string]string {
+	return map[string]string{
+		"Authorization": "Bearer " + signUpAndSignIn(t, httpClient, email, username),
+	}
+}
+
+func signUpAndSignIn(t *testing.T, httpClient *HTTPClient, email string, username string) string {
 	signupData := map[string]string{
-		"email":    "test@example.com",
-		"password": "password123",
-		"username": "testuser",
+		"email":    email,
+		"password": TestPassword,
+		"username": username,
 	}
 
 	resp := httpClient.POST("/api/v1/auth/signup", signupData)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	signinData := map[string]string{
-		"email":    "test@example.com",
-		"password": "password123",
+		"email":    email,
+		"password": TestPassword,
 	}
 
 	resp = httpClient.POST("/api/v1/auth/signin", signinData)
@@ -33,5 +47,5 @@ func GetTestAuthToken(t *testing.T, httpClient *HTTPClient) string {
 	assert.True(t, ok, "Token should be a string")
 	assert.NotEmpty(t, token)
 
-	return "Bearer " + token
+	return token
 }
@@ -0,0 +1,30 @@
+package helpers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// CreateFolder creates a folder through the API and returns its ID.
+func CreateFolder(t *testing.T, httpClient *HTTPClient, headers map[string]string, data map[string]any) string {
+	resp := httpClient.POST("/api/v1/folders", data, headers)
+	require.Equal(t, http.StatusOK, resp.StatusCode, resp.GetBodyAsString())
+
+	var response map[string]any
+	require.NoError(t, resp.ParseJSON(&response))
+
+	return response["id"].(string)
+}
+
+// CreateWord creates a word through the API and returns the created word.
+func CreateWord(t *testing.T, httpClient *HTTPClient, headers map[string]string, data map[string]any) map[string]any {
+	resp := httpClient.POST("/api/v1/words", data, headers)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, resp.GetBodyAsString())
+
+	var response map[string]any
+	require.NoError(t, resp.ParseJSON(&response))
+
+	return response
+}
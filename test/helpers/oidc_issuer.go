@@ -0,0 +1,93 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const MockOIDCClientID = "lexia-test-client"
+
+// MockOIDCIssuer is a local OpenID Connect issuer for tests. It serves its
+// signing key as a JWKS and signs ID tokens for any subject.
+type MockOIDCIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+}
+
+func NewMockOIDCIssuer(t *testing.T) *MockOIDCIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating issuer key: %v", err)
+	}
+
+	issuer := &MockOIDCIssuer{key: key, kid: "mock-key-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": issuer.kid,
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+
+	return issuer
+}
+
+func (i *MockOIDCIssuer) Close() {
+	i.server.Close()
+}
+
+func (i *MockOIDCIssuer) Issuer() string {
+	return i.server.URL
+}
+
+func (i *MockOIDCIssuer) JwksURL() string {
+	return i.server.URL + "/jwks"
+}
+
+// IDToken signs an ID token for the subject. Standard claims default to
+// values the issuer accepts and can be overridden through claims.
+func (i *MockOIDCIssuer) IDToken(t *testing.T, subject string, claims map[string]any) string {
+	t.Helper()
+
+	tokenClaims := jwt.MapClaims{
+		"iss": i.Issuer(),
+		"aud": MockOIDCClientID,
+		"sub": subject,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		tokenClaims[name] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, tokenClaims)
+	token.Header["kid"] = i.kid
+
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		t.Fatalf("signing ID token: %v", err)
+	}
+
+	return signed
+}
@@ -0,0 +1,36 @@
+package helpers
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSigningKeyID is the kid of the key the test server signs access
+// tokens with.
+const TestSigningKeyID = "test-key"
+
+// WriteTestSigningKey writes a fresh Ed25519 key to dir as <kid>.pem, the
+// layout the access token key ring loads.
+func WriteTestSigningKey(t *testing.T, dir string, kid string) {
+	t.Helper()
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generating signing key: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatalf("encoding signing key: %v", err)
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatalf("writing signing key: %v", err)
+	}
+}
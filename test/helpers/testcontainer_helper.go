@@ -4,10 +4,16 @@ import (
 	"context"
 	"fmt"
 	"lexia/ent"
+	"lexia/internal/mailer"
 	"lexia/internal/modules"
+	"lexia/internal/modules/folder"
+	"lexia/internal/modules/languages"
+	"lexia/internal/password"
 	"lexia/internal/shared"
+	"lexia/internal/throttle"
 	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -23,6 +29,11 @@ type E2ETestSuite struct {
 	ctx               context.Context
 	postgresContainer *postgres.PostgresContainer
 	dbClient          *ent.Client
+	mailer            *mailer.MemoryMailer
+	services          *shared.Services
+	stopWorkers       context.CancelFunc
+	workers           sync.WaitGroup
+	throttleStore     *throttle.MemoryStore
 	server            *gin.Engine
 	testServer        *httptest.Server
 	originalDbConnStr string
@@ -55,27 +66,68 @@ func (suite *E2ETestSuite) SetupSuite() {
 	os.Setenv(shared.EnvEnvironment, "test")
 	os.Setenv(shared.EnvDbConnectionString, connStr)
 	os.Setenv(shared.EnvPort, "8080")
-	os.Setenv(shared.EnvAccessTokenSecret, "test-jwt-secret-key-for-testing")
 	os.Setenv(shared.EnvAccessTokenExpSeconds, "3600")
 
+	keysDir := suite.T().TempDir()
+	WriteTestSigningKey(suite.T(), keysDir, TestSigningKeyID)
+	os.Setenv(shared.EnvJwtKeysDir, keysDir)
+
+	envVars, err := shared.ParseEnv()
+	suite.Require().NoError(err)
+
 	suite.dbClient, err = ent.Open("postgres", connStr)
 	suite.Require().NoError(err)
 
+	// The word search indexes need pg_trgm, which migrations enable
+	_, err = suite.dbClient.ExecContext(suite.ctx, "CREATE EXTENSION IF NOT EXISTS pg_trgm")
+	suite.Require().NoError(err)
+
 	err = suite.dbClient.Schema.Create(suite.ctx)
 	suite.Require().NoError(err)
 
+	err = languages.SyncLanguages(suite.ctx, suite.dbClient)
+	suite.Require().NoError(err)
+
+	folder.RegisterWordCountHooks(suite.dbClient)
+
+	suite.mailer = mailer.NewMemoryMailer()
+	suite.throttleStore = throttle.NewMemoryStore()
+
+	passwordHasher, err := password.New(password.DefaultConfig())
+	suite.Require().NoError(err)
+
 	resouceConfig := &shared.ResourceConfig{
-		DB: suite.dbClient,
+		Env:            envVars,
+		DB:             suite.dbClient,
+		Mailer:         suite.mailer,
+		Throttler:      throttle.New(suite.throttleStore),
+		PasswordHasher: passwordHasher,
 	}
 
+	suite.services, err = modules.NewServices(suite.dbClient, envVars)
+	suite.Require().NoError(err)
+
 	apiCfg := shared.ApiConfig{
 		ResourceConfig: resouceConfig,
+		Services:       suite.services,
 	}
 
-	suite.server, err = modules.CreateWebserver(&apiCfg)
+	suite.server, err = modules.CreateWebserver(&apiCfg, envVars)
 	suite.Require().NoError(err)
 
 	suite.testServer = httptest.NewServer(suite.server)
+
+	workerCtx, stopWorkers := context.WithCancel(suite.ctx)
+	suite.stopWorkers = stopWorkers
+	suite.workers.Add(2)
+	go func() {
+		defer suite.workers.Done()
+		suite.services.AutofillWorker.Run(workerCtx)
+	}()
+	go func() {
+		defer suite.workers.Done()
+		suite.services.SrsOptimizer.Run(workerCtx)
+	}()
 }
 
 func (suite *E2ETestSuite) TearDownSuite() {
@@ -83,6 +135,15 @@ func (suite *E2ETestSuite) TearDownSuite() {
 		suite.testServer.Close()
 	}
 
+	if suite.stopWorkers != nil {
+		suite.stopWorkers()
+		suite.workers.Wait()
+	}
+
+	if suite.services != nil {
+		suite.services.Translations.Close()
+	}
+
 	if suite.dbClient != nil {
 		suite.dbClient.Close()
 	}
@@ -101,6 +162,8 @@ func (suite *E2ETestSuite) TearDownSuite() {
 
 func (suite *E2ETestSuite) SetupTest() {
 	suite.cleanupDatabase()
+	suite.mailer.Reset()
+	suite.throttleStore.Clear()
 }
 
 func (suite *E2ETestSuite) TearDownTest() {
@@ -108,12 +171,54 @@ func (suite *E2ETestSuite) TearDownTest() {
 }
 
 func (suite *E2ETestSuite) cleanupDatabase() {
-	_, err := suite.dbClient.Word.Delete().Exec(suite.ctx)
+	_, err := suite.dbClient.ReviewLog.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.WordReview.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.Word.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.AutofillJob.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.SrsOptimizationJob.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.FolderShare.Delete().Exec(suite.ctx)
 	suite.Require().NoError(err)
 
 	_, err = suite.dbClient.Folder.Delete().Exec(suite.ctx)
 	suite.Require().NoError(err)
 
+	_, err = suite.dbClient.SrsSettings.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.TranslationCacheEntry.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.Session.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.OneTimeToken.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.Identity.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.RecoveryCode.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.PersonalAccessToken.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.AuditLog.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
+	_, err = suite.dbClient.ThrottleEntry.Delete().Exec(suite.ctx)
+	suite.Require().NoError(err)
+
 	_, err = suite.dbClient.User.Delete().Exec(suite.ctx)
 	suite.Require().NoError(err)
 }
@@ -130,6 +235,11 @@ func (suite *E2ETestSuite) GetDBClient() *ent.Client {
 	return suite.dbClient
 }
 
+// GetMailer returns the in-memory mailer the test server sends through.
+func (suite *E2ETestSuite) GetMailer() *mailer.MemoryMailer {
+	return suite.mailer
+}
+
 func (suite *E2ETestSuite) GetContext() context.Context {
 	return suite.ctx
 }